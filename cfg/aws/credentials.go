@@ -22,6 +22,7 @@ import (
 
 	"github.com/aws/amazon-cloudwatch-agent/cfg/envconfig"
 	"github.com/aws/amazon-cloudwatch-agent/extension/agenthealth/handler/stats/agent"
+	"github.com/aws/amazon-cloudwatch-agent/internal/dryrun"
 )
 
 const (
@@ -101,6 +102,9 @@ func getSession(config *aws.Config) *session.Session {
 		}
 	}
 	log.Printf("D! Successfully created credential sessions\n")
+	if dryrun.Enabled() {
+		ses.Handlers.Build.PushBackNamed(dryrun.Handler)
+	}
 	cred, err := ses.Config.Credentials.Get()
 	if err != nil {
 		log.Printf("E! Failed to get credential from session: %v", err)
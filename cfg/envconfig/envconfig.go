@@ -35,6 +35,7 @@ const (
 	CWOtelConfigContent         = "CW_OTEL_CONFIG_CONTENT"
 	CWAgentMergedOtelConfig     = "CWAGENT_MERGED_OTEL_CONFIG"
 	CWAgentLogsBackpressureMode = "CWAGENT_LOGS_BACKPRESSURE_MODE"
+	CWAgentSelfTelemetryAddress = "CWAGENT_SELF_TELEMETRY_ADDRESS"
 
 	// confused deputy prevention related headers
 	AmzSourceAccount = "AMZ_SOURCE_ACCOUNT" // populates the "x-amz-source-account" header
@@ -12,6 +12,7 @@ import (
 	"github.com/aws/amazon-cloudwatch-agent/tool/data"
 	"github.com/aws/amazon-cloudwatch-agent/tool/processors"
 	"github.com/aws/amazon-cloudwatch-agent/tool/processors/basicInfo"
+	"github.com/aws/amazon-cloudwatch-agent/tool/processors/migration/fluent"
 	"github.com/aws/amazon-cloudwatch-agent/tool/processors/migration/linux"
 	"github.com/aws/amazon-cloudwatch-agent/tool/processors/migration/windows"
 	"github.com/aws/amazon-cloudwatch-agent/tool/processors/serialization"
@@ -43,19 +44,37 @@ func main() {
 	isNonInteractiveLinuxMigration := flag.Bool("isNonInteractiveLinuxMigration", false,
 		"If true, it will do the linux config migration. Default value is false.")
 
+	isNonInteractiveFluentMigration := flag.Bool("isNonInteractiveFluentMigration", false,
+		"If true, it will migrate the tail inputs of a Fluent Bit or Fluentd config. Default value is false.")
+
 	tracesOnly := flag.Bool("tracesOnly", false, "If true, only trace configuration will be generated")
 	useParameterStore := flag.Bool("useParameterStore", false,
 		"If true, it will use the parameter store for the migrated config storage.")
 	isNonInteractiveXrayMigration = flag.Bool("nonInteractiveXrayMigration", false, "If true, then this is part of non Interactive xray migration tool.")
 	configFilePath := flag.String("configFilePath", "",
-		fmt.Sprintf("The path of the old config file. Default is %s on Windows or %s on Linux", windows.DefaultFilePathWindowsConfiguration, linux.DefaultFilePathLinuxConfiguration))
+		fmt.Sprintf("The path of the old config file. Default is %s on Windows or %s on Linux, or %s for the Fluent Bit or Fluentd migration",
+			windows.DefaultFilePathWindowsConfiguration, linux.DefaultFilePathLinuxConfiguration, fluent.DefaultFilePathFluentConfiguration))
 
 	configOutputPath = flag.String("configOutputPath", "", "Specifies where to write the configuration file generated by the wizard")
 	parameterStoreName := flag.String("parameterStoreName", "", "The parameter store name. Default is AmazonCloudWatch-windows")
 	parameterStoreRegion := flag.String("parameterStoreRegion", "", "The parameter store region. Default is us-east-1")
+	answerFilePath := flag.String("answerFile", "",
+		"The path of a YAML or JSON file that answers the questions of the wizard, which then runs without prompting. The keys are the IDs of the questions, such as os or logs.file_path, and the values their answers.")
 
 	flag.Parse()
 
+	if *answerFilePath != "" {
+		run := startProcessing
+		if *tracesOnly {
+			run = processTracesOnly
+		}
+		if err := runWithAnswerFile(*answerFilePath, run); err != nil {
+			fmt.Printf("E! %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	if *isNonInteractiveWindowsMigration {
 		addWindowsMigrationInputs(*configFilePath, *parameterStoreName, *parameterStoreRegion, *useParameterStore)
 	} else if *isNonInteractiveLinuxMigration {
@@ -68,15 +87,18 @@ func main() {
 		}
 		process(ctx, config, linux.Processor, serialization.Processor)
 		return
-	} else if *tracesOnly {
+	} else if *isNonInteractiveFluentMigration {
 		ctx := new(runtime.Context)
 		config := new(data.Config)
-		ctx.TracesOnly = true
-		ctx.ConfigOutputPath = *configOutputPath
-		if *isNonInteractiveXrayMigration {
-			ctx.NonInteractiveXrayMigration = true
+		ctx.HasExistingFluentConfig = true
+		ctx.ConfigFilePath = *configFilePath
+		if ctx.ConfigFilePath == "" {
+			ctx.ConfigFilePath = fluent.DefaultFilePathFluentConfiguration
 		}
-		process(ctx, config, tracesconfig.Processor, serialization.Processor)
+		process(ctx, config, fluent.Processor, serialization.Processor)
+		return
+	} else if *tracesOnly {
+		processTracesOnly()
 		return
 	}
 
@@ -97,6 +119,37 @@ func init() {
 	processors.StartProcessor = basicInfo.Processor
 }
 
+func processTracesOnly() {
+	ctx := new(runtime.Context)
+	config := new(data.Config)
+	ctx.TracesOnly = true
+	ctx.ConfigOutputPath = *configOutputPath
+	if *isNonInteractiveXrayMigration {
+		ctx.NonInteractiveXrayMigration = true
+	}
+	process(ctx, config, tracesconfig.Processor, serialization.Processor)
+}
+
+// runWithAnswerFile runs the wizard with the answers of the file instead of
+// the ones typed by the user. It fails on the first question the file does
+// not answer, and if the file answers questions that were not asked.
+func runWithAnswerFile(path string, run func()) error {
+	answers, err := util.LoadAnswerFile(path)
+	if err != nil {
+		return err
+	}
+	util.UseAnswerFile(answers)
+	defer util.UseAnswerFile(nil)
+	run()
+	if err = answers.Err(); err != nil {
+		return err
+	}
+	if unused := answers.Unused(); len(unused) > 0 {
+		return fmt.Errorf("the answer file answers questions that were not asked: %q", unused)
+	}
+	return nil
+}
+
 func addWindowsMigrationInputs(configFilePath string, parameterStoreName string, parameterStoreRegion string, useParameterStore bool) {
 	inputChan := testutil.SetUpTestInputStream()
 	if useParameterStore {
@@ -108,6 +161,9 @@ func addWindowsMigrationInputs(configFilePath string, parameterStoreName string,
 
 func process(ctx *runtime.Context, config *data.Config, processors ...processors.Processor) {
 	for _, processor := range processors {
+		if util.AnswerFileErr() != nil {
+			return
+		}
 		processor.Process(ctx, config)
 	}
 }
@@ -125,6 +181,10 @@ func startProcessing() {
 		ctx.NonInteractiveXrayMigration = true
 	}
 	for {
+		if util.AnswerFileErr() != nil {
+			// The answers that follow the one that failed are not used.
+			return
+		}
 		if processor == nil {
 			if util.CurOS() == util.OsTypeWindows && !*isNonInteractiveWindowsMigration {
 				util.EnterToExit()
@@ -4,11 +4,14 @@
 package main
 
 import (
+	"encoding/json"
+	"os"
 	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 
 	"github.com/aws/amazon-cloudwatch-agent/tool/processors"
 	"github.com/aws/amazon-cloudwatch-agent/tool/processors/agentconfig"
@@ -76,3 +79,44 @@ func TestWindowsMigration(t *testing.T) {
 		t.Errorf("The generated new config is incorrect, got:\n '%v'\n, want:\n '%v'.\n", actualConfig, expectedConfig)
 	}
 }
+
+func TestAnswerFile(t *testing.T) {
+	MainProcessorGlobal = &MainProcessorStruct{}
+	processors.StartProcessor = basicInfo.Processor
+	isNonInteractiveWindowsMigration = new(bool)
+	isNonInteractiveXrayMigration = new(bool)
+	outputPath := filepath.Join(t.TempDir(), "config.json")
+	configOutputPath = &outputPath
+
+	require.NoError(t, runWithAnswerFile("testdata/answers.yaml", startProcessing))
+	content, err := os.ReadFile(outputPath)
+	require.NoError(t, err)
+	var config map[string]interface{}
+	require.NoError(t, json.Unmarshal(content, &config))
+	assert.Equal(t, "cwagent", config["agent"].(map[string]interface{})["run_as_user"])
+	collectList := config["logs"].(map[string]interface{})["logs_collected"].(map[string]interface{})["files"].(map[string]interface{})["collect_list"].([]interface{})
+	assert.Len(t, collectList, 2)
+	assert.Equal(t, "/var/log/secure", collectList[1].(map[string]interface{})["file_path"])
+	assert.Equal(t, "INFREQUENT_ACCESS", collectList[1].(map[string]interface{})["log_group_class"])
+	assert.Contains(t, config, "metrics")
+	assert.NotContains(t, config, "traces")
+}
+
+func TestAnswerFileUnansweredQuestion(t *testing.T) {
+	MainProcessorGlobal = &MainProcessorStruct{}
+	processors.StartProcessor = basicInfo.Processor
+	isNonInteractiveWindowsMigration = new(bool)
+	isNonInteractiveXrayMigration = new(bool)
+	outputPath := filepath.Join(t.TempDir(), "config.json")
+	configOutputPath = &outputPath
+	answerPath := filepath.Join(t.TempDir(), "answers.json")
+
+	require.NoError(t, os.WriteFile(answerPath, []byte(`{"os": "linux"}`), 0644))
+	err := runWithAnswerFile(answerPath, startProcessing)
+	assert.EqualError(t, err, `the answer file does not answer host ("Are you using EC2 or On-Premises hosts?")`)
+	assert.NoFileExists(t, outputPath)
+
+	require.NoError(t, os.WriteFile(answerPath, []byte(`{"metrics": "yes"}`), 0644))
+	err = runWithAnswerFile(answerPath, func() {})
+	assert.EqualError(t, err, `the answer file answers questions that were not asked: ["metrics"]`)
+}
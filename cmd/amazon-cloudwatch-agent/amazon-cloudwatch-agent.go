@@ -24,6 +24,7 @@ import (
 	"github.com/influxdata/telegraf/agent"
 	"github.com/influxdata/telegraf/config"
 	"github.com/influxdata/telegraf/logger"
+	"github.com/influxdata/telegraf/models"
 	"github.com/influxdata/telegraf/plugins/inputs"
 	"github.com/influxdata/telegraf/plugins/outputs"
 	"github.com/influxdata/wlog"
@@ -36,8 +37,11 @@ import (
 	"github.com/aws/amazon-cloudwatch-agent/cfg/envconfig"
 	"github.com/aws/amazon-cloudwatch-agent/cmd/amazon-cloudwatch-agent/internal"
 	"github.com/aws/amazon-cloudwatch-agent/extension/agenthealth/handler/useragent"
+	"github.com/aws/amazon-cloudwatch-agent/internal/dryrun"
 	"github.com/aws/amazon-cloudwatch-agent/internal/mapstructure"
 	"github.com/aws/amazon-cloudwatch-agent/internal/merge/confmap"
+	"github.com/aws/amazon-cloudwatch-agent/internal/reload"
+	"github.com/aws/amazon-cloudwatch-agent/internal/selftelemetry"
 	"github.com/aws/amazon-cloudwatch-agent/internal/version"
 	cwaLogger "github.com/aws/amazon-cloudwatch-agent/logger"
 	"github.com/aws/amazon-cloudwatch-agent/logs"
@@ -59,10 +63,14 @@ var fDebug = flag.Bool("debug", false,
 	"turn on debug logging")
 var pprofAddr = flag.String("pprof-addr", "",
 	"pprof address to listen on, disabled by default, examples: 'localhost:1234', ':4567' (restricted to localhost)")
+var fSelfTelemetryAddr = flag.String("self-telemetry-addr", "",
+	"address to serve the agent's own metrics on in the Prometheus text format, disabled by default, overrides "+envconfig.CWAgentSelfTelemetryAddress+", examples: '127.0.0.1:9102', ':9102' (localhost only)")
 var fQuiet = flag.Bool("quiet", false,
 	"run in quiet mode")
 var fTest = flag.Bool("test", false, "enable test mode: gather metrics, print them out, and exit")
 var fTestWait = flag.Int("test-wait", 0, "wait up to this many seconds for service inputs to complete in test mode")
+var fDryRun = flag.Bool("dry-run", false, "enable dry run mode: run the pipelines, print the requests to CloudWatch and CloudWatch Logs instead of sending them, and exit")
+var fDryRunIntervals = flag.Int("dry-run-intervals", 2, "the number of collection intervals to run for in dry run mode")
 var fSchemaTest = flag.Bool("schematest", false, "validate the toml file schema")
 var fTomlConfig = flag.String("config", "", "configuration file to load")
 var fOtelConfigs configprovider.OtelConfigFlags
@@ -111,20 +119,29 @@ func reloadLoop(
 
 		ctx, cancel := context.WithCancel(context.Background())
 
-		signals := make(chan os.Signal)
+		signals := make(chan os.Signal, 1)
 		signal.Notify(signals, os.Interrupt, syscall.SIGHUP,
 			syscall.SIGTERM, syscall.SIGINT)
 		go func() {
-			select {
-			case sig := <-signals:
-				if sig == syscall.SIGHUP {
-					log.Println("I! Reloading Telegraf config")
-					<-reload
-					reload <- true
+			for {
+				select {
+				case sig := <-signals:
+					if sig == syscall.SIGHUP {
+						if collectorRunning.Load() {
+							// The collector reloads the config without
+							// restarting the agent, see reloader.
+							continue
+						}
+						log.Println("I! Reloading Telegraf config")
+						<-reload
+						reload <- true
+					}
+					cancel()
+				case <-stop:
+					cancel()
+				case <-ctx.Done():
 				}
-				cancel()
-			case <-stop:
-				cancel()
+				return
 			}
 		}()
 
@@ -178,6 +195,13 @@ func reloadLoop(
 		}
 
 		err := runAgent(ctx, inputFilters, outputFilters)
+		signal.Stop(signals)
+		cancel()
+		if errors.Is(err, errRestart) {
+			<-reload
+			reload <- true
+			continue
+		}
 		if err != nil && err != context.Canceled {
 			if *fStartUpErrorFile != "" {
 				f, err := os.OpenFile(*fStartUpErrorFile, os.O_CREATE|os.O_WRONLY, 0644)
@@ -272,6 +296,10 @@ func runAgent(ctx context.Context,
 		RotationMaxArchives: ag.Config.Agent.LogfileRotationMaxArchives,
 		LogWithTimezone:     "",
 	}
+	if *fDryRun {
+		// Print the logs next to the captured requests.
+		logConfig.Logfile = ""
+	}
 
 	writer := logger.NewLogWriter(logConfig)
 
@@ -292,7 +320,24 @@ func runAgent(ctx context.Context,
 		testWaitDuration := time.Duration(*fTestWait) * time.Second
 		return ag.Test(ctx, testWaitDuration)
 	}
-	if *fPidfile != "" {
+	if *fDryRun {
+		dryrun.Enable(os.Stdout)
+		stateFolders, err := redirectStateFolders(c)
+		defer func() {
+			for _, stateFolder := range stateFolders {
+				_ = os.RemoveAll(stateFolder)
+			}
+		}()
+		if err != nil {
+			return err
+		}
+		duration := dryRunDuration(c, *fDryRunIntervals)
+		log.Printf("I! Starting a dry run for %v, requests to CloudWatch and CloudWatch Logs are printed instead of sent\n", duration)
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, duration)
+		defer cancel()
+	}
+	if *fPidfile != "" && !*fDryRun {
 		f, err := os.OpenFile(*fPidfile, os.O_CREATE|os.O_WRONLY, 0644)
 		if err != nil {
 			log.Printf("E! Unable to create pidfile: %s", err)
@@ -313,13 +358,28 @@ func runAgent(ctx context.Context,
 		log.Println("I! Running in ROSA")
 	}
 
+	selfTelemetryAddr := *fSelfTelemetryAddr
+	if selfTelemetryAddr == "" {
+		selfTelemetryAddr = os.Getenv(envconfig.CWAgentSelfTelemetryAddress)
+	}
+	if selfTelemetryAddr != "" {
+		// Stopped before returning so the address can be reused on reload.
+		server, err := selftelemetry.Start(selfTelemetryAddr)
+		if err != nil {
+			log.Printf("E! Unable to start self telemetry server on %s: %v", selfTelemetryAddr, err)
+		} else {
+			defer server.Shutdown()
+		}
+	}
+
 	if envconfig.IsSelinuxEnabled() {
 		log.Println("I! SELinux Status: Enabled")
 	}
 
+	var logAgent *logs.LogAgent
 	if len(c.Inputs) != 0 && len(c.Outputs) != 0 {
 		log.Println("creating new logs agent")
-		logAgent := logs.NewLogAgent(c)
+		logAgent = logs.NewLogAgent(c)
 		// Always run logAgent as goroutine regardless of whether starting OTEL or Telegraf.
 		go logAgent.Run(ctx)
 
@@ -353,6 +413,9 @@ func runAgent(ctx context.Context,
 	}
 
 	providerSettings := configprovider.GetSettings(otelConfigs, logger)
+	if *fDryRun {
+		providerSettings.ResolverSettings.ConverterFactories = append(providerSettings.ResolverSettings.ConverterFactories, configprovider.NewDryRunConverterFactory())
+	}
 	provider, err := otelcol.NewConfigProvider(providerSettings)
 	if err != nil {
 		return fmt.Errorf("error while initializing config provider: %v", err)
@@ -379,20 +442,30 @@ func runAgent(ctx context.Context,
 	useragent.Get().SetComponents(cfg, c)
 
 	params := getCollectorParams(factories, providerSettings, loggerOptions)
-	cmd := otelcol.NewCommand(params)
-	// *************************************************************************************************
-	// ⚠️ WARNING ⚠️
-	// Noticed that args of parent process get passed here to otel collector which causes failures
-	// complaining about unrecognized args. So below change overwrites the args.
-	// The config path below here is actually used that was set in the settings above.
-	// docs: https://github.com/open-telemetry/opentelemetry-collector/blob/93cbae436ae61b832279dbbb18a0d99214b7d305/otelcol/command.go#L63
-	// *************************************************************************************************
-	var e []string
-	for _, uri := range otelConfigs {
-		e = append(e, "--config="+uri)
-	}
-	cmd.SetArgs(e)
-	return cmd.Execute()
+	if *fDryRun {
+		return runDryRun(ctx, params, c)
+	}
+	reloader, err := newReloader(ctx, c, logAgent, envConfigPath, factories, providerSettings)
+	if err != nil {
+		return err
+	}
+	params.Factories = reloader.Factories
+	col, err := otelcol.NewCollector(params)
+	if err != nil {
+		return err
+	}
+	reloader.collector = col
+	reload.Enable()
+	collectorRunning.Store(true)
+	err = col.Run(ctx)
+	collectorRunning.Store(false)
+	reload.Disable()
+	if errors.Is(err, errRestart) {
+		log.Printf("I! Restarting the agent: %v", err)
+		reloader.stop()
+		return errRestart
+	}
+	return err
 }
 
 func getCollectorParams(factories otelcol.Factories, providerSettings otelcol.ConfigProviderSettings, loggingOptions []zap.Option) otelcol.CollectorSettings {
@@ -442,8 +515,8 @@ func mergeConfigs(configPaths []string) (*confmap.Conf, error) {
 	return nil, nil
 }
 
-func components(telegrafConfig *config.Config) (otelcol.Factories, error) {
-	telegrafAdapter := adapter.NewAdapter(telegrafConfig)
+func components(telegrafConfig *config.Config, initialized ...*models.RunningInput) (otelcol.Factories, error) {
+	telegrafAdapter := adapter.NewAdapter(telegrafConfig, initialized...)
 
 	factories, err := defaultcomponents.Factories()
 	if err != nil {
@@ -551,6 +624,11 @@ func main() {
 				processorFilters,
 			)
 			return
+		case "dead-letter":
+			if err := runDeadLetter(args[1:]); err != nil {
+				log.Fatalf("E! %v", err)
+			}
+			return
 		}
 	}
 
@@ -0,0 +1,117 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"time"
+
+	"github.com/influxdata/telegraf/config"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal/deadletter"
+	"github.com/aws/amazon-cloudwatch-agent/plugins/outputs/cloudwatchlogs"
+)
+
+const (
+	deadLetterUsage = `Usage: amazon-cloudwatch-agent -config <toml> dead-letter <list|replay> [options]
+
+  list    print the log events that could not be published
+  replay  publish the log events again and remove the records that were published
+`
+	defaultReplayTimeout = 5 * time.Minute
+)
+
+// runDeadLetter handles the dead-letter subcommand.
+func runDeadLetter(args []string) error {
+	if len(args) == 0 {
+		return errors.New(deadLetterUsage)
+	}
+	fs := flag.NewFlagSet("dead-letter "+args[0], flag.ExitOnError)
+	dir := fs.String("dir", "", "dead letter directory, defaults to the dead_letter_dir of the cloudwatchlogs output in the config")
+	timeout := fs.Duration("timeout", defaultReplayTimeout, "how long to wait for the replayed log events to be published")
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+	switch args[0] {
+	case "list":
+		if *dir == "" {
+			output, err := loadCloudWatchLogsOutput()
+			if err != nil {
+				return err
+			}
+			*dir = output.DeadLetterDir
+		}
+		return listDeadLetters(os.Stdout, *dir)
+	case "replay":
+		output, err := loadCloudWatchLogsOutput()
+		if err != nil {
+			return err
+		}
+		if *dir == "" {
+			*dir = output.DeadLetterDir
+		}
+		if *dir == "" {
+			return errors.New("no dead letter directory specified")
+		}
+		result, err := output.ReplayDeadLetters(*dir, *timeout)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Replayed %d records, %d records left in %s\n", result.Replayed, result.Kept, *dir)
+		return nil
+	default:
+		return errors.New(deadLetterUsage)
+	}
+}
+
+func listDeadLetters(w io.Writer, dir string) error {
+	if dir == "" {
+		return errors.New("no dead letter directory specified")
+	}
+	paths, err := deadletter.List(dir)
+	if err != nil {
+		return err
+	}
+	for _, path := range paths {
+		record, err := deadletter.Read(path)
+		if err != nil {
+			log.Printf("W! %v", err)
+			continue
+		}
+		fmt.Fprintf(w, "%s\n  time: %s\n  target: %s/%s\n  reason: %s\n  events: %d\n",
+			path, record.Time.Format(time.RFC3339), record.Group, record.Stream, record.Reason, len(record.Events))
+		if record.Error != "" {
+			fmt.Fprintf(w, "  error: %s\n", record.Error)
+		}
+	}
+	return nil
+}
+
+// loadCloudWatchLogsOutput returns the cloudwatchlogs output of the TOML config,
+// which has the credentials and endpoint to replay the log events with.
+func loadCloudWatchLogsOutput() (*cloudwatchlogs.CloudWatchLogs, error) {
+	envConfigPath, err := getEnvConfigPath(*fTomlConfig, *fEnvConfig)
+	if err != nil {
+		return nil, err
+	}
+	if err = loadEnvironmentVariables(envConfigPath); err != nil {
+		log.Printf("W! Failed to load environment variables due to %s\n", err.Error())
+	}
+	c := config.NewConfig()
+	c.AllowUnusedFields = true
+	if err = loadTomlConfigIntoAgent(c); err != nil {
+		return nil, err
+	}
+	for _, output := range c.Outputs {
+		if cwl, ok := output.Output.(*cloudwatchlogs.CloudWatchLogs); ok {
+			return cwl, nil
+		}
+	}
+	return nil, fmt.Errorf("no cloudwatchlogs output in %s", *fTomlConfig)
+}
@@ -0,0 +1,136 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+	"time"
+
+	"github.com/influxdata/telegraf/config"
+	"go.opentelemetry.io/collector/otelcol"
+
+	"github.com/aws/amazon-cloudwatch-agent/logs"
+)
+
+// stateFolderTag is the TOML key of the folder where the log inputs save how
+// far they have read.
+const stateFolderTag = "file_state_folder"
+
+// dryRunDuration is how long the dry run collects for.
+func dryRunDuration(c *config.Config, intervals int) time.Duration {
+	if intervals < 1 {
+		intervals = 1
+	}
+	return time.Duration(intervals) * time.Duration(c.Agent.Interval)
+}
+
+// runDryRun runs the collector until the dry run is over. The log backends
+// are then closed, so the log events that are still buffered are printed.
+func runDryRun(ctx context.Context, params otelcol.CollectorSettings, c *config.Config) error {
+	col, err := otelcol.NewCollector(params)
+	if err != nil {
+		return err
+	}
+	err = col.Run(ctx)
+	closeLogBackends(c)
+	if errors.Is(err, context.DeadlineExceeded) {
+		return nil
+	}
+	return err
+}
+
+func closeLogBackends(c *config.Config) {
+	for _, output := range c.Outputs {
+		if _, ok := output.Output.(logs.LogBackend); !ok {
+			continue
+		}
+		if err := output.Output.Close(); err != nil {
+			log.Printf("E! Failed to close %s: %v", output.Config.Name, err)
+		}
+	}
+}
+
+// redirectStateFolders points the log inputs to copies of their state
+// folders, so the dry run starts reading where the agent left off without
+// moving the offsets of the agent. It returns the copies to remove once the
+// dry run is over.
+func redirectStateFolders(c *config.Config) ([]string, error) {
+	copies := map[string]string{}
+	var dirs []string
+	for _, input := range c.Inputs {
+		v := reflect.ValueOf(input.Input)
+		if v.Kind() != reflect.Pointer || v.Elem().Kind() != reflect.Struct {
+			continue
+		}
+		v = v.Elem()
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			value := v.Field(i)
+			if field.Tag.Get("toml") != stateFolderTag || value.Kind() != reflect.String || !value.CanSet() || value.String() == "" {
+				continue
+			}
+			folder := value.String()
+			dryRunFolder, ok := copies[folder]
+			if !ok {
+				var err error
+				if dryRunFolder, err = copyStateFolder(folder); err != nil {
+					return dirs, err
+				}
+				copies[folder] = dryRunFolder
+				dirs = append(dirs, dryRunFolder)
+			}
+			value.SetString(dryRunFolder)
+		}
+	}
+	return dirs, nil
+}
+
+// copyStateFolder copies the state files of the folder, if it exists, to a
+// new temporary folder.
+func copyStateFolder(folder string) (string, error) {
+	dryRunFolder, err := os.MkdirTemp("", "cwagent-dry-run-state-")
+	if err != nil {
+		return "", err
+	}
+	entries, err := os.ReadDir(folder)
+	if errors.Is(err, os.ErrNotExist) {
+		return dryRunFolder, nil
+	}
+	for _, entry := range entries {
+		if err != nil {
+			break
+		}
+		if entry.Type().IsRegular() {
+			err = copyFile(filepath.Join(folder, entry.Name()), filepath.Join(dryRunFolder, entry.Name()))
+		}
+	}
+	if err != nil {
+		_ = os.RemoveAll(dryRunFolder)
+		return "", err
+	}
+	return dryRunFolder, nil
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err = io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,58 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/influxdata/telegraf/config"
+	"github.com/influxdata/telegraf/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aws/amazon-cloudwatch-agent/plugins/inputs/logfile"
+)
+
+func TestRedirectStateFolders(t *testing.T) {
+	stateFolder := t.TempDir()
+	stateFile := filepath.Join(stateFolder, "_var_log_messages")
+	require.NoError(t, os.WriteFile(stateFile, []byte("1024\n/var/log/messages"), 0644))
+
+	first := &logfile.LogFile{FileStateFolder: stateFolder}
+	second := &logfile.LogFile{FileStateFolder: stateFolder}
+	missing := &logfile.LogFile{FileStateFolder: filepath.Join(stateFolder, "missing")}
+	c := config.NewConfig()
+	c.Inputs = []*models.RunningInput{{Input: first}, {Input: second}, {Input: missing}}
+
+	dirs, err := redirectStateFolders(c)
+	require.NoError(t, err)
+	defer func() {
+		for _, dir := range dirs {
+			os.RemoveAll(dir)
+		}
+	}()
+	assert.Len(t, dirs, 2)
+	assert.NotEqual(t, stateFolder, first.FileStateFolder)
+	assert.Equal(t, first.FileStateFolder, second.FileStateFolder)
+	got, err := os.ReadFile(filepath.Join(first.FileStateFolder, "_var_log_messages"))
+	require.NoError(t, err)
+	assert.Equal(t, "1024\n/var/log/messages", string(got))
+	assert.DirExists(t, missing.FileStateFolder)
+
+	// the state of the agent is not changed by the dry run
+	require.NoError(t, os.WriteFile(filepath.Join(first.FileStateFolder, "_var_log_messages"), []byte("2048\n/var/log/messages"), 0644))
+	got, err = os.ReadFile(stateFile)
+	require.NoError(t, err)
+	assert.Equal(t, "1024\n/var/log/messages", string(got))
+}
+
+func TestDryRunDuration(t *testing.T) {
+	c := config.NewConfig()
+	c.Agent.Interval = config.Duration(time.Minute)
+	assert.Equal(t, 2*time.Minute, dryRunDuration(c, 2))
+	assert.Equal(t, time.Minute, dryRunDuration(c, 0))
+}
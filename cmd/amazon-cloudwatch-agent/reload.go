@@ -0,0 +1,230 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"github.com/influxdata/telegraf/config"
+	"github.com/influxdata/telegraf/models"
+	"go.opentelemetry.io/collector/otelcol"
+
+	"github.com/aws/amazon-cloudwatch-agent/cfg/envconfig"
+	"github.com/aws/amazon-cloudwatch-agent/extension/agenthealth/handler/useragent"
+	"github.com/aws/amazon-cloudwatch-agent/internal/reload"
+	"github.com/aws/amazon-cloudwatch-agent/logs"
+	"github.com/aws/amazon-cloudwatch-agent/translator/tocwconfig/toyamlconfig"
+)
+
+// errRestart is returned by the collector when a reloaded config cannot be
+// applied to the running agent, which is then restarted.
+var errRestart = errors.New("the agent has to be restarted to apply the config")
+
+// collectorRunning is set while the collector runs, which reloads its config
+// itself on SIGHUP.
+var collectorRunning atomic.Bool
+
+// reloader applies a reloaded config to the running agent. The collector
+// restarts its service on SIGHUP and gets its factories again, which is when
+// the TOML config is reloaded. The inputs that are unchanged keep running, and
+// so do the log collections and the outputs.
+type reloader struct {
+	ctx            context.Context
+	inputFilters   []string
+	outputFilters  []string
+	envConfigPath  string
+	telegrafConfig *config.Config
+	snapshot       *reload.Snapshot
+	logAgent       *logs.LogAgent
+	merged         bool
+	providerConfig otelcol.ConfigProviderSettings
+	factories      otelcol.Factories
+	collector      *otelcol.Collector
+	loaded         bool
+}
+
+func newReloader(ctx context.Context, c *config.Config, logAgent *logs.LogAgent, envConfigPath string, factories otelcol.Factories, providerSettings otelcol.ConfigProviderSettings) (*reloader, error) {
+	snapshot, err := reload.LoadSnapshot(*fTomlConfig, *fConfigDirectory, envConfigPath)
+	if err != nil {
+		return nil, err
+	}
+	_, merged := os.LookupEnv(envconfig.CWAgentMergedOtelConfig)
+	return &reloader{
+		ctx:            ctx,
+		inputFilters:   c.InputFilters,
+		outputFilters:  c.OutputFilters,
+		envConfigPath:  envConfigPath,
+		telegrafConfig: c,
+		snapshot:       snapshot,
+		logAgent:       logAgent,
+		merged:         merged,
+		providerConfig: providerSettings,
+		factories:      factories,
+	}, nil
+}
+
+// Factories returns the factories of the running config when the collector
+// starts, and of the reloaded config when it restarts its service.
+func (r *reloader) Factories() (otelcol.Factories, error) {
+	if !r.loaded {
+		r.loaded = true
+		return r.factories, nil
+	}
+	log.Println("I! Reloading the agent config")
+	factories, err := r.reload()
+	if err != nil {
+		return factories, err
+	}
+	go r.releaseOnceRunning()
+	return factories, nil
+}
+
+func (r *reloader) reload() (otelcol.Factories, error) {
+	if err := loadEnvironmentVariables(r.envConfigPath); err != nil {
+		log.Printf("W! Failed to load environment variables due to %s\n", err.Error())
+	}
+	snapshot, err := reload.LoadSnapshot(*fTomlConfig, *fConfigDirectory, r.envConfigPath)
+	if err != nil {
+		return otelcol.Factories{}, err
+	}
+	plan := reload.NewPlan(r.snapshot, snapshot)
+	log.Printf("I! Reloaded the agent config: %v", plan)
+	if plan.Restart != "" {
+		return otelcol.Factories{}, fmt.Errorf("%w: %s", errRestart, plan.Restart)
+	}
+
+	c := config.NewConfig()
+	c.InputFilters = r.inputFilters
+	c.OutputFilters = r.outputFilters
+	c.AllowUnusedFields = true
+	if err = loadTomlConfigIntoAgent(c); err != nil {
+		return otelcol.Factories{}, err
+	}
+	if err = validateAgentFinalConfigAndPlugins(c); err != nil {
+		return otelcol.Factories{}, err
+	}
+
+	// The outputs are unchanged, so they keep running, and so do the inputs
+	// that are unchanged.
+	c.Outputs = r.telegrafConfig.Outputs
+	running := inputsByID(r.telegrafConfig.Inputs)
+	kept := inputsOf(running, plan.Kept)
+	for i, id := range reload.IDs(inputPlugins(c.Inputs)) {
+		if input, ok := kept[id]; ok {
+			c.Inputs[i] = input
+		}
+	}
+	started := inputsOf(inputsByID(c.Inputs), plan.Started)
+	var collections bool
+	for _, input := range started {
+		_, ok := input.Input.(logs.LogCollection)
+		collections = collections || ok
+	}
+	if collections && r.logAgent == nil {
+		return otelcol.Factories{}, fmt.Errorf("%w: the logs agent is not running", errRestart)
+	}
+
+	merged, err := mergeConfigs(fOtelConfigs)
+	if err != nil {
+		return otelcol.Factories{}, err
+	}
+	if (merged != nil) != r.merged {
+		return otelcol.Factories{}, fmt.Errorf("%w: the OTEL configs to merge changed", errRestart)
+	}
+	if merged != nil {
+		_ = os.Setenv(envconfig.CWAgentMergedOtelConfig, toyamlconfig.ToYamlConfig(merged.ToStringMap()))
+	}
+
+	initialized := make([]*models.RunningInput, 0, len(kept))
+	for _, input := range kept {
+		initialized = append(initialized, input)
+	}
+	factories, err := components(c, initialized...)
+	if err != nil {
+		return factories, fmt.Errorf("error while adapting telegraf input plugins: %v", err)
+	}
+	provider, err := otelcol.NewConfigProvider(r.providerConfig)
+	if err != nil {
+		return factories, fmt.Errorf("error while initializing config provider: %v", err)
+	}
+	cfg, err := provider.Get(r.ctx, factories)
+	if err != nil {
+		return factories, err
+	}
+	useragent.Get().SetComponents(cfg, c)
+
+	for _, input := range inputsOf(running, plan.Stopped) {
+		if collection, ok := input.Input.(logs.LogCollection); ok {
+			r.logAgent.RemoveCollection(collection)
+		}
+	}
+	for _, input := range started {
+		if collection, ok := input.Input.(logs.LogCollection); ok {
+			if err = r.logAgent.AddCollection(collection); err != nil {
+				log.Printf("E! could not start log collection %v err %v", input.Config.Name, err)
+			}
+		}
+	}
+	r.telegrafConfig = c
+	r.snapshot = snapshot
+	return factories, nil
+}
+
+// releaseOnceRunning releases the components that were kept running while
+// the service restarted, but are not used by the restarted service.
+func (r *reloader) releaseOnceRunning() {
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+	for range ticker.C {
+		if r.collector == nil || r.collector.GetState() != otelcol.StateStarting {
+			break
+		}
+	}
+	reload.Release()
+}
+
+// stop stops the log collections and closes the log backends of the running
+// config, which are not stopped by the collector, before the agent restarts.
+func (r *reloader) stop() {
+	if r.logAgent != nil {
+		for _, input := range r.telegrafConfig.Inputs {
+			if collection, ok := input.Input.(logs.LogCollection); ok {
+				r.logAgent.RemoveCollection(collection)
+			}
+		}
+	}
+	closeLogBackends(r.telegrafConfig)
+}
+
+func inputPlugins(inputs []*models.RunningInput) []reload.Plugin {
+	plugins := make([]reload.Plugin, len(inputs))
+	for i, input := range inputs {
+		plugins[i] = reload.Plugin{Name: input.Config.Name, Alias: input.Config.Alias}
+	}
+	return plugins
+}
+
+func inputsByID(inputs []*models.RunningInput) map[string]*models.RunningInput {
+	byID := map[string]*models.RunningInput{}
+	for i, id := range reload.IDs(inputPlugins(inputs)) {
+		byID[id] = inputs[i]
+	}
+	return byID
+}
+
+func inputsOf(inputs map[string]*models.RunningInput, ids []string) map[string]*models.RunningInput {
+	result := map[string]*models.RunningInput{}
+	for _, id := range ids {
+		if input, ok := inputs[id]; ok {
+			result[id] = input
+		}
+	}
+	return result
+}
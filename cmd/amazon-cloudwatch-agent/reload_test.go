@@ -0,0 +1,27 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"testing"
+
+	"github.com/influxdata/telegraf/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInputsByID(t *testing.T) {
+	cpu := &models.RunningInput{Config: &models.InputConfig{Name: "cpu"}}
+	nginx := &models.RunningInput{Config: &models.InputConfig{Name: "procstat", Alias: "nginx"}}
+	first := &models.RunningInput{Config: &models.InputConfig{Name: "logfile"}}
+	second := &models.RunningInput{Config: &models.InputConfig{Name: "logfile"}}
+
+	byID := inputsByID([]*models.RunningInput{cpu, nginx, first, second})
+	assert.Equal(t, map[string]*models.RunningInput{
+		"cpu":            cpu,
+		"procstat/nginx": nginx,
+		"logfile":        first,
+		"logfile#2":      second,
+	}, byID)
+	assert.Equal(t, map[string]*models.RunningInput{"logfile#2": second}, inputsOf(byID, []string{"logfile#2", "mem"}))
+}
@@ -4,12 +4,16 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"log"
 	"os"
+	"os/signal"
 	"path/filepath"
 	"strings"
+	"syscall"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/credentials"
@@ -28,6 +32,9 @@ const (
 	locationDefault = "default"
 	locationSSM     = "ssm"
 	locationFile    = "file"
+	locationHTTP    = "http"
+	locationHTTPS   = "https"
+	locationS3      = "s3"
 
 	locationSeparator = ":"
 
@@ -38,10 +45,7 @@ func defaultJsonConfig(mode string) (string, error) {
 	return config.DefaultJsonConfig(config.ToValidOs(""), mode), nil
 }
 
-func downloadFromSSM(region, parameterStoreName, mode string, credsConfig map[string]string) (string, error) {
-	fmt.Printf("Region: %v\n", region)
-	fmt.Printf("credsConfig: %v\n", credsConfig)
-	var ses *session.Session
+func newSession(region, mode string, credsConfig map[string]string) (*session.Session, error) {
 	credsMap := util.GetCredentials(mode, credsConfig)
 	profile, profileOk := credsMap[commonconfig.CredentialProfile]
 	sharedConfigFile, sharedConfigFileOk := credsMap[commonconfig.CredentialFile]
@@ -60,6 +64,16 @@ func downloadFromSSM(region, parameterStoreName, mode string, credsConfig map[st
 	ses, err := session.NewSession(rootconfig)
 	if err != nil {
 		fmt.Printf("Error in creating session: %v\n", err)
+		return nil, err
+	}
+	return ses, nil
+}
+
+func downloadFromSSM(region, parameterStoreName, mode string, credsConfig map[string]string) (string, error) {
+	fmt.Printf("Region: %v\n", region)
+	fmt.Printf("credsConfig: %v\n", credsConfig)
+	ses, err := newSession(region, mode, credsConfig)
+	if err != nil {
 		return "", err
 	}
 
@@ -82,6 +96,10 @@ func readFromFile(filePath string) (string, error) {
 	return string(bytes), err
 }
 
+func isHTTPLocation(location string) bool {
+	return strings.HasPrefix(location, locationHTTP+locationSeparator) || strings.HasPrefix(location, locationHTTPS+locationSeparator)
+}
+
 func EscapeFilePath(filePath string) (escapedFilePath string) {
 	escapedFilePath = filepath.ToSlash(filePath)
 	escapedFilePath = strings.Replace(escapedFilePath, "/", "_", -1)
@@ -107,14 +125,20 @@ func main() {
 		}
 	}()
 
-	var region, mode, downloadLocation, outputDir, inputConfig, multiConfig string
+	var region, mode, downloadLocation, outputDir, inputConfig, multiConfig, endpointOverride, pidfile string
+	var watchInterval time.Duration
 
 	flag.StringVar(&mode, "mode", "ec2", "Please provide the mode, i.e. ec2, onPremise, onPrem, auto")
 	flag.StringVar(&downloadLocation, "download-source", "",
-		"Download source. Example: \"ssm:my-parameter-store-name\" for an EC2 SSM Parameter Store Name holding your CloudWatch Agent configuration.")
+		"Download source. Example: \"ssm:my-parameter-store-name\" for an EC2 SSM Parameter Store Name holding your CloudWatch Agent configuration. "+
+			"\"https://host/path\" and \"s3://bucket/key\" download the configuration from a URL or an S3 object.")
 	flag.StringVar(&outputDir, "output-dir", "", "Path of output json config directory.")
 	flag.StringVar(&inputConfig, "config", "", "Please provide the common-config file")
 	flag.StringVar(&multiConfig, "multi-config", "default", "valid values: default, append, remove")
+	flag.StringVar(&endpointOverride, "endpoint-override", "", "S3 endpoint to download s3:// sources from, e.g. an S3-compatible server")
+	flag.DurationVar(&watchInterval, "watch-interval", 0,
+		"Poll the download source at this interval, and apply the configuration to the running agent when it changes. Disabled by default.")
+	flag.StringVar(&pidfile, "pidfile", defaultPidfile(), "pidfile of the agent that is signaled to reload the configuration in watch mode")
 	flag.Parse()
 
 	cc := commonconfig.New()
@@ -145,7 +169,7 @@ func main() {
 
 	region, _ = util.DetectRegion(mode, cc.CredentialsMap())
 
-	if region == "" && downloadLocation != locationDefault {
+	if region == "" && downloadLocation != locationDefault && !isHTTPLocation(downloadLocation) {
 		fmt.Println("Unable to determine aws-region.")
 		if mode == config.ModeEC2 {
 			errorMessage = "E! Please check if you can access the metadata service. For example, on linux, run 'wget -q -O - http://169.254.169.254/latest/meta-data/instance-id && echo' "
@@ -185,27 +209,58 @@ func main() {
 	}
 
 	var config, outputFilePath string
+	var src source
 	var err error
 	switch locationArray[0] {
 	case locationDefault:
 		outputFilePath = locationDefault
-		if multiConfig != "remove" {
-			config, err = defaultJsonConfig(mode)
-		}
+		src = funcSource(func() (string, error) { return defaultJsonConfig(mode) })
 	case locationSSM:
 		outputFilePath = locationSSM + "_" + EscapeFilePath(locationArray[1])
-		if multiConfig != "remove" {
-			config, err = downloadFromSSM(region, locationArray[1], mode, cc.CredentialsMap())
-		}
+		src = funcSource(func() (string, error) {
+			return downloadFromSSM(region, locationArray[1], mode, cc.CredentialsMap())
+		})
 	case locationFile:
 		outputFilePath = locationFile + "_" + EscapeFilePath(filepath.Base(locationArray[1]))
+		src = funcSource(func() (string, error) { return readFromFile(locationArray[1]) })
+	case locationHTTP, locationHTTPS:
+		outputFilePath = locationArray[0] + "_" + EscapeFilePath(strings.TrimPrefix(locationArray[1], "//"))
+		if multiConfig != "remove" {
+			src, err = newHTTPSource(downloadLocation, cc.SSLMap()[commonconfig.CABundlePath])
+		}
+	case locationS3:
+		outputFilePath = locationS3 + "_" + EscapeFilePath(strings.TrimPrefix(locationArray[1], "//"))
 		if multiConfig != "remove" {
-			config, err = readFromFile(locationArray[1])
+			var ses *session.Session
+			if ses, err = newSession(region, mode, cc.CredentialsMap()); err == nil {
+				src, err = newS3Source(ses, downloadLocation, endpointOverride)
+			}
 		}
 	default:
 		log.Panicf("E! Location type %s is not supported.", locationArray[0])
 	}
 
+	if err == nil && watchInterval > 0 {
+		if multiConfig == "remove" {
+			log.Panic("E! The remove multi-config cannot be watched.")
+		}
+		ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer cancel()
+		w := &watcher{
+			source:     src,
+			interval:   watchInterval,
+			outputPath: filepath.Join(outputDir, outputFilePath),
+			translate:  translateConfigs(outputDir, mode, inputConfig),
+			reload:     reloadAgent(pidfile),
+		}
+		fmt.Printf("Watching %s every %v\n", downloadLocation, watchInterval)
+		w.run(ctx)
+		return
+	}
+	if err == nil && multiConfig != "remove" {
+		config, err = src.fetch(context.Background())
+	}
+
 	if err != nil {
 		log.Panicf("E! Fail to fetch/remove json config: %v", err)
 	}
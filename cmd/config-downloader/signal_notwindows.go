@@ -0,0 +1,14 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+//go:build !windows
+// +build !windows
+
+package main
+
+import "syscall"
+
+// signalReload sends SIGHUP to the agent, which reloads its config.
+func signalReload(pid int) error {
+	return syscall.Kill(pid, syscall.SIGHUP)
+}
@@ -0,0 +1,15 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+//go:build windows
+// +build windows
+
+package main
+
+import "errors"
+
+// signalReload is not supported as the agent does not reload its config on
+// Windows, where the service has to be restarted instead.
+func signalReload(int) error {
+	return errors.New("the agent cannot reload its config on Windows, restart the agent service to apply it")
+}
@@ -0,0 +1,197 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal/tls"
+)
+
+const (
+	httpTimeout = 30 * time.Second
+	// maxConfigSize is the size of the largest config that is downloaded.
+	maxConfigSize = 10 << 20
+)
+
+// errNotModified is returned by a source when the config has not changed
+// since it was last fetched.
+var errNotModified = errors.New("config not modified")
+
+// A source is a location the config is downloaded from.
+type source interface {
+	// fetch returns the config, or errNotModified if it has not changed
+	// since the last fetch.
+	fetch(ctx context.Context) (string, error)
+	// reset makes the next fetch return the config even if it has not
+	// changed, so a config that failed to apply is tried again.
+	reset()
+}
+
+// funcSource is a source that is fetched in full every time.
+type funcSource func() (string, error)
+
+func (f funcSource) fetch(context.Context) (string, error) {
+	return f()
+}
+
+func (funcSource) reset() {}
+
+// httpSource downloads the config from an HTTP(S) URL. The ETag and
+// Last-Modified headers of the last response are sent back, so a server
+// that supports conditional requests does not send an unchanged config.
+type httpSource struct {
+	url          string
+	client       *http.Client
+	etag         string
+	lastModified string
+}
+
+// newHTTPSource creates a source for the URL, which trusts the certificates
+// of the CA bundle instead of the system ones if there is one.
+func newHTTPSource(rawURL, caBundlePath string) (*httpSource, error) {
+	if _, err := url.Parse(rawURL); err != nil {
+		return nil, err
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if caBundlePath != "" {
+		tlsConfig, err := (&tls.ClientConfig{TLSCA: caBundlePath}).TLSConfig()
+		if err != nil {
+			return nil, err
+		}
+		transport.TLSClientConfig = tlsConfig
+	}
+	return &httpSource{
+		url:    rawURL,
+		client: &http.Client{Transport: transport, Timeout: httpTimeout},
+	}, nil
+}
+
+func (s *httpSource) fetch(ctx context.Context) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url, nil)
+	if err != nil {
+		return "", err
+	}
+	if s.etag != "" {
+		req.Header.Set("If-None-Match", s.etag)
+	}
+	if s.lastModified != "" {
+		req.Header.Set("If-Modified-Since", s.lastModified)
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	switch {
+	case resp.StatusCode == http.StatusNotModified:
+		return "", errNotModified
+	case resp.StatusCode != http.StatusOK:
+		return "", fmt.Errorf("unexpected status %s from %s", resp.Status, s.url)
+	}
+	body, err := readConfig(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	s.etag = resp.Header.Get("ETag")
+	s.lastModified = resp.Header.Get("Last-Modified")
+	return body, nil
+}
+
+func (s *httpSource) reset() {
+	s.etag = ""
+	s.lastModified = ""
+}
+
+// s3Source downloads the config from an S3 object. The ETag of the last
+// download is sent back, so an unchanged object is not downloaded again.
+type s3Source struct {
+	bucket string
+	key    string
+	client s3iface.S3API
+	etag   string
+}
+
+// newS3Source creates a source for the s3://bucket/key location. The endpoint
+// overrides the one of the region, e.g. for an S3-compatible server, in which
+// case the bucket is in the path of the requests.
+func newS3Source(ses *session.Session, location, endpoint string) (*s3Source, error) {
+	bucket, key, err := parseS3Location(location)
+	if err != nil {
+		return nil, err
+	}
+	cfg := aws.NewConfig()
+	if endpoint != "" {
+		cfg = cfg.WithEndpoint(endpoint).WithS3ForcePathStyle(true)
+	}
+	return &s3Source{bucket: bucket, key: key, client: s3.New(ses, cfg)}, nil
+}
+
+// parseS3Location returns the bucket and key of the s3://bucket/key location.
+func parseS3Location(location string) (string, string, error) {
+	path, ok := strings.CutPrefix(location, "s3://")
+	if !ok {
+		return "", "", fmt.Errorf("%s is not an s3://bucket/key location", location)
+	}
+	bucket, key, _ := strings.Cut(path, "/")
+	if bucket == "" || key == "" {
+		return "", "", fmt.Errorf("%s is not an s3://bucket/key location", location)
+	}
+	return bucket, key, nil
+}
+
+func (s *s3Source) fetch(ctx context.Context) (string, error) {
+	input := &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(s.key),
+	}
+	if s.etag != "" {
+		input.IfNoneMatch = aws.String(s.etag)
+	}
+	output, err := s.client.GetObjectWithContext(ctx, input)
+	if err != nil {
+		var reqErr awserr.RequestFailure
+		if errors.As(err, &reqErr) && reqErr.StatusCode() == http.StatusNotModified {
+			return "", errNotModified
+		}
+		return "", err
+	}
+	defer output.Body.Close()
+	body, err := readConfig(output.Body)
+	if err != nil {
+		return "", err
+	}
+	s.etag = aws.StringValue(output.ETag)
+	return body, nil
+}
+
+func (s *s3Source) reset() {
+	s.etag = ""
+}
+
+// readConfig reads the config, which fails rather than truncating a config
+// larger than maxConfigSize.
+func readConfig(r io.Reader) (string, error) {
+	body, err := io.ReadAll(io.LimitReader(r, maxConfigSize+1))
+	if err != nil {
+		return "", err
+	}
+	if len(body) > maxConfigSize {
+		return "", fmt.Errorf("the config is larger than %d bytes", maxConfigSize)
+	}
+	return string(body), nil
+}
@@ -0,0 +1,133 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testConfig = `{"agent":{"metrics_collection_interval":60}}`
+
+// conditionalHandler serves the config at the path, and answers the
+// conditional requests for the ETag it sent.
+func conditionalHandler(t *testing.T, path string, content *string, requests *int) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		*requests++
+		assert.Equal(t, path, r.URL.Path)
+		etag := `"` + *content + `"`
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", etag)
+		_, _ = w.Write([]byte(*content))
+	}
+}
+
+func TestHTTPSource(t *testing.T) {
+	content := testConfig
+	var requests int
+	server := httptest.NewServer(conditionalHandler(t, "/config.json", &content, &requests))
+	defer server.Close()
+
+	src, err := newHTTPSource(server.URL+"/config.json", "")
+	require.NoError(t, err)
+	got, err := src.fetch(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, testConfig, got)
+
+	_, err = src.fetch(context.Background())
+	assert.ErrorIs(t, err, errNotModified)
+
+	content = `{"agent":{"metrics_collection_interval":10}}`
+	got, err = src.fetch(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, content, got)
+	assert.Equal(t, 3, requests)
+}
+
+func TestHTTPSourceError(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	src, err := newHTTPSource(server.URL, "")
+	require.NoError(t, err)
+	_, err = src.fetch(context.Background())
+	assert.ErrorContains(t, err, "404")
+
+	_, err = newHTTPSource(server.URL, "missing-ca-bundle.pem")
+	assert.Error(t, err)
+}
+
+func TestHTTPSourceTooLarge(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(strings.Repeat(" ", maxConfigSize) + testConfig))
+	}))
+	defer server.Close()
+
+	src, err := newHTTPSource(server.URL+"/config.json", "")
+	require.NoError(t, err)
+	_, err = src.fetch(context.Background())
+	assert.ErrorContains(t, err, "larger than")
+}
+
+func TestHTTPSourceReset(t *testing.T) {
+	content := testConfig
+	var requests int
+	server := httptest.NewServer(conditionalHandler(t, "/config.json", &content, &requests))
+	defer server.Close()
+
+	src, err := newHTTPSource(server.URL+"/config.json", "")
+	require.NoError(t, err)
+	_, err = src.fetch(context.Background())
+	require.NoError(t, err)
+	src.reset()
+	got, err := src.fetch(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, testConfig, got)
+}
+
+func TestS3Source(t *testing.T) {
+	content := testConfig
+	var requests int
+	server := httptest.NewServer(conditionalHandler(t, "/bucket/path/config.json", &content, &requests))
+	defer server.Close()
+
+	ses, err := session.NewSession(&aws.Config{
+		Region:      aws.String("us-east-1"),
+		Credentials: credentials.NewStaticCredentials("id", "secret", ""),
+	})
+	require.NoError(t, err)
+	src, err := newS3Source(ses, "s3://bucket/path/config.json", server.URL)
+	require.NoError(t, err)
+	got, err := src.fetch(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, testConfig, got)
+
+	_, err = src.fetch(context.Background())
+	assert.ErrorIs(t, err, errNotModified)
+	assert.Equal(t, 2, requests)
+}
+
+func TestParseS3Location(t *testing.T) {
+	bucket, key, err := parseS3Location("s3://bucket/path/config.json")
+	require.NoError(t, err)
+	assert.Equal(t, "bucket", bucket)
+	assert.Equal(t, "path/config.json", key)
+
+	for _, location := range []string{"s3://bucket", "s3:///key", "https://bucket/key"} {
+		_, _, err = parseS3Location(location)
+		assert.Error(t, err, location)
+	}
+}
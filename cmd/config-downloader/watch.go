@@ -0,0 +1,166 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal/constants"
+	"github.com/aws/amazon-cloudwatch-agent/tool/paths"
+	"github.com/aws/amazon-cloudwatch-agent/translator/cmdutil"
+	"github.com/aws/amazon-cloudwatch-agent/translator/config"
+)
+
+// watcher polls a source and applies the config when it changes. The config
+// is validated against the schema, saved next to the other configs, and
+// translated, before the agent is signaled to reload it. A config that is not
+// valid is not applied, and the agent keeps running with the one it has.
+type watcher struct {
+	source   source
+	interval time.Duration
+	// outputPath is where the applied config is saved.
+	outputPath string
+	// translate translates the configs of the output dir, including the
+	// config saved as a .tmp file next to the applied ones.
+	translate func() error
+	// reload signals the agent to reload its config.
+	reload func() error
+	// applied is the last config that was applied.
+	applied string
+}
+
+// run polls until the context is done.
+func (w *watcher) run(ctx context.Context) {
+	if content, err := os.ReadFile(w.outputPath); err == nil {
+		w.applied = string(content)
+	}
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	for {
+		if err := w.poll(ctx); err != nil {
+			log.Printf("E! Failed to apply the config: %v", err)
+		}
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// poll fetches the config and applies it if it changed.
+func (w *watcher) poll(ctx context.Context) error {
+	content, err := w.source.fetch(ctx)
+	if errors.Is(err, errNotModified) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("unable to fetch the config: %w", err)
+	}
+	if content == w.applied {
+		return nil
+	}
+	if err = w.apply(content); err != nil {
+		// Fetched again, so the config is applied once whatever failed is
+		// fixed, e.g. a translator or agent that was not ready.
+		w.source.reset()
+		return err
+	}
+	w.applied = content
+	return nil
+}
+
+// apply validates, saves and translates the config, and signals the agent to
+// reload it.
+func (w *watcher) apply(content string) error {
+	if err := validateConfig(content); err != nil {
+		return err
+	}
+	tmpPath := w.outputPath + constants.FileSuffixTmp
+	if err := os.WriteFile(tmpPath, []byte(content), 0644); err != nil {
+		return err
+	}
+	if err := w.translate(); err != nil {
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("unable to translate the config: %w", err)
+	}
+	if err := os.Rename(tmpPath, w.outputPath); err != nil {
+		return err
+	}
+	log.Printf("I! Applied the changed config to %s", w.outputPath)
+	if err := w.reload(); err != nil {
+		return fmt.Errorf("unable to reload the agent: %w", err)
+	}
+	return nil
+}
+
+// validateConfig validates the JSON config against the schema of the agent.
+func validateConfig(content string) error {
+	var jsonConfig map[string]interface{}
+	if err := json.Unmarshal([]byte(content), &jsonConfig); err != nil {
+		return fmt.Errorf("the config is not valid JSON: %w", err)
+	}
+	result, err := cmdutil.RunSchemaValidation(jsonConfig)
+	if err != nil {
+		return fmt.Errorf("unable to validate the config: %w", err)
+	}
+	if !result.Valid() {
+		var details []string
+		for _, detail := range result.Errors() {
+			details = append(details, config.GetFormattedPath(detail.Context().String())+": "+detail.Description())
+		}
+		return fmt.Errorf("the config does not match the schema: %s", strings.Join(details, "; "))
+	}
+	return nil
+}
+
+// translateConfigs runs the config translator the way the ctl script does, in
+// the append mode so the configs of the output dir are translated together.
+func translateConfigs(outputDir, mode, commonConfigPath string) func() error {
+	return func() error {
+		args := []string{
+			"--input", paths.JsonConfigPath,
+			"--input-dir", outputDir,
+			"--output", paths.TomlConfigPath,
+			"--mode", mode,
+			"--multi-config", "append",
+		}
+		if commonConfigPath != "" {
+			args = append(args, "--config", commonConfigPath)
+		}
+		output, err := exec.Command(paths.TranslatorBinaryPath, args...).CombinedOutput()
+		if err != nil {
+			return fmt.Errorf("%w: %s", err, output)
+		}
+		return nil
+	}
+}
+
+// reloadAgent signals the agent with the pid of the pidfile.
+func reloadAgent(pidfile string) func() error {
+	return func() error {
+		content, err := os.ReadFile(pidfile)
+		if err != nil {
+			return err
+		}
+		var pid int
+		if _, err = fmt.Sscanf(string(content), "%d", &pid); err != nil {
+			return fmt.Errorf("unable to read the pid in %s: %w", pidfile, err)
+		}
+		return signalReload(pid)
+	}
+}
+
+func defaultPidfile() string {
+	return filepath.Join(paths.AgentDir, "var", "amazon-cloudwatch-agent.pid")
+}
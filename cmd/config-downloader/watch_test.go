@@ -0,0 +1,107 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal/constants"
+)
+
+type testSource struct {
+	content string
+	err     error
+	resets  int
+}
+
+func (s *testSource) fetch(context.Context) (string, error) {
+	return s.content, s.err
+}
+
+func (s *testSource) reset() {
+	s.resets++
+}
+
+func TestWatcherPoll(t *testing.T) {
+	outputPath := filepath.Join(t.TempDir(), "s3_bucket_config.json")
+	src := &testSource{content: testConfig}
+	var translated, reloaded int
+	var translateErr, reloadErr error
+	w := &watcher{
+		source:     src,
+		outputPath: outputPath,
+		translate: func() error {
+			translated++
+			// the translator reads the new config from the .tmp file
+			content, err := os.ReadFile(outputPath + constants.FileSuffixTmp)
+			require.NoError(t, err)
+			assert.Equal(t, src.content, string(content))
+			return translateErr
+		},
+		reload: func() error {
+			reloaded++
+			return reloadErr
+		},
+	}
+
+	require.NoError(t, w.poll(context.Background()))
+	content, err := os.ReadFile(outputPath)
+	require.NoError(t, err)
+	assert.Equal(t, testConfig, string(content))
+	assert.Equal(t, 1, translated)
+	assert.Equal(t, 1, reloaded)
+
+	// unchanged
+	require.NoError(t, w.poll(context.Background()))
+	src.err = errNotModified
+	require.NoError(t, w.poll(context.Background()))
+	assert.Equal(t, 1, reloaded)
+
+	// not valid
+	src.err = nil
+	src.content = `{"agent":{"metrics_collection_interval":"often"}}`
+	assert.ErrorContains(t, w.poll(context.Background()), "schema")
+	src.content = `{"agent":`
+	assert.ErrorContains(t, w.poll(context.Background()), "not valid JSON")
+
+	// not translated
+	src.content = `{"agent":{"metrics_collection_interval":10}}`
+	translateErr = errors.New("translation failed")
+	assert.ErrorContains(t, w.poll(context.Background()), "translation failed")
+	assert.NoFileExists(t, outputPath+constants.FileSuffixTmp)
+
+	content, err = os.ReadFile(outputPath)
+	require.NoError(t, err)
+	assert.Equal(t, testConfig, string(content))
+	assert.Equal(t, 2, translated)
+	assert.Equal(t, 1, reloaded)
+	assert.Equal(t, 3, src.resets)
+
+	// not reloaded, so the same config is applied again at the next poll
+	translateErr = nil
+	reloadErr = errors.New("reload failed")
+	assert.ErrorContains(t, w.poll(context.Background()), "reload failed")
+	assert.Equal(t, 4, src.resets)
+	reloadErr = nil
+	require.NoError(t, w.poll(context.Background()))
+	assert.Equal(t, 4, translated)
+	assert.Equal(t, 3, reloaded)
+	assert.Equal(t, 4, src.resets)
+	require.NoError(t, w.poll(context.Background()))
+	assert.Equal(t, 3, reloaded)
+}
+
+func TestReloadAgent(t *testing.T) {
+	pidfile := filepath.Join(t.TempDir(), "amazon-cloudwatch-agent.pid")
+	assert.Error(t, reloadAgent(pidfile)())
+	require.NoError(t, os.WriteFile(pidfile, []byte("not a pid\n"), 0644))
+	assert.ErrorContains(t, reloadAgent(pidfile)(), "unable to read the pid")
+}
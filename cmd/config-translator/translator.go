@@ -15,6 +15,7 @@ import (
 	userutil "github.com/aws/amazon-cloudwatch-agent/internal/util/user"
 	"github.com/aws/amazon-cloudwatch-agent/translator"
 	"github.com/aws/amazon-cloudwatch-agent/translator/cmdutil"
+	"github.com/aws/amazon-cloudwatch-agent/translator/configdiff"
 	"github.com/aws/amazon-cloudwatch-agent/translator/context"
 	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/pipeline"
 	translatorUtil "github.com/aws/amazon-cloudwatch-agent/translator/util"
@@ -28,6 +29,8 @@ const (
 	yamlConfigFileName = "amazon-cloudwatch-agent.yaml"
 )
 
+var diffConfig string
+
 func initFlags() {
 	var inputOs = flag.String("os", "", "Please provide the os preference, valid value: windows/linux.")
 	var inputJsonFile = flag.String("input", "", "Please provide the path of input agent json config file")
@@ -36,6 +39,7 @@ func initFlags() {
 	var inputMode = flag.String("mode", "ec2", "Please provide the mode, i.e. ec2, onPremise, onPrem, auto")
 	var inputConfig = flag.String("config", "", "Please provide the common-config file")
 	var multiConfig = flag.String("multi-config", "remove", "valid values: default, append, remove")
+	flag.StringVar(&diffConfig, "diff", "", "Please provide the path of the current agent json config file, or of the deployed CWAgent TOML config file, to report what the input changes instead of writing the output files")
 	flag.Parse()
 
 	ctx := context.CurrentContext()
@@ -70,12 +74,16 @@ func initFlags() {
 
 /**
  *	config-translator --input ${JSON} --input-dir ${JSON_DIR} --output ${TOML} --mode ${param_mode} --config ${COMMON_CONFIG}
- *  --multi-config [default|append|remove]
+ *  --multi-config [default|append|remove] --diff ${CURRENT_JSON_OR_TOML}
  *
  *		multi-config:
  *			default:	only process .tmp files
  *			append:		process both existing files and .tmp files
  *			remove:		only process existing files
+ *
+ *		diff:	the current json config, or the deployed toml config with the yaml
+ *				config next to it. The changes made by the input are printed and no
+ *				file is written.
  */
 func main() {
 	initFlags()
@@ -96,6 +104,13 @@ func main() {
 	}()
 	ctx := context.CurrentContext()
 
+	// The current config is translated first, since translating a config
+	// leaves some of its state in the translator.
+	var currentSummary *configdiff.Summary
+	if diffConfig != "" {
+		currentSummary = summarizeCurrentConfig(ctx)
+	}
+
 	mergedJsonConfigMap, err := cmdutil.GenerateMergedJsonConfigMap(ctx)
 	if err != nil {
 		log.Panicf("E! Failed to generate merged json config: %v", err)
@@ -124,6 +139,10 @@ func main() {
 	if err != nil && !errors.Is(err, pipeline.ErrNoPipelines) {
 		log.Panicf("E! Failed to generate YAML configuration validation content: %v", err)
 	}
+	if currentSummary != nil {
+		reportDiff(currentSummary, tomlConfig, yamlConfig)
+		return
+	}
 	if err = cmdutil.ConfigToTomlFile(tomlConfig, tomlConfigPath); err != nil {
 		log.Panicf("E! Failed to create the configuration TOML validation file: %v", err)
 	}
@@ -135,3 +154,33 @@ func main() {
 	envConfigPath := filepath.Join(tomlConfigDir, envConfigFileName)
 	cmdutil.TranslateJsonMapToEnvConfigFile(mergedJsonConfigMap, envConfigPath)
 }
+
+// summarizeCurrentConfig summarizes the config to diff against, which is either
+// a json config to translate or a deployed toml config.
+func summarizeCurrentConfig(ctx *context.Context) *configdiff.Summary {
+	var tomlConfig, yamlConfig interface{}
+	var err error
+	if filepath.Ext(diffConfig) == ".json" {
+		tomlConfig, yamlConfig, err = cmdutil.TranslateJsonFileToConfigs(ctx, diffConfig)
+	} else {
+		tomlConfig, yamlConfig, err = configdiff.LoadFiles(diffConfig, filepath.Join(filepath.Dir(diffConfig), yamlConfigFileName))
+	}
+	if err != nil {
+		log.Panicf("E! Failed to load the current configuration %s: %v", diffConfig, err)
+	}
+	summary, err := configdiff.Summarize(tomlConfig, yamlConfig)
+	if err != nil {
+		log.Panicf("E! Failed to summarize the current configuration %s: %v", diffConfig, err)
+	}
+	return summary
+}
+
+func reportDiff(current *configdiff.Summary, tomlConfig, yamlConfig interface{}) {
+	proposed, err := configdiff.Summarize(tomlConfig, yamlConfig)
+	if err != nil {
+		log.Panicf("E! Failed to summarize the configuration: %v", err)
+	}
+	if err = configdiff.Compare(current, proposed).Write(os.Stdout); err != nil {
+		log.Panicf("E! Failed to write the configuration diff: %v", err)
+	}
+}
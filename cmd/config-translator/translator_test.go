@@ -55,6 +55,7 @@ func TestAgentConfig(t *testing.T) {
 	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/validAgent.json", true, map[string]int{})
 	expectedErrorMap := map[string]int{}
 	expectedErrorMap["invalid_type"] = 5
+	expectedErrorMap["pattern"] = 1
 	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/invalidAgent.json", false, expectedErrorMap)
 }
 
@@ -112,6 +113,20 @@ func TestLogWindowsEventConfig(t *testing.T) {
 	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/invalidLogWindowsEventsWithInvalidEventFormatType.json", false, expectedErrorMap3)
 }
 
+func TestLogJournaldConfig(t *testing.T) {
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/validLogJournald.json", true, map[string]int{})
+	expectedErrorMap := map[string]int{}
+	expectedErrorMap["enum"] = 1
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/invalidLogJournaldWithInvalidPriority.json", false, expectedErrorMap)
+}
+
+func TestLogSyslogConfig(t *testing.T) {
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/validLogSyslog.json", true, map[string]int{})
+	expectedErrorMap := map[string]int{}
+	expectedErrorMap["required"] = 1
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/invalidLogSyslogWithMissingProtocol.json", false, expectedErrorMap)
+}
+
 func TestMetricsConfig(t *testing.T) {
 	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/validLinuxMetrics.json", true, map[string]int{})
 	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/validWindowsMetrics.json", true, map[string]int{})
@@ -181,12 +196,112 @@ func TestInvalidLogFilterConfig(t *testing.T) {
 	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/invalidLogFilesWithFilters.json", false, expectedErrorMap)
 }
 
+func TestValidLogProcessorConfig(t *testing.T) {
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/validLogFilesWithProcessors.json", true, map[string]int{})
+}
+
+func TestInvalidLogProcessorConfig(t *testing.T) {
+	expectedErrorMap := map[string]int{
+		"array_min_items": 1,
+		"enum":            1,
+		"required":        1,
+	}
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/invalidLogFilesWithProcessors.json", false, expectedErrorMap)
+}
+
+func TestLogFilesWithDestinationsConfig(t *testing.T) {
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/validLogFilesWithDestinations.json", true, map[string]int{})
+	expectedErrorMap := map[string]int{
+		"required":        1,
+		"enum":            1,
+		"array_min_items": 1,
+		"string_gte":      1,
+	}
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/invalidLogFilesWithDestinations.json", false, expectedErrorMap)
+}
+
+func TestLogFilesWithMultiLineModesConfig(t *testing.T) {
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/validLogFilesWithMultiLineModes.json", true, map[string]int{})
+	expectedErrorMap := map[string]int{
+		"number_gte":   1,
+		"number_lte":   1,
+		"string_gte":   1,
+		"invalid_type": 1,
+	}
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/invalidLogFilesWithMultiLineModes.json", false, expectedErrorMap)
+}
+
+func TestLogFilesWithMultiLinePresetConfig(t *testing.T) {
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/validLogFilesWithMultiLinePreset.json", true, map[string]int{})
+	expectedErrorMap := map[string]int{
+		"enum": 1,
+	}
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/invalidLogFilesWithMultiLinePreset.json", false, expectedErrorMap)
+}
+
+func TestPreFlushAggregationConfig(t *testing.T) {
+	expectedErrorMap := map[string]int{
+		"required":   1,
+		"number_gte": 1,
+		"enum":       1,
+	}
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/invalidMetricsWithPreFlushAggregation.json", false, expectedErrorMap)
+}
+
+func TestCardinalityLimitConfig(t *testing.T) {
+	expectedErrorMap := map[string]int{
+		"number_gte": 2,
+		"required":   1,
+	}
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/invalidMetricsWithCardinalityLimit.json", false, expectedErrorMap)
+}
+
+func TestStatsDWithDogStatsDConfig(t *testing.T) {
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/validStatsDWithDogStatsD.json", true, map[string]int{})
+	expectedErrorMap := map[string]int{
+		"string_gte":   1,
+		"number_gte":   1,
+		"invalid_type": 1,
+	}
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/invalidStatsDWithDogStatsD.json", false, expectedErrorMap)
+}
+
+func TestLogGroupSettingsConfig(t *testing.T) {
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/validLogGroupSettings.json", true, map[string]int{})
+	expectedErrorMap := map[string]int{
+		"string_gte":   1,
+		"required":     1,
+		"invalid_type": 1,
+	}
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/invalidLogGroupSettings.json", false, expectedErrorMap)
+}
+
+func TestLogsDeadLetterConfig(t *testing.T) {
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/validLogsDeadLetter.json", true, map[string]int{})
+	expectedErrorMap := map[string]int{
+		"number_gte":                      1,
+		"additional_property_not_allowed": 1,
+	}
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/invalidLogsDeadLetter.json", false, expectedErrorMap)
+}
+
 func TestMetricsDestinationsConfig(t *testing.T) {
 	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/validMetricsDestinations.json", true, map[string]int{})
 	expectedErrorMap := map[string]int{}
 	expectedErrorMap["required"] = 1
 	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/invalidMetricsDestinations.json", false, expectedErrorMap)
 }
+
+func TestMetricsNamedDestinationsConfig(t *testing.T) {
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/validMetricsNamedDestinations.json", true, map[string]int{})
+	expectedErrorMap := map[string]int{
+		"required":                        1,
+		"pattern":                         1,
+		"additional_property_not_allowed": 1,
+		"array_min_items":                 1,
+	}
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/invalidMetricsNamedDestinations.json", false, expectedErrorMap)
+}
 func TestContainerInsightsJmxConfig(t *testing.T) {
 	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/validContainerInsightsJmx.json", true, map[string]int{})
 }
@@ -0,0 +1,102 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/xeipuuv/gojsonschema"
+	"gopkg.in/yaml.v3"
+
+	"github.com/aws/amazon-cloudwatch-agent/tool/telegrafmigration"
+	"github.com/aws/amazon-cloudwatch-agent/translator/config"
+)
+
+const defaultTelegrafConfigPath = "/etc/telegraf/telegraf.conf"
+
+/**
+ *	telegraf-migration --input ${TELEGRAF_CONF} --output ${JSON} --prometheusConfig ${PROMETHEUS_YAML}
+ *
+ *		input:	the telegraf config to convert.
+ *		output:	the agent json config to write, which is printed if it is not set.
+ *		prometheusConfig:	the Prometheus scrape config to write the prometheus inputs to, which are not converted if it is not set.
+ */
+func main() {
+	input := flag.String("input", defaultTelegrafConfigPath, "Please provide the path of the telegraf config file to convert")
+	output := flag.String("output", "", "Please provide the path of the output agent json config file, the config is printed if it is not set")
+	prometheusConfig := flag.String("prometheusConfig", "",
+		"Please provide the path of the output Prometheus scrape config file of the prometheus inputs, they are not converted if it is not set")
+	flag.Parse()
+
+	prometheusConfigPath := *prometheusConfig
+	if prometheusConfigPath != "" {
+		// The agent reads the scrape config from the path in the agent config.
+		absPath, err := filepath.Abs(prometheusConfigPath)
+		if err != nil {
+			log.Fatalf("E! Failed to find the absolute path of %s: %v", prometheusConfigPath, err)
+		}
+		prometheusConfigPath = absPath
+	}
+
+	content, err := os.ReadFile(*input)
+	if err != nil {
+		log.Fatalf("E! Failed to read the telegraf config file %s: %v", *input, err)
+	}
+	agentConfig, scrapeConfig, unconverted, err := telegrafmigration.Convert(content, prometheusConfigPath)
+	if err != nil {
+		log.Fatalf("E! Failed to parse the telegraf config file %s: %v", *input, err)
+	}
+	if len(unconverted) > 0 {
+		fmt.Fprintf(os.Stderr, "The following settings of %s are not converted:\n", *input)
+		for _, setting := range unconverted {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", setting)
+		}
+	}
+	_, hasMetrics := agentConfig["metrics"]
+	_, hasLogs := agentConfig["logs"]
+	if !hasMetrics && !hasLogs {
+		log.Fatalf("E! None of the inputs of %s are converted", *input)
+	}
+
+	result, err := gojsonschema.Validate(gojsonschema.NewStringLoader(config.GetJsonSchema()), gojsonschema.NewGoLoader(agentConfig))
+	if err != nil {
+		log.Fatalf("E! Failed to run schema validation because of %v", err)
+	}
+	if !result.Valid() {
+		for _, errorDetail := range result.Errors() {
+			log.Printf("E! %s: %s", config.GetFormattedPath(errorDetail.Context().String()), errorDetail.Description())
+		}
+		log.Fatal("E! Invalid Json output schema.")
+	}
+
+	if scrapeConfig != nil {
+		bytes, err := yaml.Marshal(scrapeConfig)
+		if err != nil {
+			log.Fatalf("E! Failed to marshal the Prometheus scrape config: %v", err)
+		}
+		if err = os.WriteFile(prometheusConfigPath, bytes, 0644); err != nil {
+			log.Fatalf("E! Failed to write the Prometheus scrape config file %s: %v", prometheusConfigPath, err)
+		}
+		log.Printf("I! Wrote the Prometheus scrape config of %s to %s", *input, prometheusConfigPath)
+	}
+
+	bytes, err := json.MarshalIndent(agentConfig, "", "\t")
+	if err != nil {
+		log.Fatalf("E! Failed to marshal the agent json config: %v", err)
+	}
+	bytes = append(bytes, '\n')
+	if *output == "" {
+		os.Stdout.Write(bytes)
+		return
+	}
+	if err = os.WriteFile(*output, bytes, 0644); err != nil {
+		log.Fatalf("E! Failed to write the agent json config file %s: %v", *output, err)
+	}
+	log.Printf("I! Converted %s to %s", *input, *output)
+}
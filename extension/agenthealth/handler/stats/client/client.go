@@ -16,6 +16,7 @@ import (
 	"github.com/jellydator/ttlcache/v3"
 
 	"github.com/aws/amazon-cloudwatch-agent/extension/agenthealth/handler/stats/agent"
+	"github.com/aws/amazon-cloudwatch-agent/internal/selftelemetry"
 )
 
 const (
@@ -113,6 +114,7 @@ func (csh *clientStatsHandler) HandleResponse(ctx context.Context, r *http.Respo
 	}
 	latency := time.Since(recorder.start)
 	stats.LatencyMillis = aws.Int64(latency.Milliseconds())
+	selftelemetry.RecordAPIRequest(operation, r.StatusCode, latency, recorder.payloadBytes)
 	if rejectedEntityInfoExists(r) {
 		stats.EntityRejected = aws.Int(1)
 	}
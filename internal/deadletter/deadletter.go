@@ -0,0 +1,167 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+// Package deadletter stores log events that could not be published to
+// CloudWatch Logs, so they can be inspected and replayed later.
+package deadletter
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultMaxBytes is the default size limit of the spool directory.
+	DefaultMaxBytes = 100 * 1024 * 1024
+
+	recordExt = ".json"
+	tmpExt    = ".tmp"
+
+	dirMode  = 0700
+	fileMode = 0600
+)
+
+// ErrFull is returned when writing a record would exceed the size limit.
+var ErrFull = errors.New("dead letter spool is full")
+
+// Event is a log event in a Record.
+type Event struct {
+	// Timestamp in milliseconds since epoch.
+	Timestamp int64  `json:"timestamp"`
+	Message   string `json:"message"`
+}
+
+// Record is a batch of log events that were not published, with the target
+// they were meant for and the reason they were not published.
+type Record struct {
+	Group  string    `json:"log_group"`
+	Stream string    `json:"log_stream"`
+	Class  string    `json:"log_group_class,omitempty"`
+	Reason string    `json:"reason"`
+	Error  string    `json:"error,omitempty"`
+	Time   time.Time `json:"time"`
+	Events []Event   `json:"events"`
+}
+
+// Spool writes each Record to its own file in a directory, while keeping the
+// total size of the directory under a limit.
+type Spool struct {
+	dir      string
+	maxBytes int64
+
+	mu   sync.Mutex
+	size int64
+	seq  uint64
+}
+
+// NewSpool creates the directory if it does not exist. Defaults to
+// DefaultMaxBytes if maxBytes is not positive.
+func NewSpool(dir string, maxBytes int64) (*Spool, error) {
+	if maxBytes <= 0 {
+		maxBytes = DefaultMaxBytes
+	}
+	if err := os.MkdirAll(dir, dirMode); err != nil {
+		return nil, err
+	}
+	s := &Spool{dir: dir, maxBytes: maxBytes}
+	size, err := dirSize(dir)
+	if err != nil {
+		return nil, err
+	}
+	s.size = size
+	return s, nil
+}
+
+// Dir returns the directory of the spool.
+func (s *Spool) Dir() string {
+	return s.dir
+}
+
+// Write stores the record in a new file. Returns ErrFull instead of removing
+// older records if there is no room left for it.
+func (s *Spool) Write(record Record) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.size+int64(len(data)) > s.maxBytes {
+		// records may have been replayed and removed since the last write
+		if s.size, err = dirSize(s.dir); err != nil {
+			return err
+		}
+		if s.size+int64(len(data)) > s.maxBytes {
+			return ErrFull
+		}
+	}
+	s.seq++
+	name := fmt.Sprintf("%020d-%06d%s", time.Now().UnixNano(), s.seq%1000000, recordExt)
+	path := filepath.Join(s.dir, name)
+	// write to a temporary file first, so a partial record is never listed
+	if err = os.WriteFile(path+tmpExt, data, fileMode); err != nil {
+		return err
+	}
+	if err = os.Rename(path+tmpExt, path); err != nil {
+		_ = os.Remove(path + tmpExt)
+		return err
+	}
+	s.size += int64(len(data))
+	return nil
+}
+
+// List returns the paths of the records in the directory, oldest first.
+func List(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var paths []string
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), recordExt) {
+			continue
+		}
+		paths = append(paths, filepath.Join(dir, entry.Name()))
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// Read returns the record stored in the file.
+func Read(path string) (*Record, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var record Record
+	if err = json.Unmarshal(data, &record); err != nil {
+		return nil, fmt.Errorf("invalid dead letter record %s: %w", path, err)
+	}
+	return &record, nil
+}
+
+func dirSize(dir string) (int64, error) {
+	paths, err := List(dir)
+	if err != nil {
+		return 0, err
+	}
+	var size int64
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			return 0, err
+		}
+		size += info.Size()
+	}
+	return size, nil
+}
@@ -0,0 +1,87 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package deadletter
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSpool(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "dead-letter")
+	s, err := NewSpool(dir, 0)
+	require.NoError(t, err)
+	assert.Equal(t, dir, s.Dir())
+
+	first := Record{
+		Group:  "group",
+		Stream: "stream",
+		Reason: "retries_exhausted",
+		Error:  "RequestError: send request failed",
+		Time:   time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		Events: []Event{{Timestamp: 1704067200000, Message: "first"}},
+	}
+	second := Record{
+		Group:  "group",
+		Stream: "stream",
+		Reason: "too_old",
+		Time:   time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+		Events: []Event{{Timestamp: 1, Message: "second"}, {Timestamp: 2, Message: "third"}},
+	}
+	require.NoError(t, s.Write(first))
+	require.NoError(t, s.Write(second))
+
+	paths, err := List(dir)
+	require.NoError(t, err)
+	require.Len(t, paths, 2)
+	got, err := Read(paths[0])
+	require.NoError(t, err)
+	assert.Equal(t, first, *got)
+	got, err = Read(paths[1])
+	require.NoError(t, err)
+	assert.Equal(t, second, *got)
+
+	info, err := os.Stat(paths[0])
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(fileMode), info.Mode().Perm())
+}
+
+func TestSpoolFull(t *testing.T) {
+	dir := t.TempDir()
+	record := Record{Group: "group", Stream: "stream", Reason: "stopped", Events: []Event{{Message: "message"}}}
+	s, err := NewSpool(dir, 250)
+	require.NoError(t, err)
+	require.NoError(t, s.Write(record))
+	assert.ErrorIs(t, s.Write(record), ErrFull)
+
+	// a replayed record frees up its space
+	paths, err := List(dir)
+	require.NoError(t, err)
+	require.Len(t, paths, 1)
+	require.NoError(t, os.Remove(paths[0]))
+	assert.NoError(t, s.Write(record))
+
+	// the size of existing records counts towards the limit
+	s, err = NewSpool(dir, 250)
+	require.NoError(t, err)
+	assert.ErrorIs(t, s.Write(record), ErrFull)
+}
+
+func TestListIgnoresOtherFiles(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "partial.json"+tmpExt), []byte("{"), fileMode))
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "sub.json"), dirMode))
+	paths, err := List(dir)
+	require.NoError(t, err)
+	assert.Empty(t, paths)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "invalid.json"), []byte("{"), fileMode))
+	_, err = Read(filepath.Join(dir, "invalid.json"))
+	assert.Error(t, err)
+}
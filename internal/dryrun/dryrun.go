@@ -0,0 +1,98 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+// Package dryrun captures the requests to CloudWatch and CloudWatch Logs and
+// prints them instead of sending them, so a configuration can be checked
+// without publishing anything.
+package dryrun
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/request"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal/util/collections"
+	"github.com/aws/amazon-cloudwatch-agent/sdk/service/cloudwatch"
+	"github.com/aws/amazon-cloudwatch-agent/sdk/service/cloudwatchlogs"
+)
+
+// capturedServices are the services whose requests are captured. Requests
+// to other services, e.g. EC2 for the instance tags, are sent as usual.
+var capturedServices = collections.NewSet(cloudwatch.ServiceName, cloudwatchlogs.ServiceName)
+
+var (
+	enabled atomic.Bool
+	mu      sync.Mutex
+	out     io.Writer
+)
+
+// Handler captures the requests to the CloudWatch and CloudWatch Logs APIs
+// once dry run is enabled. It must be in the Build phase, so that the
+// parameters are already validated and invalid requests still fail.
+var Handler = request.NamedHandler{Name: "dryrun.Handler", Fn: capture}
+
+// Request is a captured request as it would have been sent.
+type Request struct {
+	Time      time.Time `json:"time"`
+	Service   string    `json:"service"`
+	Operation string    `json:"operation"`
+	Input     any       `json:"input"`
+}
+
+// Enable starts capturing the requests and writes them to w.
+func Enable(w io.Writer) {
+	mu.Lock()
+	defer mu.Unlock()
+	out = w
+	enabled.Store(true)
+}
+
+// Enabled returns whether the agent is running a dry run.
+func Enabled() bool {
+	return enabled.Load()
+}
+
+func capture(r *request.Request) {
+	if !Enabled() || r.Error != nil || !capturedServices.Contains(r.ClientInfo.ServiceName) {
+		return
+	}
+	write(Request{
+		Time:      time.Now(),
+		Service:   r.ClientInfo.ServiceName,
+		Operation: r.Operation.Name,
+		Input:     r.Params,
+	})
+	// Complete the request with an empty successful response instead of
+	// signing and sending it. The output is left as its zero value.
+	r.Handlers.Sign.Clear()
+	r.Handlers.Send.Clear()
+	r.Handlers.Send.PushBack(respond)
+	r.Handlers.UnmarshalMeta.Clear()
+	r.Handlers.ValidateResponse.Clear()
+	r.Handlers.Unmarshal.Clear()
+	r.Handlers.UnmarshalError.Clear()
+}
+
+func respond(r *request.Request) {
+	r.HTTPResponse = &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{},
+		Body:       io.NopCloser(strings.NewReader("")),
+	}
+}
+
+func write(req Request) {
+	b, err := json.MarshalIndent(req, "", "  ")
+	if err != nil {
+		return
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	_, _ = out.Write(append(b, '\n'))
+}
@@ -0,0 +1,72 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package dryrun
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aws/amazon-cloudwatch-agent/sdk/service/cloudwatch"
+)
+
+func TestCapture(t *testing.T) {
+	var sent atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		sent.Add(1)
+		w.Header().Set("Content-Type", "text/xml")
+		_, _ = w.Write([]byte(`<PutMetricDataResponse><ResponseMetadata><RequestId>1</RequestId></ResponseMetadata></PutMetricDataResponse>`))
+	}))
+	defer server.Close()
+
+	sess := session.Must(session.NewSession(&aws.Config{
+		Region:      aws.String("us-east-1"),
+		Endpoint:    aws.String(server.URL),
+		Credentials: credentials.NewStaticCredentials("id", "secret", ""),
+		MaxRetries:  aws.Int(0),
+	}))
+	sess.Handlers.Build.PushBackNamed(Handler)
+	svc := cloudwatch.New(sess)
+	input := &cloudwatch.PutMetricDataInput{
+		Namespace: aws.String("CWAgent"),
+		MetricData: []*cloudwatch.MetricDatum{{
+			MetricName: aws.String("mem_used_percent"),
+			Dimensions: []*cloudwatch.Dimension{{Name: aws.String("host"), Value: aws.String("h1")}},
+			Value:      aws.Float64(42),
+		}},
+	}
+
+	// requests are sent until dry run is enabled
+	_, err := svc.PutMetricData(input)
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, sent.Load())
+
+	var buf bytes.Buffer
+	Enable(&buf)
+	assert.True(t, Enabled())
+	_, err = svc.PutMetricData(input)
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, sent.Load())
+
+	var got map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &got))
+	assert.Equal(t, "monitoring", got["service"])
+	assert.Equal(t, "PutMetricData", got["operation"])
+	assert.Equal(t, "CWAgent", got["input"].(map[string]any)["Namespace"])
+
+	// invalid requests still fail
+	buf.Reset()
+	_, err = svc.PutMetricData(&cloudwatch.PutMetricDataInput{})
+	assert.Error(t, err)
+	assert.Empty(t, buf.String())
+}
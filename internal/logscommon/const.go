@@ -31,6 +31,7 @@ const (
 	LogEntryField = "value"
 
 	WindowsEventLogPrefix = "Amazon_CloudWatch_WindowsEventLog_"
+	JournaldPrefix        = "Amazon_CloudWatch_Journald_"
 	LogType               = "log_type"
 
 	LogBackpressureModeKey = "backpressure_mode"
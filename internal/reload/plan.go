@@ -0,0 +1,201 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+// Package reload works out which plugins of the agent are restarted when its
+// config is reloaded, and keeps the unchanged components running while the
+// collector service restarts.
+package reload
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+const (
+	inputsKey  = "inputs"
+	outputsKey = "outputs"
+	aliasKey   = "alias"
+	confSuffix = ".conf"
+)
+
+// A Plugin is a telegraf plugin of the config.
+type Plugin struct {
+	Name  string
+	Alias string
+	// Config is the TOML table of the plugin, before the environment
+	// variables are substituted.
+	Config any
+}
+
+// A Snapshot is the TOML config of the agent, split into the parts that can
+// be reloaded separately.
+type Snapshot struct {
+	// Settings are the parts of the config that are shared by every plugin,
+	// e.g. the agent table, and the environment variables of the agent.
+	Settings map[string]any
+	Inputs   []Plugin
+	Outputs  []Plugin
+}
+
+// LoadSnapshot loads the TOML config, the *.conf files of the config
+// directory if there is one, and the env config.
+func LoadSnapshot(tomlPath, configDir, envConfigPath string) (*Snapshot, error) {
+	paths := []string{tomlPath}
+	if configDir != "" {
+		err := filepath.Walk(configDir, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if !info.IsDir() && strings.HasSuffix(path, confSuffix) {
+				paths = append(paths, path)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	s := &Snapshot{Settings: map[string]any{}}
+	for _, path := range paths {
+		var tables map[string]any
+		if _, err := toml.DecodeFile(path, &tables); err != nil {
+			return nil, fmt.Errorf("unable to load %s: %w", path, err)
+		}
+		for key, value := range tables {
+			switch key {
+			case inputsKey:
+				s.Inputs = append(s.Inputs, plugins(value)...)
+			case outputsKey:
+				s.Outputs = append(s.Outputs, plugins(value)...)
+			default:
+				s.Settings[path+":"+key] = value
+			}
+		}
+	}
+	if envConfigPath != "" {
+		content, err := os.ReadFile(envConfigPath)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+		s.Settings[envConfigPath] = string(content)
+	}
+	return s, nil
+}
+
+func plugins(value any) []Plugin {
+	tables, _ := value.(map[string]any)
+	var result []Plugin
+	for _, name := range sortedKeys(tables) {
+		var configs []map[string]any
+		switch config := tables[name].(type) {
+		case []map[string]any:
+			configs = config
+		case map[string]any:
+			configs = []map[string]any{config}
+		}
+		for _, config := range configs {
+			alias, _ := config[aliasKey].(string)
+			result = append(result, Plugin{Name: name, Alias: alias, Config: config})
+		}
+	}
+	return result
+}
+
+// IDs returns the IDs of the plugins, which are their name and alias, with
+// the position of the plugin among the ones with the same name and alias when
+// there is more than one.
+func IDs(plugins []Plugin) []string {
+	ids := make([]string, len(plugins))
+	seen := map[string]int{}
+	for i, plugin := range plugins {
+		id := plugin.Name
+		if plugin.Alias != "" {
+			id += "/" + plugin.Alias
+		}
+		seen[id]++
+		if n := seen[id]; n > 1 {
+			id = fmt.Sprintf("%s#%d", id, n)
+		}
+		ids[i] = id
+	}
+	return ids
+}
+
+// A Plan is what a reload changes in the running agent.
+type Plan struct {
+	// Restart is why the agent has to be restarted to apply the config, or
+	// empty if the config can be applied to the running agent.
+	Restart string
+	// Kept are the IDs of the inputs that are unchanged and keep running.
+	Kept []string
+	// Stopped are the IDs of the inputs that are removed or changed.
+	Stopped []string
+	// Started are the IDs of the inputs that are added or changed.
+	Started []string
+}
+
+// NewPlan compares the running config with the reloaded one.
+func NewPlan(current, next *Snapshot) Plan {
+	var plan Plan
+	switch {
+	case !reflect.DeepEqual(current.Settings, next.Settings):
+		plan.Restart = "the agent settings changed"
+	case !reflect.DeepEqual(current.Outputs, next.Outputs):
+		plan.Restart = "the outputs changed"
+	}
+	if plan.Restart != "" {
+		return plan
+	}
+	currentInputs := map[string]Plugin{}
+	for i, id := range IDs(current.Inputs) {
+		currentInputs[id] = current.Inputs[i]
+	}
+	nextIDs := IDs(next.Inputs)
+	for i, id := range nextIDs {
+		input, ok := currentInputs[id]
+		switch {
+		case !ok:
+			plan.Started = append(plan.Started, id)
+		case reflect.DeepEqual(input, next.Inputs[i]):
+			plan.Kept = append(plan.Kept, id)
+		default:
+			plan.Stopped = append(plan.Stopped, id)
+			plan.Started = append(plan.Started, id)
+		}
+		delete(currentInputs, id)
+	}
+	for _, id := range IDs(current.Inputs) {
+		if _, ok := currentInputs[id]; ok {
+			plan.Stopped = append(plan.Stopped, id)
+		}
+	}
+	return plan
+}
+
+// Changed returns whether the reload changes anything.
+func (p Plan) Changed() bool {
+	return p.Restart != "" || len(p.Stopped) > 0 || len(p.Started) > 0
+}
+
+func (p Plan) String() string {
+	if p.Restart != "" {
+		return "restart, " + p.Restart
+	}
+	return fmt.Sprintf("kept %v, stopped %v, started %v", p.Kept, p.Stopped, p.Started)
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
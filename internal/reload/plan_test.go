@@ -0,0 +1,167 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package reload
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const baseConfig = `
+[agent]
+  interval = "60s"
+
+[inputs]
+
+  [[inputs.cpu]]
+    fieldpass = ["usage_idle"]
+
+  [[inputs.logfile]]
+    file_state_folder = "/opt/aws/amazon-cloudwatch-agent/logs/state"
+
+    [[inputs.logfile.file_config]]
+      file_path = "/var/log/messages"
+      log_group_name = "messages"
+
+  [[inputs.procstat]]
+    alias = "nginx"
+    exe = "nginx"
+
+  [[inputs.statsd]]
+    service_address = ":8125"
+
+[outputs]
+
+  [[outputs.cloudwatchlogs]]
+    region = "us-east-1"
+`
+
+func writeSnapshot(t *testing.T, content string, env string) *Snapshot {
+	t.Helper()
+	dir := t.TempDir()
+	tomlPath := filepath.Join(dir, "amazon-cloudwatch-agent.toml")
+	envPath := filepath.Join(dir, "env-config.json")
+	require.NoError(t, os.WriteFile(tomlPath, []byte(content), 0644))
+	require.NoError(t, os.WriteFile(envPath, []byte(env), 0644))
+	s, err := LoadSnapshot(tomlPath, "", envPath)
+	require.NoError(t, err)
+	// the paths are part of the settings, so both snapshots use the same ones
+	settings := map[string]any{}
+	for key, value := range s.Settings {
+		rel, _ := filepath.Rel(dir, key)
+		settings[rel] = value
+	}
+	s.Settings = settings
+	return s
+}
+
+func TestNewPlan(t *testing.T) {
+	current := writeSnapshot(t, baseConfig, `{}`)
+
+	testCases := map[string]struct {
+		config  string
+		env     string
+		want    Plan
+		changed bool
+	}{
+		"Unchanged": {
+			config: baseConfig,
+			env:    `{}`,
+			want:   Plan{Kept: []string{"cpu", "logfile", "procstat/nginx", "statsd"}},
+		},
+		"AddedLogFile": {
+			config: baseConfig + `
+  [[inputs.logfile]]
+    file_state_folder = "/opt/aws/amazon-cloudwatch-agent/logs/state"
+`,
+			env:     `{}`,
+			want:    Plan{Kept: []string{"cpu", "logfile", "procstat/nginx", "statsd"}, Started: []string{"logfile#2"}},
+			changed: true,
+		},
+		"ChangedInput": {
+			config: replace(baseConfig, `fieldpass = ["usage_idle"]`, `fieldpass = ["usage_idle", "usage_user"]`),
+			env:    `{}`,
+			want: Plan{
+				Kept:    []string{"logfile", "procstat/nginx", "statsd"},
+				Stopped: []string{"cpu"},
+				Started: []string{"cpu"},
+			},
+			changed: true,
+		},
+		"RemovedInput": {
+			config: replace(baseConfig, `alias = "nginx"`, `alias = "httpd"`),
+			env:    `{}`,
+			want: Plan{
+				Kept:    []string{"cpu", "logfile", "statsd"},
+				Stopped: []string{"procstat/nginx"},
+				Started: []string{"procstat/httpd"},
+			},
+			changed: true,
+		},
+		"ChangedAgent": {
+			config:  replace(baseConfig, `interval = "60s"`, `interval = "10s"`),
+			env:     `{}`,
+			want:    Plan{Restart: "the agent settings changed"},
+			changed: true,
+		},
+		"ChangedEnv": {
+			config:  baseConfig,
+			env:     `{"CWAGENT_LOG_LEVEL": "DEBUG"}`,
+			want:    Plan{Restart: "the agent settings changed"},
+			changed: true,
+		},
+		"ChangedOutput": {
+			config:  replace(baseConfig, `region = "us-east-1"`, `region = "us-west-2"`),
+			env:     `{}`,
+			want:    Plan{Restart: "the outputs changed"},
+			changed: true,
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			next := writeSnapshot(t, testCase.config, testCase.env)
+			plan := NewPlan(current, next)
+			assert.Equal(t, testCase.want, plan)
+			assert.Equal(t, testCase.changed, plan.Changed())
+		})
+	}
+}
+
+func TestLoadSnapshotConfigDirectory(t *testing.T) {
+	dir := t.TempDir()
+	tomlPath := filepath.Join(dir, "amazon-cloudwatch-agent.toml")
+	require.NoError(t, os.WriteFile(tomlPath, []byte(baseConfig), 0644))
+	confDir := filepath.Join(dir, "conf.d")
+	require.NoError(t, os.Mkdir(confDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(confDir, "mem.conf"), []byte("[[inputs.mem]]\n  fieldpass = [\"used_percent\"]\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(confDir, "README.md"), []byte("not a config"), 0644))
+
+	s, err := LoadSnapshot(tomlPath, confDir, filepath.Join(dir, "missing.json"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"cpu", "logfile", "procstat/nginx", "statsd", "mem"}, IDs(s.Inputs))
+	assert.Len(t, s.Outputs, 1)
+}
+
+func TestIDs(t *testing.T) {
+	assert.Equal(t, []string{"cpu", "procstat/a", "procstat/a#2", "procstat", "cpu#2"}, IDs([]Plugin{
+		{Name: "cpu"},
+		{Name: "procstat", Alias: "a"},
+		{Name: "procstat", Alias: "a"},
+		{Name: "procstat"},
+		{Name: "cpu"},
+	}))
+}
+
+func replace(s, old, new string) string {
+	result := strings.Replace(s, old, new, 1)
+	if result == s {
+		panic("nothing replaced: " + old)
+	}
+	return result
+}
@@ -0,0 +1,80 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package reload
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// A Releaser is a component that can be kept running while the collector
+// service restarts.
+type Releaser interface {
+	// Release stops the component once it is no longer used.
+	Release()
+}
+
+var (
+	enabled atomic.Bool
+	mu      sync.Mutex
+	parked  []Releaser
+)
+
+// Enable keeps the components that are shut down by the collector service
+// running until they are reclaimed or released. It is only enabled by the
+// agent, which releases them once the service has restarted.
+func Enable() {
+	enabled.Store(true)
+}
+
+// Disable releases the parked components, and shuts the components down as
+// usual from then on.
+func Disable() {
+	enabled.Store(false)
+	Release()
+}
+
+// Enabled returns whether the components are parked on shut down.
+func Enabled() bool {
+	return enabled.Load()
+}
+
+// Park keeps the component running instead of shutting it down, and returns
+// false if parking is not enabled, in which case the component is shut down
+// as usual.
+func Park(r Releaser) bool {
+	if !Enabled() {
+		return false
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	parked = append(parked, r)
+	return true
+}
+
+// Reclaim returns the first parked component of type T that matches, which
+// is then used by the restarted service instead of a new one.
+func Reclaim[T Releaser](match func(T) bool) (T, bool) {
+	mu.Lock()
+	defer mu.Unlock()
+	for i, r := range parked {
+		if t, ok := r.(T); ok && match(t) {
+			parked = append(parked[:i], parked[i+1:]...)
+			return t, true
+		}
+	}
+	var zero T
+	return zero, false
+}
+
+// Release stops the parked components that were not reclaimed.
+func Release() {
+	mu.Lock()
+	released := parked
+	parked = nil
+	mu.Unlock()
+	for _, r := range released {
+		r.Release()
+	}
+}
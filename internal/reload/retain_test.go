@@ -0,0 +1,42 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package reload
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testComponent struct {
+	name     string
+	released bool
+}
+
+func (c *testComponent) Release() {
+	c.released = true
+}
+
+func TestRetain(t *testing.T) {
+	first := &testComponent{name: "first"}
+	assert.False(t, Park(first))
+
+	Enable()
+	defer Disable()
+	second := &testComponent{name: "second"}
+	assert.True(t, Park(first))
+	assert.True(t, Park(second))
+
+	got, ok := Reclaim(func(c *testComponent) bool { return c.name == "second" })
+	assert.True(t, ok)
+	assert.Same(t, second, got)
+	_, ok = Reclaim(func(c *testComponent) bool { return c.name == "second" })
+	assert.False(t, ok)
+
+	Release()
+	assert.True(t, first.released)
+	assert.False(t, second.released)
+	_, ok = Reclaim(func(*testComponent) bool { return true })
+	assert.False(t, ok)
+}
@@ -0,0 +1,164 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+// Package selftelemetry collects the health metrics of the agent itself and
+// exposes them in the Prometheus text format.
+package selftelemetry
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal/version"
+)
+
+const (
+	namespace = "cwagent"
+
+	labelLogGroup   = "log_group"
+	labelLogStream  = "log_stream"
+	labelReason     = "reason"
+	labelOperation  = "operation"
+	labelStatusCode = "status_code"
+
+	// DropReasonInvalidTime is used for events outside the accepted time range.
+	DropReasonInvalidTime = "invalid_time"
+	// DropReasonQueueFull is used for events evicted from a full non-blocking queue.
+	DropReasonQueueFull = "queue_full"
+	// DropReasonRetriesExhausted is used for events whose batch failed for longer than the retry duration.
+	DropReasonRetriesExhausted = "retries_exhausted"
+	// DropReasonUnretryable is used for events whose batch failed with an error that is not retried.
+	DropReasonUnretryable = "unretryable"
+	// DropReasonStopped is used for events whose batch was still being retried on shutdown.
+	DropReasonStopped = "stopped"
+	// DropReasonTooOld is used for events CloudWatch Logs rejected as too old.
+	DropReasonTooOld = "too_old"
+	// DropReasonTooNew is used for events CloudWatch Logs rejected as too new.
+	DropReasonTooNew = "too_new"
+	// DropReasonExpired is used for events CloudWatch Logs rejected as older than the retention.
+	DropReasonExpired = "expired"
+)
+
+var (
+	registry = prometheus.NewRegistry()
+
+	// LogsQueueDepth is the number of events buffered or in flight for a log stream.
+	LogsQueueDepth = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "logs",
+		Name:      "queue_depth",
+		Help:      "Number of log events waiting to be published or being retried.",
+	}, []string{labelLogGroup, labelLogStream})
+	// LogsEventsDropped is the number of events that will not be published.
+	LogsEventsDropped = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "logs",
+		Name:      "events_dropped_total",
+		Help:      "Number of log events dropped before being published.",
+	}, []string{labelLogGroup, labelLogStream, labelReason})
+	// LogsEventsDeadLettered is the number of dropped events written to the dead letter spool.
+	LogsEventsDeadLettered = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "logs",
+		Name:      "events_dead_lettered_total",
+		Help:      "Number of dropped log events written to the dead letter spool.",
+	}, []string{labelLogGroup, labelLogStream, labelReason})
+	// LogsRetries is the number of retried PutLogEvents requests.
+	LogsRetries = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "logs",
+		Name:      "retries_total",
+		Help:      "Number of retried PutLogEvents requests.",
+	}, []string{labelLogGroup, labelLogStream})
+	// LogsEventsSent is the number of published events.
+	LogsEventsSent = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "logs",
+		Name:      "events_sent_total",
+		Help:      "Number of log events published.",
+	}, []string{labelLogGroup, labelLogStream})
+	// LogsBytesSent is the size of the published events.
+	LogsBytesSent = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "logs",
+		Name:      "bytes_sent_total",
+		Help:      "Size in bytes of the log events published, including the per event overhead.",
+	}, []string{labelLogGroup, labelLogStream})
+
+	apiRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "api",
+		Name:      "requests_total",
+		Help:      "Number of AWS API requests by operation and response status code.",
+	}, []string{labelOperation, labelStatusCode})
+	apiRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "api",
+		Name:      "request_duration_seconds",
+		Help:      "Latency of AWS API requests by operation.",
+		Buckets:   []float64{0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30},
+	}, []string{labelOperation})
+	apiRequestBytes = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "api",
+		Name:      "request_bytes_total",
+		Help:      "Payload size in bytes of AWS API requests by operation.",
+	}, []string{labelOperation})
+)
+
+func init() {
+	buildInfo := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "build_info",
+		Help:      "Always 1, labeled with the version of the agent.",
+	}, []string{"version"})
+	buildInfo.WithLabelValues(version.Number()).Set(1)
+	registry.MustRegister(
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		collectors.NewGoCollector(),
+		buildInfo,
+		LogsQueueDepth,
+		LogsEventsDropped,
+		LogsEventsDeadLettered,
+		LogsRetries,
+		LogsEventsSent,
+		LogsBytesSent,
+		apiRequests,
+		apiRequestDuration,
+		apiRequestBytes,
+	)
+}
+
+// RegisterGaugeFunc exposes the value returned by fn as the cwagent_<name>
+// gauge. Panics if the name is already registered, so it is meant to be called
+// from package initialization.
+func RegisterGaugeFunc(name, help string, fn func() float64) {
+	registry.MustRegister(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      name,
+		Help:      help,
+	}, fn))
+}
+
+// RecordAPIRequest records the response of an AWS API request.
+func RecordAPIRequest(operation string, statusCode int, latency time.Duration, payloadBytes int64) {
+	apiRequests.WithLabelValues(operation, strconv.Itoa(statusCode)).Inc()
+	apiRequestDuration.WithLabelValues(operation).Observe(latency.Seconds())
+	apiRequestBytes.WithLabelValues(operation).Add(float64(payloadBytes))
+}
+
+// DeleteLogStream removes the series of a log stream, so the ones of the
+// streams that are no longer published to are not exposed until the agent
+// restarts.
+func DeleteLogStream(group, stream string) {
+	labels := prometheus.Labels{labelLogGroup: group, labelLogStream: stream}
+	LogsQueueDepth.Delete(labels)
+	LogsRetries.Delete(labels)
+	LogsEventsSent.Delete(labels)
+	LogsBytesSent.Delete(labels)
+	LogsEventsDropped.DeletePartialMatch(labels)
+	LogsEventsDeadLettered.DeletePartialMatch(labels)
+}
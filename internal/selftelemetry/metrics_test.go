@@ -0,0 +1,28 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package selftelemetry
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeleteLogStream(t *testing.T) {
+	depth := testutil.CollectAndCount(LogsQueueDepth)
+	dropped := testutil.CollectAndCount(LogsEventsDropped)
+	sent := testutil.CollectAndCount(LogsEventsSent)
+	LogsQueueDepth.WithLabelValues("deleted", "stream").Set(1)
+	LogsEventsDropped.WithLabelValues("deleted", "stream", DropReasonQueueFull).Inc()
+	LogsEventsDropped.WithLabelValues("deleted", "stream", DropReasonStopped).Inc()
+	LogsEventsSent.WithLabelValues("deleted", "stream").Inc()
+	LogsEventsSent.WithLabelValues("deleted", "other").Inc()
+
+	DeleteLogStream("deleted", "stream")
+
+	assert.Equal(t, depth, testutil.CollectAndCount(LogsQueueDepth))
+	assert.Equal(t, dropped, testutil.CollectAndCount(LogsEventsDropped))
+	assert.Equal(t, sent+1, testutil.CollectAndCount(LogsEventsSent))
+}
@@ -0,0 +1,71 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package selftelemetry
+
+import (
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServer(t *testing.T) {
+	RegisterGaugeFunc("test_gauge", "A gauge for testing.", func() float64 { return 42 })
+	LogsQueueDepth.WithLabelValues("group", "stream").Add(3)
+	LogsEventsDropped.WithLabelValues("group", "stream", DropReasonQueueFull).Inc()
+	LogsBytesSent.WithLabelValues("group", "stream").Add(1024)
+	RecordAPIRequest("PutLogEvents", http.StatusOK, 150*time.Millisecond, 512)
+
+	s, err := Start("127.0.0.1:0")
+	require.NoError(t, err)
+	defer s.Shutdown()
+
+	resp, err := http.Get("http://" + s.Addr().String() + MetricsPath)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	for _, want := range []string{
+		"cwagent_test_gauge 42",
+		`cwagent_logs_queue_depth{log_group="group",log_stream="stream"} 3`,
+		`cwagent_logs_events_dropped_total{log_group="group",log_stream="stream",reason="queue_full"} 1`,
+		`cwagent_logs_bytes_sent_total{log_group="group",log_stream="stream"} 1024`,
+		`cwagent_api_requests_total{operation="PutLogEvents",status_code="200"} 1`,
+		`cwagent_api_request_duration_seconds_bucket{operation="PutLogEvents",le="0.25"} 1`,
+		`cwagent_api_request_bytes_total{operation="PutLogEvents"} 512`,
+		"cwagent_build_info{version=",
+		"go_goroutines",
+	} {
+		assert.Contains(t, string(body), want)
+	}
+}
+
+func TestServerDefaultsToLocalhost(t *testing.T) {
+	s, err := Start(":0")
+	require.NoError(t, err)
+	defer s.Shutdown()
+	host, _, err := net.SplitHostPort(s.Addr().String())
+	require.NoError(t, err)
+	assert.True(t, net.ParseIP(host).IsLoopback())
+}
+
+func TestServerInvalidAddress(t *testing.T) {
+	_, err := Start("localhost")
+	assert.Error(t, err)
+}
+
+func TestServerShutdownReleasesAddress(t *testing.T) {
+	s, err := Start("127.0.0.1:0")
+	require.NoError(t, err)
+	addr := s.Addr().String()
+	s.Shutdown()
+	s, err = Start(addr)
+	require.NoError(t, err)
+	s.Shutdown()
+}
@@ -0,0 +1,188 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package state
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Cursor is an opaque position in a log source that cannot be expressed as
+// a byte offset, e.g. a journald cursor. Seq orders the cursors read during
+// the same run, since they can be acknowledged out of order.
+type Cursor struct {
+	Seq   uint64
+	Value string
+}
+
+type cursorManager struct {
+	name          string
+	stateFilePath string
+	queue         chan Cursor
+	saveInterval  time.Duration
+	maxPending    int
+}
+
+// CursorManager is a state manager that persists the Cursor with the highest
+// Seq acknowledged without a gap before it and restores its Value.
+type CursorManager Manager[Cursor, string]
+
+var _ CursorManager = (*cursorManager)(nil)
+
+func NewCursorManager(cfg ManagerConfig) CursorManager {
+	if cfg.QueueSize <= 0 {
+		cfg.QueueSize = defaultQueueSize
+	}
+	if cfg.SaveInterval <= 0 {
+		cfg.SaveInterval = defaultSaveInterval
+	}
+	return &cursorManager{
+		name:          cfg.Name,
+		stateFilePath: cfg.StateFilePath(),
+		queue:         make(chan Cursor, cfg.QueueSize),
+		saveInterval:  cfg.SaveInterval,
+		maxPending:    cfg.QueueSize,
+	}
+}
+
+func (m *cursorManager) ID() string {
+	return m.name
+}
+
+// Enqueue the Cursor. Will drop the oldest in the queue if full.
+func (m *cursorManager) Enqueue(item Cursor) {
+	select {
+	case m.queue <- item:
+	default:
+		old := <-m.queue
+		log.Printf("D! Cursor queue is full for %s. Dropping oldest cursor: %d", m.stateFilePath, old.Seq)
+		m.queue <- item
+	}
+}
+
+// Restore the cursor if the state file exists.
+func (m *cursorManager) Restore() (string, error) {
+	content, err := os.ReadFile(m.stateFilePath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			log.Printf("D! No state file exists for %s", m.name)
+		} else {
+			log.Printf("W! Failed to read state file for %s: %v", m.name, err)
+		}
+		return "", err
+	}
+	cursor, _, _ := strings.Cut(string(content), "\n")
+	if cursor == "" {
+		err = fmt.Errorf("state file %s has no cursor", m.stateFilePath)
+		log.Printf("W! Invalid state file content: %v", err)
+		return "", err
+	}
+	log.Printf("I! Reading from cursor %s in %s", cursor, m.name)
+	return cursor, nil
+}
+
+// save the cursor in the state file.
+func (m *cursorManager) save(cursor string) error {
+	if m.stateFilePath == "" {
+		return nil
+	}
+	return os.WriteFile(m.stateFilePath, []byte(cursor+"\n"+m.name), FileMode)
+}
+
+// Run starts the update/save loop.
+func (m *cursorManager) Run(notification Notification) {
+	t := time.NewTicker(m.saveInterval)
+	defer t.Stop()
+
+	tracker := newCursorTracker(m.name, m.maxPending)
+	shouldSave := false
+	for {
+		select {
+		case item := <-m.queue:
+			changed := tracker.Insert(item)
+			shouldSave = shouldSave || changed
+		case <-t.C:
+			if !shouldSave {
+				continue
+			}
+			if err := m.save(tracker.Current().Value); err != nil {
+				log.Printf("E! Error happened when saving state file (%s): %v", m.stateFilePath, err)
+				continue
+			}
+			shouldSave = false
+		case <-notification.Delete:
+			log.Printf("W! Deleting state file (%s)", m.stateFilePath)
+			if err := os.Remove(m.stateFilePath); err != nil {
+				log.Printf("W! Error happened while deleting state file (%s) on cleanup: %v", m.stateFilePath, err)
+			}
+			return
+		case <-notification.Done:
+			if !shouldSave {
+				return
+			}
+			if err := m.save(tracker.Current().Value); err != nil {
+				log.Printf("E! Error happened during final state file (%s) save, duplicate log maybe sent at next start: %v", m.stateFilePath, err)
+			}
+			return
+		}
+	}
+}
+
+// cursorTracker tracks the acknowledged cursors. The current cursor is the one
+// with the highest Seq that has no gap before it, since the cursors before a
+// gap are still being published. The acknowledged cursors after a gap are kept
+// until it is filled. The cursors of a dropped batch are never acknowledged,
+// so once more than maxPending are kept, the oldest gap is skipped. It is not
+// thread-safe.
+type cursorTracker struct {
+	name       string
+	current    Cursor
+	pending    map[uint64]Cursor
+	maxPending int
+}
+
+func newCursorTracker(name string, maxPending int) *cursorTracker {
+	return &cursorTracker{
+		name:       name,
+		pending:    make(map[uint64]Cursor),
+		maxPending: maxPending,
+	}
+}
+
+// Current returns the cursor with the highest Seq acknowledged without a gap.
+func (t *cursorTracker) Current() Cursor {
+	return t.current
+}
+
+// Insert the acknowledged cursor. Returns true if the current cursor changed.
+func (t *cursorTracker) Insert(item Cursor) bool {
+	if item.Seq <= t.current.Seq {
+		return false
+	}
+	t.pending[item.Seq] = item
+	changed := false
+	if t.maxPending > 0 && len(t.pending) > t.maxPending {
+		oldest := slices.Min(slices.Collect(maps.Keys(t.pending)))
+		log.Printf("D! Cursors %d to %d were not acknowledged for %s. Skipping them", t.current.Seq+1, oldest-1, t.name)
+		t.current = t.pending[oldest]
+		delete(t.pending, oldest)
+		changed = true
+	}
+	for {
+		next, ok := t.pending[t.current.Seq+1]
+		if !ok {
+			break
+		}
+		t.current = next
+		delete(t.pending, next.Seq)
+		changed = true
+	}
+	return changed
+}
@@ -0,0 +1,83 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package state
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCursorManager(t *testing.T) {
+	t.Run("Restore/Missing", func(t *testing.T) {
+		manager := NewCursorManager(ManagerConfig{StateFileDir: t.TempDir(), Name: "missing"})
+		got, err := manager.Restore()
+		assert.Error(t, err)
+		assert.Equal(t, "", got)
+	})
+	t.Run("Restore/Invalid", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "invalid"), []byte("\ninvalid"), FileMode))
+		manager := NewCursorManager(ManagerConfig{StateFileDir: tmpDir, Name: "invalid"})
+		got, err := manager.Restore()
+		assert.Error(t, err)
+		assert.Equal(t, "", got)
+	})
+	t.Run("Run", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		cfg := ManagerConfig{
+			StateFileDir:    tmpDir,
+			StateFilePrefix: "prefix_",
+			Name:            "journal",
+			SaveInterval:    time.Millisecond,
+		}
+		manager := NewCursorManager(cfg)
+		assert.Equal(t, "journal", manager.ID())
+		done := make(chan struct{})
+		finished := make(chan struct{})
+		go func() {
+			manager.Run(Notification{Done: done})
+			close(finished)
+		}()
+		// Acknowledged out of order.
+		manager.Enqueue(Cursor{Seq: 2, Value: "s=2"})
+		manager.Enqueue(Cursor{Seq: 3, Value: "s=3"})
+		manager.Enqueue(Cursor{Seq: 1, Value: "s=1"})
+		assert.Eventually(t, func() bool {
+			content, err := os.ReadFile(cfg.StateFilePath())
+			return err == nil && string(content) == "s=3\njournal"
+		}, time.Second, time.Millisecond)
+		close(done)
+		<-finished
+
+		got, err := NewCursorManager(cfg).Restore()
+		assert.NoError(t, err)
+		assert.Equal(t, "s=3", got)
+	})
+}
+
+func TestCursorTracker(t *testing.T) {
+	tracker := newCursorTracker("journal", 3)
+	assert.Equal(t, Cursor{}, tracker.Current())
+	// Acknowledged out of order.
+	assert.False(t, tracker.Insert(Cursor{Seq: 2, Value: "s=2"}))
+	assert.Equal(t, Cursor{}, tracker.Current())
+	assert.True(t, tracker.Insert(Cursor{Seq: 1, Value: "s=1"}))
+	assert.Equal(t, "s=2", tracker.Current().Value)
+	assert.False(t, tracker.Insert(Cursor{Seq: 1, Value: "s=1"}))
+	// Cursor 3 is missing, e.g. its batch was dropped.
+	assert.False(t, tracker.Insert(Cursor{Seq: 5, Value: "s=5"}))
+	assert.False(t, tracker.Insert(Cursor{Seq: 4, Value: "s=4"}))
+	assert.False(t, tracker.Insert(Cursor{Seq: 7, Value: "s=7"}))
+	assert.Equal(t, "s=2", tracker.Current().Value)
+	// Skips the gap once more than 3 cursors are pending.
+	assert.True(t, tracker.Insert(Cursor{Seq: 8, Value: "s=8"}))
+	assert.Equal(t, "s=5", tracker.Current().Value)
+	assert.True(t, tracker.Insert(Cursor{Seq: 6, Value: "s=6"}))
+	assert.Equal(t, "s=8", tracker.Current().Value)
+}
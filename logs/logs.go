@@ -7,11 +7,14 @@ import (
 	"context"
 	"errors"
 	"log"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/influxdata/telegraf"
 	"github.com/influxdata/telegraf/config"
 
+	"github.com/aws/amazon-cloudwatch-agent/internal/selftelemetry"
 	"github.com/aws/amazon-cloudwatch-agent/internal/state"
 	"github.com/aws/amazon-cloudwatch-agent/plugins/inputs/logfile/tail"
 	"github.com/aws/amazon-cloudwatch-agent/sdk/service/cloudwatchlogs"
@@ -19,6 +22,12 @@ import (
 
 var ErrOutputStopped = errors.New("Output plugin stopped")
 
+func init() {
+	selftelemetry.RegisterGaugeFunc("tail_open_files", "Number of files held open by the log file tailers.", func() float64 {
+		return float64(tail.OpenFileCount.Load())
+	})
+}
+
 // A LogCollection is a collection of LogSrc, a plugin which can provide many LogSrc
 type LogCollection interface {
 	FindLogSrc() []LogSrc
@@ -55,6 +64,63 @@ type LogSrc interface {
 	Stop()
 }
 
+// A LogRoute is one of the destinations a MultiRouteLogSrc publishes to.
+type LogRoute interface {
+	Group() string
+	Stream() string
+	Destination() string
+	Retention() int
+	Class() string
+	// ShouldPublish returns true if the event should be published to the route.
+	ShouldPublish(LogEvent) bool
+}
+
+// A MultiRouteLogSrc is a LogSrc that publishes each event to all of its
+// routes. When it has routes, they are used instead of the group, stream and
+// destination of the LogSrc, and the offset of an event is only advanced once
+// every route it was published to has acknowledged it.
+type MultiRouteLogSrc interface {
+	LogSrc
+	Routes() []LogRoute
+}
+
+// A DynamicLogSrc is a LogSrc whose log group and stream names depend on the
+// log events. The LogDest of each name is created the first time an event
+// needs it.
+type DynamicLogSrc interface {
+	LogSrc
+	// IsDynamic returns true if the names depend on the log events.
+	IsDynamic() bool
+	// Target returns the log group and stream names of the log event.
+	Target(LogEvent) (group, stream string)
+	// FallbackTarget returns the names that the log events are published to
+	// once the src publishes to as many names as it can at once.
+	FallbackTarget() (group, stream string)
+}
+
+// LogGroupSettings are applied to the log groups the agent creates, and
+// reconciled on the log groups that already exist.
+type LogGroupSettings struct {
+	KMSKeyID             string
+	Tags                 map[string]string
+	SubscriptionFilter   *SubscriptionFilter
+	DataProtectionPolicy string
+}
+
+// A SubscriptionFilter streams the log events of a log group to a destination.
+type SubscriptionFilter struct {
+	FilterName     string `toml:"filter_name"`
+	FilterPattern  string `toml:"filter_pattern"`
+	DestinationARN string `toml:"destination_arn"`
+	RoleARN        string `toml:"role_arn"`
+}
+
+// A LogGroupSettingsProvider is a LogSrc with its own settings for the log
+// groups it is published to. Returns nil to use the settings of the LogBackend.
+type LogGroupSettingsProvider interface {
+	LogGroupSettings() *LogGroupSettings
+}
+
 // A LogBackend is able to return a LogDest of a given name.
 // The same name should always return the same LogDest.
 type LogBackend interface {
@@ -67,13 +133,24 @@ type LogDest interface {
 	Publish(events []LogEvent) error
 }
 
+// A ReleasableLogDest is a LogDest that the LogBackend can free once none of
+// the sources that created it publish to it anymore. Release is called once
+// for each time the LogDest is created by a DynamicLogSrc.
+type ReleasableLogDest interface {
+	LogDest
+	Release()
+}
+
 // LogAgent is the agent handles pure log pipelines
 type LogAgent struct {
 	Config                    *config.Config
 	backends                  map[string]LogBackend
 	destNames                 map[LogDest]string
 	collections               []LogCollection
+	srcs                      map[LogCollection][]LogSrc
+	collectionsMu             sync.Mutex
 	retentionAlreadyAttempted map[string]bool
+	retentionMu               sync.Mutex
 }
 
 func NewLogAgent(c *config.Config) *LogAgent {
@@ -81,6 +158,7 @@ func NewLogAgent(c *config.Config) *LogAgent {
 		Config:                    c,
 		backends:                  make(map[string]LogBackend),
 		destNames:                 make(map[LogDest]string),
+		srcs:                      make(map[LogCollection][]LogSrc),
 		retentionAlreadyAttempted: make(map[string]bool),
 	}
 }
@@ -106,11 +184,9 @@ func (l *LogAgent) Run(ctx context.Context) {
 	for _, input := range l.Config.Inputs {
 		if collection, ok := input.Input.(LogCollection); ok {
 			log.Printf("I! [logagent] found plugin %v is a log collection", input.Config.Name)
-			err := collection.Start(nil)
-			if err != nil {
+			if err := l.AddCollection(collection); err != nil {
 				log.Printf("E! could not start log collection %v err %v", input.Config.Name, err)
 			}
-			l.collections = append(l.collections, collection)
 		}
 	}
 
@@ -120,9 +196,27 @@ func (l *LogAgent) Run(ctx context.Context) {
 		select {
 		case <-t.C:
 			log.Printf("D! [logagent] open file count, %v", tail.OpenFileCount.Load())
+			l.collectionsMu.Lock()
 			for _, c := range l.collections {
 				srcs := c.FindLogSrc()
+				l.srcs[c] = append(l.srcs[c], srcs...)
 				for _, src := range srcs {
+					if mrs, ok := src.(MultiRouteLogSrc); ok {
+						if routes := mrs.Routes(); len(routes) > 0 {
+							l.runMultiRoute(src, routes)
+							continue
+						}
+					}
+					if dls, ok := src.(DynamicLogSrc); ok && dls.IsDynamic() {
+						backend, ok := l.backends[dls.Destination()]
+						if !ok {
+							log.Printf("E! [logagent] Failed to find destination %s for log source %s/%s(%s) ", dls.Destination(), dls.Group(), dls.Stream(), dls.Description())
+							continue
+						}
+						log.Printf("I! [logagent] piping log from %s/%s(%s) to %s with dynamic names", dls.Group(), dls.Stream(), dls.Description(), dls.Destination())
+						go l.runSrcToDynamicDests(dls, backend)
+						continue
+					}
 					dname := src.Destination()
 					logGroup := src.Group()
 					logStream := src.Stream()
@@ -141,15 +235,68 @@ func (l *LogAgent) Run(ctx context.Context) {
 					go l.runSrcToDest(src, dest)
 				}
 			}
+			l.collectionsMu.Unlock()
 		case <-ctx.Done():
 			return
 		}
 	}
 }
 
+// AddCollection starts the log collection and pipes the log sources it finds.
+// Collections added to the config by a reload are added while the LogAgent
+// runs, without restarting the other collections.
+func (l *LogAgent) AddCollection(collection LogCollection) error {
+	// the collection is added even if it fails to start, as it was before
+	// collections could be added on reload
+	err := collection.Start(nil)
+	l.collectionsMu.Lock()
+	defer l.collectionsMu.Unlock()
+	l.collections = append(l.collections, collection)
+	return err
+}
+
+// RemoveCollection stops the log collection and the log sources it found, so
+// it can be removed or replaced on a reload. The log destinations are kept,
+// with the log events that are still being published.
+func (l *LogAgent) RemoveCollection(collection LogCollection) {
+	l.collectionsMu.Lock()
+	for i, c := range l.collections {
+		if c == collection {
+			l.collections = append(l.collections[:i], l.collections[i+1:]...)
+			break
+		}
+	}
+	srcs := l.srcs[collection]
+	delete(l.srcs, collection)
+	l.collectionsMu.Unlock()
+
+	for _, src := range srcs {
+		src.Stop()
+	}
+	if input, ok := collection.(telegraf.ServiceInput); ok {
+		input.Stop()
+	}
+}
+
+// forgetSrc is called once the src has stopped, so it is not stopped again
+// when its collection is removed.
+func (l *LogAgent) forgetSrc(src LogSrc) {
+	l.collectionsMu.Lock()
+	defer l.collectionsMu.Unlock()
+	for c, srcs := range l.srcs {
+		for i, s := range srcs {
+			if s == src {
+				l.srcs[c] = append(srcs[:i], srcs[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
 func (l *LogAgent) runSrcToDest(src LogSrc, dest LogDest) {
 	eventsCh := make(chan LogEvent)
 	defer src.Stop()
+	defer l.forgetSrc(src)
 
 	closed := false
 	src.SetOutput(func(e LogEvent) {
@@ -178,7 +325,301 @@ func (l *LogAgent) runSrcToDest(src LogSrc, dest LogDest) {
 	}
 }
 
+// runSrcToDynamicDests publishes each log event of the src to the LogDest of
+// its log group and stream, which is created by the backend the first time.
+// The events of the names past maxDynamicDests are published to the fallback
+// names of the src, and the dests that have been idle for
+// dynamicDestIdleTimeout are released.
+func (l *LogAgent) runSrcToDynamicDests(src DynamicLogSrc, backend LogBackend) {
+	eventsCh := make(chan LogEvent)
+	defer src.Stop()
+	defer l.forgetSrc(src)
+
+	closed := false
+	src.SetOutput(func(e LogEvent) {
+		if closed {
+			return
+		}
+		if e == nil {
+			close(eventsCh)
+			closed = true
+			log.Printf("I! [logagent] Log src has stopped for %v", src.Description())
+			return
+		}
+		eventsCh <- e
+	})
+
+	dname := src.Destination()
+	dests := newDynamicDests(func(group, stream string) LogDest {
+		retention := l.checkRetentionAlreadyAttempted(src.Retention(), group)
+		log.Printf("I! [logagent] piping log from %s/%s(%s) to %s with retention %d", group, stream, src.Description(), dname, retention)
+		return backend.CreateDest(group, stream, retention, src.Class(), src)
+	})
+	// once the src stops, the dests are released when they are idle, since
+	// their last log events may still be being published
+	defer time.AfterFunc(dynamicDestIdleTimeout, func() { dests.evict(time.Now()) })
+	t := time.NewTicker(dynamicDestIdleTimeout / 4)
+	defer t.Stop()
+
+	for {
+		var e LogEvent
+		select {
+		case now := <-t.C:
+			dests.evict(now.Add(-dynamicDestIdleTimeout))
+			continue
+		case event, ok := <-eventsCh:
+			if !ok {
+				return
+			}
+			e = event
+		}
+		group, stream := src.Target(e)
+		dest := dests.get(group, stream, false)
+		if dest == nil {
+			group, stream = src.FallbackTarget()
+			if !dests.overflowed {
+				dests.overflowed = true
+				log.Printf("W! [logagent] Log src %v publishes to more than %d log groups and streams, the log events of the other ones are published to %s/%s", src.Description(), maxDynamicDests, group, stream)
+			}
+			dest = dests.get(group, stream, true)
+		}
+		err := dest.Publish([]LogEvent{e})
+		if err == ErrOutputStopped {
+			log.Printf("I! [logagent] Log destination %v has stopped, finalizing %v", dname, src.Description())
+			return
+		}
+		if err != nil {
+			log.Printf("E! [logagent] Failed to publish log to %v, error: %v", dname, err)
+			return
+		}
+	}
+}
+
+const (
+	// maxDynamicDests is the max number of log groups and streams that a
+	// DynamicLogSrc publishes to at once.
+	maxDynamicDests = 1000
+	// dynamicDestIdleTimeout is how long the LogDest of a DynamicLogSrc is
+	// kept without log events before it is released.
+	dynamicDestIdleTimeout = 10 * time.Minute
+)
+
+// dynamicDests are the LogDest of the log groups and streams of a
+// DynamicLogSrc.
+type dynamicDests struct {
+	create     func(group, stream string) LogDest
+	dests      map[[2]string]*dynamicDest
+	overflowed bool
+}
+
+type dynamicDest struct {
+	LogDest
+	lastUsed time.Time
+}
+
+func newDynamicDests(create func(group, stream string) LogDest) *dynamicDests {
+	return &dynamicDests{create: create, dests: make(map[[2]string]*dynamicDest)}
+}
+
+// get returns the LogDest of the names, which is created if there are fewer
+// than maxDynamicDests of them or if force is set. Returns nil otherwise.
+func (d *dynamicDests) get(group, stream string, force bool) LogDest {
+	key := [2]string{group, stream}
+	dest, ok := d.dests[key]
+	if !ok {
+		if len(d.dests) >= maxDynamicDests && !force {
+			return nil
+		}
+		dest = &dynamicDest{LogDest: d.create(group, stream)}
+		d.dests[key] = dest
+	}
+	dest.lastUsed = time.Now()
+	return dest.LogDest
+}
+
+// evict releases the dests that have not been used since before.
+func (d *dynamicDests) evict(before time.Time) {
+	for key, dest := range d.dests {
+		if dest.lastUsed.Before(before) {
+			delete(d.dests, key)
+			if r, ok := dest.LogDest.(ReleasableLogDest); ok {
+				r.Release()
+			}
+		}
+	}
+}
+
+// runMultiRoute creates a LogDest for each of the routes and pipes the log
+// events of the src to all of them.
+func (l *LogAgent) runMultiRoute(src LogSrc, routes []LogRoute) {
+	var dests []LogDest
+	var destRoutes []LogRoute
+	for _, route := range routes {
+		dname := route.Destination()
+		backend, ok := l.backends[dname]
+		if !ok {
+			log.Printf("E! [logagent] Failed to find destination %s for log source %s/%s(%s) ", dname, route.Group(), route.Stream(), src.Description())
+			continue
+		}
+		retention := l.checkRetentionAlreadyAttempted(route.Retention(), route.Group())
+		dest := backend.CreateDest(route.Group(), route.Stream(), retention, route.Class(), src)
+		l.destNames[dest] = dname
+		log.Printf("I! [logagent] piping log from %s/%s(%s) to %s with retention %d", route.Group(), route.Stream(), src.Description(), dname, retention)
+		dests = append(dests, dest)
+		destRoutes = append(destRoutes, route)
+	}
+	if len(dests) == 0 {
+		return
+	}
+	go l.runSrcToDests(src, destRoutes, dests)
+}
+
+// runSrcToDests publishes each log event of the src to every dest whose
+// route accepts it. The event is only acknowledged to the src once all of
+// them are done with it.
+func (l *LogAgent) runSrcToDests(src LogSrc, routes []LogRoute, dests []LogDest) {
+	eventsCh := make(chan LogEvent)
+	defer src.Stop()
+	defer l.forgetSrc(src)
+
+	closed := false
+	src.SetOutput(func(e LogEvent) {
+		if closed {
+			return
+		}
+		if e == nil {
+			close(eventsCh)
+			closed = true
+			log.Printf("I! [logagent] Log src has stopped for %v", src.Description())
+			return
+		}
+		eventsCh <- e
+	})
+
+	acks := &ackBatcher{}
+	defer acks.stop()
+	t := time.NewTicker(ackFlushInterval)
+	defer t.Stop()
+
+	matched := make([]LogDest, 0, len(dests))
+	for {
+		var e LogEvent
+		select {
+		case <-t.C:
+			acks.flush()
+			continue
+		case event, ok := <-eventsCh:
+			if !ok {
+				return
+			}
+			e = event
+		}
+		matched = matched[:0]
+		for i, route := range routes {
+			if route.ShouldPublish(e) {
+				matched = append(matched, dests[i])
+			}
+		}
+		if len(matched) == 0 {
+			acks.ack(e)
+			continue
+		}
+		fe := &fanOutLogEvent{LogEvent: e, acks: acks}
+		fe.pending.Store(int32(len(matched)))
+		for _, dest := range matched {
+			err := dest.Publish([]LogEvent{fe})
+			if err == ErrOutputStopped {
+				log.Printf("I! [logagent] Log destination %v has stopped, finalizing %v", l.destNames[dest], src.Description())
+				return
+			}
+			if err != nil {
+				log.Printf("E! [logagent] Failed to publish log to %v, error: %v", l.destNames[dest], err)
+				return
+			}
+		}
+	}
+}
+
+// fanOutLogEvent is a LogEvent published to several destinations. It hides
+// the state of the original event from the destinations, so the offset is
+// only advanced when the last destination is done with it.
+type fanOutLogEvent struct {
+	LogEvent
+	pending atomic.Int32
+	acks    *ackBatcher
+}
+
+func (e *fanOutLogEvent) Done() {
+	if e.pending.Add(-1) == 0 {
+		e.acks.ack(e.LogEvent)
+	}
+}
+
+// ackFlushInterval is how often the merged ranges are enqueued, which is the
+// interval the state managers save at by default.
+const ackFlushInterval = 100 * time.Millisecond
+
+// ackBatcher acknowledges the events of a src. The ranges of the events that
+// are acknowledged one after the other are merged, and enqueued on every flush
+// rather than once per event, so that the state queue does not overflow.
+type ackBatcher struct {
+	mu      sync.Mutex
+	batcher *state.RangeQueueBatcher
+	end     uint64
+	stopped bool
+}
+
+// ack marks the event as done and advances its offset if it has one.
+func (b *ackBatcher) ack(e LogEvent) {
+	if sle, ok := e.(StatefulLogEvent); ok {
+		b.merge(sle.RangeQueue(), sle.Range())
+	}
+	e.Done()
+}
+
+func (b *ackBatcher) merge(queue state.FileRangeQueue, r state.Range) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	// The batcher keeps the min start and max end, so only a range that
+	// continues the merged ones is added to it.
+	if b.batcher != nil && r.StartOffset() != b.end {
+		b.flushLocked()
+	}
+	if b.batcher == nil {
+		b.batcher = state.NewRangeQueueBatcher(queue)
+	}
+	b.batcher.Merge(r)
+	b.end = r.EndOffset()
+	if b.stopped {
+		b.flushLocked()
+	}
+}
+
+func (b *ackBatcher) flush() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.flushLocked()
+}
+
+func (b *ackBatcher) flushLocked() {
+	if b.batcher != nil {
+		b.batcher.Done()
+		b.batcher = nil
+	}
+}
+
+// stop flushes the merged ranges. The ranges of the events that the dests are
+// done with after it are enqueued right away.
+func (b *ackBatcher) stop() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.stopped = true
+	b.flushLocked()
+}
+
 func (l *LogAgent) checkRetentionAlreadyAttempted(retention int, logGroup string) int {
+	l.retentionMu.Lock()
+	defer l.retentionMu.Unlock()
 	if retention > 0 && l.retentionAlreadyAttempted[logGroup] {
 		log.Printf("D! [logagent] Retention already set for log group %s, current retention %d", logGroup, retention)
 		retention = -1
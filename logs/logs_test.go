@@ -4,10 +4,18 @@
 package logs
 
 import (
+	"context"
+	"fmt"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/influxdata/telegraf"
 	"github.com/influxdata/telegraf/config"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal/state"
 )
 
 func TestRetentionAlreadySet(t *testing.T) {
@@ -20,3 +28,316 @@ func TestRetentionAlreadySet(t *testing.T) {
 	assert.Equal(t, -1, secondAttempt)
 	assert.True(t, l.retentionAlreadyAttempted["logGroup1"])
 }
+
+type stubRoute struct {
+	group  string
+	accept func(LogEvent) bool
+}
+
+func (r stubRoute) Group() string                 { return r.group }
+func (r stubRoute) Stream() string                { return "stream" }
+func (r stubRoute) Destination() string           { return "stub" }
+func (r stubRoute) Retention() int                { return -1 }
+func (r stubRoute) Class() string                 { return "" }
+func (r stubRoute) ShouldPublish(e LogEvent) bool { return r.accept(e) }
+
+type stubSrc struct {
+	LogSrc
+	events  []LogEvent
+	stopped chan struct{}
+}
+
+func (s *stubSrc) SetOutput(fn func(LogEvent)) {
+	go func() {
+		for _, e := range s.events {
+			fn(e)
+		}
+		fn(nil)
+	}()
+}
+
+func (s *stubSrc) Description() string { return "stub" }
+func (s *stubSrc) Stop()               { close(s.stopped) }
+
+type stubDest struct {
+	mu        sync.Mutex
+	published []LogEvent
+}
+
+func (d *stubDest) Publish(events []LogEvent) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.published = append(d.published, events...)
+	return nil
+}
+
+func (d *stubDest) done() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for _, e := range d.published {
+		e.Done()
+	}
+	d.published = nil
+}
+
+type stubQueue struct {
+	mu     sync.Mutex
+	ranges []state.Range
+}
+
+func (q *stubQueue) ID() string { return "stub" }
+
+func (q *stubQueue) Enqueue(r state.Range) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.ranges = append(q.ranges, r)
+}
+
+func (q *stubQueue) enqueued() []state.Range {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.ranges
+}
+
+type stubEvent struct {
+	msg   string
+	r     state.Range
+	queue *stubQueue
+}
+
+func (e stubEvent) Message() string                  { return e.msg }
+func (e stubEvent) Time() time.Time                  { return time.Time{} }
+func (e stubEvent) Done()                            {}
+func (e stubEvent) Range() state.Range               { return e.r }
+func (e stubEvent) RangeQueue() state.FileRangeQueue { return e.queue }
+
+func TestRunSrcToDests(t *testing.T) {
+	queue := &stubQueue{}
+	src := &stubSrc{
+		events: []LogEvent{
+			stubEvent{msg: "both", r: state.NewRange(0, 5), queue: queue},
+			stubEvent{msg: "second", r: state.NewRange(5, 12), queue: queue},
+			stubEvent{msg: "none", r: state.NewRange(12, 17), queue: queue},
+		},
+		stopped: make(chan struct{}),
+	}
+	routes := []LogRoute{
+		stubRoute{group: "first", accept: func(e LogEvent) bool { return e.Message() == "both" }},
+		stubRoute{group: "second", accept: func(e LogEvent) bool { return e.Message() != "none" }},
+	}
+	first, second := &stubDest{}, &stubDest{}
+
+	l := NewLogAgent(config.NewConfig())
+	l.runSrcToDests(src, routes, []LogDest{first, second})
+	<-src.stopped
+
+	assert.Len(t, first.published, 1)
+	assert.Len(t, second.published, 2)
+	// the event not published to any destination is acknowledged right away
+	assert.Equal(t, []state.Range{state.NewRange(12, 17)}, queue.enqueued())
+
+	second.done()
+	assert.Equal(t, []state.Range{state.NewRange(12, 17), state.NewRange(5, 12)}, queue.enqueued())
+	first.done()
+	assert.Equal(t, []state.Range{state.NewRange(12, 17), state.NewRange(5, 12), state.NewRange(0, 5)}, queue.enqueued())
+}
+
+func TestAckBatcher(t *testing.T) {
+	queue := &stubQueue{}
+	acks := &ackBatcher{}
+	acks.ack(stubEvent{r: state.NewRange(0, 5), queue: queue})
+	acks.ack(stubEvent{r: state.NewRange(5, 12), queue: queue})
+	assert.Empty(t, queue.enqueued())
+	// a range that does not continue the merged ones starts a new batch
+	acks.ack(stubEvent{r: state.NewRange(20, 25), queue: queue})
+	assert.Equal(t, []state.Range{state.NewRange(0, 12)}, queue.enqueued())
+	acks.ack(stubEvent{r: state.NewRange(12, 20), queue: queue})
+	acks.flush()
+	assert.Equal(t, []state.Range{state.NewRange(0, 12), state.NewRange(20, 25), state.NewRange(12, 20)}, queue.enqueued())
+
+	acks.ack(stubEvent{r: state.NewRange(25, 30), queue: queue})
+	acks.stop()
+	acks.ack(stubEvent{r: state.NewRange(30, 31), queue: queue})
+	assert.Equal(t, []state.Range{state.NewRange(0, 12), state.NewRange(20, 25), state.NewRange(12, 20),
+		state.NewRange(25, 30), state.NewRange(30, 31)}, queue.enqueued())
+}
+
+type dynamicSrc struct {
+	stubSrc
+}
+
+func (s *dynamicSrc) Destination() string { return "stub" }
+func (s *dynamicSrc) Retention() int      { return 7 }
+func (s *dynamicSrc) Class() string       { return "" }
+func (s *dynamicSrc) IsDynamic() bool     { return true }
+
+func (s *dynamicSrc) Target(e LogEvent) (string, string) {
+	return "group-" + e.Message(), "stream"
+}
+
+func (s *dynamicSrc) FallbackTarget() (string, string) {
+	return "group-unknown", "stream"
+}
+
+type stubBackend struct {
+	dests      map[string]*stubDest
+	retentions map[string]int
+}
+
+func (b *stubBackend) CreateDest(group, _ string, retention int, _ string, _ LogSrc) LogDest {
+	d := &stubDest{}
+	b.dests[group] = d
+	b.retentions[group] = retention
+	return d
+}
+
+func TestRunSrcToDynamicDests(t *testing.T) {
+	src := &dynamicSrc{stubSrc{
+		events: []LogEvent{
+			stubEvent{msg: "a"},
+			stubEvent{msg: "b"},
+			stubEvent{msg: "a"},
+		},
+		stopped: make(chan struct{}),
+	}}
+	backend := &stubBackend{dests: map[string]*stubDest{}, retentions: map[string]int{}}
+
+	l := NewLogAgent(config.NewConfig())
+	l.checkRetentionAlreadyAttempted(7, "group-b")
+	l.runSrcToDynamicDests(src, backend)
+	<-src.stopped
+
+	assert.Len(t, backend.dests, 2)
+	assert.Len(t, backend.dests["group-a"].published, 2)
+	assert.Len(t, backend.dests["group-b"].published, 1)
+	assert.Equal(t, map[string]int{"group-a": 7, "group-b": -1}, backend.retentions)
+}
+
+type releasableDest struct {
+	stubDest
+	released int
+}
+
+func (d *releasableDest) Release() {
+	d.released++
+}
+
+func TestDynamicDests(t *testing.T) {
+	created := map[string]*releasableDest{}
+	dests := newDynamicDests(func(group, _ string) LogDest {
+		d := &releasableDest{}
+		created[group] = d
+		return d
+	})
+	for i := 0; i < maxDynamicDests; i++ {
+		require.NotNil(t, dests.get(fmt.Sprint(i), "stream", false))
+	}
+	assert.Same(t, created["0"], dests.get("0", "stream", false))
+	// the names past the max are published to the fallback
+	assert.Nil(t, dests.get("overflow", "stream", false))
+	assert.NotNil(t, dests.get("fallback", "stream", true))
+	assert.Len(t, created, maxDynamicDests+1)
+
+	for _, dest := range dests.dests {
+		dest.lastUsed = dest.lastUsed.Add(-time.Hour)
+	}
+	dests.get("0", "stream", false)
+	dests.evict(time.Now().Add(-time.Minute))
+	assert.Len(t, dests.dests, 1)
+	assert.Zero(t, created["0"].released)
+	assert.Equal(t, 1, created["1"].released)
+	assert.Equal(t, 1, created["fallback"].released)
+	// the evicted names can be published to again
+	assert.NotNil(t, dests.get("overflow", "stream", false))
+}
+
+type stubCollection struct {
+	srcs    []LogSrc
+	stopped bool
+}
+
+func (c *stubCollection) FindLogSrc() []LogSrc {
+	srcs := c.srcs
+	c.srcs = nil
+	return srcs
+}
+
+func (c *stubCollection) Start(telegraf.Accumulator) error  { return nil }
+func (c *stubCollection) Stop()                             { c.stopped = true }
+func (c *stubCollection) SampleConfig() string              { return "" }
+func (c *stubCollection) Description() string               { return "" }
+func (c *stubCollection) Gather(telegraf.Accumulator) error { return nil }
+
+// tailSrc publishes nothing until it is stopped, like a tailer of a file
+// that is not written to.
+type tailSrc struct {
+	stubSrc
+	group string
+	done  chan struct{}
+	once  sync.Once
+}
+
+func newTailSrc(group string) *tailSrc {
+	return &tailSrc{stubSrc: stubSrc{stopped: make(chan struct{})}, group: group, done: make(chan struct{})}
+}
+
+func (s *tailSrc) SetOutput(fn func(LogEvent)) {
+	go func() {
+		<-s.done
+		fn(nil)
+	}()
+}
+
+func (s *tailSrc) Group() string       { return s.group }
+func (s *tailSrc) Stream() string      { return "stream" }
+func (s *tailSrc) Destination() string { return "stub" }
+func (s *tailSrc) Retention() int      { return -1 }
+func (s *tailSrc) Class() string       { return "" }
+
+func (s *tailSrc) Stop() {
+	s.once.Do(func() {
+		close(s.done)
+		close(s.stopped)
+	})
+}
+
+func TestAddRemoveCollection(t *testing.T) {
+	keptSrc, removedSrc := newTailSrc("kept"), newTailSrc("removed")
+	kept := &stubCollection{srcs: []LogSrc{keptSrc}}
+	removed := &stubCollection{srcs: []LogSrc{removedSrc}}
+
+	l := NewLogAgent(config.NewConfig())
+	l.backends["stub"] = &stubBackend{dests: map[string]*stubDest{}, retentions: map[string]int{}}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go l.Run(ctx)
+
+	require.NoError(t, l.AddCollection(kept))
+	require.NoError(t, l.AddCollection(removed))
+	assert.Eventually(t, func() bool {
+		l.collectionsMu.Lock()
+		defer l.collectionsMu.Unlock()
+		return len(l.srcs[kept]) == 1 && len(l.srcs[removed]) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+
+	l.RemoveCollection(removed)
+	<-removedSrc.stopped
+	assert.True(t, removed.stopped)
+	assert.False(t, kept.stopped)
+	select {
+	case <-keptSrc.stopped:
+		t.Fatal("the log source of the kept collection was stopped")
+	default:
+	}
+	l.collectionsMu.Lock()
+	assert.Equal(t, []LogCollection{kept}, l.collections)
+	l.collectionsMu.Unlock()
+
+	// the src is forgotten once it stops on its own
+	keptSrc.Stop()
+	assert.Eventually(t, func() bool {
+		l.collectionsMu.Lock()
+		defer l.collectionsMu.Unlock()
+		return len(l.srcs[kept]) == 0
+	}, 5*time.Second, 10*time.Millisecond)
+}
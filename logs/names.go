@@ -0,0 +1,25 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package logs
+
+import "regexp"
+
+var (
+	// the characters that are not allowed in log group and stream names
+	invalidLogGroupNameChars  = regexp.MustCompile(`[^A-Za-z0-9_.\-/#]`)
+	invalidLogStreamNameChars = regexp.MustCompile(`[:*]`)
+)
+
+// SanitizeLogGroupName replaces the characters that are not allowed in log
+// group names with "_", e.g. in the values that the inputs resolve the
+// placeholders of a log group name to.
+func SanitizeLogGroupName(value string) string {
+	return invalidLogGroupNameChars.ReplaceAllString(value, "_")
+}
+
+// SanitizeLogStreamName replaces the characters that are not allowed in log
+// stream names with "_".
+func SanitizeLogStreamName(value string) string {
+	return invalidLogStreamNameChars.ReplaceAllString(value, "_")
+}
@@ -0,0 +1,16 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package logs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSanitizeNames(t *testing.T) {
+	assert.Equal(t, "/apps/web_api_#1.log-x", SanitizeLogGroupName("/apps/web api:#1.log-x"))
+	assert.Equal(t, "caf_", SanitizeLogGroupName("café"))
+	assert.Equal(t, "host_1 my app_é", SanitizeLogStreamName("host:1 my app*é"))
+}
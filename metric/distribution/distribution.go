@@ -45,10 +45,40 @@ type Distribution interface {
 	ConvertToOtel(dp pmetric.HistogramDataPoint)
 
 	ConvertFromOtel(dp pmetric.HistogramDataPoint, unit string)
+
+	ConvertFromOtelExponentialHistogram(dp pmetric.ExponentialHistogramDataPoint, unit string)
 }
 
 var NewDistribution func() Distribution
 
+// RangeExponentialHistogram calls fn with a representative value and count for
+// the zero bucket and each non-empty positive bucket of the data point. The
+// representative value is the midpoint of the bucket boundaries. Negative
+// buckets are not supported, so their total count is returned instead.
+func RangeExponentialHistogram(dp pmetric.ExponentialHistogramDataPoint, fn func(value, count float64)) (negativeCount uint64) {
+	if dp.ZeroCount() > 0 {
+		fn(0, float64(dp.ZeroCount()))
+	}
+	// Bucket i covers (base^i, base^(i+1)] where base = 2^(2^-scale).
+	exponent := math.Exp2(-float64(dp.Scale()))
+	positive := dp.Positive()
+	for i := 0; i < positive.BucketCounts().Len(); i++ {
+		count := positive.BucketCounts().At(i)
+		if count == 0 {
+			continue
+		}
+		index := float64(positive.Offset()) + float64(i)
+		lower := math.Exp2(index * exponent)
+		upper := math.Exp2((index + 1) * exponent)
+		fn(lower+(upper-lower)/2, float64(count))
+	}
+	negative := dp.Negative().BucketCounts()
+	for i := 0; i < negative.Len(); i++ {
+		negativeCount += negative.At(i)
+	}
+	return negativeCount
+}
+
 // IsSupportedValue checks to see if the metric is between the min value and 2^360 and not a NaN.
 // This matches the accepted range described in the MetricDatum documentation
 // https://docs.aws.amazon.com/AmazonCloudWatch/latest/APIReference/API_MetricDatum.html
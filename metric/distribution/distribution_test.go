@@ -8,6 +8,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/pdata/pmetric"
 )
 
 func TestIsAcceptedValue(t *testing.T) {
@@ -28,3 +29,34 @@ func TestIsAcceptedValue(t *testing.T) {
 		assert.Equal(t, testCase.want, IsSupportedValue(testCase.input, MinValue, MaxValue))
 	}
 }
+
+func TestRangeExponentialHistogram(t *testing.T) {
+	dp := pmetric.NewExponentialHistogramDataPoint()
+	dp.SetScale(0)
+	dp.SetZeroCount(2)
+	dp.Positive().SetOffset(1)
+	// (2, 4], (4, 8], (8, 16]
+	dp.Positive().BucketCounts().FromRaw([]uint64{3, 0, 5})
+	dp.Negative().BucketCounts().FromRaw([]uint64{1, 4})
+
+	var values, counts []float64
+	negativeCount := RangeExponentialHistogram(dp, func(value, count float64) {
+		values = append(values, value)
+		counts = append(counts, count)
+	})
+	assert.Equal(t, []float64{0, 3, 12}, values)
+	assert.Equal(t, []float64{2, 3, 5}, counts)
+	assert.EqualValues(t, 5, negativeCount)
+
+	// Scale 1 has base sqrt(2), so bucket -1 is (2^-0.5, 1].
+	dp = pmetric.NewExponentialHistogramDataPoint()
+	dp.SetScale(1)
+	dp.Positive().SetOffset(-1)
+	dp.Positive().BucketCounts().FromRaw([]uint64{1})
+	values = values[:0]
+	negativeCount = RangeExponentialHistogram(dp, func(value, _ float64) {
+		values = append(values, value)
+	})
+	assert.InDelta(t, (math.Sqrt(0.5)+1)/2, values[0], 1e-9)
+	assert.EqualValues(t, 0, negativeCount)
+}
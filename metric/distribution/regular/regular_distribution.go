@@ -173,6 +173,34 @@ func (rd *RegularDistribution) ConvertFromOtel(dp pmetric.HistogramDataPoint, un
 	}
 }
 
+// ConvertFromOtelExponentialHistogram adds the midpoint of each bucket with the
+// bucket count as its weight. The exact sum, minimum and maximum from the data
+// point replace the estimated ones unless negative values had to be dropped.
+func (rd *RegularDistribution) ConvertFromOtelExponentialHistogram(dp pmetric.ExponentialHistogramDataPoint, unit string) {
+	negativeCount := distribution.RangeExponentialHistogram(dp, func(value, count float64) {
+		if err := rd.AddEntryWithUnit(value, count, unit); err != nil {
+			log.Printf("D! cannot add exponential histogram bucket: %v", err)
+		}
+	})
+	rd.unit = unit
+	if negativeCount > 0 {
+		log.Printf("D! dropped %v negative values from exponential histogram", negativeCount)
+		return
+	}
+	if rd.sampleCount == 0 {
+		return
+	}
+	if dp.HasSum() {
+		rd.sum = dp.Sum()
+	}
+	if dp.HasMin() {
+		rd.minimum = dp.Min()
+	}
+	if dp.HasMax() {
+		rd.maximum = dp.Max()
+	}
+}
+
 func (regularDist *RegularDistribution) GetCount(value float64) float64 {
 	return regularDist.buckets[value]
 }
@@ -186,6 +186,34 @@ func (sd *SEH1Distribution) ConvertFromOtel(dp pmetric.HistogramDataPoint, unit
 	}
 }
 
+// ConvertFromOtelExponentialHistogram adds the midpoint of each bucket with the
+// bucket count as its weight. The exact sum, minimum and maximum from the data
+// point replace the estimated ones unless negative values had to be dropped.
+func (sd *SEH1Distribution) ConvertFromOtelExponentialHistogram(dp pmetric.ExponentialHistogramDataPoint, unit string) {
+	negativeCount := distribution.RangeExponentialHistogram(dp, func(value, count float64) {
+		if err := sd.AddEntryWithUnit(value, count, unit); err != nil {
+			log.Printf("D! cannot add exponential histogram bucket: %v", err)
+		}
+	})
+	sd.unit = unit
+	if negativeCount > 0 {
+		log.Printf("D! dropped %v negative values from exponential histogram", negativeCount)
+		return
+	}
+	if sd.sampleCount == 0 {
+		return
+	}
+	if dp.HasSum() {
+		sd.sum = dp.Sum()
+	}
+	if dp.HasMin() {
+		sd.minimum = dp.Min()
+	}
+	if dp.HasMax() {
+		sd.maximum = dp.Max()
+	}
+}
+
 func (seh1Distribution *SEH1Distribution) CanAdd(value float64, sizeLimit int) bool {
 	if seh1Distribution.Size() < sizeLimit {
 		return true
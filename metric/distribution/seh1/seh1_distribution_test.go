@@ -9,6 +9,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/pdata/pmetric"
 
 	"github.com/aws/amazon-cloudwatch-agent/metric/distribution"
 )
@@ -108,3 +109,39 @@ func cloneSEH1Distribution(dist *SEH1Distribution) *SEH1Distribution {
 func truncate(f float64) string {
 	return big.NewFloat(f).SetPrec(100).String()
 }
+
+func TestConvertFromOtelExponentialHistogram(t *testing.T) {
+	dp := pmetric.NewExponentialHistogramDataPoint()
+	dp.SetScale(0)
+	dp.SetZeroCount(1)
+	dp.Positive().SetOffset(1)
+	dp.Positive().BucketCounts().FromRaw([]uint64{3, 0, 5})
+	dp.SetCount(9)
+	dp.SetSum(75)
+	dp.SetMin(0)
+	dp.SetMax(15)
+
+	dist := NewSEH1Distribution()
+	dist.ConvertFromOtelExponentialHistogram(dp, "Milliseconds")
+	assert.Equal(t, 9.0, dist.SampleCount())
+	assert.Equal(t, 75.0, dist.Sum())
+	assert.Equal(t, 0.0, dist.Minimum())
+	assert.Equal(t, 15.0, dist.Maximum())
+	assert.Equal(t, "Milliseconds", dist.Unit())
+	assert.Equal(t, 3, dist.Size())
+	_, counts := dist.ValuesAndCounts()
+	var total float64
+	for _, count := range counts {
+		total += count
+	}
+	assert.Equal(t, 9.0, total)
+
+	// The statistics are estimated from the buckets if negative values are dropped.
+	dp.Negative().BucketCounts().FromRaw([]uint64{2})
+	dp.SetMin(-1)
+	dist = NewSEH1Distribution()
+	dist.ConvertFromOtelExponentialHistogram(dp, "")
+	assert.Equal(t, 9.0, dist.SampleCount())
+	assert.Equal(t, 0.0, dist.Minimum())
+	assert.Equal(t, 3.0*3+12*5, dist.Sum())
+}
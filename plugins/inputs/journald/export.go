@@ -0,0 +1,106 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package journald
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+const (
+	cursorField            = "__CURSOR"
+	realtimeTimestampField = "__REALTIME_TIMESTAMP"
+	// maxFieldSize bounds the size of a binary field to protect against a
+	// corrupt stream.
+	maxFieldSize = 64 * 1024 * 1024
+)
+
+// exportReader parses the journal export format written by
+// "journalctl -o export". Entries are separated by an empty line. Each field
+// is either "KEY=value\n" or, for values that are binary or contain newlines,
+// "KEY\n" followed by the value length as a little-endian uint64, the value
+// and "\n".
+// See https://systemd.io/JOURNAL_EXPORT_FORMATS/
+type exportReader struct {
+	r *bufio.Reader
+}
+
+func newExportReader(r io.Reader) *exportReader {
+	return &exportReader{r: bufio.NewReader(r)}
+}
+
+// Next returns the fields of the next entry. Returns io.EOF once the stream
+// ends between entries.
+func (er *exportReader) Next() (map[string]string, error) {
+	var entry map[string]string
+	for {
+		line, err := er.r.ReadBytes('\n')
+		if err != nil {
+			if errors.Is(err, io.EOF) && len(line) == 0 {
+				if entry != nil {
+					// The last entry is not followed by an empty line.
+					return entry, nil
+				}
+				return nil, io.EOF
+			}
+			if errors.Is(err, io.EOF) {
+				err = io.ErrUnexpectedEOF
+			}
+			return nil, err
+		}
+		line = line[:len(line)-1]
+		if len(line) == 0 {
+			if entry == nil {
+				continue
+			}
+			return entry, nil
+		}
+		if entry == nil {
+			entry = make(map[string]string)
+		}
+		if i := bytes.IndexByte(line, '='); i >= 0 {
+			entry[string(line[:i])] = string(line[i+1:])
+			continue
+		}
+		value, err := er.readBinaryValue()
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for field %s: %w", line, err)
+		}
+		entry[string(line)] = value
+	}
+}
+
+func (er *exportReader) readBinaryValue() (string, error) {
+	var size uint64
+	if err := binary.Read(er.r, binary.LittleEndian, &size); err != nil {
+		return "", err
+	}
+	if size > maxFieldSize {
+		return "", fmt.Errorf("size %d exceeds %d", size, maxFieldSize)
+	}
+	value := make([]byte, size+1)
+	if _, err := io.ReadFull(er.r, value); err != nil {
+		return "", err
+	}
+	if value[size] != '\n' {
+		return "", errors.New("missing newline after binary value")
+	}
+	return string(value[:size]), nil
+}
+
+// entryTime returns the wallclock time at which the entry was received by
+// the journal.
+func entryTime(entry map[string]string) time.Time {
+	us, err := strconv.ParseInt(entry[realtimeTimestampField], 10, 64)
+	if err != nil {
+		return time.Time{}
+	}
+	return time.UnixMicro(us)
+}
@@ -0,0 +1,68 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package journald
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func binaryField(key, value string) string {
+	var buf bytes.Buffer
+	buf.WriteString(key + "\n")
+	_ = binary.Write(&buf, binary.LittleEndian, uint64(len(value)))
+	buf.WriteString(value + "\n")
+	return buf.String()
+}
+
+func TestExportReader(t *testing.T) {
+	input := "__CURSOR=s=1\n__REALTIME_TIMESTAMP=1700000000000001\nMESSAGE=first\n\n" +
+		"__CURSOR=s=2\n" + binaryField("MESSAGE", "multi\nline") + "PRIORITY=6\n\n" +
+		"__CURSOR=s=3\nMESSAGE=a=b\n"
+	er := newExportReader(strings.NewReader(input))
+
+	entry, err := er.Next()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"__CURSOR":             "s=1",
+		"__REALTIME_TIMESTAMP": "1700000000000001",
+		"MESSAGE":              "first",
+	}, entry)
+	assert.Equal(t, time.UnixMicro(1700000000000001), entryTime(entry))
+
+	entry, err = er.Next()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"__CURSOR": "s=2", "MESSAGE": "multi\nline", "PRIORITY": "6"}, entry)
+	assert.True(t, entryTime(entry).IsZero())
+
+	entry, err = er.Next()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"__CURSOR": "s=3", "MESSAGE": "a=b"}, entry)
+
+	_, err = er.Next()
+	assert.ErrorIs(t, err, io.EOF)
+}
+
+func TestExportReader_Truncated(t *testing.T) {
+	field := binaryField("MESSAGE", "binary")
+	testCases := map[string]string{
+		"PartialLine":    "__CURSOR=s=1\nMESSAGE=par",
+		"PartialBinary":  "__CURSOR=s=1\n" + field[:12],
+		"MissingNewline": "__CURSOR=s=1\n" + field[:len(field)-1] + "X",
+	}
+	for name, input := range testCases {
+		t.Run(name, func(t *testing.T) {
+			_, err := newExportReader(strings.NewReader(input)).Next()
+			assert.Error(t, err)
+			assert.NotErrorIs(t, err, io.EOF)
+		})
+	}
+}
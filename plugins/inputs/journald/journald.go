@@ -0,0 +1,223 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package journald
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/plugins/inputs"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal/logscommon"
+	"github.com/aws/amazon-cloudwatch-agent/internal/state"
+	"github.com/aws/amazon-cloudwatch-agent/logs"
+)
+
+const (
+	defaultJournalctlPath = "journalctl"
+	stateQueueSize        = 100
+)
+
+var priorities = map[string]string{
+	"emerg":   "0",
+	"alert":   "1",
+	"crit":    "2",
+	"err":     "3",
+	"warning": "4",
+	"notice":  "5",
+	"info":    "6",
+	"debug":   "7",
+}
+
+// JournalConfig is the configuration of a single journal source.
+type JournalConfig struct {
+	// Directory to read the journal files from. Uses the system journal if empty.
+	Directory string `toml:"directory"`
+	// Units only collects entries for these systemd units.
+	Units []string `toml:"units"`
+	// Priority only collects entries with this priority or a more important one.
+	Priority string `toml:"priority"`
+	// Matches only collects entries matching "FIELD=value". Matches for the same
+	// field are combined with OR, matches for different fields with AND.
+	Matches []string `toml:"matches"`
+	// Fields to include in the message. All fields except the journal's
+	// internal address fields are included if empty.
+	Fields []string `toml:"fields"`
+	// FromBeginning reads the whole journal if there is no saved cursor.
+	FromBeginning bool `toml:"from_beginning"`
+
+	LogGroupName  string `toml:"log_group_name"`
+	LogStreamName string `toml:"log_stream_name"`
+	LogGroupClass string `toml:"log_group_class"`
+	Destination   string `toml:"destination"`
+	Retention     int    `toml:"retention_in_days"`
+
+	matches map[string][]string
+}
+
+// init validates the filters of the config.
+func (c *JournalConfig) init() error {
+	if c.Priority != "" {
+		if p, ok := priorities[c.Priority]; ok {
+			c.Priority = p
+		} else if len(c.Priority) != 1 || c.Priority[0] < '0' || c.Priority[0] > '7' {
+			return fmt.Errorf("invalid journald priority %q", c.Priority)
+		}
+	}
+	c.matches = make(map[string][]string)
+	for _, match := range c.Matches {
+		field, value, ok := strings.Cut(match, "=")
+		if !ok || field == "" {
+			return fmt.Errorf("invalid journald match %q, expected FIELD=value", match)
+		}
+		c.matches[field] = append(c.matches[field], value)
+	}
+	return nil
+}
+
+// shouldPublish returns true if the entry satisfies all matches.
+func (c *JournalConfig) shouldPublish(entry map[string]string) bool {
+	for field, values := range c.matches {
+		value, ok := entry[field]
+		if !ok {
+			return false
+		}
+		matched := false
+		for _, v := range values {
+			if v == value {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
+
+// journalctlArgs returns the arguments to stream the journal in export
+// format, resuming after the cursor if there is one. Without a cursor, the
+// stream starts at the beginning of the journal or at its end.
+func (c *JournalConfig) journalctlArgs(cursor string, fromBeginning bool) []string {
+	args := c.filterArgs([]string{"--output=export", "--follow"})
+	if cursor != "" {
+		args = append(args, "--after-cursor="+cursor)
+	} else if fromBeginning {
+		args = append(args, "--no-tail")
+	} else {
+		args = append(args, "--lines=0")
+	}
+	return args
+}
+
+// lastEntryArgs returns the arguments to print the last entry of the journal
+// in export format, or nothing if the journal has no entries.
+func (c *JournalConfig) lastEntryArgs() []string {
+	return append(c.filterArgs([]string{"--output=export", "--quiet"}), "--lines=1")
+}
+
+func (c *JournalConfig) filterArgs(args []string) []string {
+	if c.Directory != "" {
+		args = append(args, "--directory="+c.Directory)
+	}
+	for _, unit := range c.Units {
+		args = append(args, "--unit="+unit)
+	}
+	if c.Priority != "" {
+		args = append(args, "--priority="+c.Priority)
+	}
+	return args
+}
+
+type Plugin struct {
+	FileStateFolder string          `toml:"file_state_folder"`
+	Destination     string          `toml:"destination"`
+	JournalctlPath  string          `toml:"journalctl_path"`
+	Journals        []JournalConfig `toml:"journal_config"`
+	Log             telegraf.Logger `toml:"-"`
+
+	newSrcs []logs.LogSrc
+}
+
+var _ logs.LogCollection = (*Plugin)(nil)
+
+func (p *Plugin) Description() string {
+	return "A plugin to collect systemd journal entries"
+}
+
+func (p *Plugin) SampleConfig() string {
+	return `
+  ## folder path where the journal cursor of each journal_config is stored
+  file_state_folder = "/opt/aws/amazon-cloudwatch-agent/logs/state"
+  destination = "cloudwatchlogs"
+
+  [[inputs.journald.journal_config]]
+    ## Read the journal files in this directory instead of the system journal
+    # directory = "/var/log/journal"
+    units = ["sshd.service"]
+    ## Maximum priority, either a name (emerg ... debug) or a number (0 ... 7)
+    priority = "warning"
+    matches = ["_TRANSPORT=syslog"]
+    ## Journal fields to include in the JSON message, defaults to all fields
+    fields = ["MESSAGE", "PRIORITY", "_SYSTEMD_UNIT", "_HOSTNAME"]
+    ## Read the whole journal when there is no saved cursor
+    from_beginning = false
+    log_group_name = "journald"
+    log_stream_name = "{instance_id}"
+`
+}
+
+func (p *Plugin) Gather(_ telegraf.Accumulator) error {
+	return nil
+}
+
+func (p *Plugin) FindLogSrc() []logs.LogSrc {
+	srcs := p.newSrcs
+	p.newSrcs = nil
+	return srcs
+}
+
+func (p *Plugin) Start(_ telegraf.Accumulator) error {
+	if p.FileStateFolder == "" {
+		return errors.New("empty FileStateFolder")
+	}
+	if err := os.MkdirAll(p.FileStateFolder, 0755); err != nil {
+		return fmt.Errorf("failed to create state file directory %s: %w", p.FileStateFolder, err)
+	}
+	journalctlPath := p.JournalctlPath
+	if journalctlPath == "" {
+		journalctlPath = defaultJournalctlPath
+	}
+	for i := range p.Journals {
+		jc := &p.Journals[i]
+		if err := jc.init(); err != nil {
+			return err
+		}
+		destination := jc.Destination
+		if destination == "" {
+			destination = p.Destination
+		}
+		// Assume no 2 JournalConfigs have the same combination of
+		// LogGroupName and LogStreamName.
+		stateManager := state.NewCursorManager(state.ManagerConfig{
+			StateFileDir:    p.FileStateFolder,
+			StateFilePrefix: logscommon.JournaldPrefix,
+			Name:            jc.LogGroupName + "_" + jc.LogStreamName,
+			QueueSize:       stateQueueSize,
+		})
+		p.newSrcs = append(p.newSrcs, newJournalSrc(jc, destination, stateManager, newJournalctlReader(journalctlPath, jc)))
+	}
+	return nil
+}
+
+func (p *Plugin) Stop() {
+}
+
+func init() {
+	inputs.Add("journald", func() telegraf.Input { return &Plugin{} })
+}
@@ -0,0 +1,241 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package journald
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal/logscommon"
+	"github.com/aws/amazon-cloudwatch-agent/logs"
+)
+
+type mockJournalReader struct {
+	mu            sync.Mutex
+	input         string
+	lastCursor    string
+	cursors       []string
+	fromBeginning []bool
+}
+
+func (r *mockJournalReader) Open(_ context.Context, cursor string, fromBeginning bool) (io.ReadCloser, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cursors = append(r.cursors, cursor)
+	r.fromBeginning = append(r.fromBeginning, fromBeginning)
+	input := r.input
+	r.input = ""
+	return io.NopCloser(strings.NewReader(input)), nil
+}
+
+func (r *mockJournalReader) LastCursor(context.Context) (string, error) {
+	return r.lastCursor, nil
+}
+
+func TestJournalConfig(t *testing.T) {
+	testCases := map[string]struct {
+		config   JournalConfig
+		cursor   string
+		wantErr  bool
+		wantArgs []string
+	}{
+		"Default": {
+			wantArgs: []string{"--output=export", "--follow", "--lines=0"},
+		},
+		"FromBeginning": {
+			config: JournalConfig{
+				Directory:     "/var/log/journal",
+				Units:         []string{"sshd.service", "cron.service"},
+				Priority:      "warning",
+				FromBeginning: true,
+			},
+			wantArgs: []string{
+				"--output=export", "--follow", "--directory=/var/log/journal",
+				"--unit=sshd.service", "--unit=cron.service", "--priority=4", "--no-tail",
+			},
+		},
+		"Cursor": {
+			config:   JournalConfig{Priority: "3", FromBeginning: true},
+			cursor:   "s=1",
+			wantArgs: []string{"--output=export", "--follow", "--priority=3", "--after-cursor=s=1"},
+		},
+		"LastEntry": {
+			config:   JournalConfig{Units: []string{"sshd.service"}},
+			wantArgs: []string{"--output=export", "--quiet", "--unit=sshd.service", "--lines=1"},
+		},
+		"InvalidPriority": {
+			config:  JournalConfig{Priority: "8"},
+			wantErr: true,
+		},
+		"InvalidMatch": {
+			config:  JournalConfig{Matches: []string{"_TRANSPORT"}},
+			wantErr: true,
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			err := testCase.config.init()
+			if testCase.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			if name == "LastEntry" {
+				assert.Equal(t, testCase.wantArgs, testCase.config.lastEntryArgs())
+				return
+			}
+			assert.Equal(t, testCase.wantArgs, testCase.config.journalctlArgs(testCase.cursor, testCase.config.FromBeginning))
+		})
+	}
+}
+
+func TestJournalConfig_ShouldPublish(t *testing.T) {
+	config := JournalConfig{Matches: []string{"_TRANSPORT=syslog", "_TRANSPORT=journal", "_COMM=sshd"}}
+	require.NoError(t, config.init())
+	assert.True(t, config.shouldPublish(map[string]string{"_TRANSPORT": "syslog", "_COMM": "sshd"}))
+	assert.True(t, config.shouldPublish(map[string]string{"_TRANSPORT": "journal", "_COMM": "sshd"}))
+	assert.False(t, config.shouldPublish(map[string]string{"_TRANSPORT": "kernel", "_COMM": "sshd"}))
+	assert.False(t, config.shouldPublish(map[string]string{"_TRANSPORT": "syslog"}))
+}
+
+func TestPlugin(t *testing.T) {
+	tmpDir := t.TempDir()
+	plugin := &Plugin{
+		FileStateFolder: tmpDir,
+		Destination:     "cloudwatchlogs",
+		Journals: []JournalConfig{
+			{LogGroupName: "group", LogStreamName: "stream", Retention: 7, LogGroupClass: "STANDARD"},
+			{LogGroupName: "other", LogStreamName: "stream", Destination: "other", Priority: "bad"},
+		},
+	}
+	assert.Error(t, plugin.Start(nil))
+
+	plugin.Journals = plugin.Journals[:1]
+	plugin.newSrcs = nil
+	require.NoError(t, plugin.Start(nil))
+	srcs := plugin.FindLogSrc()
+	require.Len(t, srcs, 1)
+	assert.Empty(t, plugin.FindLogSrc())
+	src := srcs[0]
+	assert.Equal(t, "group", src.Group())
+	assert.Equal(t, "stream", src.Stream())
+	assert.Equal(t, "cloudwatchlogs", src.Destination())
+	assert.Equal(t, 7, src.Retention())
+	assert.Equal(t, "STANDARD", src.Class())
+	assert.Nil(t, src.Entity())
+}
+
+func TestJournalSrc(t *testing.T) {
+	reopenInterval = time.Millisecond
+	defer func() { reopenInterval = 5 * time.Second }()
+
+	tmpDir := t.TempDir()
+	plugin := &Plugin{
+		FileStateFolder: tmpDir,
+		Journals: []JournalConfig{{
+			LogGroupName:  "group",
+			LogStreamName: "stream",
+			Matches:       []string{"_COMM=sshd"},
+			Fields:        []string{"MESSAGE", "_COMM", "MISSING"},
+		}},
+	}
+	require.NoError(t, plugin.Start(nil))
+	src := plugin.FindLogSrc()[0].(*journalSrc)
+	reader := &mockJournalReader{
+		input: "__CURSOR=s=1\n__REALTIME_TIMESTAMP=1700000000000000\nMESSAGE=accepted\n_COMM=sshd\n\n" +
+			"__CURSOR=s=2\nMESSAGE=ignored\n_COMM=cron\n\n" +
+			"__CURSOR=s=3\n" + binaryField("MESSAGE", "multi\nline") + "_COMM=sshd\n_PID=1\n\n",
+	}
+	src.reader = reader
+
+	events := make(chan logs.LogEvent, 10)
+	src.SetOutput(func(e logs.LogEvent) {
+		events <- e
+	})
+
+	e := <-events
+	assert.Equal(t, `{"MESSAGE":"accepted","_COMM":"sshd"}`, e.Message())
+	assert.Equal(t, time.UnixMicro(1700000000000000), e.Time())
+	e2 := <-events
+	assert.Equal(t, `{"MESSAGE":"multi\nline","_COMM":"sshd"}`, e2.Message())
+	// Out of order acknowledgement still saves the latest cursor.
+	e2.Done()
+	e.Done()
+
+	// The stream is reopened after the last cursor read, including filtered
+	// entries.
+	assert.Eventually(t, func() bool {
+		reader.mu.Lock()
+		defer reader.mu.Unlock()
+		return len(reader.cursors) > 1 && reader.cursors[1] == "s=3"
+	}, time.Second, time.Millisecond)
+
+	src.Stop()
+	assert.Eventually(t, func() bool {
+		select {
+		case e := <-events:
+			return e == nil
+		default:
+			return false
+		}
+	}, time.Second, time.Millisecond)
+
+	stateFile := filepath.Join(tmpDir, logscommon.JournaldPrefix+"group_stream")
+	assert.Eventually(t, func() bool {
+		content, err := os.ReadFile(stateFile)
+		return err == nil && string(content) == "s=3\ngroup_stream"
+	}, time.Second, time.Millisecond)
+	assert.Equal(t, "", reader.cursors[0])
+	assert.True(t, reader.fromBeginning[0], "an empty journal is read from the beginning")
+}
+
+func TestJournalSrc_SeedCursor(t *testing.T) {
+	reopenInterval = time.Millisecond
+	defer func() { reopenInterval = 5 * time.Second }()
+
+	tmpDir := t.TempDir()
+	plugin := &Plugin{
+		FileStateFolder: tmpDir,
+		Journals:        []JournalConfig{{LogGroupName: "group", LogStreamName: "stream"}},
+	}
+	require.NoError(t, plugin.Start(nil))
+	src := plugin.FindLogSrc()[0].(*journalSrc)
+	reader := &mockJournalReader{lastCursor: "s=0"}
+	src.reader = reader
+	events := make(chan logs.LogEvent, 10)
+	src.SetOutput(func(e logs.LogEvent) {
+		events <- e
+	})
+
+	// Without a saved cursor, the journal is read after its last entry, also
+	// when it is reopened, and the cursor is saved for the next start.
+	assert.Eventually(t, func() bool {
+		reader.mu.Lock()
+		defer reader.mu.Unlock()
+		return len(reader.cursors) > 1
+	}, time.Second, time.Millisecond)
+	reader.mu.Lock()
+	assert.Equal(t, []string{"s=0", "s=0"}, reader.cursors[:2])
+	reader.mu.Unlock()
+	stateFile := filepath.Join(tmpDir, logscommon.JournaldPrefix+"group_stream")
+	assert.Eventually(t, func() bool {
+		content, err := os.ReadFile(stateFile)
+		return err == nil && string(content) == "s=0\ngroup_stream"
+	}, time.Second, time.Millisecond)
+	src.Stop()
+	for e := range events {
+		if e == nil {
+			break
+		}
+	}
+}
@@ -0,0 +1,293 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package journald
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal/state"
+	"github.com/aws/amazon-cloudwatch-agent/logs"
+	"github.com/aws/amazon-cloudwatch-agent/sdk/service/cloudwatchlogs"
+)
+
+var (
+	// reopenInterval is the time to wait before reading the journal again
+	// after the journal stream ended.
+	reopenInterval = 5 * time.Second
+)
+
+// journalReader opens a journal stream in export format that starts after the
+// cursor, or at the beginning or the end of the journal if the cursor is empty.
+type journalReader interface {
+	Open(ctx context.Context, cursor string, fromBeginning bool) (io.ReadCloser, error)
+	// LastCursor returns the cursor of the last entry of the journal, or an
+	// empty string if the journal has no entries.
+	LastCursor(ctx context.Context) (string, error)
+}
+
+type journalctlReader struct {
+	path   string
+	config *JournalConfig
+}
+
+func newJournalctlReader(path string, config *JournalConfig) journalReader {
+	return &journalctlReader{path: path, config: config}
+}
+
+func (r *journalctlReader) Open(ctx context.Context, cursor string, fromBeginning bool) (io.ReadCloser, error) {
+	return r.start(ctx, r.config.journalctlArgs(cursor, fromBeginning))
+}
+
+func (r *journalctlReader) LastCursor(ctx context.Context) (string, error) {
+	out, err := exec.CommandContext(ctx, r.path, r.config.lastEntryArgs()...).Output()
+	if err != nil {
+		return "", err
+	}
+	var cursor string
+	er := newExportReader(bytes.NewReader(out))
+	for {
+		entry, err := er.Next()
+		if errors.Is(err, io.EOF) {
+			return cursor, nil
+		}
+		if err != nil {
+			return "", err
+		}
+		cursor = entry[cursorField]
+	}
+}
+
+func (r *journalctlReader) start(ctx context.Context, args []string) (io.ReadCloser, error) {
+	cmd := exec.CommandContext(ctx, r.path, args...)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	if err = cmd.Start(); err != nil {
+		return nil, err
+	}
+	return &journalctlStream{ReadCloser: stdout, cmd: cmd, stderr: &stderr}, nil
+}
+
+type journalctlStream struct {
+	io.ReadCloser
+	cmd    *exec.Cmd
+	stderr *strings.Builder
+}
+
+// Close stops journalctl if it is still running and waits for it to exit.
+func (s *journalctlStream) Close() error {
+	if s.cmd.Process != nil {
+		_ = s.cmd.Process.Kill()
+	}
+	err := s.cmd.Wait()
+	if msg := strings.TrimSpace(s.stderr.String()); msg != "" {
+		log.Printf("D! [journald] journalctl output: %s", msg)
+	}
+	return err
+}
+
+type LogEvent struct {
+	msg    string
+	t      time.Time
+	cursor state.Cursor
+	src    *journalSrc
+}
+
+var _ logs.LogEvent = (*LogEvent)(nil)
+
+func (le LogEvent) Message() string {
+	return le.msg
+}
+
+func (le LogEvent) Time() time.Time {
+	return le.t
+}
+
+// Done saves the cursor of the event once it has been published.
+func (le LogEvent) Done() {
+	le.src.stateManager.Enqueue(le.cursor)
+}
+
+type journalSrc struct {
+	config       *JournalConfig
+	destination  string
+	stateManager state.CursorManager
+	reader       journalReader
+
+	outputFn  func(logs.LogEvent)
+	seq       uint64
+	ctx       context.Context
+	cancel    context.CancelFunc
+	done      chan struct{}
+	startOnce sync.Once
+	stopOnce  sync.Once
+}
+
+var _ logs.LogSrc = (*journalSrc)(nil)
+
+func newJournalSrc(config *JournalConfig, destination string, stateManager state.CursorManager, reader journalReader) *journalSrc {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &journalSrc{
+		config:       config,
+		destination:  destination,
+		stateManager: stateManager,
+		reader:       reader,
+		ctx:          ctx,
+		cancel:       cancel,
+		done:         make(chan struct{}),
+	}
+}
+
+func (j *journalSrc) SetOutput(fn func(logs.LogEvent)) {
+	if fn == nil {
+		return
+	}
+	j.outputFn = fn
+	j.startOnce.Do(func() {
+		go j.stateManager.Run(state.Notification{Done: j.done})
+		go j.run()
+	})
+}
+
+func (j *journalSrc) Group() string {
+	return j.config.LogGroupName
+}
+
+func (j *journalSrc) Stream() string {
+	return j.config.LogStreamName
+}
+
+func (j *journalSrc) Description() string {
+	return "journald " + j.stateManager.ID()
+}
+
+func (j *journalSrc) Destination() string {
+	return j.destination
+}
+
+func (j *journalSrc) Retention() int {
+	return j.config.Retention
+}
+
+func (j *journalSrc) Class() string {
+	return j.config.LogGroupClass
+}
+
+func (j *journalSrc) Stop() {
+	j.stopOnce.Do(func() {
+		close(j.done)
+		j.cancel()
+	})
+}
+
+func (j *journalSrc) Entity() *cloudwatchlogs.Entity {
+	return nil
+}
+
+func (j *journalSrc) run() {
+	defer j.outputFn(nil)
+
+	cursor, _ := j.stateManager.Restore()
+	fromBeginning := j.config.FromBeginning
+	for {
+		if cursor == "" && !fromBeginning {
+			// Start after the last entry rather than at the end of the
+			// journal, so the entries written while the journal is reopened
+			// or the agent is stopped are not skipped.
+			if c, err := j.reader.LastCursor(j.ctx); err != nil {
+				log.Printf("W! [journald] Unable to find the last journal entry for %s: %v", j.stateManager.ID(), err)
+			} else if c != "" {
+				cursor = c
+				j.seq++
+				j.stateManager.Enqueue(state.Cursor{Seq: j.seq, Value: cursor})
+			} else {
+				// Every entry is written after this point.
+				fromBeginning = true
+			}
+		}
+		cursor = j.read(cursor, fromBeginning)
+		select {
+		case <-j.done:
+			return
+		case <-time.After(reopenInterval):
+		}
+	}
+}
+
+// read publishes the entries of one journal stream and returns the cursor of
+// the last entry read.
+func (j *journalSrc) read(cursor string, fromBeginning bool) string {
+	stream, err := j.reader.Open(j.ctx, cursor, fromBeginning)
+	if err != nil {
+		log.Printf("E! [journald] Unable to read journal for %s: %v", j.stateManager.ID(), err)
+		return cursor
+	}
+	defer stream.Close()
+
+	er := newExportReader(stream)
+	for {
+		entry, err := er.Next()
+		if err != nil {
+			if !errors.Is(err, io.EOF) && j.ctx.Err() == nil {
+				log.Printf("E! [journald] Error reading journal for %s: %v", j.stateManager.ID(), err)
+			}
+			return cursor
+		}
+		if c := entry[cursorField]; c != "" {
+			cursor = c
+		}
+		if !j.config.shouldPublish(entry) {
+			continue
+		}
+		msg, err := j.message(entry)
+		if err != nil {
+			log.Printf("E! [journald] Unable to convert journal entry to JSON for %s: %v", j.stateManager.ID(), err)
+			continue
+		}
+		j.seq++
+		j.outputFn(&LogEvent{
+			msg:    msg,
+			t:      entryTime(entry),
+			cursor: state.Cursor{Seq: j.seq, Value: cursor},
+			src:    j,
+		})
+	}
+}
+
+// message returns the entry as a JSON object of the configured fields.
+func (j *journalSrc) message(entry map[string]string) (string, error) {
+	fields := make(map[string]string, len(entry))
+	if len(j.config.Fields) == 0 {
+		for k, v := range entry {
+			// Skip the address fields, e.g. __CURSOR, that are only meaningful
+			// to the journal.
+			if !strings.HasPrefix(k, "__") {
+				fields[k] = v
+			}
+		}
+	} else {
+		for _, k := range j.config.Fields {
+			if v, ok := entry[k]; ok {
+				fields[k] = v
+			}
+		}
+	}
+	b, err := json.Marshal(fields)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
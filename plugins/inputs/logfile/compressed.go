@@ -0,0 +1,144 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package logfile
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal/state"
+	"github.com/aws/amazon-cloudwatch-agent/plugins/inputs/logfile/globpath"
+	"github.com/aws/amazon-cloudwatch-agent/plugins/inputs/logfile/tail"
+)
+
+const (
+	// compressedFileStatePrefix is the prefix of the state files marking
+	// compressed files that have been read completely.
+	compressedFileStatePrefix = "compressed_"
+	// compressedFileFingerprintSize is the number of leading bytes hashed to
+	// identify a compressed file.
+	compressedFileFingerprintSize = 4096
+)
+
+type compressedFileInfo struct {
+	size        int64
+	modTime     time.Time
+	fingerprint string
+}
+
+// compressedFileFingerprint identifies a compressed file by its size and a
+// hash of its first bytes. Unlike the file name, the fingerprint does not
+// change when logrotate renames app.log.1.gz to app.log.2.gz.
+func compressedFileFingerprint(filename string) (string, error) {
+	f, err := tail.OpenFile(filename)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return "", err
+	}
+	h := sha256.New()
+	if _, err = io.CopyN(h, f, compressedFileFingerprintSize); err != nil && !errors.Is(err, io.EOF) {
+		return "", err
+	}
+	return fmt.Sprintf("%x_%d", h.Sum(nil)[:16], info.Size()), nil
+}
+
+// getCompressedFileFingerprint returns the fingerprint of the file, only
+// hashing the file again if its size or modification time has changed.
+func (t *LogFile) getCompressedFileFingerprint(filename string, info os.FileInfo) (string, error) {
+	if cached, ok := t.compressedFiles[filename]; ok && cached.size == info.Size() && cached.modTime.Equal(info.ModTime()) {
+		return cached.fingerprint, nil
+	}
+	fingerprint, err := compressedFileFingerprint(filename)
+	if err != nil {
+		return "", err
+	}
+	if t.compressedFiles == nil {
+		t.compressedFiles = make(map[string]compressedFileInfo)
+	}
+	t.compressedFiles[filename] = compressedFileInfo{size: info.Size(), modTime: info.ModTime(), fingerprint: fingerprint}
+	return fingerprint, nil
+}
+
+func (t *LogFile) compressedFileStatePath(fingerprint string) string {
+	return state.FilePath(t.FileStateFolder, compressedFileStatePrefix+fingerprint)
+}
+
+// shouldReadCompressedFile returns true if the compressed file can be
+// decompressed and has not been read completely before.
+func (t *LogFile) shouldReadCompressedFile(fileconfig *FileConfig, filename string, info os.FileInfo) bool {
+	if !fileconfig.ReadCompressedFiles || !tail.IsSupportedCompressedFile(filename) {
+		return false
+	}
+	fingerprint, err := t.getCompressedFileFingerprint(filename, info)
+	if err != nil {
+		t.Log.Errorf("Failed to read compressed file %s: %v", filename, err)
+		return false
+	}
+	_, err = os.Stat(t.compressedFileStatePath(fingerprint))
+	return errors.Is(err, os.ErrNotExist)
+}
+
+// markCompressedFileCompleted records in the state folder that the compressed
+// file has been read to the end and its events acknowledged, so it is not read
+// again.
+func (t *LogFile) markCompressedFileCompleted(filename, fingerprint string) {
+	if err := os.WriteFile(t.compressedFileStatePath(fingerprint), []byte(filename+"\n"), state.FileMode); err != nil {
+		t.Log.Errorf("Failed to record completion of compressed file %s: %v", filename, err)
+		return
+	}
+	t.Log.Infof("Finished reading compressed file %s", filename)
+}
+
+// cleanupCompressedFileState removes the completion state files of compressed
+// files that are no longer matched by any file config.
+func (t *LogFile) cleanupCompressedFileState(files []string) {
+	var stateFiles []string
+	for _, file := range files {
+		if strings.HasPrefix(filepath.Base(file), compressedFileStatePrefix) {
+			stateFiles = append(stateFiles, file)
+		}
+	}
+	if len(stateFiles) == 0 {
+		return
+	}
+
+	current := make(map[string]struct{})
+	for i := range t.FileConfig {
+		fileconfig := &t.FileConfig[i]
+		if !fileconfig.ReadCompressedFiles {
+			continue
+		}
+		g, err := globpath.Compile(fileconfig.FilePath)
+		if err != nil {
+			continue
+		}
+		for matchedFileName := range g.Match() {
+			if !tail.IsSupportedCompressedFile(matchedFileName) {
+				continue
+			}
+			if fingerprint, err := compressedFileFingerprint(matchedFileName); err == nil {
+				current[t.compressedFileStatePath(fingerprint)] = struct{}{}
+			}
+		}
+	}
+
+	for _, file := range stateFiles {
+		if _, ok := current[file]; ok {
+			continue
+		}
+		if err := os.Remove(file); err != nil {
+			t.Log.Errorf("Error happens when deleting old state file %s: %v", file, err)
+		}
+	}
+}
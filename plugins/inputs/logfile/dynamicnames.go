@@ -0,0 +1,178 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package logfile
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// unresolvedNameValue replaces the placeholders of the fields that a log event
+// does not have.
+const unresolvedNameValue = "unknown"
+
+var namePlaceholderRegexp = regexp.MustCompile(`\{([A-Za-z_][\w.]*)\}`)
+
+// hasNamePlaceholders returns true if the log group or stream name references
+// the captures of the file path or the fields of the log events.
+func hasNamePlaceholders(name string) bool {
+	return namePlaceholderRegexp.MatchString(name)
+}
+
+// replaceNamePlaceholders replaces the placeholders of the name with the
+// values found by lookup. The placeholders that are not found are kept.
+func replaceNamePlaceholders(name string, lookup func(key string) (string, bool)) string {
+	return namePlaceholderRegexp.ReplaceAllStringFunc(name, func(placeholder string) string {
+		if value, ok := lookup(placeholder[1 : len(placeholder)-1]); ok {
+			return value
+		}
+		return placeholder
+	})
+}
+
+// compileFilePath splits a file path with named capture groups, e.g.
+// /var/log/apps/(?P<app>[^/]+)/*.log, into the glob of the files to tail and
+// the regexp of the captures. Each capture group is globbed as a *, so it
+// cannot span several directories.
+func compileFilePath(filePath string) (string, *regexp.Regexp, error) {
+	var glob, expr strings.Builder
+	expr.WriteString("^")
+	for i := 0; i < len(filePath); {
+		switch {
+		case strings.HasPrefix(filePath[i:], "(?P<"):
+			end, err := captureGroupEnd(filePath, i)
+			if err != nil {
+				return "", nil, err
+			}
+			glob.WriteString("*")
+			expr.WriteString(filePath[i:end])
+			i = end
+		case strings.HasPrefix(filePath[i:], "**"):
+			glob.WriteString("**")
+			expr.WriteString(".*")
+			i += 2
+		case filePath[i] == '*':
+			glob.WriteByte('*')
+			expr.WriteString(`[^/\\]*`)
+			i++
+		case filePath[i] == '?':
+			glob.WriteByte('?')
+			expr.WriteString(`[^/\\]`)
+			i++
+		case filePath[i] == '[':
+			end := strings.IndexByte(filePath[i:], ']')
+			if end < 0 {
+				return "", nil, fmt.Errorf("file_path %v has an unclosed [", filePath)
+			}
+			class := filePath[i : i+end+1]
+			glob.WriteString(class)
+			expr.WriteString(strings.Replace(class, "[!", "[^", 1))
+			i += end + 1
+		case filePath[i] == '{':
+			end := strings.IndexByte(filePath[i:], '}')
+			if end < 0 {
+				return "", nil, fmt.Errorf("file_path %v has an unclosed {", filePath)
+			}
+			alternatives := strings.Split(filePath[i+1:i+end], ",")
+			for j, alternative := range alternatives {
+				alternatives[j] = regexp.QuoteMeta(alternative)
+			}
+			glob.WriteString(filePath[i : i+end+1])
+			expr.WriteString("(?:" + strings.Join(alternatives, "|") + ")")
+			i += end + 1
+		default:
+			glob.WriteByte(filePath[i])
+			expr.WriteString(regexp.QuoteMeta(filePath[i : i+1]))
+			i++
+		}
+	}
+	expr.WriteString("$")
+	captures, err := regexp.Compile(expr.String())
+	if err != nil {
+		return "", nil, fmt.Errorf("file_path %v has invalid capture groups: %v", filePath, err)
+	}
+	return glob.String(), captures, nil
+}
+
+// captureGroupEnd returns the index after the parenthesis that closes the
+// capture group starting at start.
+func captureGroupEnd(filePath string, start int) (int, error) {
+	depth := 0
+	for i := start; i < len(filePath); i++ {
+		switch filePath[i] {
+		case '\\':
+			i++
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return i + 1, nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("file_path %v has an unclosed capture group", filePath)
+}
+
+// filePathCaptures returns the named captures of the file path regexp in the
+// file name.
+func filePathCaptures(captures *regexp.Regexp, filename string) map[string]string {
+	match := captures.FindStringSubmatch(filename)
+	if match == nil {
+		return nil
+	}
+	values := make(map[string]string)
+	for i, name := range captures.SubexpNames() {
+		if name != "" {
+			values[name] = match[i]
+		}
+	}
+	return values
+}
+
+// resolveEventName replaces the placeholders of the name with the fields of
+// the log event message, which is a JSON object once a processor has parsed it.
+// Nested fields are referenced with dots, e.g. {request.tenant}.
+func resolveEventName(name string, fields map[string]interface{}, sanitize func(string) string) string {
+	return replaceNamePlaceholders(name, func(key string) (string, bool) {
+		value := eventField(fields, key)
+		if value == "" {
+			value = unresolvedNameValue
+		}
+		return sanitize(value), true
+	})
+}
+
+// parseEventFields returns the fields of the JSON object of the message, or
+// nil if the message is not a JSON object.
+func parseEventFields(message string) map[string]interface{} {
+	var fields map[string]interface{}
+	if err := json.Unmarshal([]byte(message), &fields); err != nil {
+		return nil
+	}
+	return fields
+}
+
+func eventField(fields map[string]interface{}, key string) string {
+	var value interface{} = fields
+	for _, part := range strings.Split(key, ".") {
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return ""
+		}
+		value = object[part]
+	}
+	switch v := value.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	}
+	return ""
+}
@@ -0,0 +1,111 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package logfile
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aws/amazon-cloudwatch-agent/logs"
+)
+
+func TestCompileFilePath(t *testing.T) {
+	testCases := map[string]struct {
+		filePath     string
+		wantGlob     string
+		filename     string
+		wantCaptures map[string]string
+	}{
+		"Directory": {
+			filePath:     "/var/log/apps/(?P<app>[^/]+)/*.log",
+			wantGlob:     "/var/log/apps/*/*.log",
+			filename:     "/var/log/apps/billing/server.log",
+			wantCaptures: map[string]string{"app": "billing"},
+		},
+		"FileName": {
+			filePath:     "/var/log/(?P<tenant>\\w+)-(?P<env>prod|dev).log*",
+			wantGlob:     "/var/log/*-*.log*",
+			filename:     "/var/log/acme-prod.log.1",
+			wantCaptures: map[string]string{"tenant": "acme", "env": "prod"},
+		},
+		"Glob": {
+			filePath:     "/var/log/**/(?P<app>[a-z]+).{log,txt}",
+			wantGlob:     "/var/log/**/*.{log,txt}",
+			filename:     "/var/log/a/b/web.txt",
+			wantCaptures: map[string]string{"app": "web"},
+		},
+		"NoMatch": {
+			filePath: "/var/log/apps/(?P<app>[a-z]+)/*.log",
+			wantGlob: "/var/log/apps/*/*.log",
+			filename: "/var/log/apps/App1/server.log",
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			glob, captures, err := compileFilePath(testCase.filePath)
+			require.NoError(t, err)
+			assert.Equal(t, testCase.wantGlob, glob)
+			assert.Equal(t, testCase.wantCaptures, filePathCaptures(captures, testCase.filename))
+		})
+	}
+
+	for _, filePath := range []string{
+		"/var/log/(?P<app>[^/]+/*.log",
+		"/var/log/(?P<app[^/]+)/*.log",
+		"/var/log/[a-z/(?P<app>.+)",
+	} {
+		_, _, err := compileFilePath(filePath)
+		assert.Error(t, err, filePath)
+	}
+}
+
+func TestResolveEventName(t *testing.T) {
+	fields := parseEventFields(`{"level":"ERROR","code":500,"request":{"tenant":"acme:corp"}}`)
+	assert.Equal(t, "app-ERROR-500", resolveEventName("app-{level}-{code}", fields, logs.SanitizeLogStreamName))
+	assert.Equal(t, "/tenants/acme_corp", resolveEventName("/tenants/{request.tenant}", fields, logs.SanitizeLogGroupName))
+	assert.Equal(t, "acme_corp", resolveEventName("{request.tenant}", fields, logs.SanitizeLogStreamName))
+	assert.Equal(t, "/tenants/unknown", resolveEventName("/tenants/{tenant}", fields, logs.SanitizeLogGroupName))
+	assert.Equal(t, "unknown", resolveEventName("{level}", parseEventFields("ERROR not json"), logs.SanitizeLogStreamName))
+	assert.Equal(t, "static", resolveEventName("static", fields, logs.SanitizeLogStreamName))
+}
+
+func TestHasEventNamePlaceholders(t *testing.T) {
+	config := &FileConfig{
+		FilePath:      "/var/log/apps/(?P<app>[^/]+)/*.log",
+		LogGroupName:  "/apps/{app}",
+		LogStreamName: "{instance_id}",
+	}
+	require.NoError(t, config.init())
+	assert.Equal(t, "/var/log/apps/*/*.log", config.FilePath)
+	assert.True(t, config.hasEventNamePlaceholders(config.LogGroupName, config.LogStreamName))
+
+	config.LogStreamName = "server"
+	assert.False(t, config.hasEventNamePlaceholders(config.LogGroupName, config.LogStreamName))
+	group, stream := config.resolveFilePathCaptures("/var/log/apps/web api/server.log", config.LogGroupName, config.LogStreamName)
+	assert.Equal(t, "/apps/web_api", group)
+	assert.Equal(t, "server", stream)
+
+	config = &FileConfig{
+		FilePath:     "/var/log/app.log",
+		LogGroupName: "/app/{level}",
+		Destinations: []*DestinationConfig{{LogGroupName: "errors"}},
+	}
+	assert.Error(t, config.init())
+
+	config = &FileConfig{
+		FilePath:     "/var/log/apps/(?P<app>[^/]+)/*.log",
+		LogGroupName: "/apps/{app}",
+		Destinations: []*DestinationConfig{{LogGroupName: "/errors/{app}"}},
+	}
+	assert.NoError(t, config.init())
+
+	config = &FileConfig{
+		FilePath:     "/var/log/apps/(?P<app>[^/]+)/*.log",
+		LogGroupName: "/apps/{app}",
+		Destinations: []*DestinationConfig{{LogStreamName: "{level}"}},
+	}
+	assert.Error(t, config.init())
+}
@@ -16,6 +16,7 @@ import (
 	"golang.org/x/text/encoding"
 	"golang.org/x/text/encoding/ianaindex"
 
+	"github.com/aws/amazon-cloudwatch-agent/internal"
 	"github.com/aws/amazon-cloudwatch-agent/internal/logscommon"
 	"github.com/aws/amazon-cloudwatch-agent/logs"
 	"github.com/aws/amazon-cloudwatch-agent/profiler"
@@ -57,6 +58,20 @@ type FileConfig struct {
 	//If this config is specified as "{timestamp_regex}", it means to use the same regex as timestampFromLogLine.
 	//If this config is specified as some regex, it will use the regex to determine if this line is a start line of multiline entry.
 	MultiLineStartPattern string `toml:"multi_line_start_pattern"`
+	//The regex of the last line of a multiline entry. The line after it starts a new entry.
+	MultiLineEndPattern string `toml:"multi_line_end_pattern"`
+	//The regex of the lines that continue the previous line, e.g. "^\s" for indented lines.
+	//It cannot be used with multi_line_start_pattern.
+	MultiLineContinuePattern string `toml:"multi_line_continue_pattern"`
+	//Indicate whether the lines that do not match the multiline patterns are the ones that start, end or continue an entry.
+	MultiLineNegate bool `toml:"multi_line_negate"`
+	//The max number of lines in a multiline entry. The entry is published once it is reached.
+	MultiLineMaxLines int `toml:"multi_line_max_lines"`
+	//How long to wait for more lines before publishing a multiline entry, defaults to 5 seconds.
+	MultiLineTimeout internal.Duration `toml:"multi_line_timeout"`
+	//The name of a built-in state machine that groups the lines of stack traces into the entry before them:
+	//java, python, go, dotnet or ruby. It cannot be used with multi_line_start_pattern or multi_line_continue_pattern.
+	MultiLinePreset string `toml:"multi_line_preset"`
 
 	// automatically remove the file / symlink after uploading.
 	// This auto removal does not support the case where other log rotation mechanism is already in place.
@@ -71,6 +86,9 @@ type FileConfig struct {
 	FromBeginning bool `toml:"from_beginning"`
 	//Indicate whether it is a named pipe.
 	Pipe bool `toml:"pipe"`
+	//Indicate whether to read .gz, .bz2 and .zst files matching the file path.
+	//Compressed files are read once from beginning to end, and are not read again after that.
+	ReadCompressedFiles bool `toml:"read_compressed_files"`
 
 	//Indicate logType for scroll
 	LogType string `toml:"log_type"`
@@ -87,8 +105,21 @@ type FileConfig struct {
 	//Indicate retention in days for log group
 	RetentionInDays int `toml:"retention_in_days"`
 
+	//Settings applied to the log group when the agent creates it, instead of the ones of the output.
+	KMSKeyID             string                   `toml:"kms_key_id"`
+	LogGroupTags         map[string]string        `toml:"log_group_tags"`
+	SubscriptionFilter   *logs.SubscriptionFilter `toml:"subscription_filter"`
+	DataProtectionPolicy string                   `toml:"data_protection_policy"`
+
 	Filters []*LogFilter `toml:"filters"`
 
+	//Processors rewrite the log events that pass the filters, in order.
+	Processors []*LogProcessor `toml:"processors"`
+
+	//Additional destinations the log events are published to, each with its own filters.
+	//The offset of the file is only advanced once every destination has published an event.
+	Destinations []*DestinationConfig `toml:"destinations"`
+
 	//Customer specified service.name
 	ServiceName string `toml:"service_name"`
 	//Customer specified deployment.environment
@@ -100,16 +131,45 @@ type FileConfig struct {
 	TimestampRegexP *regexp.Regexp
 	//Regexp go type multiline start regex
 	MultiLineStartPatternP *regexp.Regexp
+	//Regexp go type multiline end regex
+	MultiLineEndPatternP *regexp.Regexp
+	//Regexp go type multiline continue regex
+	MultiLineContinuePatternP *regexp.Regexp
 	//Regexp go type blacklist regex
 	BlacklistRegexP *regexp.Regexp
 	//Decoder object
-	Enc         encoding.Encoding
-	sampleCount int
+	Enc              encoding.Encoding
+	sampleCount      int
+	multilinePresetP multilinePreset
+	logGroupSettings *logs.LogGroupSettings
+	//Regexp of the named captures of the file path
+	filePathCapturesP *regexp.Regexp
+}
+
+// The destination config presents an additional destination for the events of a file.
+type DestinationConfig struct {
+	//Log Destination override, defaults to the destination of the log file.
+	Destination string `toml:"destination"`
+	//The log group name, defaults to the log group name of the log file.
+	LogGroupName string `toml:"log_group_name"`
+	//The log stream name, defaults to the log stream name of the log file.
+	LogStreamName string `toml:"log_stream_name"`
+	//log group class
+	LogGroupClass string `toml:"log_group_class"`
+	//Indicate retention in days for log group
+	RetentionInDays int `toml:"retention_in_days"`
+
+	Filters []*LogFilter `toml:"filters"`
 }
 
 // Initialize some variables in the FileConfig object based on the rest info fetched from the configuration file.
 func (config *FileConfig) init() error {
 	var err error
+	if strings.Contains(config.FilePath, "(?P<") {
+		if config.FilePath, config.filePathCapturesP, err = compileFilePath(config.FilePath); err != nil {
+			return err
+		}
+	}
 	if !(config.Encoding == "" || config.Encoding == "utf_8" || config.Encoding == "utf-8" || config.Encoding == "utf8" || config.Encoding == "ascii") {
 		if config.Enc, _ = charset.Lookup(config.Encoding); config.Enc == nil {
 			if config.Enc, _ = ianaindex.IANA.Encoding(config.Encoding); config.Enc == nil {
@@ -136,17 +196,48 @@ func (config *FileConfig) init() error {
 		}
 	}
 
-	if config.MultiLineStartPattern == "" {
+	if config.MultiLineStartPattern != "" && config.MultiLineContinuePattern != "" {
+		return errors.New("multi_line_start_pattern and multi_line_continue_pattern cannot be used together")
+	}
+	if config.MultiLineEndPattern != "" {
+		if config.MultiLineEndPatternP, err = regexp.Compile(config.MultiLineEndPattern); err != nil {
+			return fmt.Errorf("multi_line_end_pattern has issue, regexp: Compile( %v ): %v", config.MultiLineEndPattern, err.Error())
+		}
+	}
+	if config.MultiLineContinuePattern != "" {
+		if config.MultiLineContinuePatternP, err = regexp.Compile(config.MultiLineContinuePattern); err != nil {
+			return fmt.Errorf("multi_line_continue_pattern has issue, regexp: Compile( %v ): %v", config.MultiLineContinuePattern, err.Error())
+		}
+	}
+
+	if config.MultiLinePreset != "" {
+		if config.MultiLineStartPattern != "" || config.MultiLineContinuePattern != "" {
+			return errors.New("multi_line_preset cannot be used with multi_line_start_pattern or multi_line_continue_pattern")
+		}
+		if config.multilinePresetP, err = getMultilinePreset(config.MultiLinePreset); err != nil {
+			return err
+		}
+	}
+
+	// the default start pattern would split the entries of the other patterns
+	if config.MultiLineStartPattern == "" && config.MultiLineEndPatternP == nil && config.MultiLineContinuePatternP == nil && config.multilinePresetP == nil {
 		config.MultiLineStartPattern = "^[\\S]"
 	}
 	if config.MultiLineStartPattern == "{timestamp_regex}" {
 		config.MultiLineStartPatternP = config.TimestampRegexP
-	} else {
+	} else if config.MultiLineStartPattern != "" {
 		if config.MultiLineStartPatternP, err = regexp.Compile(config.MultiLineStartPattern); err != nil {
 			return fmt.Errorf("multi_line_start_pattern has issue, regexp: Compile( %v ): %v", config.MultiLineStartPattern, err.Error())
 		}
 	}
 
+	if config.MultiLineMaxLines < 0 {
+		return fmt.Errorf("multi_line_max_lines cannot be negative: %v", config.MultiLineMaxLines)
+	}
+	if config.MultiLineTimeout.Duration < 0 {
+		return fmt.Errorf("multi_line_timeout cannot be negative: %v", config.MultiLineTimeout.Duration)
+	}
+
 	if config.Blacklist != "" {
 		if config.BlacklistRegexP, err = regexp.Compile(config.Blacklist); err != nil {
 			return fmt.Errorf("blacklist regex has issue, regexp: Compile( %v ): %v", config.Blacklist, err.Error())
@@ -163,6 +254,17 @@ func (config *FileConfig) init() error {
 	if config.RetentionInDays == 0 {
 		config.RetentionInDays = -1
 	}
+	if config.KMSKeyID != "" || len(config.LogGroupTags) > 0 || config.SubscriptionFilter != nil || config.DataProtectionPolicy != "" {
+		if config.SubscriptionFilter != nil && config.SubscriptionFilter.DestinationARN == "" {
+			return errors.New("subscription_filter requires destination_arn")
+		}
+		config.logGroupSettings = &logs.LogGroupSettings{
+			KMSKeyID:             config.KMSKeyID,
+			Tags:                 config.LogGroupTags,
+			SubscriptionFilter:   config.SubscriptionFilter,
+			DataProtectionPolicy: config.DataProtectionPolicy,
+		}
+	}
 
 	for _, f := range config.Filters {
 		err = f.init()
@@ -171,6 +273,30 @@ func (config *FileConfig) init() error {
 		}
 	}
 
+	for _, p := range config.Processors {
+		if err = p.init(); err != nil {
+			return err
+		}
+	}
+
+	if len(config.Destinations) > 0 && config.hasEventNamePlaceholders(config.LogGroupName, config.LogStreamName) {
+		return errors.New("log_group_name and log_stream_name cannot reference the fields of the log events with destinations")
+	}
+	for _, d := range config.Destinations {
+		// The destinations only resolve the named captures of the file path.
+		if config.hasEventNamePlaceholders(d.LogGroupName, d.LogStreamName) {
+			return errors.New("log_group_name and log_stream_name of destinations can only reference the named captures of the file path")
+		}
+		if d.RetentionInDays == 0 {
+			d.RetentionInDays = -1
+		}
+		for _, f := range d.Filters {
+			if err = f.init(); err != nil {
+				return err
+			}
+		}
+	}
+
 	return nil
 }
 
@@ -226,11 +352,76 @@ func (config *FileConfig) timestampFromLogLine(logValue string) (time.Time, stri
 
 // This method determine whether the line is a start line for multiline log entry.
 func (config *FileConfig) isMultilineStart(logValue string) bool {
-
+	if config.MultiLineContinuePatternP != nil {
+		// a line that does not continue the previous line starts a new entry
+		return config.MultiLineContinuePatternP.MatchString(logValue) == config.MultiLineNegate
+	}
 	if config.MultiLineStartPatternP == nil {
 		return false
 	}
-	return config.MultiLineStartPatternP.MatchString(logValue)
+	return config.MultiLineStartPatternP.MatchString(logValue) != config.MultiLineNegate
+}
+
+// This method returns the function that determines whether the line is a start line for multiline log entry,
+// or nil if the multiline mode is disabled, and the function that resets its state, or nil if it has none.
+// Each file needs its own functions, since a preset keeps the state of the file.
+func (config *FileConfig) multilineStartFn() (func(string) bool, func()) {
+	if config.multilinePresetP != nil {
+		s := config.multilinePresetP.newState()
+		return s.isStart, s.reset
+	}
+	if config.MultiLineStartPattern != "" || config.MultiLineContinuePatternP != nil || config.MultiLineEndPatternP != nil {
+		return config.isMultilineStart, nil
+	}
+	return nil, nil
+}
+
+// This method replaces the placeholders of the named captures of the file path in the log group and stream names.
+// The other placeholders are the fields of the log events, which are resolved by the tailerSrc.
+func (config *FileConfig) resolveFilePathCaptures(filename, group, stream string) (string, string) {
+	if config.filePathCapturesP == nil {
+		return group, stream
+	}
+	captures := filePathCaptures(config.filePathCapturesP, filename)
+	lookup := func(sanitize func(string) string) func(string) (string, bool) {
+		return func(key string) (string, bool) {
+			value, ok := captures[key]
+			return sanitize(value), ok
+		}
+	}
+	return replaceNamePlaceholders(group, lookup(logs.SanitizeLogGroupName)), replaceNamePlaceholders(stream, lookup(logs.SanitizeLogStreamName))
+}
+
+// This method determines whether any of the log group or stream names references the fields of the log events, i.e.
+// has placeholders that are not named captures of the file path.
+func (config *FileConfig) hasEventNamePlaceholders(names ...string) bool {
+	lookup := func(string) (string, bool) {
+		return "", false
+	}
+	if config.filePathCapturesP != nil {
+		captures := make(map[string]string)
+		for _, name := range config.filePathCapturesP.SubexpNames() {
+			captures[name] = name
+		}
+		lookup = func(key string) (string, bool) {
+			value, ok := captures[key]
+			return value, ok
+		}
+	}
+	for _, name := range names {
+		if hasNamePlaceholders(replaceNamePlaceholders(name, lookup)) {
+			return true
+		}
+	}
+	return false
+}
+
+// This method determine whether the line is the last line of a multiline log entry.
+func (config *FileConfig) isMultilineEnd(logValue string) bool {
+	if config.MultiLineEndPatternP == nil {
+		return false
+	}
+	return config.MultiLineEndPatternP.MatchString(logValue) != config.MultiLineNegate
 }
 
 func ShouldPublish(logGroupName, logStreamName string, filters []*LogFilter, event logs.LogEvent) bool {
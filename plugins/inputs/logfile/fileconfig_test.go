@@ -12,6 +12,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/aws/amazon-cloudwatch-agent/logs"
 	"github.com/aws/amazon-cloudwatch-agent/tool/util"
 )
 
@@ -351,6 +352,83 @@ func TestMultiLineStartPattern(t *testing.T) {
 	assert.False(t, multiLineStart, "This should not be a multi-line start line.")
 }
 
+func TestMultiLinePatterns(t *testing.T) {
+	testCases := map[string]struct {
+		config    FileConfig
+		wantStart map[string]bool
+		wantEnd   map[string]bool
+	}{
+		"Default": {
+			config:    FileConfig{},
+			wantStart: map[string]bool{"line": true, " line": false},
+			wantEnd:   map[string]bool{"line": false},
+		},
+		"Continue": {
+			config:    FileConfig{MultiLineContinuePattern: "^\\s|^Caused by:"},
+			wantStart: map[string]bool{"line": true, "\tat line": false, "Caused by: error": false},
+		},
+		"ContinueNegate": {
+			config:    FileConfig{MultiLineContinuePattern: "^\\d{4}-", MultiLineNegate: true},
+			wantStart: map[string]bool{"2024-01-01 line": true, "Traceback": false},
+		},
+		"End": {
+			config:    FileConfig{MultiLineEndPattern: ";$"},
+			wantStart: map[string]bool{"line": false, "line;": false},
+			wantEnd:   map[string]bool{"line": false, "line;": true},
+		},
+		"EndNegate": {
+			config:  FileConfig{MultiLineEndPattern: "\\\\$", MultiLineNegate: true},
+			wantEnd: map[string]bool{"line \\": false, "line": true},
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			config := testCase.config
+			require.NoError(t, config.init())
+			for line, want := range testCase.wantStart {
+				assert.Equal(t, want, config.isMultilineStart(line), line)
+			}
+			for line, want := range testCase.wantEnd {
+				assert.Equal(t, want, config.isMultilineEnd(line), line)
+			}
+		})
+	}
+}
+
+func TestMultiLinePatternsInitFailure(t *testing.T) {
+	config := FileConfig{MultiLineStartPattern: "^\\S", MultiLineContinuePattern: "^\\s"}
+	assert.Error(t, config.init())
+	config = FileConfig{MultiLineEndPattern: "("}
+	assert.Error(t, config.init())
+	config = FileConfig{MultiLineContinuePattern: "("}
+	assert.Error(t, config.init())
+	config = FileConfig{MultiLineMaxLines: -1}
+	assert.Error(t, config.init())
+}
+
+func TestLogGroupSettings(t *testing.T) {
+	config := FileConfig{FilePath: "/tmp/logfile.log"}
+	require.NoError(t, config.init())
+	assert.Nil(t, config.logGroupSettings)
+
+	filter := &logs.SubscriptionFilter{DestinationARN: "arn:aws:lambda:us-east-1:123456789012:function:f"}
+	config = FileConfig{
+		FilePath:           "/tmp/logfile.log",
+		KMSKeyID:           "arn:aws:kms:us-east-1:123456789012:key/abc",
+		LogGroupTags:       map[string]string{"team": "security"},
+		SubscriptionFilter: filter,
+	}
+	require.NoError(t, config.init())
+	assert.Equal(t, &logs.LogGroupSettings{
+		KMSKeyID:           "arn:aws:kms:us-east-1:123456789012:key/abc",
+		Tags:               map[string]string{"team": "security"},
+		SubscriptionFilter: filter,
+	}, config.logGroupSettings)
+
+	config = FileConfig{FilePath: "/tmp/logfile.log", SubscriptionFilter: &logs.SubscriptionFilter{}}
+	assert.Error(t, config.init())
+}
+
 func TestFileConfigInitWithFilters(t *testing.T) {
 	filter1 := LogFilter{
 		Type:       includeFilterType,
@@ -400,6 +478,27 @@ func TestFileConfigInitWithFiltersFails(t *testing.T) {
 	assert.Equal(t, "filter regex has issue, regexp: Compile( StatusCode: ([4-5]\\d\\d ): error parsing regexp: missing closing ): `StatusCode: ([4-5]\\d\\d`", err.Error())
 }
 
+func TestFileConfigInitWithProcessorsFails(t *testing.T) {
+	fileConfig := &FileConfig{
+		FilePath: "/tmp/logfile.log",
+		Processors: []*LogProcessor{
+			{
+				Type:       maskProcessorType,
+				Expression: "password=\\S+",
+			},
+			{
+				Type:       parseRegexProcessorType,
+				Expression: "StatusCode: ([4-5]\\d\\d)",
+			},
+		},
+	}
+
+	err := fileConfig.init()
+	assert.Error(t, err)
+	assert.Equal(t, "processor regex StatusCode: ([4-5]\\d\\d) has no named capture groups", err.Error())
+	assert.Equal(t, defaultMaskReplacement, fileConfig.Processors[0].Replacement)
+}
+
 func TestLogEmptyFilters(t *testing.T) {
 	assertPublishedForFilters(t, []*LogFilter{}, "foo")
 	assertPublishedForFilters(t, []*LogFilter{}, "Some other log message")
@@ -697,3 +796,20 @@ func initializeLogFiltersForBenchmarks(b *testing.B, filters []*LogFilter) []*Lo
 	}
 	return filters
 }
+
+func TestFileConfigInitWithDestinations(t *testing.T) {
+	fileConfig := &FileConfig{
+		FilePath: "/tmp/logfile.log",
+		Destinations: []*DestinationConfig{
+			{
+				LogGroupName: "errors",
+				Filters:      []*LogFilter{{Type: includeFilterType, Expression: "ERROR"}},
+			},
+		},
+	}
+	assert.NoError(t, fileConfig.init())
+	assert.Equal(t, -1, fileConfig.Destinations[0].RetentionInDays)
+
+	fileConfig.Destinations[0].Filters = []*LogFilter{{Type: "unknown", Expression: "ERROR"}}
+	assert.Error(t, fileConfig.init())
+}
@@ -36,6 +36,7 @@ type LogFile struct {
 	Log telegraf.Logger `toml:"-"`
 
 	configs           map[*FileConfig]map[string]*tailerSrc
+	compressedFiles   map[string]compressedFileInfo
 	done              chan struct{}
 	removeTailerSrcCh chan *tailerSrc
 	started           bool
@@ -45,6 +46,7 @@ func NewLogFile() *LogFile {
 
 	return &LogFile{
 		configs:           make(map[*FileConfig]map[string]*tailerSrc),
+		compressedFiles:   make(map[string]compressedFileInfo),
 		done:              make(chan struct{}),
 		removeTailerSrcCh: make(chan *tailerSrc, 100),
 	}
@@ -85,6 +87,8 @@ const sampleConfig = `
       from_beginning = false
       ## Whether file is a named pipe
       pipe = false
+      ## Read matching .gz, .bz2 and .zst files once from beginning to end
+      read_compressed_files = false
       destination = "cloudwatchlogs"
       ## Max size of each log event, defaults to 262144 (256KB)
       max_event_size = 262144
@@ -179,9 +183,10 @@ func (t *LogFile) FindLogSrc() []logs.LogSrc {
 				t.configs[fileconfig] = dests
 			}
 
+			compressed := isCompressedFile(filename)
 			if _, ok := dests[filename]; ok {
 				continue
-			} else if fileconfig.AutoRemoval {
+			} else if fileconfig.AutoRemoval && !compressed {
 				// This logic means auto_removal does not work with publish_multi_logs
 				for _, dst := range dests {
 					// Stop all other tailers in favor of the newly found file
@@ -199,7 +204,7 @@ func (t *LogFile) FindLogSrc() []logs.LogSrc {
 			restored, err := stateManager.Restore()
 			if err == nil { // Missing state file would be an error too
 				seekFile = &tail.SeekInfo{Whence: io.SeekStart, Offset: restored.Last().EndOffsetInt64()}
-			} else if !fileconfig.Pipe && !fileconfig.FromBeginning {
+			} else if !fileconfig.Pipe && !fileconfig.FromBeginning && !compressed {
 				seekFile = &tail.SeekInfo{Whence: io.SeekEnd, Offset: 0}
 			}
 
@@ -212,27 +217,38 @@ func (t *LogFile) FindLogSrc() []logs.LogSrc {
 				isutf16 = true
 			}
 
-			tailer, err := tail.TailFile(filename,
-				tail.Config{
-					ReOpen:      false,
-					Follow:      true,
-					Location:    seekFile,
-					GapsToRead:  gapsToRead,
-					MustExist:   true,
-					Pipe:        fileconfig.Pipe,
-					Poll:        true,
-					MaxLineSize: fileconfig.MaxEventSize,
-					IsUTF16:     isutf16,
-				})
+			var tailer *tail.Tail
+			if compressed {
+				tailer, err = tail.TailCompressedFile(filename,
+					tail.Config{
+						Location:    seekFile,
+						MaxLineSize: fileconfig.MaxEventSize,
+						IsUTF16:     isutf16,
+					})
+			} else {
+				tailer, err = tail.TailFile(filename,
+					tail.Config{
+						ReOpen:      false,
+						Follow:      true,
+						Location:    seekFile,
+						GapsToRead:  gapsToRead,
+						MustExist:   true,
+						Pipe:        fileconfig.Pipe,
+						Poll:        true,
+						MaxLineSize: fileconfig.MaxEventSize,
+						IsUTF16:     isutf16,
+					})
+			}
 
 			if err != nil {
 				t.Log.Errorf("Failed to tail file %v with error: %v", filename, err)
 				continue
 			}
 
-			var mlCheck func(string) bool
-			if fileconfig.MultiLineStartPattern != "" {
-				mlCheck = fileconfig.isMultilineStart
+			mlCheck, mlReset := fileconfig.multilineStartFn()
+			var mlEndCheck func(string) bool
+			if fileconfig.MultiLineEndPatternP != nil {
+				mlEndCheck = fileconfig.isMultilineEnd
 			}
 
 			groupName := fileconfig.LogGroupName
@@ -243,16 +259,26 @@ func (t *LogFile) FindLogSrc() []logs.LogSrc {
 			if fileconfig.PublishMultiLogs {
 				if groupName == "" {
 					groupName = generateLogGroupName(filename)
-				} else {
+				} else if !hasNamePlaceholders(streamName) {
 					streamName = generateLogStreamName(filename, fileconfig.LogStreamName)
 				}
 			}
+			groupName, streamName = fileconfig.resolveFilePathCaptures(filename, groupName, streamName)
 
 			destination := fileconfig.Destination
 			if destination == "" {
 				destination = t.Destination
 			}
 
+			// Compressed files are read once and closed, so neither removing
+			// them nor releasing the file descriptor under backpressure applies.
+			autoRemoval := fileconfig.AutoRemoval
+			backpressureMode := fileconfig.BackpressureMode
+			if compressed {
+				autoRemoval = false
+				backpressureMode = ""
+			}
+
 			src := NewTailerSrc(
 				groupName, streamName,
 				t.Destination,
@@ -260,16 +286,37 @@ func (t *LogFile) FindLogSrc() []logs.LogSrc {
 				fileconfig.LogGroupClass,
 				fileconfig.FilePath,
 				tailer,
-				fileconfig.AutoRemoval,
+				autoRemoval,
 				mlCheck,
+				mlEndCheck,
+				fileconfig.MultiLineMaxLines,
+				fileconfig.MultiLineTimeout.Duration,
 				fileconfig.Filters,
+				fileconfig.Processors,
 				fileconfig.timestampFromLogLine,
 				fileconfig.Enc,
 				fileconfig.MaxEventSize,
 				fileconfig.TruncateSuffix,
 				fileconfig.RetentionInDays,
-				fileconfig.BackpressureMode,
+				backpressureMode,
 			)
+			src.logGroupSettings = fileconfig.logGroupSettings
+			src.resetMLState = mlReset
+
+			for _, d := range fileconfig.Destinations {
+				routeGroup, routeStream, routeDestination := d.LogGroupName, d.LogStreamName, d.Destination
+				if routeGroup == "" {
+					routeGroup = groupName
+				}
+				if routeStream == "" {
+					routeStream = streamName
+				}
+				if routeDestination == "" {
+					routeDestination = t.Destination
+				}
+				routeGroup, routeStream = fileconfig.resolveFilePathCaptures(filename, routeGroup, routeStream)
+				src.AddRoute(routeGroup, routeStream, routeDestination, d.LogGroupClass, d.RetentionInDays, d.Filters)
+			}
 
 			src.AddCleanUpFn(func(ts *tailerSrc) func() {
 				return func() {
@@ -281,6 +328,13 @@ func (t *LogFile) FindLogSrc() []logs.LogSrc {
 				}
 			}(src))
 
+			if compressed {
+				fingerprint := t.compressedFiles[filename].fingerprint
+				src.AddCompletedFn(func() {
+					t.markCompressedFileCompleted(filename, fingerprint)
+				})
+			}
+
 			srcs = append(srcs, src)
 
 			dests[filename] = src
@@ -306,7 +360,8 @@ func (t *LogFile) getTargetFiles(fileconfig *FileConfig) ([]string, error) {
 			continue
 		}
 
-		if isCompressedFile(matchedFileName) {
+		compressed := isCompressedFile(matchedFileName)
+		if compressed && !fileconfig.ReadCompressedFiles {
 			continue
 		}
 
@@ -321,7 +376,14 @@ func (t *LogFile) getTargetFiles(fileconfig *FileConfig) ([]string, error) {
 		if blacklistP != nil && blacklistP.MatchString(fileBaseName) {
 			continue
 		}
-		if !fileconfig.PublishMultiLogs {
+		if compressed {
+			// Compressed files are read once, so they are added regardless of
+			// publish_multi_logs to backfill rotated files.
+			if t.shouldReadCompressedFile(fileconfig, matchedFileName, matchedFileInfo) {
+				targetFileList = append(targetFileList, matchedFileName)
+				t.Log.Debugf("Added compressed file: %s", matchedFileName)
+			}
+		} else if !fileconfig.PublishMultiLogs {
 			if targetFileName == "" || matchedFileInfo.ModTime().After(targetModTime) {
 				targetFileName = matchedFileName
 				targetModTime = matchedFileInfo.ModTime()
@@ -331,7 +393,7 @@ func (t *LogFile) getTargetFiles(fileconfig *FileConfig) ([]string, error) {
 			t.Log.Debugf("Multi-log mode - added file: %s", matchedFileName)
 		}
 	}
-	//If targetFileName != "", it means customer doesn't enable publish_multi_logs feature, targetFileList should be empty in this case.
+	//If targetFileName != "", it means customer doesn't enable publish_multi_logs feature, targetFileList should only contain compressed files in this case.
 	if targetFileName != "" {
 		targetFileList = append(targetFileList, targetFileName)
 	}
@@ -350,7 +412,9 @@ func (t *LogFile) cleanupStateFolder() {
 			continue
 		}
 
-		if strings.Contains(file, logscommon.WindowsEventLogPrefix) {
+		if strings.Contains(file, logscommon.WindowsEventLogPrefix) ||
+			strings.Contains(file, logscommon.JournaldPrefix) ||
+			strings.HasPrefix(filepath.Base(file), compressedFileStatePrefix) {
 			continue
 		}
 
@@ -370,6 +434,7 @@ func (t *LogFile) cleanupStateFolder() {
 			t.Log.Errorf("Error happens when deleting old state file %s: %v", file, err)
 		}
 	}
+	t.cleanupCompressedFileState(files)
 }
 
 func (t *LogFile) cleanUpStoppedTailerSrc() {
@@ -390,7 +455,7 @@ func (t *LogFile) cleanUpStoppedTailerSrc() {
 	}
 }
 
-// Compressed file should be skipped.
+// Compressed file should be skipped unless read_compressed_files is enabled.
 // This func is to determine whether the file is compressed or not based on the file name suffix.
 func isCompressedFile(filename string) bool {
 	suffix := filepath.Ext(filename)
@@ -4,6 +4,8 @@
 package logfile
 
 import (
+	"bytes"
+	"compress/gzip"
 	"fmt"
 	"log"
 	"os"
@@ -190,6 +192,89 @@ func TestCompressedFile(t *testing.T) {
 	assert.True(t, compressed, "This should be a compressed file.")
 }
 
+func TestReadCompressedFiles(t *testing.T) {
+	multilineWaitPeriod = 10 * time.Millisecond
+	dir := t.TempDir()
+	stateDir := t.TempDir()
+	activeFile := filepath.Join(dir, "app.log")
+	require.NoError(t, os.WriteFile(activeFile, []byte("active\n"), 0600))
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	_, err := w.Write([]byte("rotated begin1\n append line1\nrotated begin2\n"))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	compressedFile := filepath.Join(dir, "app.log.1.gz")
+	require.NoError(t, os.WriteFile(compressedFile, buf.Bytes(), 0600))
+	old := time.Now().Add(-time.Hour)
+	require.NoError(t, os.Chtimes(compressedFile, old, old))
+
+	tt := NewLogFile()
+	tt.Log = TestLogger{t}
+	tt.FileStateFolder = stateDir
+	tt.FileConfig = []FileConfig{{
+		FilePath:              filepath.Join(dir, "app.log*"),
+		FromBeginning:         true,
+		ReadCompressedFiles:   true,
+		MultiLineStartPattern: "^rotated|^active",
+	}}
+	require.NoError(t, tt.FileConfig[0].init())
+	tt.started = true
+
+	lsrcs := tt.FindLogSrc()
+	require.Len(t, lsrcs, 2)
+	var lsrc logs.LogSrc
+	for _, src := range lsrcs {
+		if src.Description() == compressedFile {
+			lsrc = src
+		} else {
+			assert.Equal(t, activeFile, src.Description())
+			src.Stop()
+		}
+	}
+	require.NotNil(t, lsrc)
+
+	evts := make(chan logs.LogEvent)
+	lsrc.SetOutput(func(e logs.LogEvent) {
+		evts <- e
+	})
+	e1 := <-evts
+	assert.Equal(t, "rotated begin1\n append line1", e1.Message())
+	e2 := <-evts
+	assert.Equal(t, "rotated begin2", e2.Message())
+	// The source exits once the whole file has been read.
+	assert.Nil(t, <-evts)
+
+	// The file is only completed once the last event is acknowledged.
+	fingerprint, err := compressedFileFingerprint(compressedFile)
+	require.NoError(t, err)
+	_, err = os.Stat(tt.compressedFileStatePath(fingerprint))
+	assert.True(t, os.IsNotExist(err))
+	for _, e := range []logs.LogEvent{e1, e2} {
+		sle := e.(logs.StatefulLogEvent)
+		sle.RangeQueue().Enqueue(sle.Range())
+	}
+	_, err = os.Stat(tt.compressedFileStatePath(fingerprint))
+	require.NoError(t, err)
+
+	// A completed file is not read again, even after being renamed.
+	renamedFile := filepath.Join(dir, "app.log.2.gz")
+	require.NoError(t, os.Rename(compressedFile, renamedFile))
+	tt.cleanupStateFolder()
+	_, err = os.Stat(tt.compressedFileStatePath(fingerprint))
+	require.NoError(t, err)
+	targetFiles, err := tt.getTargetFiles(&tt.FileConfig[0])
+	require.NoError(t, err)
+	assert.Equal(t, []string{activeFile}, targetFiles)
+
+	// The completion state is removed once the file is gone.
+	require.NoError(t, os.Remove(renamedFile))
+	tt.cleanupStateFolder()
+	_, err = os.Stat(tt.compressedFileStatePath(fingerprint))
+	assert.True(t, os.IsNotExist(err))
+
+	tt.Stop()
+}
+
 func TestMultipleFilesForSameConfig(t *testing.T) {
 	multilineWaitPeriod = 10 * time.Millisecond
 	tmpfile1, err := createTempFile("", "tmp1_")
@@ -288,6 +373,81 @@ func TestLogsMultilineEvent(t *testing.T) {
 	tt.Stop()
 }
 
+func TestLogsMultilineModes(t *testing.T) {
+	multilineWaitPeriod = 10 * time.Millisecond
+	testCases := map[string]struct {
+		config FileConfig
+		input  string
+		want   []string
+	}{
+		"EndPattern": {
+			config: FileConfig{MultiLineEndPattern: ";$"},
+			input:  "select *\nfrom t;\nselect 1;\n",
+			want:   []string{"select *\nfrom t;", "select 1;"},
+		},
+		"ContinuePattern": {
+			config: FileConfig{MultiLineContinuePattern: "^\\s|^Caused by:"},
+			input:  "Exception: first\n\tat a\nCaused by: second\n\tat b\nnext\n",
+			want:   []string{"Exception: first\n\tat a\nCaused by: second\n\tat b", "next"},
+		},
+		"ContinuePatternNegate": {
+			config: FileConfig{MultiLineContinuePattern: "^\\d{4}-", MultiLineNegate: true},
+			input:  "2024-01-01 a\nTraceback (most recent call last):\n  File \"a.py\"\nValueError: a\n2024-01-01 b\n",
+			want:   []string{"2024-01-01 a\nTraceback (most recent call last):\n  File \"a.py\"\nValueError: a", "2024-01-01 b"},
+		},
+		"MaxLines": {
+			config: FileConfig{MultiLineMaxLines: 2},
+			input:  "a\n b\n c\nd\n",
+			want:   []string{"a\n b", " c", "d"},
+		},
+		"MaxLinesOne": {
+			config: FileConfig{MultiLineEndPattern: "^end$", MultiLineMaxLines: 1},
+			input:  "a\nb\n",
+			want:   []string{"a", "b"},
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			tmpfile, err := createTempFile("", "")
+			require.NoError(t, err)
+			defer os.Remove(tmpfile.Name())
+			_, err = tmpfile.WriteString(testCase.input)
+			require.NoError(t, err)
+
+			tt := NewLogFile()
+			tt.Log = TestLogger{t}
+			config := testCase.config
+			config.FilePath = tmpfile.Name()
+			config.FromBeginning = true
+			// the last event is published on timeout
+			config.MultiLineTimeout.Duration = 50 * time.Millisecond
+			tt.FileConfig = []FileConfig{config}
+			require.NoError(t, tt.FileConfig[0].init())
+			tt.started = true
+
+			lsrcs := tt.FindLogSrc()
+			require.Len(t, lsrcs, 1)
+			lsrc := lsrcs[0]
+			evts := make(chan logs.LogEvent)
+			lsrc.SetOutput(func(e logs.LogEvent) {
+				evts <- e
+			})
+
+			for _, want := range testCase.want {
+				select {
+				case e := <-evts:
+					assert.Equal(t, want, e.Message())
+				case <-time.After(5 * time.Second):
+					t.Fatalf("Timed out waiting for event %q", want)
+				}
+			}
+
+			lsrc.Stop()
+			tt.Stop()
+		})
+	}
+}
+
 // When file is removed, the related tail routing should exit
 func TestLogsFileRemove(t *testing.T) {
 	multilineWaitPeriod = 10 * time.Millisecond
@@ -1044,6 +1204,50 @@ func TestLogsPartialLineReading(t *testing.T) {
 	tt.Stop()
 }
 
+func TestLogFileDynamicNames(t *testing.T) {
+	dir := t.TempDir()
+	for _, app := range []string{"billing", "orders"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, "apps", app), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "apps", app, "server.log"), []byte("{\"level\":\"ERROR\"}\n"), 0644))
+	}
+
+	tt := NewLogFile()
+	tt.Log = TestLogger{t}
+	tt.FileConfig = []FileConfig{{
+		FilePath:         filepath.Join(dir, "apps") + string(filepath.Separator) + "(?P<app>[^/\\\\]+)" + string(filepath.Separator) + "*.log",
+		LogGroupName:     "/apps/{app}",
+		LogStreamName:    "{app}-{level}",
+		FromBeginning:    true,
+		PublishMultiLogs: true,
+	}}
+	require.NoError(t, tt.FileConfig[0].init())
+	tt.started = true
+
+	lsrcs := tt.FindLogSrc()
+	require.Len(t, lsrcs, 2)
+	var groups []string
+	for _, lsrc := range lsrcs {
+		groups = append(groups, lsrc.Group())
+		app := filepath.Base(lsrc.Group())
+		assert.Equal(t, app+"-{level}", lsrc.Stream())
+
+		dls, ok := lsrc.(logs.DynamicLogSrc)
+		require.True(t, ok)
+		assert.True(t, dls.IsDynamic())
+		group, stream := dls.Target(&LogEvent{msg: `{"level":"ERROR"}`})
+		assert.Equal(t, "/apps/"+app, group)
+		assert.Equal(t, app+"-ERROR", stream)
+		_, stream = dls.Target(&LogEvent{msg: "not json"})
+		assert.Equal(t, app+"-unknown", stream)
+		group, stream = dls.FallbackTarget()
+		assert.Equal(t, "/apps/"+app, group)
+		assert.Equal(t, app+"-unknown", stream)
+		lsrc.Stop()
+	}
+	assert.ElementsMatch(t, []string{"/apps/billing", "/apps/orders"}, groups)
+	tt.Stop()
+}
+
 func TestLogFileMultiLogsReading(t *testing.T) {
 	multilineWaitPeriod = 10 * time.Millisecond
 	logEntryString := "This is from Agent log"
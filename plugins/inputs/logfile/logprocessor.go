@@ -0,0 +1,209 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package logfile
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+
+	"github.com/elastic/go-grok"
+)
+
+const (
+	parseRegexProcessorType = "parse_regex"
+	parseGrokProcessorType  = "parse_grok"
+	addKeysProcessorType    = "add_keys"
+	removeKeysProcessorType = "remove_keys"
+	renameKeysProcessorType = "rename_keys"
+	maskProcessorType       = "mask"
+
+	defaultMaskReplacement = "***"
+)
+
+var (
+	validProcessorTypes = []string{
+		parseRegexProcessorType,
+		parseGrokProcessorType,
+		addKeysProcessorType,
+		removeKeysProcessorType,
+		renameKeysProcessorType,
+		maskProcessorType,
+	}
+)
+
+// LogProcessor rewrites the message of a log event before it is published.
+// Processors of a file config are applied in order:
+//   - parse_regex and parse_grok replace the message with a JSON object of
+//     the named captures of the expression, if the message matches.
+//   - add_keys, remove_keys and rename_keys change the top level keys of a
+//     message that is a JSON object.
+//   - mask replaces the matches of the expression in the message.
+type LogProcessor struct {
+	Type string `toml:"type"`
+	// Expression is a regular expression for parse_regex and mask, and a grok
+	// pattern for parse_grok.
+	Expression string `toml:"expression"`
+	// Patterns are additional grok pattern definitions used by the expression.
+	Patterns map[string]string `toml:"patterns"`
+	// Fields are the keys and values to add for add_keys, and the old and new
+	// key names for rename_keys.
+	Fields map[string]string `toml:"fields"`
+	// Keys to delete for remove_keys.
+	Keys []string `toml:"keys"`
+	// Replacement for the matches of mask. Can reference capture groups, e.g.
+	// "${1}***".
+	Replacement string `toml:"replacement"`
+
+	expressionP *regexp.Regexp
+	grok        *grok.Grok
+}
+
+func (p *LogProcessor) init() error {
+	var err error
+	switch p.Type {
+	case parseRegexProcessorType:
+		if p.expressionP, err = regexp.Compile(p.Expression); err != nil {
+			return fmt.Errorf("processor regex has issue, regexp: Compile( %v ): %v", p.Expression, err.Error())
+		}
+		if !hasNamedCaptures(p.expressionP) {
+			return fmt.Errorf("processor regex %v has no named capture groups", p.Expression)
+		}
+	case parseGrokProcessorType:
+		if p.grok, err = grok.NewComplete(p.Patterns); err != nil {
+			return fmt.Errorf("processor grok patterns have issue: %v", err)
+		}
+		if err = p.grok.Compile(p.Expression, true); err != nil {
+			return fmt.Errorf("processor grok pattern has issue, Compile( %v ): %v", p.Expression, err)
+		}
+	case maskProcessorType:
+		if p.expressionP, err = regexp.Compile(p.Expression); err != nil {
+			return fmt.Errorf("processor regex has issue, regexp: Compile( %v ): %v", p.Expression, err.Error())
+		}
+		if p.Replacement == "" {
+			p.Replacement = defaultMaskReplacement
+		}
+	case addKeysProcessorType, renameKeysProcessorType:
+		if len(p.Fields) == 0 {
+			return fmt.Errorf("processor type %s requires fields", p.Type)
+		}
+	case removeKeysProcessorType:
+		if len(p.Keys) == 0 {
+			return fmt.Errorf("processor type %s requires keys", p.Type)
+		}
+	default:
+		return fmt.Errorf("processor type %s is incorrect, valid types are: %v", p.Type, validProcessorTypes)
+	}
+	return nil
+}
+
+// Process returns the rewritten message.
+func (p *LogProcessor) Process(msg string) string {
+	switch p.Type {
+	case parseRegexProcessorType:
+		return p.parseRegex(msg)
+	case parseGrokProcessorType:
+		return p.parseGrok(msg)
+	case maskProcessorType:
+		return p.expressionP.ReplaceAllString(msg, p.Replacement)
+	default:
+		return p.rewriteKeys(msg)
+	}
+}
+
+func (p *LogProcessor) parseRegex(msg string) string {
+	match := p.expressionP.FindStringSubmatch(msg)
+	if match == nil {
+		return msg
+	}
+	fields := make(map[string]any)
+	for i, name := range p.expressionP.SubexpNames() {
+		if name != "" && match[i] != "" {
+			fields[name] = match[i]
+		}
+	}
+	return marshalOrDefault(fields, msg)
+}
+
+func (p *LogProcessor) parseGrok(msg string) string {
+	fields, err := p.grok.ParseTypedString(msg)
+	if err != nil || len(fields) == 0 {
+		return msg
+	}
+	return marshalOrDefault(fields, msg)
+}
+
+func (p *LogProcessor) rewriteKeys(msg string) string {
+	fields, ok := unmarshalObject(msg)
+	if !ok {
+		return msg
+	}
+	switch p.Type {
+	case addKeysProcessorType:
+		for k, v := range p.Fields {
+			fields[k] = v
+		}
+	case removeKeysProcessorType:
+		for _, k := range p.Keys {
+			delete(fields, k)
+		}
+	case renameKeysProcessorType:
+		renamed := make(map[string]any, len(p.Fields))
+		for from, to := range p.Fields {
+			if v, ok := fields[from]; ok {
+				delete(fields, from)
+				renamed[to] = v
+			}
+		}
+		for k, v := range renamed {
+			fields[k] = v
+		}
+	}
+	return marshalOrDefault(fields, msg)
+}
+
+// unmarshalObject returns the fields of the message if it is a JSON object.
+// Numbers are kept as is instead of being converted to float64.
+func unmarshalObject(msg string) (map[string]any, bool) {
+	if len(msg) == 0 || msg[0] != '{' {
+		return nil, false
+	}
+	d := json.NewDecoder(bytes.NewBufferString(msg))
+	d.UseNumber()
+	var fields map[string]any
+	if err := d.Decode(&fields); err != nil || d.More() {
+		return nil, false
+	}
+	return fields, true
+}
+
+// marshalOrDefault returns the fields as a JSON object, or the original
+// message if they cannot be marshaled.
+func marshalOrDefault(fields map[string]any, msg string) string {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(fields); err != nil {
+		return msg
+	}
+	return string(bytes.TrimSuffix(buf.Bytes(), []byte("\n")))
+}
+
+func hasNamedCaptures(re *regexp.Regexp) bool {
+	for _, name := range re.SubexpNames() {
+		if name != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// processMessage applies the processors to the message in order.
+func processMessage(processors []*LogProcessor, msg string) string {
+	for _, p := range processors {
+		msg = p.Process(msg)
+	}
+	return msg
+}
@@ -0,0 +1,166 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package logfile
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogProcessorInit(t *testing.T) {
+	testCases := map[string]struct {
+		processor LogProcessor
+		wantErr   bool
+	}{
+		"ParseRegex": {
+			processor: LogProcessor{Type: parseRegexProcessorType, Expression: `(?P<level>\w+) (?P<msg>.*)`},
+		},
+		"ParseRegexWithoutNamedCaptures": {
+			processor: LogProcessor{Type: parseRegexProcessorType, Expression: `(\w+) (.*)`},
+			wantErr:   true,
+		},
+		"ParseRegexInvalid": {
+			processor: LogProcessor{Type: parseRegexProcessorType, Expression: `(?P<level>\w+`},
+			wantErr:   true,
+		},
+		"ParseGrok": {
+			processor: LogProcessor{Type: parseGrokProcessorType, Expression: `%{IP:client} %{WORD:method}`},
+		},
+		"ParseGrokUnknownPattern": {
+			processor: LogProcessor{Type: parseGrokProcessorType, Expression: `%{UNKNOWN_PATTERN:x}`},
+			wantErr:   true,
+		},
+		"AddKeysWithoutFields": {
+			processor: LogProcessor{Type: addKeysProcessorType},
+			wantErr:   true,
+		},
+		"RenameKeysWithoutFields": {
+			processor: LogProcessor{Type: renameKeysProcessorType},
+			wantErr:   true,
+		},
+		"RemoveKeysWithoutKeys": {
+			processor: LogProcessor{Type: removeKeysProcessorType},
+			wantErr:   true,
+		},
+		"MaskInvalid": {
+			processor: LogProcessor{Type: maskProcessorType, Expression: `[`},
+			wantErr:   true,
+		},
+		"InvalidType": {
+			processor: LogProcessor{Type: "something wrong"},
+			wantErr:   true,
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			err := testCase.processor.init()
+			if testCase.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestLogProcessorProcess(t *testing.T) {
+	testCases := map[string]struct {
+		processor LogProcessor
+		input     string
+		want      string
+	}{
+		"ParseRegex": {
+			processor: LogProcessor{Type: parseRegexProcessorType, Expression: `^(?P<level>\w+) (?P<msg>.*)$`},
+			input:     "ERROR disk <sda> is full",
+			want:      `{"level":"ERROR","msg":"disk <sda> is full"}`,
+		},
+		"ParseRegexSkipsEmptyCaptures": {
+			processor: LogProcessor{Type: parseRegexProcessorType, Expression: `^(?P<level>\w+)(?: (?P<msg>.+))?$`},
+			input:     "ERROR",
+			want:      `{"level":"ERROR"}`,
+		},
+		"ParseRegexNoMatch": {
+			processor: LogProcessor{Type: parseRegexProcessorType, Expression: `^(?P<level>[A-Z]+):`},
+			input:     "not matching",
+			want:      "not matching",
+		},
+		"ParseGrok": {
+			processor: LogProcessor{Type: parseGrokProcessorType, Expression: `%{IP:client} %{WORD:method} %{NUMBER:bytes:int}`},
+			input:     "10.0.0.1 GET 512",
+			want:      `{"bytes":512,"client":"10.0.0.1","method":"GET"}`,
+		},
+		"ParseGrokCustomPattern": {
+			processor: LogProcessor{
+				Type:       parseGrokProcessorType,
+				Expression: `%{REQUEST_ID:request_id}`,
+				Patterns:   map[string]string{"REQUEST_ID": `req-[0-9a-f]+`},
+			},
+			input: "req-3fa9",
+			want:  `{"request_id":"req-3fa9"}`,
+		},
+		"ParseGrokNoMatch": {
+			processor: LogProcessor{Type: parseGrokProcessorType, Expression: `%{IP:client}`},
+			input:     "no address here",
+			want:      "no address here",
+		},
+		"AddKeys": {
+			processor: LogProcessor{Type: addKeysProcessorType, Fields: map[string]string{"env": "prod"}},
+			input:     `{"count":12345678901234567890,"env":"dev"}`,
+			want:      `{"count":12345678901234567890,"env":"prod"}`,
+		},
+		"AddKeysNotObject": {
+			processor: LogProcessor{Type: addKeysProcessorType, Fields: map[string]string{"env": "prod"}},
+			input:     "plain text",
+			want:      "plain text",
+		},
+		"RemoveKeys": {
+			processor: LogProcessor{Type: removeKeysProcessorType, Keys: []string{"password", "missing"}},
+			input:     `{"user":"alice","password":"secret"}`,
+			want:      `{"user":"alice"}`,
+		},
+		"RenameKeys": {
+			processor: LogProcessor{Type: renameKeysProcessorType, Fields: map[string]string{"a": "b", "b": "a", "c": "d"}},
+			input:     `{"a":1,"b":2}`,
+			want:      `{"a":2,"b":1}`,
+		},
+		"RewriteKeysTrailingData": {
+			processor: LogProcessor{Type: removeKeysProcessorType, Keys: []string{"a"}},
+			input:     `{"a":1} {"b":2}`,
+			want:      `{"a":1} {"b":2}`,
+		},
+		"Mask": {
+			processor: LogProcessor{Type: maskProcessorType, Expression: `\d{4}-\d{4}-\d{4}-\d{4}`},
+			input:     "card 1234-5678-9012-3456 declined",
+			want:      "card *** declined",
+		},
+		"MaskWithReplacement": {
+			processor: LogProcessor{Type: maskProcessorType, Expression: `(\d{4})-\d{4}-\d{4}-\d{4}`, Replacement: "${1}-****-****-****"},
+			input:     "card 1234-5678-9012-3456 declined",
+			want:      "card 1234-****-****-**** declined",
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			require.NoError(t, testCase.processor.init())
+			assert.Equal(t, testCase.want, testCase.processor.Process(testCase.input))
+		})
+	}
+}
+
+func TestProcessMessage(t *testing.T) {
+	processors := []*LogProcessor{
+		{Type: parseRegexProcessorType, Expression: `^(?P<user>\S+) (?P<token>\S+)$`},
+		{Type: removeKeysProcessorType, Keys: []string{"token"}},
+		{Type: addKeysProcessorType, Fields: map[string]string{"source": "app"}},
+		{Type: renameKeysProcessorType, Fields: map[string]string{"user": "username"}},
+		{Type: maskProcessorType, Expression: `alice`},
+	}
+	for _, p := range processors {
+		require.NoError(t, p.init())
+	}
+	assert.Equal(t, `{"source":"app","username":"***"}`, processMessage(processors, "alice abc123"))
+	assert.Equal(t, "unchanged", processMessage(nil, "unchanged"))
+}
@@ -0,0 +1,163 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package logfile
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const multilineStartState = "start"
+
+// multilineRule moves the state of a multilinePreset to next when a line
+// matches the pattern. The line is then part of the current log event.
+type multilineRule struct {
+	pattern *regexp.Regexp
+	next    string
+}
+
+// multilinePreset is a state machine that groups the lines of a stack trace
+// into the log event before it, whatever the format of the log event is.
+type multilinePreset map[string][]multilineRule
+
+// multilineState is the state of a multilinePreset for the lines of a file,
+// so each file needs its own.
+type multilineState struct {
+	preset multilinePreset
+	state  string
+}
+
+func (p multilinePreset) newState() *multilineState {
+	return &multilineState{preset: p, state: multilineStartState}
+}
+
+// isStart returns true if the line starts a new log event.
+func (s *multilineState) isStart(line string) bool {
+	if next, ok := s.preset.next(s.state, line); ok {
+		s.state = next
+		return false
+	}
+	if s.state == multilineStartState {
+		return true
+	}
+	// the stack trace is over, the line may start another one
+	s.state = multilineStartState
+	if next, ok := s.preset.next(s.state, line); ok {
+		s.state = next
+		return false
+	}
+	return true
+}
+
+// reset moves back to the start state once the log event is published
+// without a line that starts the next one, e.g. on timeout, since the stack
+// trace it was part of is over.
+func (s *multilineState) reset() {
+	s.state = multilineStartState
+}
+
+func (p multilinePreset) next(state, line string) (string, bool) {
+	for _, rule := range p[state] {
+		if rule.pattern.MatchString(line) {
+			return rule.next, true
+		}
+	}
+	return "", false
+}
+
+var (
+	javaFrame   = multilineRule{regexp.MustCompile(`^\s+at \S+`), "java"}
+	dotnetFrame = multilineRule{regexp.MustCompile(`^\s+at \S+`), "dotnet"}
+	rubyFrame   = multilineRule{regexp.MustCompile(`^\s+(\d+: )?from \S+:\d+:in `), "ruby"}
+)
+
+// multilinePresets are the state machines of the supported languages. In the
+// start state, a line that matches a rule continues the log event before it,
+// e.g. an exception logged after the message of the application.
+var multilinePresets = map[string]multilinePreset{
+	"java": {
+		multilineStartState: {
+			{regexp.MustCompile(`^Exception in thread "[^"]*" \S+`), "java"},
+			{regexp.MustCompile(`^([a-zA-Z_$][\w$]*\.)+[\w$]*(Exception|Error|Throwable)(: .*)?$`), "java"},
+			javaFrame,
+		},
+		"java": {
+			javaFrame,
+			{regexp.MustCompile(`^\s*(Caused by|Suppressed): \S+`), "java"},
+			{regexp.MustCompile(`^\s*\.\.\. \d+ (more|common frames omitted)$`), "java"},
+		},
+	},
+	"python": {
+		multilineStartState: {
+			{regexp.MustCompile(`^Traceback \(most recent call last\):$`), "python"},
+		},
+		"python": {
+			{regexp.MustCompile(`^\s`), "python"},
+			// the exception is the last line of the traceback
+			{regexp.MustCompile(`^[a-zA-Z_][\w.]*(: .*)?$`), "python_exception"},
+		},
+		"python_exception": {
+			{regexp.MustCompile(`^$`), "python_exception"},
+			{regexp.MustCompile(`^(During handling of the above exception, another exception occurred|The above exception was the direct cause of the following exception):$`), "python_chain"},
+		},
+		"python_chain": {
+			{regexp.MustCompile(`^$`), "python_chain"},
+			{regexp.MustCompile(`^Traceback \(most recent call last\):$`), "python"},
+		},
+	},
+	"go": {
+		multilineStartState: {
+			{regexp.MustCompile(`^(panic|fatal error): `), "go"},
+		},
+		"go": {
+			{regexp.MustCompile(`^$`), "go"},
+			{regexp.MustCompile(`^\s`), "go"},
+			{regexp.MustCompile(`^(goroutine \d+ \[.*\]:|created by \S+|\[signal .*\]|exit status \d+)`), "go"},
+			{regexp.MustCompile(`^(panic|fatal error): `), "go"},
+			// the function of a frame, e.g. main.(*T).run(...)
+			{regexp.MustCompile(`^[^\s:]+\(.*\)$`), "go"},
+		},
+	},
+	"dotnet": {
+		multilineStartState: {
+			{regexp.MustCompile(`^(Unhandled [eE]xception\. )?([a-zA-Z_]\w*\.)+\w*Exception(: .*)?$`), "dotnet"},
+			dotnetFrame,
+		},
+		"dotnet": {
+			dotnetFrame,
+			{regexp.MustCompile(`^\s*---> ([a-zA-Z_]\w*\.)+\w*Exception`), "dotnet"},
+			{regexp.MustCompile(`^\s*--- End of (inner exception stack trace|stack trace from previous location).*---$`), "dotnet"},
+		},
+	},
+	"ruby": {
+		multilineStartState: {
+			{regexp.MustCompile("^\\S+:\\d+:in [`'].*\\)$"), "ruby"},
+			rubyFrame,
+		},
+		"ruby": {
+			rubyFrame,
+			{regexp.MustCompile("^\\S+:\\d+:in [`']"), "ruby"},
+		},
+	},
+}
+
+// multilinePresetNames returns the names of the presets, for error messages.
+func multilinePresetNames() string {
+	names := make([]string, 0, len(multilinePresets))
+	for name := range multilinePresets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+func getMultilinePreset(name string) (multilinePreset, error) {
+	preset, ok := multilinePresets[name]
+	if !ok {
+		return nil, fmt.Errorf("multi_line_preset %q is not supported, supported presets are %s", name, multilinePresetNames())
+	}
+	return preset, nil
+}
@@ -0,0 +1,125 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package logfile
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMultilinePresets(t *testing.T) {
+	testCases := map[string]struct {
+		preset string
+		want   []string
+	}{
+		"java": {
+			preset: "java",
+			want: []string{
+				`Exception in thread "main" java.lang.NullPointerException
+	at com.example.App.main(App.java:7)`,
+				"2024-01-01 10:00:00 INFO Starting",
+				`2024-01-01 10:00:01 ERROR Request failed
+java.lang.IllegalStateException: outer
+	at com.example.App.handle(App.java:10)
+	at com.example.App.main(App.java:5)
+Caused by: java.io.IOException: inner
+	at com.example.Store.read(Store.java:20)
+	... 2 more`,
+				"2024-01-01 10:00:02 INFO Done",
+			},
+		},
+		"python": {
+			preset: "python",
+			want: []string{
+				`[2024-01-01 10:00:01] ERROR in app: Exception on /
+Traceback (most recent call last):
+  File "app.py", line 10, in handle
+    return int(value)
+ValueError: invalid literal for int() with base 10: 'a'
+
+During handling of the above exception, another exception occurred:
+
+Traceback (most recent call last):
+  File "app.py", line 12, in handle
+    raise RuntimeError("bad request")
+RuntimeError: bad request`,
+				"[2024-01-01 10:00:02] INFO: Done",
+			},
+		},
+		"go": {
+			preset: "go",
+			want: []string{
+				`panic: runtime error: index out of range [1] with length 1
+
+goroutine 1 [running]:
+main.(*Server).handle(0xc000010000, {0x0, 0x0})
+	/app/main.go:10 +0x1d
+main.main()
+	/app/main.go:5 +0x25
+exit status 2`,
+				"2024/01/01 10:00:01 restarted",
+			},
+		},
+		"dotnet": {
+			preset: "dotnet",
+			want: []string{
+				`fail: Microsoft.AspNetCore.Server.Kestrel[13]
+System.InvalidOperationException: outer
+ ---> System.ArgumentNullException: Value cannot be null.
+   at App.Store.Read() in /app/Store.cs:line 20
+   --- End of inner exception stack trace ---
+   at App.Controller.Get() in /app/Controller.cs:line 10`,
+				"info: Microsoft.Hosting.Lifetime[0]",
+			},
+		},
+		"ruby": {
+			preset: "ruby",
+			want: []string{
+				`E, [2024-01-01T10:00:01] ERROR -- : request failed
+app.rb:3:in 'divide': divided by 0 (ZeroDivisionError)
+	from app.rb:7:in 'handle'
+	from app.rb:10:in '<main>'`,
+				"I, [2024-01-01T10:00:02] INFO -- : done",
+			},
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			preset, err := getMultilinePreset(testCase.preset)
+			require.NoError(t, err)
+			lines := strings.Split(strings.Join(testCase.want, "\n"), "\n")
+			assert.Equal(t, testCase.want, groupLines(preset.newState().isStart, lines))
+		})
+	}
+}
+
+func TestMultilinePresetInit(t *testing.T) {
+	config := FileConfig{MultiLinePreset: "java"}
+	require.NoError(t, config.init())
+	assert.Empty(t, config.MultiLineStartPattern)
+	isStart, reset := config.multilineStartFn()
+	assert.NotNil(t, isStart)
+	assert.NotNil(t, reset)
+
+	config = FileConfig{MultiLinePreset: "cobol"}
+	assert.ErrorContains(t, config.init(), "dotnet, go, java, python, ruby")
+	config = FileConfig{MultiLinePreset: "java", MultiLineStartPattern: "^\\S"}
+	assert.Error(t, config.init())
+}
+
+// groupLines groups the lines into events the way the tailerSrc does.
+func groupLines(isStart func(string) bool, lines []string) []string {
+	var events []string
+	for _, line := range lines {
+		if isStart(line) || len(events) == 0 {
+			events = append(events, line)
+		} else {
+			events[len(events)-1] += "\n" + line
+		}
+	}
+	return events
+}
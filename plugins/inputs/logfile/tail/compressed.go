@@ -0,0 +1,142 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package tail
+
+import (
+	"bufio"
+	"compress/bzip2"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"path/filepath"
+
+	"github.com/influxdata/telegraf/models"
+	"github.com/klauspost/compress/zstd"
+)
+
+type decompressorFunc func(io.Reader) (io.Reader, io.Closer, error)
+
+var decompressors = map[string]decompressorFunc{
+	".gz": func(r io.Reader) (io.Reader, io.Closer, error) {
+		gr, err := gzip.NewReader(r)
+		return gr, gr, err
+	},
+	".bz2": func(r io.Reader) (io.Reader, io.Closer, error) {
+		return bzip2.NewReader(r), nil, nil
+	},
+	".zst": func(r io.Reader) (io.Reader, io.Closer, error) {
+		zr, err := zstd.NewReader(r)
+		if err != nil {
+			return nil, nil, err
+		}
+		return zr, closerFunc(zr.Close), nil
+	},
+}
+
+type closerFunc func()
+
+func (f closerFunc) Close() error {
+	f()
+	return nil
+}
+
+// IsSupportedCompressedFile returns true if the file can be read with
+// TailCompressedFile based on its extension.
+func IsSupportedCompressedFile(filename string) bool {
+	_, ok := decompressors[filepath.Ext(filename)]
+	return ok
+}
+
+// TailCompressedFile reads the compressed file once from the beginning to the
+// end through a streaming decompressor. The line offsets are offsets in the
+// decompressed content. Only a Location relative to the start is supported,
+// the decompressed content before it is skipped. The `Tail.Lines` channel is
+// closed once the end of the file is reached. Follow, ReOpen, Pipe and
+// GapsToRead are ignored.
+func TailCompressedFile(filename string, config Config) (*Tail, error) {
+	decompress, ok := decompressors[filepath.Ext(filename)]
+	if !ok {
+		return nil, fmt.Errorf("unsupported compressed file %s", filename)
+	}
+
+	t := &Tail{
+		Filename:      filename,
+		Lines:         make(chan *Line),
+		Config:        config,
+		FileDeletedCh: make(chan struct{}),
+	}
+	t.Follow = false
+	t.ReOpen = false
+
+	if t.Logger == nil {
+		t.Logger = models.NewLogger("inputs", "tail", "")
+	}
+
+	var err error
+	t.file, err = OpenFile(t.Filename)
+	if err != nil {
+		return nil, err
+	}
+	OpenFileCount.Add(1)
+
+	r, closer, err := decompress(bufio.NewReader(t.file))
+	if err != nil {
+		t.CloseFile()
+		return nil, fmt.Errorf("unable to decompress file %s: %w", filename, err)
+	}
+	t.decompressor = closer
+	if t.MaxLineSize > 0 {
+		// add 2 to account for newline characters
+		t.reader = bufio.NewReaderSize(r, t.MaxLineSize+2)
+	} else {
+		t.reader = bufio.NewReader(r)
+	}
+
+	go t.readCompressedFileSync()
+
+	return t, nil
+}
+
+func (tail *Tail) readCompressedFileSync() {
+	defer tail.Done()
+	defer tail.close()
+
+	if tail.Location != nil && tail.Location.Whence == io.SeekStart && tail.Location.Offset > 0 {
+		n, err := io.CopyN(io.Discard, tail.reader, tail.Location.Offset)
+		tail.curOffset = n
+		if err == io.EOF {
+			tail.Kill(nil)
+			return
+		} else if err != nil {
+			tail.Killf("Error skipping to offset %d in %s: %s", tail.Location.Offset, tail.Filename, err)
+			return
+		}
+	}
+
+	for {
+		line, err := tail.readLine()
+		if err == nil {
+			tail.sendLine(line, tail.curOffset)
+		} else if err == io.EOF {
+			if line != "" {
+				tail.sendLine(line, tail.curOffset)
+			}
+			// Reaching the end is the only way to stop without an error.
+			tail.Kill(nil)
+			return
+		} else {
+			tail.Killf("Error reading %s: %s", tail.Filename, err)
+			return
+		}
+
+		select {
+		case <-tail.Dying():
+			if tail.Err() == errStopAtEOF {
+				continue
+			}
+			return
+		default:
+		}
+	}
+}
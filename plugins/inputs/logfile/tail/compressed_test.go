@@ -0,0 +1,99 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package tail
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const compressedContent = "line1\nline2\r\nline3"
+
+func writeCompressedFile(t *testing.T, name string) string {
+	t.Helper()
+	var buf bytes.Buffer
+	switch filepath.Ext(name) {
+	case ".gz":
+		w := gzip.NewWriter(&buf)
+		_, err := w.Write([]byte(compressedContent))
+		require.NoError(t, err)
+		require.NoError(t, w.Close())
+	case ".zst":
+		w, err := zstd.NewWriter(&buf)
+		require.NoError(t, err)
+		_, err = w.Write([]byte(compressedContent))
+		require.NoError(t, err)
+		require.NoError(t, w.Close())
+	}
+	filename := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(filename, buf.Bytes(), 0600))
+	return filename
+}
+
+func readAllLines(t *testing.T, tail *Tail) (texts []string, offsets []int64) {
+	t.Helper()
+	for line := range tail.Lines {
+		require.NoError(t, line.Err)
+		texts = append(texts, line.Text)
+		offsets = append(offsets, line.Offset)
+	}
+	return texts, offsets
+}
+
+func TestTailCompressedFile(t *testing.T) {
+	for _, name := range []string{"app.log.gz", "app.log.zst"} {
+		t.Run(name, func(t *testing.T) {
+			filename := writeCompressedFile(t, name)
+			tail, err := TailCompressedFile(filename, Config{Logger: &testLogger{}})
+			require.NoError(t, err)
+			texts, offsets := readAllLines(t, tail)
+			assert.Equal(t, []string{"line1", "line2", "line3"}, texts)
+			assert.Equal(t, []int64{6, 13, 18}, offsets)
+			assert.NoError(t, tail.Wait())
+			assert.True(t, tail.IsFileClosed())
+
+			// Resume after the first line.
+			tail, err = TailCompressedFile(filename, Config{
+				Logger:   &testLogger{},
+				Location: &SeekInfo{Whence: io.SeekStart, Offset: 6},
+			})
+			require.NoError(t, err)
+			texts, offsets = readAllLines(t, tail)
+			assert.Equal(t, []string{"line2", "line3"}, texts)
+			assert.Equal(t, []int64{13, 18}, offsets)
+			assert.NoError(t, tail.Wait())
+
+			// Nothing left after the last line.
+			tail, err = TailCompressedFile(filename, Config{
+				Logger:   &testLogger{},
+				Location: &SeekInfo{Whence: io.SeekStart, Offset: 18},
+			})
+			require.NoError(t, err)
+			texts, _ = readAllLines(t, tail)
+			assert.Empty(t, texts)
+			assert.NoError(t, tail.Wait())
+		})
+	}
+}
+
+func TestTailCompressedFile_Invalid(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "app.log.gz")
+	require.NoError(t, os.WriteFile(filename, []byte("not gzip"), 0600))
+	_, err := TailCompressedFile(filename, Config{Logger: &testLogger{}})
+	assert.Error(t, err)
+
+	_, err = TailCompressedFile(filepath.Join(t.TempDir(), "app.log.rar"), Config{Logger: &testLogger{}})
+	assert.Error(t, err)
+
+	assert.True(t, IsSupportedCompressedFile("app.log.bz2"))
+	assert.False(t, IsSupportedCompressedFile("app.log.zip"))
+}
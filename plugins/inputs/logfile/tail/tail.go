@@ -79,6 +79,8 @@ type Tail struct {
 
 	file   *os.File
 	reader *bufio.Reader
+	// decompressor is only set for compressed files, see TailCompressedFile.
+	decompressor io.Closer
 
 	watcher watch.FileWatcher
 	changes *watch.FileChanges
@@ -187,6 +189,10 @@ func (tail *Tail) IsFileClosed() bool {
 }
 
 func (tail *Tail) CloseFile() {
+	if tail.decompressor != nil {
+		tail.decompressor.Close()
+		tail.decompressor = nil
+	}
 	if tail.file != nil {
 		tail.file.Close()
 		tail.file = nil
@@ -29,8 +29,15 @@ var (
 	defaultBufferSize   = 1
 )
 
+// multilineWaitTicks is the number of ticks a multiline event waits for more
+// lines before it is published.
+const multilineWaitTicks = 5
+
 type LogEvent struct {
-	msg    string
+	msg string
+	// raw is the message before it is processed and truncated, which the
+	// filters of the routes match.
+	raw    string
 	t      time.Time
 	offset state.Range
 	src    *tailerSrc
@@ -54,9 +61,25 @@ func (le LogEvent) Range() state.Range {
 }
 
 func (le LogEvent) RangeQueue() state.FileRangeQueue {
+	if len(le.src.completedFns) > 0 {
+		return completionQueue{FileRangeQueue: le.src.stateManager, ts: le.src}
+	}
 	return le.src.stateManager
 }
 
+// completionQueue passes the acknowledged ranges of a tailerSrc with completed
+// functions on to its state manager, and tracks them to call the functions
+// once the file is read to the end and the last published event is acked.
+type completionQueue struct {
+	state.FileRangeQueue
+	ts *tailerSrc
+}
+
+func (q completionQueue) Enqueue(r state.Range) {
+	q.FileRangeQueue.Enqueue(r)
+	q.ts.ackRange(r)
+}
+
 type tailerSrc struct {
 	group           string
 	stream          string
@@ -74,10 +97,23 @@ type tailerSrc struct {
 
 	outputFn           func(logs.LogEvent)
 	isMLStart          func(string) bool
+	resetMLState       func()
+	isMLEnd            func(string) bool
+	mlMaxLines         int
+	mlTimeout          time.Duration
 	filters            []*LogFilter
+	processors         []*LogProcessor
+	routes             []logs.LogRoute
+	logGroupSettings   *logs.LogGroupSettings
 	done               chan struct{}
 	startTailerOnce    sync.Once
 	cleanUpFns         []func()
+	completedFns       []func()
+	completionMu       sync.Mutex
+	publishedEnd       uint64
+	ackedEnd           uint64
+	readToEnd          bool
+	completed          bool
 	backpressureFdDrop bool
 	buffer             chan *LogEvent
 	stopOnce           sync.Once
@@ -85,6 +121,50 @@ type tailerSrc struct {
 
 // Verify tailerSrc implements LogSrc
 var _ logs.LogSrc = (*tailerSrc)(nil)
+var _ logs.MultiRouteLogSrc = (*tailerSrc)(nil)
+var _ logs.LogGroupSettingsProvider = (*tailerSrc)(nil)
+var _ logs.DynamicLogSrc = (*tailerSrc)(nil)
+
+// tailerRoute is an additional destination of a tailerSrc.
+type tailerRoute struct {
+	group           string
+	stream          string
+	destination     string
+	class           string
+	retentionInDays int
+	filters         []*LogFilter
+}
+
+var _ logs.LogRoute = (*tailerRoute)(nil)
+
+func (r *tailerRoute) Group() string {
+	return r.group
+}
+
+func (r *tailerRoute) Stream() string {
+	return r.stream
+}
+
+func (r *tailerRoute) Destination() string {
+	return r.destination
+}
+
+func (r *tailerRoute) Retention() int {
+	return r.retentionInDays
+}
+
+func (r *tailerRoute) Class() string {
+	return r.class
+}
+
+func (r *tailerRoute) ShouldPublish(e logs.LogEvent) bool {
+	// The filters match the message as it was read, as they do for a src
+	// without routes, so a route does not change what the others publish.
+	if le, ok := e.(*LogEvent); ok {
+		e = LogEvent{msg: le.raw, t: le.t}
+	}
+	return ShouldPublish(r.group, r.stream, r.filters, e)
+}
 
 func NewTailerSrc(
 	group, stream, destination string,
@@ -93,7 +173,11 @@ func NewTailerSrc(
 	tailer *tail.Tail,
 	autoRemoval bool,
 	isMultilineStartFn func(string) bool,
+	isMultilineEndFn func(string) bool,
+	multilineMaxLines int,
+	multilineTimeout time.Duration,
 	filters []*LogFilter,
+	processors []*LogProcessor,
 	timestampFn func(string) (time.Time, string),
 	enc encoding.Encoding,
 	maxEventSize int,
@@ -111,7 +195,11 @@ func NewTailerSrc(
 		tailer:             tailer,
 		autoRemoval:        autoRemoval,
 		isMLStart:          isMultilineStartFn,
+		isMLEnd:            isMultilineEndFn,
+		mlMaxLines:         multilineMaxLines,
+		mlTimeout:          multilineTimeout,
 		filters:            filters,
+		processors:         processors,
 		timestampFn:        timestampFn,
 		enc:                enc,
 		maxEventSize:       maxEventSize,
@@ -168,6 +256,27 @@ func (ts *tailerSrc) Class() string {
 	return ts.class
 }
 
+// IsDynamic returns true if the group or stream name references the fields of
+// the log events.
+func (ts *tailerSrc) IsDynamic() bool {
+	return hasNamePlaceholders(ts.group) || hasNamePlaceholders(ts.stream)
+}
+
+func (ts *tailerSrc) Target(e logs.LogEvent) (string, string) {
+	fields := parseEventFields(e.Message())
+	return resolveEventName(ts.group, fields, logs.SanitizeLogGroupName), resolveEventName(ts.stream, fields, logs.SanitizeLogStreamName)
+}
+
+// FallbackTarget returns the names of the log events that have none of the
+// fields, i.e. with the fields replaced by unknown.
+func (ts *tailerSrc) FallbackTarget() (string, string) {
+	return resolveEventName(ts.group, nil, logs.SanitizeLogGroupName), resolveEventName(ts.stream, nil, logs.SanitizeLogStreamName)
+}
+
+func (ts *tailerSrc) LogGroupSettings() *logs.LogGroupSettings {
+	return ts.logGroupSettings
+}
+
 func (ts *tailerSrc) Stop() {
 	ts.stopOnce.Do(func() {
 		close(ts.done)
@@ -177,10 +286,87 @@ func (ts *tailerSrc) Stop() {
 	})
 }
 
+// AddRoute adds a destination the events are published to in addition to
+// the group and stream of the tailerSrc. Must be called before SetOutput.
+func (ts *tailerSrc) AddRoute(group, stream, destination, logClass string, retentionInDays int, filters []*LogFilter) {
+	ts.routes = append(ts.routes, &tailerRoute{
+		group:           group,
+		stream:          stream,
+		destination:     destination,
+		class:           logClass,
+		retentionInDays: retentionInDays,
+		filters:         filters,
+	})
+}
+
+// Routes returns the destinations of the tailerSrc, starting with its own
+// group and stream, or nil if no route was added.
+func (ts *tailerSrc) Routes() []logs.LogRoute {
+	if len(ts.routes) == 0 {
+		return nil
+	}
+	routes := []logs.LogRoute{&tailerRoute{
+		group:           ts.group,
+		stream:          ts.stream,
+		destination:     ts.destination,
+		class:           ts.class,
+		retentionInDays: ts.retentionInDays,
+		filters:         ts.filters,
+	}}
+	return append(routes, ts.routes...)
+}
+
 func (ts *tailerSrc) AddCleanUpFn(f func()) {
 	ts.cleanUpFns = append(ts.cleanUpFns, f)
 }
 
+// AddCompletedFn adds a function that is called once the tailer has read the
+// whole file and stopped without an error, and all the published events have
+// been acknowledged. Must be called before SetOutput.
+func (ts *tailerSrc) AddCompletedFn(f func()) {
+	ts.completedFns = append(ts.completedFns, f)
+}
+
+// trackPublished records the end offset of an event that is published, which
+// has to be acknowledged before the completed functions are called.
+func (ts *tailerSrc) trackPublished(r state.Range) {
+	if len(ts.completedFns) == 0 {
+		return
+	}
+	ts.completionMu.Lock()
+	defer ts.completionMu.Unlock()
+	ts.publishedEnd = max(ts.publishedEnd, r.EndOffset())
+}
+
+func (ts *tailerSrc) ackRange(r state.Range) {
+	ts.completionMu.Lock()
+	ts.ackedEnd = max(ts.ackedEnd, r.EndOffset())
+	ts.completionMu.Unlock()
+	ts.checkCompleted()
+}
+
+func (ts *tailerSrc) setReadToEnd() {
+	ts.completionMu.Lock()
+	ts.readToEnd = true
+	ts.completionMu.Unlock()
+	ts.checkCompleted()
+}
+
+// checkCompleted calls the completed functions once, if the file has been read
+// to the end and the last published event has been acknowledged.
+func (ts *tailerSrc) checkCompleted() {
+	ts.completionMu.Lock()
+	if ts.completed || !ts.readToEnd || ts.ackedEnd < ts.publishedEnd {
+		ts.completionMu.Unlock()
+		return
+	}
+	ts.completed = true
+	ts.completionMu.Unlock()
+	for _, cf := range ts.completedFns {
+		cf()
+	}
+}
+
 func (ts *tailerSrc) Entity() *cloudwatchlogs.Entity {
 	es := entitystore.GetEntityStore()
 	if es != nil {
@@ -190,12 +376,20 @@ func (ts *tailerSrc) Entity() *cloudwatchlogs.Entity {
 }
 
 func (ts *tailerSrc) runTail() {
-	defer ts.cleanUp()
-	t := time.NewTicker(multilineWaitPeriod)
+	// The file is only removed once it is read to the end, and not when the
+	// tailer is stopped, e.g. because its config changed on a reload.
+	var ended bool
+	defer func() { ts.cleanUp(ended) }()
+	// a pending multiline event is published after the timeout, checked on each tick
+	waitPeriod := multilineWaitPeriod
+	if ts.mlTimeout > 0 {
+		waitPeriod = max(ts.mlTimeout/multilineWaitTicks, time.Millisecond)
+	}
+	t := time.NewTicker(waitPeriod)
 	defer t.Stop()
 	var init string
 	var msgBuf bytes.Buffer
-	var cnt int
+	var cnt, lines int
 	fo := state.Range{}
 	ignoreUntilNextEvent := false
 
@@ -203,7 +397,11 @@ func (ts *tailerSrc) runTail() {
 		select {
 		case line, ok := <-ts.tailer.Lines:
 			if !ok {
+				ended = true
 				ts.publishEvent(msgBuf, fo)
+				if ts.tailer.Err() == nil && len(ts.completedFns) > 0 {
+					ts.setReadToEnd()
+				}
 				return
 			}
 
@@ -233,15 +431,25 @@ func (ts *tailerSrc) runTail() {
 			} else if ignoreUntilNextEvent || msgBuf.Len() >= ts.maxEventSize {
 				ignoreUntilNextEvent = true
 				fo.ShiftInt64(line.Offset)
+				if ts.isMLEnd != nil && ts.isMLEnd(text) {
+					ts.publishEvent(msgBuf, fo)
+					msgBuf.Reset()
+					ignoreUntilNextEvent = false
+					cnt, lines = 0, 0
+				}
 				continue
 			} else {
+				// The event is truncated once it is processed, since the
+				// processors need the whole of it.
 				msgBuf.WriteString("\n")
 				msgBuf.WriteString(text)
-				if msgBuf.Len() > ts.maxEventSize {
-					msgBuf.Truncate(ts.maxEventSize - len(ts.truncateSuffix))
-					msgBuf.WriteString(ts.truncateSuffix)
-				}
 				fo.ShiftInt64(line.Offset)
+				lines++
+				if ts.isMultilineComplete(text, lines) {
+					ts.publishEvent(msgBuf, fo)
+					msgBuf.Reset()
+					cnt, lines = 0, 0
+				}
 				continue
 			}
 
@@ -249,16 +457,24 @@ func (ts *tailerSrc) runTail() {
 			msgBuf.Reset()
 			msgBuf.WriteString(init)
 			fo.ShiftInt64(line.Offset)
-			cnt = 0
+			cnt, lines = 0, 1
+			if init != "" && ts.isMultilineComplete(init, lines) {
+				ts.publishEvent(msgBuf, fo)
+				msgBuf.Reset()
+				lines = 0
+			}
 		case <-t.C:
 			if msgBuf.Len() > 0 {
 				cnt++
 			}
 
-			if cnt >= 5 {
+			if cnt >= multilineWaitTicks {
 				ts.publishEvent(msgBuf, fo)
 				msgBuf.Reset()
-				cnt = 0
+				cnt, lines = 0, 0
+				if ts.resetMLState != nil {
+					ts.resetMLState()
+				}
 			}
 		case <-ts.done:
 			return
@@ -266,6 +482,16 @@ func (ts *tailerSrc) runTail() {
 	}
 }
 
+// isMultilineComplete returns true if the line is the last line of the
+// multiline event, either because it matches the end pattern or because the
+// event has the max number of lines.
+func (ts *tailerSrc) isMultilineComplete(text string, lines int) bool {
+	if ts.isMLEnd != nil && ts.isMLEnd(text) {
+		return true
+	}
+	return ts.mlMaxLines > 0 && lines >= ts.mlMaxLines
+}
+
 func (ts *tailerSrc) publishEvent(msgBuf bytes.Buffer, fo state.Range) {
 	// helper to handle event publishing
 	if msgBuf.Len() == 0 {
@@ -275,11 +501,15 @@ func (ts *tailerSrc) publishEvent(msgBuf bytes.Buffer, fo state.Range) {
 	timestamp, modifiedMsg := ts.timestampFn(msg)
 	e := &LogEvent{
 		msg:    modifiedMsg,
+		raw:    modifiedMsg,
 		t:      timestamp,
 		offset: fo,
 		src:    ts,
 	}
-	if ShouldPublish(ts.group, ts.stream, ts.filters, e) {
+	// with routes, the filters are applied by the logs agent for each route
+	if len(ts.routes) > 0 || ShouldPublish(ts.group, ts.stream, ts.filters, e) {
+		e.msg = ts.truncate(processMessage(ts.processors, e.msg))
+		ts.trackPublished(fo)
 		if ts.backpressureFdDrop {
 			select {
 			case ts.buffer <- e:
@@ -316,6 +546,15 @@ func (ts *tailerSrc) publishEvent(msgBuf bytes.Buffer, fo state.Range) {
 	}
 }
 
+// truncate cuts the message to the max event size, ending it with the
+// truncate suffix.
+func (ts *tailerSrc) truncate(msg string) string {
+	if len(msg) <= ts.maxEventSize {
+		return msg
+	}
+	return msg[:ts.maxEventSize-len(ts.truncateSuffix)] + ts.truncateSuffix
+}
+
 func (ts *tailerSrc) runSender() {
 	log.Printf("D! [logfile] runSender starting for %s", ts.tailer.Filename)
 
@@ -342,8 +581,8 @@ func (ts *tailerSrc) runSender() {
 	}
 }
 
-func (ts *tailerSrc) cleanUp() {
-	if ts.autoRemoval {
+func (ts *tailerSrc) cleanUp(ended bool) {
+	if ts.autoRemoval && ended {
 		if err := os.Remove(ts.tailer.Filename); err != nil {
 			log.Printf("W! [logfile] Failed to auto remove file %v: %v", ts.tailer.Filename, err)
 		} else {
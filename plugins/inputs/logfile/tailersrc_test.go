@@ -79,6 +79,10 @@ func TestTailerSrc(t *testing.T) {
 		false, // AutoRemoval
 		regexp.MustCompile("^[\\S]").MatchString,
 		nil,
+		0,
+		0,
+		nil,
+		nil,
 		parseRFC3339Timestamp,
 		nil, // encoding
 		defaultMaxEventSize,
@@ -198,6 +202,10 @@ func TestStatefulLogEvent(t *testing.T) {
 		false, // AutoRemoval
 		regexp.MustCompile("^[\\S]").MatchString,
 		nil,
+		0,
+		0,
+		nil,
+		nil,
 		parseRFC3339Timestamp,
 		nil, // encoding
 		defaultMaxEventSize,
@@ -428,7 +436,11 @@ func setupTailer(t *testing.T, multiLineFn func(string) bool, maxEventSize int,
 		tailer,
 		autoRemoval,
 		multiLineFn,
+		nil,
+		0,
+		0,
 		config.Filters,
+		config.Processors,
 		parseRFC3339Timestamp,
 		nil, // encoding
 		maxEventSize,
@@ -580,3 +592,167 @@ func TestTailerSrcCloseFileDescriptorOnBufferBlock(t *testing.T) {
 	finalCount := tail.OpenFileCount.Load()
 	assert.LessOrEqual(t, finalCount, initialCount, "File count should not increase")
 }
+
+func TestTailerSrcRoutes(t *testing.T) {
+	ts := &tailerSrc{
+		group:           "group",
+		stream:          "stream",
+		destination:     "cloudwatchlogs",
+		retentionInDays: 7,
+	}
+	assert.Nil(t, ts.Routes())
+
+	filter := &LogFilter{Type: includeFilterType, Expression: "ERROR"}
+	require.NoError(t, filter.init())
+	ts.AddRoute("archive", "stream", "cloudwatchlogs", "INFREQUENT_ACCESS", -1, []*LogFilter{filter})
+
+	routes := ts.Routes()
+	require.Len(t, routes, 2)
+	assert.Equal(t, "group", routes[0].Group())
+	assert.Equal(t, 7, routes[0].Retention())
+	assert.True(t, routes[0].ShouldPublish(LogEvent{msg: "INFO: all good"}))
+	assert.Equal(t, "archive", routes[1].Group())
+	assert.Equal(t, "INFREQUENT_ACCESS", routes[1].Class())
+	assert.True(t, routes[1].ShouldPublish(LogEvent{msg: "ERROR: failed"}))
+	assert.False(t, routes[1].ShouldPublish(LogEvent{msg: "INFO: all good"}))
+}
+
+func TestTailerSrcRoutesFilterRawMessage(t *testing.T) {
+	mask := &LogProcessor{Type: maskProcessorType, Expression: `password=\S+`}
+	require.NoError(t, mask.init())
+	secret := &LogFilter{Type: includeFilterType, Expression: "password="}
+	require.NoError(t, secret.init())
+	ending := &LogFilter{Type: includeFilterType, Expression: "TAIL$"}
+	require.NoError(t, ending.init())
+	var got []logs.LogEvent
+	ts := &tailerSrc{
+		group:          "group",
+		stream:         "stream",
+		filters:        []*LogFilter{secret},
+		processors:     []*LogProcessor{mask},
+		maxEventSize:   40,
+		truncateSuffix: "[T]",
+		timestampFn: func(msg string) (time.Time, string) {
+			return time.Time{}, msg
+		},
+		outputFn: func(e logs.LogEvent) {
+			got = append(got, e)
+		},
+	}
+	ts.AddRoute("archive", "stream", "cloudwatchlogs", "", -1, []*LogFilter{ending})
+
+	var msgBuf bytes.Buffer
+	msgBuf.WriteString("login password=hunter2 " + strings.Repeat("x", 40) + " TAIL")
+	ts.publishEvent(msgBuf, state.Range{})
+	require.Len(t, got, 1)
+	assert.Equal(t, "login *** "+strings.Repeat("x", 27)+"[T]", got[0].Message())
+	// Both routes match the message as it was read, not the masked and
+	// truncated one.
+	routes := ts.Routes()
+	require.Len(t, routes, 2)
+	assert.True(t, routes[0].ShouldPublish(got[0]))
+	assert.True(t, routes[1].ShouldPublish(got[0]))
+}
+
+func TestTailerSrcProcessBeforeTruncate(t *testing.T) {
+	processors := []*LogProcessor{
+		{Type: parseRegexProcessorType, Expression: `^(?P<level>\S+) .*$`},
+	}
+	for _, p := range processors {
+		require.NoError(t, p.init())
+	}
+	var got []string
+	ts := &tailerSrc{
+		maxEventSize:   24,
+		truncateSuffix: "[T]",
+		processors:     processors,
+		timestampFn: func(msg string) (time.Time, string) {
+			return time.Time{}, msg
+		},
+		outputFn: func(e logs.LogEvent) {
+			got = append(got, e.Message())
+		},
+	}
+	var msgBuf bytes.Buffer
+	// The whole event is parsed, even though it is longer than the max size.
+	msgBuf.WriteString("INFO " + strings.Repeat("x", 40))
+	ts.publishEvent(msgBuf, state.Range{})
+	// The processed event is truncated.
+	msgBuf.Reset()
+	msgBuf.WriteString(strings.Repeat("y", 40))
+	processors[0].Expression = `^(?P<message>.*)$`
+	require.NoError(t, processors[0].init())
+	ts.publishEvent(msgBuf, state.Range{})
+	assert.Equal(t, []string{`{"level":"INFO"}`, `{"message":"yyyyyyyyy[T]`}, got)
+}
+
+func TestTailerSrcMultilinePresetTimeout(t *testing.T) {
+	file, err := os.CreateTemp(t.TempDir(), "tailsrctest-*.log")
+	require.NoError(t, err)
+	defer file.Close()
+	tailer, err := tail.TailFile(file.Name(), tail.Config{
+		Follow:      true,
+		Location:    &tail.SeekInfo{Whence: io.SeekStart, Offset: 0},
+		MustExist:   true,
+		Poll:        true,
+		MaxLineSize: defaultMaxEventSize,
+	})
+	require.NoError(t, err)
+	m := state.NewFileRangeManager(state.ManagerConfig{StateFileDir: t.TempDir(), Name: "state"})
+
+	preset, err := getMultilinePreset("python")
+	require.NoError(t, err)
+	s := preset.newState()
+	ts := NewTailerSrc(
+		"groupName", "streamName",
+		"destination",
+		m,
+		util.StandardLogGroupClass,
+		"tailsrctest-*.log",
+		tailer,
+		false, // AutoRemoval
+		s.isStart,
+		nil,
+		0,
+		100*time.Millisecond,
+		nil,
+		nil,
+		parseRFC3339Timestamp,
+		nil, // encoding
+		defaultMaxEventSize,
+		defaultTruncateSuffix,
+		1,
+		"",
+	)
+	ts.resetMLState = s.reset
+
+	defer ts.Stop()
+
+	events := make(chan string, 10)
+	ts.SetOutput(func(evt logs.LogEvent) {
+		if evt != nil {
+			events <- evt.Message()
+		}
+	})
+	next := func() string {
+		select {
+		case msg := <-events:
+			return msg
+		case <-time.After(5 * time.Second):
+			require.Fail(t, "no event is published")
+			return ""
+		}
+	}
+
+	fmt.Fprintln(file, "Traceback (most recent call last):")
+	fmt.Fprintln(file, `  File "app.py", line 1, in <module>`)
+	assert.Equal(t, "Traceback (most recent call last):\n  File \"app.py\", line 1, in <module>", next())
+	// The traceback published on timeout is over, so the indented lines that
+	// follow it are not grouped as if they were part of it.
+	fmt.Fprintln(file, "  indented")
+	fmt.Fprintln(file, "  also indented")
+	fmt.Fprintln(file, "done")
+	assert.Equal(t, "  indented", next())
+	assert.Equal(t, "  also indented", next())
+	assert.Equal(t, "done", next())
+}
@@ -25,6 +25,7 @@ func (c *Calculator) Calculate(pmb PrometheusMetricBatch) (result PrometheusMetr
 	var gauges PrometheusMetricBatch
 	var counters PrometheusMetricBatch
 	var summaries PrometheusMetricBatch
+	var histograms PrometheusMetricBatch
 
 	for _, pm := range pmb {
 		if pm.isGauge() {
@@ -43,12 +44,18 @@ func (c *Calculator) Calculate(pmb PrometheusMetricBatch) (result PrometheusMetr
 			} else {
 				summaries = appendValidValue(summaries, pm)
 			}
+		} else if pm.isHistogram() {
+			// <basename>_bucket, <basename>_count and <basename>_sum are all cumulative
+			if calculatedMetric := c.deltaCalculator.calculate(pm); calculatedMetric != nil {
+				histograms = append(histograms, calculatedMetric)
+			}
 		}
 	}
 
 	result = append(result, gauges...)
 	result = append(result, counters...)
 	result = append(result, summaries...)
+	result = append(result, histograms...)
 	return
 }
 
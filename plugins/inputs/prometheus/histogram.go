@@ -0,0 +1,143 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package prometheus
+
+import (
+	"log"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"go.opentelemetry.io/collector/pdata/pmetric"
+
+	"github.com/aws/amazon-cloudwatch-agent/metric/distribution"
+	"github.com/aws/amazon-cloudwatch-agent/metric/distribution/regular"
+)
+
+const histogramBucketLabel = "le"
+
+type histogramBucket struct {
+	upperBound float64
+	count      float64
+}
+
+// histogramSeries collects the _bucket, _sum and _count series of one
+// histogram with one label set.
+type histogramSeries struct {
+	buckets  []histogramBucket
+	sum      float64
+	count    float64
+	hasCount bool
+	timeInMS int64
+}
+
+// mergeHistograms merges the _bucket, _sum and _count series of each histogram
+// into a single distribution per label set. The series are expected to already
+// hold the delta values computed by the Calculator. Histograms sharing the same
+// labels are merged into the same metricMaterial, keyed by the histogram name.
+func mergeHistograms(pmb PrometheusMetricBatch) (result []*metricMaterial) {
+	seriesMap := make(map[string]map[string]*histogramSeries)
+	tagsMap := make(map[string]map[string]string)
+	for _, pm := range pmb {
+		if !pm.isHistogram() {
+			continue
+		}
+		tags := make(map[string]string, len(pm.tags))
+		for k, v := range pm.tags {
+			if k != histogramBucketLabel {
+				tags[k] = v
+			}
+		}
+		metricKey := getTagsKey(&PrometheusMetric{tags: tags}).String()
+		if _, ok := seriesMap[metricKey]; !ok {
+			seriesMap[metricKey] = make(map[string]*histogramSeries)
+			tagsMap[metricKey] = tags
+		}
+		name := normalizeMetricName(pm.metricName, histogramSummarySuffixes)
+		hs, ok := seriesMap[metricKey][name]
+		if !ok {
+			hs = &histogramSeries{timeInMS: pm.timeInMS}
+			seriesMap[metricKey][name] = hs
+		}
+		switch {
+		case strings.HasSuffix(pm.metricName, histogramBucketSuffix):
+			upperBound, err := strconv.ParseFloat(pm.tags[histogramBucketLabel], 64)
+			if err != nil {
+				log.Printf("D! Drop histogram bucket with invalid %q label: %v", histogramBucketLabel, pm)
+				continue
+			}
+			hs.buckets = append(hs.buckets, histogramBucket{upperBound: upperBound, count: pm.metricValue})
+		case strings.HasSuffix(pm.metricName, histogramSummarySumSuffix):
+			hs.sum = pm.metricValue
+		case strings.HasSuffix(pm.metricName, histogramSummaryCountSuffix):
+			hs.count = pm.metricValue
+			hs.hasCount = true
+		}
+	}
+
+	for metricKey, histograms := range seriesMap {
+		var mm *metricMaterial
+		for name, hs := range histograms {
+			dist := hs.toDistribution()
+			if dist == nil {
+				continue
+			}
+			if mm == nil {
+				mm = &metricMaterial{tags: tagsMap[metricKey], fields: map[string]interface{}{}, timeInMS: hs.timeInMS}
+			}
+			mm.fields[name] = dist
+		}
+		if mm != nil {
+			result = append(result, mm)
+		}
+	}
+	return result
+}
+
+// toDistribution converts the cumulative bucket counts into a distribution.
+// Every bucket is represented by the midpoint of its boundaries, except the
+// first one which uses its upper bound and the +Inf one which uses the largest
+// finite bound. Returns nil if the histogram is incomplete or has no samples.
+func (hs *histogramSeries) toDistribution() distribution.Distribution {
+	if !hs.hasCount || hs.count <= 0 || len(hs.buckets) == 0 {
+		return nil
+	}
+	sort.Slice(hs.buckets, func(i, j int) bool {
+		return hs.buckets[i].upperBound < hs.buckets[j].upperBound
+	})
+
+	dp := pmetric.NewHistogramDataPoint()
+	dp.SetCount(uint64(hs.count))
+	dp.SetSum(hs.sum)
+	minimum, maximum := math.Inf(1), math.Inf(-1)
+	var lowerBound, cumulative float64
+	for i, b := range hs.buckets {
+		count := b.count - cumulative
+		cumulative = math.Max(cumulative, b.count)
+		value := b.upperBound
+		if math.IsInf(b.upperBound, 1) {
+			value = lowerBound
+		} else if i > 0 {
+			value = lowerBound + (b.upperBound-lowerBound)/2
+		}
+		lowerBound = b.upperBound
+		if count <= 0 || value < distribution.MinValue || value > distribution.MaxValue {
+			continue
+		}
+		dp.ExplicitBounds().Append(value)
+		dp.BucketCounts().Append(uint64(count))
+		minimum = math.Min(minimum, value)
+		maximum = math.Max(maximum, value)
+	}
+	if dp.BucketCounts().Len() == 0 {
+		return nil
+	}
+	dp.SetMin(minimum)
+	dp.SetMax(maximum)
+
+	dist := regular.NewRegularDistribution()
+	dist.ConvertFromOtel(dp, "")
+	return dist
+}
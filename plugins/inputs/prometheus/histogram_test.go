@@ -0,0 +1,90 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package prometheus
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aws/amazon-cloudwatch-agent/metric/distribution"
+)
+
+func buildHistogram(path string, timeInMS int64, buckets map[string]float64, sum float64, count float64) (result PrometheusMetricBatch) {
+	for le, v := range buckets {
+		result = append(result, &PrometheusMetric{
+			tags:        map[string]string{"path": path, "le": le, prometheusMetricTypeKey: "histogram"},
+			metricName:  "request_duration_seconds_bucket",
+			metricValue: v,
+			metricType:  "histogram",
+			timeInMS:    timeInMS,
+		})
+	}
+	result = append(result, &PrometheusMetric{
+		tags:        map[string]string{"path": path, prometheusMetricTypeKey: "histogram"},
+		metricName:  "request_duration_seconds_sum",
+		metricValue: sum,
+		metricType:  "histogram",
+		timeInMS:    timeInMS,
+	}, &PrometheusMetric{
+		tags:        map[string]string{"path": path, prometheusMetricTypeKey: "histogram"},
+		metricName:  "request_duration_seconds_count",
+		metricValue: count,
+		metricType:  "histogram",
+		timeInMS:    timeInMS,
+	})
+	return result
+}
+
+func TestMergeHistograms(t *testing.T) {
+	calculator := NewCalculator()
+
+	// The first scrape only initializes the deltas.
+	pmb := calculator.Calculate(buildHistogram("/", 1000, map[string]float64{"0.1": 2, "1": 5, "+Inf": 6}, 3, 6))
+	assert.Empty(t, mergeHistograms(pmb))
+
+	pmb = calculator.Calculate(buildHistogram("/", 2000, map[string]float64{"0.1": 4, "1": 8, "+Inf": 10}, 6.5, 10))
+	pmb = append(pmb, &PrometheusMetric{
+		tags:        map[string]string{"path": "/"},
+		metricName:  "up",
+		metricValue: 1,
+		metricType:  "gauge",
+		timeInMS:    2000,
+	})
+	mms := mergeHistograms(pmb)
+	require.Len(t, mms, 1)
+	assert.Equal(t, map[string]string{"path": "/", prometheusMetricTypeKey: "histogram"}, mms[0].tags)
+	assert.EqualValues(t, 2000, mms[0].timeInMS)
+	require.Len(t, mms[0].fields, 1)
+
+	dist, ok := mms[0].fields["request_duration_seconds"].(distribution.Distribution)
+	require.True(t, ok)
+	assert.Equal(t, 4.0, dist.SampleCount())
+	assert.Equal(t, 3.5, dist.Sum())
+	assert.Equal(t, 0.1, dist.Minimum())
+	assert.Equal(t, 1.0, dist.Maximum())
+	values, counts := dist.ValuesAndCounts()
+	got := map[float64]float64{}
+	for i := range values {
+		got[values[i]] = counts[i]
+	}
+	assert.Equal(t, map[float64]float64{0.1: 2, 0.55: 1, 1: 1}, got)
+
+	// Non-histogram metrics are left to mergeMetrics.
+	metricMaterials := mergeMetrics(pmb)
+	require.Len(t, metricMaterials, 1)
+	assert.Equal(t, map[string]interface{}{"up": 1.0}, metricMaterials[0].fields)
+}
+
+func TestMergeHistograms_Incomplete(t *testing.T) {
+	pmb := buildHistogram("/", 1000, map[string]float64{"0.1": 2, "+Inf": 2}, 0.1, 2)
+	// Drop the _count series.
+	pmb = pmb[:len(pmb)-1]
+	assert.Empty(t, mergeHistograms(pmb))
+
+	// No new observations since the previous scrape.
+	pmb = buildHistogram("/", 1000, map[string]float64{"0.1": 0, "+Inf": 0}, 0, 0)
+	assert.Empty(t, mergeHistograms(pmb))
+}
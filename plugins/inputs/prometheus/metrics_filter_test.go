@@ -22,7 +22,7 @@ func buildTestData(pass int, drop int) (result PrometheusMetricBatch) {
 	for i := 0; i < drop; i++ {
 		pm := &PrometheusMetric{
 			metricName: fmt.Sprintf("dropped_id_%d", i),
-			metricType: "histogram",
+			metricType: "untyped",
 		}
 		result = append(result, pm)
 	}
@@ -80,6 +80,20 @@ func TestMetricsFilterFilter_ExceedMaxDroppedMetrics(t *testing.T) {
 	assert.Equal(t, 0, len(p.droppedMetrics))
 }
 
+func TestMetricsFilterFilter_KeepHistograms(t *testing.T) {
+	p := &MetricsFilter{maxDropMetricsLogged: 3}
+
+	batch := PrometheusMetricBatch{
+		{metricName: "request_duration_seconds_bucket", metricType: "histogram"},
+		{metricName: "request_duration_seconds_sum", metricType: "histogram"},
+		{metricName: "request_duration_seconds_count", metricType: "histogram"},
+	}
+	batch = p.Filter(batch)
+
+	assert.Equal(t, 3, len(batch))
+	assert.Equal(t, 0, len(p.droppedMetrics))
+}
+
 func TestMetricsFilterFilter_MetricsFilter(t *testing.T) {
 	mf := NewMetricsFilter()
 	assert.Equal(t, MaxDropMetricsLogged, mf.maxDropMetricsLogged)
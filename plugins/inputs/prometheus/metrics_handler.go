@@ -46,21 +46,28 @@ func (mh *metricsHandler) handle(pmb PrometheusMetricBatch) {
 	// Add metric type info
 	pmb = mh.mtHandler.Handle(pmb)
 
-	// Filter out Histogram and untyped Metrics and adding logging
+	// Filter out untyped Metrics and adding logging
 	pmb = mh.filter.Filter(pmb)
 
-	// do calculation: calculate delta for counter
+	// do calculation: calculate delta for counter and histogram
 	pmb = mh.calculator.Calculate(pmb)
 
 	// do merge: merge metrics which are sharing same tags
 	metricMaterials := mergeMetrics(pmb)
 
+	// do merge: merge histogram buckets, sum and count into distributions
+	histogramMaterials := mergeHistograms(pmb)
+
 	// set emf
 	mh.setEmfMetadata(metricMaterials)
+	mh.setEmfMetadata(histogramMaterials)
 
 	for _, metricMaterial := range metricMaterials {
 		mh.acc.AddFields("prometheus", metricMaterial.fields, metricMaterial.tags, time.UnixMilli(metricMaterial.timeInMS))
 	}
+	for _, metricMaterial := range histogramMaterials {
+		mh.acc.AddHistogram("prometheus", metricMaterial.fields, metricMaterial.tags, time.UnixMilli(metricMaterial.timeInMS))
+	}
 }
 
 // set timestamp, version, logstream
@@ -39,6 +39,10 @@ func getUniqMetricKey(pm *PrometheusMetric) string {
 func mergeMetrics(pmb PrometheusMetricBatch) (result []*metricMaterial) {
 	metricMap := make(map[string]*metricMaterial)
 	for _, pm := range pmb {
+		if pm.isHistogram() {
+			// histograms are merged into distributions by mergeHistograms
+			continue
+		}
 		metricKey := getMetricKeyForMerging(pm)
 		metricData := metricMap[metricKey]
 		metricMap[metricKey] = mergePrometheusMetrics(metricData, pm)
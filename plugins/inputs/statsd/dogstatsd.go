@@ -0,0 +1,208 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package statsd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	eventPrefix        = "_e{"
+	serviceCheckPrefix = "_sc|"
+
+	eventType        = "event"
+	serviceCheckType = "service_check"
+)
+
+// serviceCheckStatuses are the names of the service check status codes.
+var serviceCheckStatuses = []string{"OK", "WARNING", "CRITICAL", "UNKNOWN"}
+
+// dogStatsDEvent is the JSON object of a dogstatsd event or service check
+// that is published to CloudWatch Logs.
+type dogStatsDEvent struct {
+	Type           string            `json:"type"`
+	Title          string            `json:"title,omitempty"`
+	Text           string            `json:"text,omitempty"`
+	Name           string            `json:"name,omitempty"`
+	Status         string            `json:"status,omitempty"`
+	Message        string            `json:"message,omitempty"`
+	Hostname       string            `json:"hostname,omitempty"`
+	Priority       string            `json:"priority,omitempty"`
+	AlertType      string            `json:"alert_type,omitempty"`
+	AggregationKey string            `json:"aggregation_key,omitempty"`
+	SourceTypeName string            `json:"source_type_name,omitempty"`
+	Tags           map[string]string `json:"tags,omitempty"`
+
+	timestamp time.Time
+}
+
+// parseEventLine parses a dogstatsd event, which looks like
+// _e{<title length>,<text length>}:<title>|<text>|d:<timestamp>|h:<hostname>|p:<priority>|t:<alert type>|k:<aggregation key>|s:<source type>|#<tags>
+// and publishes it.
+func (s *Statsd) parseEventLine(line string) error {
+	e, err := parseEvent(line)
+	if err != nil {
+		log.Printf("E! Error: %s, Unable to parse event: %s\n", err, line)
+		return errors.New("Error Parsing statsd event")
+	}
+	return s.publishEvent(e)
+}
+
+// parseServiceCheckLine parses a dogstatsd service check, which looks like
+// _sc|<name>|<status>|d:<timestamp>|h:<hostname>|#<tags>|m:<message>
+// and publishes it.
+func (s *Statsd) parseServiceCheckLine(line string) error {
+	e, err := parseServiceCheck(line)
+	if err != nil {
+		log.Printf("E! Error: %s, Unable to parse service check: %s\n", err, line)
+		return errors.New("Error Parsing statsd service check")
+	}
+	return s.publishEvent(e)
+}
+
+func parseEvent(line string) (*dogStatsDEvent, error) {
+	header, rest, ok := strings.Cut(line[len(eventPrefix):], "}:")
+	if !ok {
+		return nil, errors.New("missing the title and text lengths")
+	}
+	titleLen, textLen, ok := strings.Cut(header, ",")
+	if !ok {
+		return nil, errors.New("missing the text length")
+	}
+	titleLength, err := strconv.Atoi(titleLen)
+	if err != nil || titleLength < 0 {
+		return nil, fmt.Errorf("invalid title length %s", titleLen)
+	}
+	textLength, err := strconv.Atoi(textLen)
+	if err != nil || textLength < 0 {
+		return nil, fmt.Errorf("invalid text length %s", textLen)
+	}
+	if titleLength == 0 {
+		return nil, errors.New("empty title")
+	}
+	if titleLength > len(rest) || textLength > len(rest)-titleLength-1 || rest[titleLength] != '|' {
+		return nil, errors.New("title and text do not match their lengths")
+	}
+
+	e := &dogStatsDEvent{
+		Type:      eventType,
+		Title:     rest[:titleLength],
+		Text:      strings.ReplaceAll(rest[titleLength+1:titleLength+1+textLength], `\n`, "\n"),
+		Priority:  "normal",
+		AlertType: "info",
+	}
+	rest = rest[titleLength+1+textLength:]
+	if rest == "" {
+		return e, nil
+	}
+	if rest[0] != '|' {
+		return nil, errors.New("text does not match its length")
+	}
+	for _, segment := range strings.Split(rest[1:], "|") {
+		switch {
+		case strings.HasPrefix(segment, "d:"):
+			if e.timestamp, err = parseTimestamp(segment[2:]); err != nil {
+				return nil, err
+			}
+		case strings.HasPrefix(segment, "h:"):
+			e.Hostname = segment[2:]
+		case strings.HasPrefix(segment, "p:"):
+			e.Priority = segment[2:]
+		case strings.HasPrefix(segment, "t:"):
+			e.AlertType = segment[2:]
+		case strings.HasPrefix(segment, "k:"):
+			e.AggregationKey = segment[2:]
+		case strings.HasPrefix(segment, "s:"):
+			e.SourceTypeName = segment[2:]
+		case strings.HasPrefix(segment, "#"):
+			e.Tags = make(map[string]string)
+			parseDataDogTags(segment[1:], e.Tags)
+		default:
+			log.Printf("W! Ignoring unknown event field %s\n", segment)
+		}
+	}
+	return e, nil
+}
+
+func parseServiceCheck(line string) (*dogStatsDEvent, error) {
+	rest := line[len(serviceCheckPrefix):]
+	// the message is the last field and can contain pipes
+	rest, message, _ := strings.Cut(rest, "|m:")
+	segments := strings.Split(rest, "|")
+	if len(segments) < 2 || segments[0] == "" {
+		return nil, errors.New("missing the name or status")
+	}
+	status, err := strconv.Atoi(segments[1])
+	if err != nil || status < 0 || status >= len(serviceCheckStatuses) {
+		return nil, fmt.Errorf("invalid status %s", segments[1])
+	}
+
+	e := &dogStatsDEvent{
+		Type:    serviceCheckType,
+		Name:    segments[0],
+		Status:  serviceCheckStatuses[status],
+		Message: strings.ReplaceAll(message, `\n`, "\n"),
+	}
+	for _, segment := range segments[2:] {
+		switch {
+		case strings.HasPrefix(segment, "d:"):
+			if e.timestamp, err = parseTimestamp(segment[2:]); err != nil {
+				return nil, err
+			}
+		case strings.HasPrefix(segment, "h:"):
+			e.Hostname = segment[2:]
+		case strings.HasPrefix(segment, "#"):
+			e.Tags = make(map[string]string)
+			parseDataDogTags(segment[1:], e.Tags)
+		default:
+			log.Printf("W! Ignoring unknown service check field %s\n", segment)
+		}
+	}
+	return e, nil
+}
+
+// parseTimestamp parses the unix timestamp in seconds of an event or service
+// check.
+func parseTimestamp(value string) (time.Time, error) {
+	seconds, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid timestamp %s", value)
+	}
+	return time.Unix(seconds, 0), nil
+}
+
+// publishEvent publishes the event to the log source as a JSON object. The
+// events are dropped if no log group is configured.
+func (s *Statsd) publishEvent(e *dogStatsDEvent) error {
+	s.Lock()
+	src := s.eventSrc
+	warn := src == nil && !s.eventsWarned
+	if warn {
+		s.eventsWarned = true
+	}
+	s.Unlock()
+	if src == nil {
+		if warn {
+			log.Printf("W! Dropping statsd events and service checks, log_group_name is not configured\n")
+		}
+		return nil
+	}
+
+	msg, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	t := e.timestamp
+	if t.IsZero() {
+		t = time.Now()
+	}
+	src.publish(&LogEvent{msg: string(msg), t: t})
+	return nil
+}
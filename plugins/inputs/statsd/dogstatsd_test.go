@@ -0,0 +1,128 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package statsd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aws/amazon-cloudwatch-agent/logs"
+)
+
+func TestParseEvent(t *testing.T) {
+	testCases := map[string]struct {
+		line string
+		want *dogStatsDEvent
+	}{
+		"Minimal": {
+			line: "_e{5,4}:title|text",
+			want: &dogStatsDEvent{Type: eventType, Title: "title", Text: "text", Priority: "normal", AlertType: "info"},
+		},
+		"AllFields": {
+			line: `_e{10,12}:deploy|api|line1\nline2|d:1700000000|h:web-1|p:low|t:warning|k:deploys|s:jenkins|#env:prod,canary`,
+			want: &dogStatsDEvent{
+				Type:           eventType,
+				Title:          "deploy|api",
+				Text:           "line1\nline2",
+				Hostname:       "web-1",
+				Priority:       "low",
+				AlertType:      "warning",
+				AggregationKey: "deploys",
+				SourceTypeName: "jenkins",
+				Tags:           map[string]string{"env": "prod", "canary": "<empty>"},
+				timestamp:      time.Unix(1700000000, 0),
+			},
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			got, err := parseEvent(testCase.line)
+			require.NoError(t, err)
+			assert.Equal(t, testCase.want, got)
+		})
+	}
+
+	for _, line := range []string{
+		"_e{5,4}title|text",
+		"_e{5}:title|text",
+		"_e{a,4}:title|text",
+		"_e{0,4}:|text",
+		"_e{5,10}:title|text",
+		"_e{5,2}:title|text",
+		"_e{5,4}:title|text|d:yesterday",
+		"_e{-1,4}:title|text",
+		"_e{5,-1}:title|text",
+		"_e{9223372036854775807,1}:a|b",
+		"_e{1,9223372036854775807}:a|b",
+	} {
+		_, err := parseEvent(line)
+		assert.Error(t, err, line)
+	}
+}
+
+func TestParseServiceCheck(t *testing.T) {
+	got, err := parseServiceCheck("_sc|db.up|2|d:1700000000|h:db-1|#env:prod|m:connection refused | retrying")
+	require.NoError(t, err)
+	assert.Equal(t, &dogStatsDEvent{
+		Type:      serviceCheckType,
+		Name:      "db.up",
+		Status:    "CRITICAL",
+		Message:   "connection refused | retrying",
+		Hostname:  "db-1",
+		Tags:      map[string]string{"env": "prod"},
+		timestamp: time.Unix(1700000000, 0),
+	}, got)
+
+	got, err = parseServiceCheck("_sc|db.up|0")
+	require.NoError(t, err)
+	assert.Equal(t, &dogStatsDEvent{Type: serviceCheckType, Name: "db.up", Status: "OK"}, got)
+
+	for _, line := range []string{"_sc|db.up", "_sc||0", "_sc|db.up|4", "_sc|db.up|ok"} {
+		_, err = parseServiceCheck(line)
+		assert.Error(t, err, line)
+	}
+}
+
+func TestPublishEvents(t *testing.T) {
+	s := &Statsd{
+		AllowedPendingMessages: defaultAllowPendingMessage,
+		LogGroupName:           "statsd-events",
+		LogStreamName:          "host",
+		Destination:            "cloudwatchlogs",
+	}
+	require.NoError(t, s.Start(nil))
+	defer s.Stop()
+
+	srcs := s.FindLogSrc()
+	require.Len(t, srcs, 1)
+	assert.Empty(t, s.FindLogSrc())
+	src := srcs[0]
+	assert.Equal(t, "statsd-events", src.Group())
+	assert.Equal(t, "host", src.Stream())
+	assert.Equal(t, "cloudwatchlogs", src.Destination())
+
+	events := make(chan logs.LogEvent, 2)
+	src.SetOutput(func(e logs.LogEvent) {
+		if e != nil {
+			events <- e
+		}
+	})
+	s.in <- []byte("_e{5,4}:title|text|d:1700000000\n_sc|db.up|1|m:slow")
+
+	e := <-events
+	assert.JSONEq(t, `{"type":"event","title":"title","text":"text","priority":"normal","alert_type":"info"}`, e.Message())
+	assert.Equal(t, time.Unix(1700000000, 0), e.Time())
+	e = <-events
+	assert.JSONEq(t, `{"type":"service_check","name":"db.up","status":"WARNING","message":"slow"}`, e.Message())
+}
+
+func TestPublishEvents_NoLogGroup(t *testing.T) {
+	s := NewTestStatsd()
+	assert.NoError(t, s.parseEventLine("_e{5,4}:title|text"))
+	assert.True(t, s.eventsWarned)
+	assert.Empty(t, s.FindLogSrc())
+}
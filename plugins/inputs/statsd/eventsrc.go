@@ -0,0 +1,137 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package statsd
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"github.com/aws/amazon-cloudwatch-agent/logs"
+	"github.com/aws/amazon-cloudwatch-agent/sdk/service/cloudwatchlogs"
+)
+
+const (
+	eventSrcBufferSize = 1000
+	dropWarnInterval   = time.Minute
+)
+
+type LogEvent struct {
+	msg string
+	t   time.Time
+}
+
+var _ logs.LogEvent = (*LogEvent)(nil)
+
+func (le LogEvent) Message() string {
+	return le.msg
+}
+
+func (le LogEvent) Time() time.Time {
+	return le.t
+}
+
+// Done is a no-op since datagrams cannot be received again.
+func (le LogEvent) Done() {
+}
+
+// eventSrc publishes the dogstatsd events and service checks to a log group
+// and stream.
+type eventSrc struct {
+	group       string
+	stream      string
+	destination string
+
+	events    chan logs.LogEvent
+	outputFn  func(logs.LogEvent)
+	done      chan struct{}
+	startOnce sync.Once
+	stopOnce  sync.Once
+
+	mu           sync.Mutex
+	dropped      int
+	lastDropWarn time.Time
+}
+
+var _ logs.LogSrc = (*eventSrc)(nil)
+
+func newEventSrc(group, stream, destination string) *eventSrc {
+	return &eventSrc{
+		group:       group,
+		stream:      stream,
+		destination: destination,
+		events:      make(chan logs.LogEvent, eventSrcBufferSize),
+		done:        make(chan struct{}),
+	}
+}
+
+// publish queues the event until it is picked up by the output. The event is
+// dropped if the queue is full, as the listeners cannot apply backpressure.
+func (s *eventSrc) publish(e logs.LogEvent) {
+	select {
+	case s.events <- e:
+		return
+	default:
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.dropped++
+	if time.Since(s.lastDropWarn) >= dropWarnInterval {
+		log.Printf("W! [statsd] Dropped %d events for %s/%s because the output is not keeping up", s.dropped, s.group, s.stream)
+		s.dropped = 0
+		s.lastDropWarn = time.Now()
+	}
+}
+
+func (s *eventSrc) SetOutput(fn func(logs.LogEvent)) {
+	if fn == nil {
+		return
+	}
+	s.outputFn = fn
+	s.startOnce.Do(func() { go s.run() })
+}
+
+func (s *eventSrc) run() {
+	defer s.outputFn(nil)
+	for {
+		select {
+		case e := <-s.events:
+			s.outputFn(e)
+		case <-s.done:
+			return
+		}
+	}
+}
+
+func (s *eventSrc) Group() string {
+	return s.group
+}
+
+func (s *eventSrc) Stream() string {
+	return s.stream
+}
+
+func (s *eventSrc) Description() string {
+	return "statsd events"
+}
+
+func (s *eventSrc) Destination() string {
+	return s.destination
+}
+
+func (s *eventSrc) Retention() int {
+	return -1
+}
+
+func (s *eventSrc) Class() string {
+	return ""
+}
+
+func (s *eventSrc) Stop() {
+	s.stopOnce.Do(func() { close(s.done) })
+}
+
+func (s *eventSrc) Entity() *cloudwatchlogs.Entity {
+	return nil
+}
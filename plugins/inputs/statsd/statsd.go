@@ -4,10 +4,12 @@
 package statsd
 
 import (
+	"bufio"
 	"errors"
 	"fmt"
 	"log"
 	"net"
+	"os"
 	"sort"
 	"strconv"
 	"strings"
@@ -18,6 +20,7 @@ import (
 	"github.com/influxdata/telegraf"
 	"github.com/influxdata/telegraf/plugins/inputs"
 
+	"github.com/aws/amazon-cloudwatch-agent/logs"
 	"github.com/aws/amazon-cloudwatch-agent/metric/distribution"
 	"github.com/aws/amazon-cloudwatch-agent/plugins/inputs/statsd/graphite"
 )
@@ -31,6 +34,7 @@ const (
 
 	defaultSeparator           = "_"
 	defaultAllowPendingMessage = 10000
+	defaultMaxTCPConnections   = 250
 )
 
 var dropwarn = "E! Error: statsd message queue full. " +
@@ -40,6 +44,18 @@ var dropwarn = "E! Error: statsd message queue full. " +
 type Statsd struct {
 	// Address & Port to serve from
 	ServiceAddress string
+	// Address & Port to accept newline separated lines over TCP from, disabled if empty
+	TCPServiceAddress string `toml:"tcp_service_address"`
+	// Maximum number of TCP connections, new connections are closed once reached
+	MaxTCPConnections int `toml:"max_tcp_connections"`
+	// Path of the Unix datagram socket to serve from, disabled if empty
+	SocketPath string `toml:"socket_path"`
+
+	// The log group and stream that the dogstatsd events and service checks
+	// are published to. They are dropped if LogGroupName is empty.
+	LogGroupName  string `toml:"log_group_name"`
+	LogStreamName string `toml:"log_stream_name"`
+	Destination   string `toml:"destination"`
 
 	// Number of messages allowed to queue up in between calls to Gather. If this
 	// fills up, packets will get dropped until the next Gather interval is ran.
@@ -65,7 +81,8 @@ type Statsd struct {
 	sync.Mutex
 	wg sync.WaitGroup
 	// drops tracks the number of dropped metrics.
-	drops int
+	drops   int
+	started bool
 
 	// Channel for all incoming statsd packets
 	in   chan []byte
@@ -82,11 +99,23 @@ type Statsd struct {
 	// bucket -> influx templates
 	Templates []string
 
-	listener *net.UDPConn
+	listener    *net.UDPConn
+	tcpListener net.Listener
+	udsListener *net.UnixConn
+
+	connsMu sync.Mutex
+	conns   map[net.Conn]struct{}
+
+	// Source of the dogstatsd events and service checks
+	eventSrc     *eventSrc
+	newSrcs      []logs.LogSrc
+	eventsWarned bool
 
 	graphiteParser *graphite.GraphiteParser
 }
 
+var _ logs.LogCollection = (*Statsd)(nil)
+
 // One statsd metric, form is <bucket>:<value>|<mtype>|@<samplerate>
 type metric struct {
 	name       string
@@ -134,6 +163,20 @@ const sampleConfig = `
   ## Address and port to host UDP listener on
   service_address = ":8125"
 
+  ## Address and port to host TCP listener on, with newline separated lines
+  # tcp_service_address = ":8125"
+  ## Maximum number of concurrent TCP connections to allow
+  # max_tcp_connections = 250
+
+  ## Path of the Unix datagram socket to listen on
+  # socket_path = "/var/run/datadog/dsd.socket"
+
+  ## Log group and stream to publish the dogstatsd events (_e) and service
+  ## checks (_sc) to, as JSON objects. They are dropped if not set.
+  # log_group_name = "statsd-events"
+  # log_stream_name = "my-host"
+  # destination = "cloudwatchlogs"
+
   ## The following configuration options control when telegraf clears it's cache
   ## of previous values. If set to false, then telegraf will only clear it's
   ## cache when the daemon is restarted.
@@ -212,7 +255,15 @@ func (s *Statsd) Gather(acc telegraf.Accumulator) error {
 	return nil
 }
 
+// Start starts the listeners. It is called by both the metrics pipeline and
+// the log agent, which share the plugin, so it only starts them once.
 func (s *Statsd) Start(_ telegraf.Accumulator) error {
+	s.Lock()
+	defer s.Unlock()
+	if s.started {
+		return nil
+	}
+
 	// Make data structures
 	s.done = make(chan struct{})
 	s.in = make(chan []byte, s.AllowedPendingMessages)
@@ -221,53 +272,172 @@ func (s *Statsd) Start(_ telegraf.Accumulator) error {
 	s.counters = make(map[string]cachedcounter)
 	s.sets = make(map[string]cachedset)
 	s.timings = make(map[string]cachedtimings)
+	s.conns = make(map[net.Conn]struct{})
 
 	if s.MetricSeparator == "" {
 		s.MetricSeparator = defaultSeparator
 	}
+	if s.MaxTCPConnections <= 0 {
+		s.MaxTCPConnections = defaultMaxTCPConnections
+	}
+
+	if err := s.listen(); err != nil {
+		s.closeListeners()
+		return err
+	}
+
+	if s.LogGroupName != "" {
+		stream := s.LogStreamName
+		if stream == "" {
+			stream, _ = os.Hostname()
+		}
+		s.eventSrc = newEventSrc(s.LogGroupName, stream, s.Destination)
+		s.newSrcs = append(s.newSrcs, s.eventSrc)
+	}
 
-	s.wg.Add(2)
-	// Start the UDP listener
-	go s.udpListen()
+	s.wg.Add(1)
 	// Start the line parser
 	go s.parser()
+	s.started = true
 	log.Printf("I! Started the statsd service on %s\n", s.ServiceAddress)
 	return nil
 }
 
-// udpListen starts listening for udp packets on the configured port.
-func (s *Statsd) udpListen() error {
+// listen opens the UDP, TCP and Unix datagram socket listeners that are
+// configured and starts reading from them.
+func (s *Statsd) listen() error {
+	if s.ServiceAddress != "" {
+		address, err := net.ResolveUDPAddr("udp", s.ServiceAddress)
+		if err != nil {
+			return fmt.Errorf("invalid statsd service address %s: %w", s.ServiceAddress, err)
+		}
+		if s.listener, err = net.ListenUDP("udp", address); err != nil {
+			return fmt.Errorf("ListenUDP - %w", err)
+		}
+		log.Println("I! Statsd listener listening on: ", s.listener.LocalAddr().String())
+		s.wg.Add(1)
+		go s.udpListen()
+	}
+	if s.TCPServiceAddress != "" {
+		var err error
+		if s.tcpListener, err = net.Listen("tcp", s.TCPServiceAddress); err != nil {
+			return fmt.Errorf("ListenTCP - %w", err)
+		}
+		log.Println("I! Statsd TCP listener listening on: ", s.tcpListener.Addr().String())
+		s.wg.Add(1)
+		go s.tcpListen()
+	}
+	if s.SocketPath != "" {
+		// remove the socket of a previous run
+		if err := os.Remove(s.SocketPath); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("unable to remove statsd socket %s: %w", s.SocketPath, err)
+		}
+		var err error
+		if s.udsListener, err = net.ListenUnixgram("unixgram", &net.UnixAddr{Name: s.SocketPath, Net: "unixgram"}); err != nil {
+			return fmt.Errorf("ListenUnixgram - %w", err)
+		}
+		log.Println("I! Statsd listener listening on socket: ", s.SocketPath)
+		s.wg.Add(1)
+		go s.udsListen()
+	}
+	return nil
+}
+
+// udpListen reads the udp packets of the listener until it is closed.
+func (s *Statsd) udpListen() {
 	defer s.wg.Done()
-	var err error
-	address, _ := net.ResolveUDPAddr("udp", s.ServiceAddress)
-	s.listener, err = net.ListenUDP("udp", address)
-	if err != nil {
-		log.Fatalf("ERROR: ListenUDP - %s", err)
+	buf := make([]byte, UDP_MAX_PACKET_SIZE)
+	for {
+		n, _, err := s.listener.ReadFromUDP(buf)
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return
+			}
+			log.Printf("E! Error READ: %s\n", err.Error())
+			continue
+		}
+		s.enqueue(buf[:n])
 	}
-	log.Println("I! Statsd listener listening on: ", s.listener.LocalAddr().String())
+}
 
+// udsListen reads the datagrams of the Unix socket until it is closed.
+func (s *Statsd) udsListen() {
+	defer s.wg.Done()
 	buf := make([]byte, UDP_MAX_PACKET_SIZE)
 	for {
-		select {
-		case <-s.done:
-			return nil
-		default:
-			n, _, err := s.listener.ReadFromUDP(buf)
-			if err != nil && !strings.Contains(err.Error(), "closed network") {
-				log.Printf("E! Error READ: %s\n", err.Error())
-				continue
+		n, _, err := s.udsListener.ReadFromUnix(buf)
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return
 			}
-			bufCopy := make([]byte, n)
-			copy(bufCopy, buf[:n])
-
-			select {
-			case s.in <- bufCopy:
-			default:
-				s.drops++
-				if s.drops == 1 || s.AllowedPendingMessages == 0 || s.drops%s.AllowedPendingMessages == 0 {
-					log.Printf(dropwarn, s.drops)
-				}
+			log.Printf("E! Error READ: %s\n", err.Error())
+			continue
+		}
+		s.enqueue(buf[:n])
+	}
+}
+
+// tcpListen accepts TCP connections until the listener is closed.
+func (s *Statsd) tcpListen() {
+	defer s.wg.Done()
+	for {
+		conn, err := s.tcpListener.Accept()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return
 			}
+			log.Printf("E! Error accepting statsd TCP connection: %s\n", err.Error())
+			continue
+		}
+		s.connsMu.Lock()
+		if len(s.conns) >= s.MaxTCPConnections {
+			s.connsMu.Unlock()
+			log.Printf("W! Closing statsd TCP connection from %s, already %d connections open\n", conn.RemoteAddr(), s.MaxTCPConnections)
+			conn.Close()
+			continue
+		}
+		s.conns[conn] = struct{}{}
+		s.connsMu.Unlock()
+		s.wg.Add(1)
+		go s.handleTCPConn(conn)
+	}
+}
+
+// handleTCPConn reads the newline separated lines of the connection until it
+// is closed.
+func (s *Statsd) handleTCPConn(conn net.Conn) {
+	defer s.wg.Done()
+	defer func() {
+		s.connsMu.Lock()
+		delete(s.conns, conn)
+		s.connsMu.Unlock()
+		conn.Close()
+	}()
+	scanner := bufio.NewScanner(conn)
+	scanner.Buffer(make([]byte, 0, 4096), UDP_MAX_PACKET_SIZE)
+	for scanner.Scan() {
+		s.enqueue(scanner.Bytes())
+	}
+	if err := scanner.Err(); err != nil && !errors.Is(err, net.ErrClosed) {
+		log.Printf("E! Error reading statsd TCP connection from %s: %s\n", conn.RemoteAddr(), err.Error())
+	}
+}
+
+// enqueue copies the packet to the s.in channel, or drops it if the channel is
+// full.
+func (s *Statsd) enqueue(packet []byte) {
+	bufCopy := make([]byte, len(packet))
+	copy(bufCopy, packet)
+
+	select {
+	case s.in <- bufCopy:
+	default:
+		s.Lock()
+		s.drops++
+		drops := s.drops
+		s.Unlock()
+		if drops == 1 || s.AllowedPendingMessages == 0 || drops%s.AllowedPendingMessages == 0 {
+			log.Printf(dropwarn, drops)
 		}
 	}
 }
@@ -286,7 +456,13 @@ func (s *Statsd) parser() error {
 			lines := strings.Split(string(packet), "\n")
 			for _, line := range lines {
 				line = strings.TrimSpace(line)
-				if line != "" {
+				switch {
+				case line == "":
+				case strings.HasPrefix(line, eventPrefix):
+					s.parseEventLine(line)
+				case strings.HasPrefix(line, serviceCheckPrefix):
+					s.parseServiceCheckLine(line)
+				default:
 					s.parseStatsdLine(line)
 				}
 			}
@@ -310,24 +486,7 @@ func (s *Statsd) parseStatsdLine(line string) error {
 		for _, segment := range pipesplit {
 			if len(segment) > 0 && segment[0] == '#' {
 				// we have ourselves a tag; they are comma separated
-				tagstr := segment[1:]
-				tags := strings.Split(tagstr, ",")
-				for _, tag := range tags {
-					ts := strings.SplitN(tag, ":", 2)
-					var k, v string
-					switch len(ts) {
-					case 1:
-						// just a tag
-						k = ts[0]
-						v = "<empty>" //cloudwatch does not allow empty string
-					case 2:
-						k = ts[0]
-						v = ts[1]
-					}
-					if k != "" {
-						lineTags[k] = v
-					}
-				}
+				parseDataDogTags(segment[1:], lineTags)
 			} else {
 				recombinedSegments = append(recombinedSegments, segment)
 			}
@@ -375,7 +534,7 @@ func (s *Statsd) parseStatsdLine(line string) error {
 
 		// Validate metric type
 		switch pipesplit[1] {
-		case "g", "c", "s", "ms", "h":
+		case "g", "c", "s", "ms", "h", "d":
 			m.mtype = pipesplit[1]
 		default:
 			log.Printf("E! Error: Statsd Metric type %s unsupported", pipesplit[1])
@@ -392,7 +551,7 @@ func (s *Statsd) parseStatsdLine(line string) error {
 		}
 
 		switch m.mtype {
-		case "g", "ms", "h":
+		case "g", "ms", "h", "d":
 			v, err := strconv.ParseFloat(pipesplit[0], 64)
 			if err != nil {
 				log.Printf("E! Error: parsing value to float64: %s\n", line)
@@ -432,6 +591,8 @@ func (s *Statsd) parseStatsdLine(line string) error {
 			m.tags["metric_type"] = "timing"
 		case "h":
 			m.tags["metric_type"] = "histogram"
+		case "d":
+			m.tags["metric_type"] = "distribution"
 		}
 
 		if len(lineTags) > 0 {
@@ -495,6 +656,26 @@ func (s *Statsd) parseName(bucket string) (string, string, map[string]string) {
 	return name, field, tags
 }
 
+// parseDataDogTags parses the comma separated datadog tags into the map.
+func parseDataDogTags(tagstr string, tags map[string]string) {
+	for _, tag := range strings.Split(tagstr, ",") {
+		ts := strings.SplitN(tag, ":", 2)
+		var k, v string
+		switch len(ts) {
+		case 1:
+			// just a tag
+			k = ts[0]
+			v = "<empty>" //cloudwatch does not allow empty string
+		case 2:
+			k = ts[0]
+			v = ts[1]
+		}
+		if k != "" {
+			tags[k] = v
+		}
+	}
+}
+
 // Parse the key,value out of a string that looks like "key=value"
 func parseKeyValue(keyvalue string) (string, string) {
 	var key, val string
@@ -519,7 +700,7 @@ func (s *Statsd) aggregate(m metric) {
 	defer s.Unlock()
 
 	switch m.mtype {
-	case "ms", "h":
+	case "ms", "h", "d":
 		// Check if the measurement exists
 		cached, ok := s.timings[m.hash]
 		if !ok {
@@ -604,14 +785,55 @@ func (s *Statsd) aggregate(m metric) {
 }
 
 func (s *Statsd) Stop() {
+	s.Lock()
+	if !s.started {
+		s.Unlock()
+		return
+	}
+	s.started = false
 	log.Println("D! Stopping the statsd service")
 	close(s.done)
-	s.listener.Close()
+	s.closeListeners()
+	src := s.eventSrc
+	s.eventSrc = nil
+	s.Unlock()
+
 	s.wg.Wait()
 	close(s.in)
+	if src != nil {
+		src.Stop()
+	}
 	log.Println("D! Stopped the statsd service")
 }
 
+func (s *Statsd) closeListeners() {
+	if s.listener != nil {
+		s.listener.Close()
+	}
+	if s.tcpListener != nil {
+		s.tcpListener.Close()
+	}
+	if s.udsListener != nil {
+		s.udsListener.Close()
+		os.Remove(s.SocketPath)
+	}
+	s.connsMu.Lock()
+	defer s.connsMu.Unlock()
+	for conn := range s.conns {
+		conn.Close()
+	}
+}
+
+// FindLogSrc returns the source of the dogstatsd events and service checks
+// once it is started.
+func (s *Statsd) FindLogSrc() []logs.LogSrc {
+	s.Lock()
+	defer s.Unlock()
+	srcs := s.newSrcs
+	s.newSrcs = nil
+	return srcs
+}
+
 func init() {
 	inputs.Add("statsd", func() telegraf.Input {
 		return &Statsd{
@@ -622,6 +844,8 @@ func init() {
 			DeleteGauges:           true,
 			DeleteSets:             true,
 			DeleteTimings:          true,
+			MaxTCPConnections:      defaultMaxTCPConnections,
+			Destination:            "cloudwatchlogs",
 		}
 	})
 }
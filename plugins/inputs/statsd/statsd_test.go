@@ -7,10 +7,15 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"net"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/influxdata/telegraf/testutil"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/aws/amazon-cloudwatch-agent/metric/distribution"
 	"github.com/aws/amazon-cloudwatch-agent/metric/distribution/seh1"
@@ -41,6 +46,7 @@ func TestParse_ValidLines(t *testing.T) {
 		"valid:45|g",
 		"valid.timer:45|ms",
 		"valid.timer:45|h",
+		"valid.distribution:45|d",
 	}
 
 	for _, line := range valid_lines {
@@ -293,6 +299,38 @@ func TestParse_Timings(t *testing.T) {
 	assert.Equal(t, dist, fields[defaultFieldName])
 }
 
+func TestParse_Distributions(t *testing.T) {
+	s := NewTestStatsd()
+	s.ParseDataDogTags = true
+	acc := &testutil.Accumulator{}
+
+	valid_lines := []string{
+		"test.distribution:1|d|#env:prod",
+		"test.distribution:11|d|@0.5|#env:prod",
+	}
+
+	for _, line := range valid_lines {
+		err := s.parseStatsdLine(line)
+		if err != nil {
+			t.Errorf("Parsing line %s should not have resulted in an error\n", line)
+		}
+	}
+	assert.Error(t, s.parseStatsdLine("test.distribution:+1|d"))
+
+	s.Gather(acc)
+
+	dist := distribution.NewDistribution()
+	assert.NoError(t, dist.AddEntry(1, 1))
+	assert.NoError(t, dist.AddEntry(11, 2))
+
+	metrics := acc.Metrics
+	assert.Equal(t, 1, len(metrics))
+	metric := metrics[0]
+	assert.Equal(t, "test_distribution", metric.Measurement)
+	assert.Equal(t, map[string]string{"metric_type": "distribution", "env": "prod"}, metric.Tags)
+	assert.Equal(t, dist, metric.Fields[defaultFieldName])
+}
+
 func TestParseScientificNotation(t *testing.T) {
 	s := NewTestStatsd()
 	sciNotationLines := []string{
@@ -1479,6 +1517,61 @@ func test_validate_gauge(
 	return nil
 }
 
+func TestStatsd_Listeners(t *testing.T) {
+	dir, err := os.MkdirTemp("", "statsd")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	socketPath := filepath.Join(dir, "dsd.socket")
+
+	s := &Statsd{
+		ServiceAddress:         "127.0.0.1:0",
+		TCPServiceAddress:      "127.0.0.1:0",
+		SocketPath:             socketPath,
+		AllowedPendingMessages: defaultAllowPendingMessage,
+		DeleteCounters:         true,
+	}
+	require.NoError(t, s.Start(nil))
+	// the log agent starts the shared plugin again
+	require.NoError(t, s.Start(nil))
+	defer s.Stop()
+
+	udp, err := net.Dial("udp", s.listener.LocalAddr().String())
+	require.NoError(t, err)
+	defer udp.Close()
+	_, err = udp.Write([]byte("udp.counter:1|c"))
+	require.NoError(t, err)
+
+	uds, err := net.Dial("unixgram", socketPath)
+	require.NoError(t, err)
+	defer uds.Close()
+	_, err = uds.Write([]byte("uds.counter:2|c"))
+	require.NoError(t, err)
+
+	tcp, err := net.Dial("tcp", s.tcpListener.Addr().String())
+	require.NoError(t, err)
+	defer tcp.Close()
+	_, err = tcp.Write([]byte("tcp.counter:3|c\ntcp.counter:4|c\n"))
+	require.NoError(t, err)
+
+	counts := make(map[string]interface{})
+	assert.Eventually(t, func() bool {
+		acc := &testutil.Accumulator{}
+		require.NoError(t, s.Gather(acc))
+		for _, m := range acc.Metrics {
+			counts[m.Measurement] = m.Fields[defaultFieldName].(int64) + toInt64(counts[m.Measurement])
+		}
+		return len(counts) == 3 && counts["tcp_counter"] == int64(7)
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, map[string]interface{}{"udp_counter": int64(1), "uds_counter": int64(2), "tcp_counter": int64(7)}, counts)
+}
+
+func toInt64(v interface{}) int64 {
+	if i, ok := v.(int64); ok {
+		return i
+	}
+	return 0
+}
+
 func init() {
 	distribution.NewDistribution = seh1.NewSEH1Distribution
 }
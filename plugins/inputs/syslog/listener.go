@@ -0,0 +1,293 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package syslog
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+
+	tlsint "github.com/influxdata/telegraf/plugins/common/tls"
+)
+
+const (
+	ProtocolUDP = "udp"
+	ProtocolTCP = "tcp"
+	ProtocolTLS = "tls"
+
+	EventFormatText = "text"
+	EventFormatJSON = "json"
+
+	defaultMaxMessageSize = 64 * 1024
+	maxDatagramSize       = 64 * 1024
+	// maxOctetCountDigits bounds the length prefix of an octet counted frame.
+	maxOctetCountDigits = 9
+)
+
+// ListenerConfig is the configuration of a single syslog listener.
+type ListenerConfig struct {
+	// Protocol is one of udp, tcp or tls.
+	Protocol string `toml:"protocol"`
+	// Address to listen on, e.g. ":514".
+	Address string `toml:"service_address"`
+	// Format is one of auto, rfc5424 or rfc3164.
+	Format string `toml:"format"`
+	// EventFormat is text to publish the received message as is or json to
+	// publish the parsed fields.
+	EventFormat    string `toml:"event_format"`
+	MaxMessageSize int    `toml:"max_message_size"`
+	tlsint.ServerConfig
+
+	// LogGroupName and LogStreamName can contain the {syslog_hostname},
+	// {syslog_app_name}, {syslog_facility} and {syslog_severity} placeholders,
+	// which are replaced with the fields of each message.
+	LogGroupName  string `toml:"log_group_name"`
+	LogStreamName string `toml:"log_stream_name"`
+	LogGroupClass string `toml:"log_group_class"`
+	Destination   string `toml:"destination"`
+	Retention     int    `toml:"retention_in_days"`
+}
+
+func (c *ListenerConfig) init() error {
+	switch c.Protocol {
+	case ProtocolUDP, ProtocolTCP, ProtocolTLS:
+	default:
+		return fmt.Errorf("invalid syslog protocol %q", c.Protocol)
+	}
+	switch c.Format {
+	case "":
+		c.Format = FormatAuto
+	case FormatAuto, FormatRFC5424, FormatRFC3164:
+	default:
+		return fmt.Errorf("invalid syslog format %q", c.Format)
+	}
+	switch c.EventFormat {
+	case "":
+		c.EventFormat = EventFormatText
+	case EventFormatText, EventFormatJSON:
+	default:
+		return fmt.Errorf("invalid syslog event format %q", c.EventFormat)
+	}
+	if c.MaxMessageSize <= 0 {
+		c.MaxMessageSize = defaultMaxMessageSize
+	}
+	if c.LogGroupName == "" {
+		return errors.New("syslog listener requires a log group name")
+	}
+	return nil
+}
+
+// listener receives syslog messages on a socket and passes each message to
+// the handler, with the parser of the goroutine that received it since the
+// parsers are not safe for concurrent use.
+type listener struct {
+	config  *ListenerConfig
+	handler func(*listener, *parser, []byte)
+
+	packetConn net.PacketConn
+	ln         net.Listener
+
+	mu     sync.Mutex
+	conns  map[net.Conn]struct{}
+	closed bool
+	wg     sync.WaitGroup
+}
+
+func newListener(config *ListenerConfig, handler func(*listener, *parser, []byte)) *listener {
+	return &listener{
+		config:  config,
+		handler: handler,
+		conns:   make(map[net.Conn]struct{}),
+	}
+}
+
+func (l *listener) start() error {
+	var err error
+	switch l.config.Protocol {
+	case ProtocolUDP:
+		if l.packetConn, err = net.ListenPacket("udp", l.config.Address); err != nil {
+			return err
+		}
+		l.wg.Add(1)
+		go l.serveUDP()
+		return nil
+	case ProtocolTLS:
+		var tlsConfig *tls.Config
+		if tlsConfig, err = l.config.ServerConfig.TLSConfig(); err != nil {
+			return err
+		}
+		if tlsConfig == nil {
+			return errors.New("syslog tls listener requires tls_cert and tls_key")
+		}
+		l.ln, err = tls.Listen("tcp", l.config.Address, tlsConfig)
+	default:
+		l.ln, err = net.Listen("tcp", l.config.Address)
+	}
+	if err != nil {
+		return err
+	}
+	l.wg.Add(1)
+	go l.serveStream()
+	return nil
+}
+
+// addr returns the address the listener is bound to.
+func (l *listener) addr() net.Addr {
+	if l.packetConn != nil {
+		return l.packetConn.LocalAddr()
+	}
+	return l.ln.Addr()
+}
+
+func (l *listener) close() {
+	l.mu.Lock()
+	l.closed = true
+	if l.packetConn != nil {
+		l.packetConn.Close()
+	}
+	if l.ln != nil {
+		l.ln.Close()
+	}
+	for conn := range l.conns {
+		conn.Close()
+	}
+	l.mu.Unlock()
+	l.wg.Wait()
+}
+
+func (l *listener) isClosed() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.closed
+}
+
+// serveUDP handles each datagram as a single message.
+func (l *listener) serveUDP() {
+	defer l.wg.Done()
+	p := newParser(l.config.Format)
+	buf := make([]byte, maxDatagramSize)
+	for {
+		n, _, err := l.packetConn.ReadFrom(buf)
+		if err != nil {
+			if l.isClosed() {
+				return
+			}
+			continue
+		}
+		if frame := trimFrame(buf[:min(n, l.config.MaxMessageSize)]); len(frame) > 0 {
+			l.handler(l, p, frame)
+		}
+	}
+}
+
+func (l *listener) serveStream() {
+	defer l.wg.Done()
+	for {
+		conn, err := l.ln.Accept()
+		if err != nil {
+			if l.isClosed() {
+				return
+			}
+			continue
+		}
+		l.mu.Lock()
+		if l.closed {
+			l.mu.Unlock()
+			conn.Close()
+			return
+		}
+		l.conns[conn] = struct{}{}
+		l.wg.Add(1)
+		l.mu.Unlock()
+		go l.handleConn(conn)
+	}
+}
+
+func (l *listener) handleConn(conn net.Conn) {
+	defer func() {
+		l.mu.Lock()
+		delete(l.conns, conn)
+		l.mu.Unlock()
+		conn.Close()
+		l.wg.Done()
+	}()
+	p := newParser(l.config.Format)
+	r := bufio.NewReader(conn)
+	for {
+		frame, err := readFrame(r, l.config.MaxMessageSize)
+		if frame = trimFrame(frame); len(frame) > 0 {
+			l.handler(l, p, frame)
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// readFrame reads the next message from a stream. Messages are either octet
+// counted ("LEN SP MSG") or terminated by a newline, as described in RFC 6587.
+// Messages larger than maxSize are truncated.
+func readFrame(r *bufio.Reader, maxSize int) ([]byte, error) {
+	b, err := r.Peek(1)
+	if err != nil {
+		return nil, err
+	}
+	if b[0] >= '1' && b[0] <= '9' {
+		return readOctetCountedFrame(r, maxSize)
+	}
+	return readNonTransparentFrame(r, maxSize)
+}
+
+func readOctetCountedFrame(r *bufio.Reader, maxSize int) ([]byte, error) {
+	prefix, err := r.ReadSlice(' ')
+	if err != nil {
+		if errors.Is(err, bufio.ErrBufferFull) {
+			err = errors.New("invalid octet count")
+		}
+		return nil, err
+	}
+	if len(prefix)-1 > maxOctetCountDigits {
+		return nil, fmt.Errorf("invalid octet count %q", prefix)
+	}
+	size, err := strconv.Atoi(string(prefix[:len(prefix)-1]))
+	if err != nil {
+		return nil, fmt.Errorf("invalid octet count %q", prefix)
+	}
+	frame := make([]byte, min(size, maxSize))
+	if _, err = io.ReadFull(r, frame); err != nil {
+		return nil, err
+	}
+	if size > maxSize {
+		if _, err = r.Discard(size - maxSize); err != nil {
+			return frame, err
+		}
+	}
+	return frame, nil
+}
+
+func readNonTransparentFrame(r *bufio.Reader, maxSize int) ([]byte, error) {
+	var frame []byte
+	for {
+		line, err := r.ReadSlice('\n')
+		if len(frame) < maxSize {
+			frame = append(frame, line[:min(len(line), maxSize-len(frame))]...)
+		}
+		if errors.Is(err, bufio.ErrBufferFull) {
+			continue
+		}
+		return frame, err
+	}
+}
+
+// trimFrame removes the trailing line break and NUL characters some senders
+// append to the message.
+func trimFrame(frame []byte) []byte {
+	return bytes.TrimRight(frame, "\r\n\x00")
+}
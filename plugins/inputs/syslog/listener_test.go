@@ -0,0 +1,66 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package syslog
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadFrame(t *testing.T) {
+	input := "11 <13>message" +
+		"<13>newline\n" +
+		"16 <13>long message" +
+		"<13>" + strings.Repeat("x", 20) + "\r\n" +
+		"<13>last"
+	r := bufio.NewReaderSize(strings.NewReader(input), 16)
+	var got []string
+	for {
+		frame, err := readFrame(r, 10)
+		if len(frame) > 0 {
+			got = append(got, string(trimFrame(frame)))
+		}
+		if err != nil {
+			assert.ErrorIs(t, err, io.EOF)
+			break
+		}
+	}
+	assert.Equal(t, []string{
+		"<13>messag",
+		"<13>newlin",
+		"<13>long m",
+		"<13>xxxxxx",
+		"<13>last",
+	}, got)
+}
+
+func TestReadFrame_InvalidOctetCount(t *testing.T) {
+	_, err := readFrame(bufio.NewReader(strings.NewReader("1234567890 <13>message")), 10)
+	assert.Error(t, err)
+	_, err = readFrame(bufio.NewReader(strings.NewReader("12a <13>message")), 10)
+	assert.Error(t, err)
+}
+
+func TestListenerConfig(t *testing.T) {
+	config := ListenerConfig{Protocol: ProtocolUDP, LogGroupName: "group"}
+	require.NoError(t, config.init())
+	assert.Equal(t, FormatAuto, config.Format)
+	assert.Equal(t, EventFormatText, config.EventFormat)
+	assert.Equal(t, defaultMaxMessageSize, config.MaxMessageSize)
+
+	invalid := []ListenerConfig{
+		{Protocol: "http", LogGroupName: "group"},
+		{Protocol: ProtocolTCP, Format: "rfc1234", LogGroupName: "group"},
+		{Protocol: ProtocolTCP, EventFormat: "xml", LogGroupName: "group"},
+		{Protocol: ProtocolTCP},
+	}
+	for _, config := range invalid {
+		assert.Error(t, config.init())
+	}
+}
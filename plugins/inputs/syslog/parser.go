@@ -0,0 +1,125 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package syslog
+
+import (
+	"bytes"
+	"encoding/json"
+	"time"
+
+	gosyslog "github.com/leodido/go-syslog/v4"
+	"github.com/leodido/go-syslog/v4/rfc3164"
+	"github.com/leodido/go-syslog/v4/rfc5424"
+)
+
+const (
+	FormatAuto    = "auto"
+	FormatRFC5424 = "rfc5424"
+	FormatRFC3164 = "rfc3164"
+
+	// nilValue is used for fields missing from the message, as in RFC 5424.
+	nilValue = "-"
+)
+
+// record is a parsed syslog message.
+type record struct {
+	Hostname       string                       `json:"hostname,omitempty"`
+	AppName        string                       `json:"app_name,omitempty"`
+	ProcID         string                       `json:"proc_id,omitempty"`
+	MsgID          string                       `json:"msg_id,omitempty"`
+	Facility       string                       `json:"facility,omitempty"`
+	Severity       string                       `json:"severity,omitempty"`
+	StructuredData map[string]map[string]string `json:"structured_data,omitempty"`
+	Message        string                       `json:"message"`
+
+	timestamp time.Time
+}
+
+func (r *record) json() (string, error) {
+	b, err := json.Marshal(r)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+type parser struct {
+	format  string
+	rfc5424 gosyslog.Machine
+	rfc3164 gosyslog.Machine
+}
+
+func newParser(format string) *parser {
+	return &parser{
+		format:  format,
+		rfc5424: rfc5424.NewParser(rfc5424.WithBestEffort()),
+		rfc3164: rfc3164.NewParser(
+			rfc3164.WithBestEffort(),
+			rfc3164.WithYear(rfc3164.CurrentYear{}),
+			rfc3164.WithRFC3339(),
+		),
+	}
+}
+
+// parse returns the fields of the message. Fields that cannot be parsed are
+// left empty. The whole line is used as the message if the PRI is invalid,
+// and everything after the PRI if the rest of the header is invalid.
+func (p *parser) parse(line []byte) record {
+	machine := p.rfc3164
+	if p.format == FormatRFC5424 || (p.format != FormatRFC3164 && isRFC5424(line)) {
+		machine = p.rfc5424
+	}
+	msg, _ := machine.Parse(line)
+	var base *gosyslog.Base
+	r := record{}
+	switch m := msg.(type) {
+	case *rfc5424.SyslogMessage:
+		base = &m.Base
+		if m.StructuredData != nil {
+			r.StructuredData = *m.StructuredData
+		}
+	case *rfc3164.SyslogMessage:
+		base = &m.Base
+	}
+	if base == nil || !base.Valid() {
+		r.Message = string(line)
+		return r
+	}
+	r.Hostname = stringValue(base.Hostname)
+	r.AppName = stringValue(base.Appname)
+	r.ProcID = stringValue(base.ProcID)
+	r.MsgID = stringValue(base.MsgID)
+	r.Facility = stringValue(base.FacilityLevel())
+	r.Severity = stringValue(base.SeverityShortLevel())
+	r.Message = stringValue(base.Message)
+	if r.Message == "" && r.Hostname == "" && base.Timestamp == nil {
+		r.Message = string(line[bytes.IndexByte(line, '>')+1:])
+	}
+	if base.Timestamp != nil {
+		r.timestamp = *base.Timestamp
+	}
+	return r
+}
+
+// isRFC5424 returns true if the PRI part is followed by a version, which
+// RFC 3164 messages do not have.
+func isRFC5424(line []byte) bool {
+	end := bytes.IndexByte(line, '>')
+	if end < 0 || end > 4 {
+		return false
+	}
+	version := line[end+1:]
+	i := 0
+	for i < len(version) && i < 3 && version[i] >= '0' && version[i] <= '9' {
+		i++
+	}
+	return i > 0 && i < len(version) && version[i] == ' '
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,92 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package syslog
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParser(t *testing.T) {
+	testCases := map[string]struct {
+		format string
+		input  string
+		want   record
+	}{
+		"RFC5424": {
+			format: FormatAuto,
+			input:  `<165>1 2003-10-11T22:14:15.003Z mymachine.example.com evntslog 1234 ID47 [exampleSDID@32473 iut="3"] An application event`,
+			want: record{
+				Hostname:       "mymachine.example.com",
+				AppName:        "evntslog",
+				ProcID:         "1234",
+				MsgID:          "ID47",
+				Facility:       "local4",
+				Severity:       "notice",
+				StructuredData: map[string]map[string]string{"exampleSDID@32473": {"iut": "3"}},
+				Message:        "An application event",
+				timestamp:      time.Date(2003, 10, 11, 22, 14, 15, 3000000, time.UTC),
+			},
+		},
+		"RFC3164": {
+			format: FormatAuto,
+			input:  `<34>Oct 11 22:14:15 mymachine su[123]: 'su root' failed for lonvick on /dev/pts/8`,
+			want: record{
+				Hostname:  "mymachine",
+				AppName:   "su",
+				ProcID:    "123",
+				Facility:  "auth",
+				Severity:  "crit",
+				Message:   "'su root' failed for lonvick on /dev/pts/8",
+				timestamp: time.Date(time.Now().Year(), 10, 11, 22, 14, 15, 0, time.UTC),
+			},
+		},
+		"RFC3164/Forced": {
+			format: FormatRFC3164,
+			input:  `<13>1 message`,
+			want: record{
+				Facility: "user",
+				Severity: "notice",
+				Message:  "1 message",
+			},
+		},
+		"RFC3164/NoHeader": {
+			format: FormatAuto,
+			input:  `<13>hello world`,
+			want: record{
+				Facility: "user",
+				Severity: "notice",
+				Message:  "hello world",
+			},
+		},
+		"Invalid": {
+			format: FormatAuto,
+			input:  "not syslog",
+			want:   record{Message: "not syslog"},
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			got := newParser(testCase.format).parse([]byte(testCase.input))
+			assert.Equal(t, testCase.want, got)
+		})
+	}
+}
+
+func TestIsRFC5424(t *testing.T) {
+	assert.True(t, isRFC5424([]byte("<165>1 2003-10-11T22:14:15.003Z host app - - - msg")))
+	assert.True(t, isRFC5424([]byte("<0>12 -")))
+	assert.False(t, isRFC5424([]byte("<34>Oct 11 22:14:15 host su: msg")))
+	assert.False(t, isRFC5424([]byte("<34>1")))
+	assert.False(t, isRFC5424([]byte("message")))
+}
+
+func TestRecordJSON(t *testing.T) {
+	r := record{Hostname: "host", Facility: "user", Severity: "info", Message: "hello"}
+	got, err := r.json()
+	assert.NoError(t, err)
+	assert.Equal(t, `{"hostname":"host","facility":"user","severity":"info","message":"hello"}`, got)
+}
@@ -0,0 +1,167 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package syslog
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/plugins/inputs"
+
+	"github.com/aws/amazon-cloudwatch-agent/logs"
+)
+
+// maxSrcsPerListener bounds the number of log group and stream combinations
+// a listener can route to, so senders cannot create an unbounded number of
+// log streams.
+const maxSrcsPerListener = 1000
+
+type srcKey struct {
+	listener *listener
+	group    string
+	stream   string
+}
+
+type Plugin struct {
+	Destination string           `toml:"destination"`
+	Listeners   []ListenerConfig `toml:"listener_config"`
+	Log         telegraf.Logger  `toml:"-"`
+
+	listeners []*listener
+
+	mu          sync.Mutex
+	srcs        map[srcKey]*syslogSrc
+	srcCount    map[*listener]int
+	newSrcs     []logs.LogSrc
+	limitWarned map[*listener]bool
+}
+
+var _ logs.LogCollection = (*Plugin)(nil)
+
+func (p *Plugin) Description() string {
+	return "A plugin to receive syslog messages over UDP, TCP or TLS"
+}
+
+func (p *Plugin) SampleConfig() string {
+	return `
+  destination = "cloudwatchlogs"
+
+  [[inputs.syslog.listener_config]]
+    ## One of udp, tcp or tls
+    protocol = "udp"
+    service_address = ":514"
+    ## One of auto, rfc5424 or rfc3164
+    format = "auto"
+    ## Publish the message as received (text) or the parsed fields (json)
+    event_format = "text"
+    ## The log group and stream names can contain the {syslog_hostname},
+    ## {syslog_app_name}, {syslog_facility} and {syslog_severity} placeholders
+    log_group_name = "syslog/{syslog_facility}"
+    log_stream_name = "{syslog_hostname}"
+
+  [[inputs.syslog.listener_config]]
+    protocol = "tls"
+    service_address = ":6514"
+    tls_cert = "/etc/ssl/certs/syslog.pem"
+    tls_key = "/etc/ssl/private/syslog.key"
+    ## Require client certificates signed by these CAs
+    # tls_allowed_cacerts = ["/etc/ssl/certs/ca.pem"]
+    log_group_name = "syslog"
+`
+}
+
+func (p *Plugin) Gather(_ telegraf.Accumulator) error {
+	return nil
+}
+
+func (p *Plugin) FindLogSrc() []logs.LogSrc {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	srcs := p.newSrcs
+	p.newSrcs = nil
+	return srcs
+}
+
+func (p *Plugin) Start(_ telegraf.Accumulator) error {
+	p.srcs = make(map[srcKey]*syslogSrc)
+	p.srcCount = make(map[*listener]int)
+	p.limitWarned = make(map[*listener]bool)
+	for i := range p.Listeners {
+		config := &p.Listeners[i]
+		if err := config.init(); err != nil {
+			p.Stop()
+			return err
+		}
+		if config.LogStreamName == "" {
+			config.LogStreamName = defaultLogStreamName
+		}
+		if config.Destination == "" {
+			config.Destination = p.Destination
+		}
+		l := newListener(config, p.handle)
+		if err := l.start(); err != nil {
+			p.Stop()
+			return fmt.Errorf("failed to start syslog listener on %s %s: %w", config.Protocol, config.Address, err)
+		}
+		p.Log.Infof("Listening for syslog messages on %s %s", config.Protocol, l.addr())
+		p.listeners = append(p.listeners, l)
+	}
+	return nil
+}
+
+func (p *Plugin) Stop() {
+	for _, l := range p.listeners {
+		l.close()
+	}
+	p.listeners = nil
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, src := range p.srcs {
+		src.Stop()
+	}
+}
+
+// handle routes the message to the source of its log group and stream.
+func (p *Plugin) handle(l *listener, parser *parser, frame []byte) {
+	r := parser.parse(frame)
+	msg := string(frame)
+	if l.config.EventFormat == EventFormatJSON {
+		var err error
+		if msg, err = r.json(); err != nil {
+			p.Log.Errorf("Unable to convert syslog message to JSON: %v", err)
+			return
+		}
+	}
+	group := resolveTemplate(l.config.LogGroupName, &r, logs.SanitizeLogGroupName)
+	stream := resolveTemplate(l.config.LogStreamName, &r, logs.SanitizeLogStreamName)
+	if src := p.getSrc(l, group, stream); src != nil {
+		src.publish(&LogEvent{msg: msg, t: r.timestamp})
+	}
+}
+
+func (p *Plugin) getSrc(l *listener, group, stream string) *syslogSrc {
+	key := srcKey{listener: l, group: group, stream: stream}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if src, ok := p.srcs[key]; ok {
+		return src
+	}
+	if p.srcCount[l] >= maxSrcsPerListener {
+		if !p.limitWarned[l] {
+			p.Log.Warnf("Dropping syslog messages for %s/%s, listener on %s already routes to %d log streams", group, stream, l.config.Address, maxSrcsPerListener)
+			p.limitWarned[l] = true
+		}
+		return nil
+	}
+	src := newSyslogSrc(group, stream, l.config, l.config.Destination)
+	p.srcs[key] = src
+	p.srcCount[l]++
+	p.newSrcs = append(p.newSrcs, src)
+	return src
+}
+
+func init() {
+	inputs.Add("syslog", func() telegraf.Input { return &Plugin{} })
+}
@@ -0,0 +1,254 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package syslog
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	tlsint "github.com/influxdata/telegraf/plugins/common/tls"
+	"github.com/influxdata/telegraf/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aws/amazon-cloudwatch-agent/logs"
+)
+
+// collectEvents waits for n events across the sources found by the plugin
+// and returns the messages by "group/stream".
+func collectEvents(t *testing.T, p *Plugin, n int) map[string][]string {
+	t.Helper()
+	events := make(chan [2]string, n)
+	got := make(map[string][]string)
+	deadline := time.After(5 * time.Second)
+	for count := 0; count < n; {
+		for _, src := range p.FindLogSrc() {
+			name := src.Group() + "/" + src.Stream()
+			src.SetOutput(func(e logs.LogEvent) {
+				if e != nil {
+					events <- [2]string{name, e.Message()}
+				}
+			})
+		}
+		select {
+		case e := <-events:
+			got[e[0]] = append(got[e[0]], e[1])
+			count++
+		case <-time.After(10 * time.Millisecond):
+		case <-deadline:
+			t.Fatalf("received %d of %d events: %v", count, n, got)
+		}
+	}
+	return got
+}
+
+func TestPlugin_UDP(t *testing.T) {
+	p := &Plugin{
+		Destination: "cloudwatchlogs",
+		Listeners: []ListenerConfig{{
+			Protocol:      ProtocolUDP,
+			Address:       "127.0.0.1:0",
+			LogGroupName:  "syslog/{syslog_facility}",
+			LogStreamName: "{syslog_hostname}.{syslog_app_name}",
+			Retention:     7,
+		}},
+		Log: &testutil.Logger{},
+	}
+	require.NoError(t, p.Start(nil))
+	defer p.Stop()
+
+	conn, err := net.Dial("udp", p.listeners[0].addr().String())
+	require.NoError(t, err)
+	defer conn.Close()
+	messages := []string{
+		"<34>Oct 11 22:14:15 host1 su: first\n",
+		"<165>1 2003-10-11T22:14:15.003Z host2 app - - - second",
+		"<34>Oct 11 22:14:16 host1 su: third",
+	}
+	for _, msg := range messages {
+		_, err = conn.Write([]byte(msg))
+		require.NoError(t, err)
+	}
+
+	got := collectEvents(t, p, 3)
+	assert.Equal(t, map[string][]string{
+		"syslog/auth/host1.su":    {"<34>Oct 11 22:14:15 host1 su: first", "<34>Oct 11 22:14:16 host1 su: third"},
+		"syslog/local4/host2.app": {"<165>1 2003-10-11T22:14:15.003Z host2 app - - - second"},
+	}, got)
+	for _, src := range p.srcs {
+		assert.Equal(t, "cloudwatchlogs", src.Destination())
+		assert.Equal(t, 7, src.Retention())
+	}
+}
+
+func TestPlugin_TCP(t *testing.T) {
+	p := &Plugin{
+		Listeners: []ListenerConfig{{
+			Protocol:     ProtocolTCP,
+			Address:      "127.0.0.1:0",
+			EventFormat:  EventFormatJSON,
+			LogGroupName: "syslog",
+		}},
+		Log: &testutil.Logger{},
+	}
+	require.NoError(t, p.Start(nil))
+	defer p.Stop()
+
+	conn, err := net.Dial("tcp", p.listeners[0].addr().String())
+	require.NoError(t, err)
+	_, err = conn.Write([]byte("<13>Oct 11 22:14:15 host app: newline\n32 <13>Oct 11 22:14:15 host app: oc"))
+	require.NoError(t, err)
+	require.NoError(t, conn.Close())
+
+	got := collectEvents(t, p, 2)
+	assert.Equal(t, map[string][]string{
+		"syslog/host": {
+			`{"hostname":"host","app_name":"app","facility":"user","severity":"notice","message":"newline"}`,
+			`{"hostname":"host","app_name":"app","facility":"user","severity":"notice","message":"oc"}`,
+		},
+	}, got)
+}
+
+func TestPlugin_TCPConnections(t *testing.T) {
+	p := &Plugin{
+		Listeners: []ListenerConfig{{
+			Protocol:      ProtocolTCP,
+			Address:       "127.0.0.1:0",
+			EventFormat:   EventFormatJSON,
+			LogGroupName:  "syslog",
+			LogStreamName: "{syslog_hostname}",
+		}},
+		Log: &testutil.Logger{},
+	}
+	require.NoError(t, p.Start(nil))
+	defer p.Stop()
+
+	// The connections are served concurrently, each with its own parser.
+	const conns, messages = 4, 20
+	var wg sync.WaitGroup
+	for i := 0; i < conns; i++ {
+		conn, err := net.Dial("tcp", p.listeners[0].addr().String())
+		require.NoError(t, err)
+		wg.Add(1)
+		go func(host string) {
+			defer wg.Done()
+			defer conn.Close()
+			for j := 0; j < messages; j++ {
+				if _, err := fmt.Fprintf(conn, "<13>Oct 11 22:14:15 %s app: %d\n", host, j); err != nil {
+					return
+				}
+			}
+		}("host" + strconv.Itoa(i))
+	}
+	wg.Wait()
+
+	got := collectEvents(t, p, conns*messages)
+	for i := 0; i < conns; i++ {
+		host := "host" + strconv.Itoa(i)
+		events := got["syslog/"+host]
+		require.Len(t, events, messages, host)
+		for j, e := range events {
+			assert.Equal(t, fmt.Sprintf(`{"hostname":%q,"app_name":"app","facility":"user","severity":"notice","message":"%d"}`, host, j), e)
+		}
+	}
+}
+
+func TestPlugin_TLS(t *testing.T) {
+	certFile, keyFile := writeTestCert(t)
+	p := &Plugin{
+		Listeners: []ListenerConfig{{
+			Protocol:     ProtocolTLS,
+			Address:      "127.0.0.1:0",
+			ServerConfig: tlsint.ServerConfig{TLSCert: certFile, TLSKey: keyFile},
+			LogGroupName: "syslog",
+		}},
+		Log: &testutil.Logger{},
+	}
+	require.NoError(t, p.Start(nil))
+	defer p.Stop()
+
+	conn, err := tls.Dial("tcp", p.listeners[0].addr().String(), &tls.Config{InsecureSkipVerify: true})
+	require.NoError(t, err)
+	_, err = conn.Write([]byte("<13>Oct 11 22:14:15 host app: secure\n"))
+	require.NoError(t, err)
+	require.NoError(t, conn.Close())
+
+	got := collectEvents(t, p, 1)
+	assert.Equal(t, map[string][]string{"syslog/host": {"<13>Oct 11 22:14:15 host app: secure"}}, got)
+}
+
+func TestPlugin_StartError(t *testing.T) {
+	p := &Plugin{
+		Listeners: []ListenerConfig{
+			{Protocol: ProtocolUDP, Address: "127.0.0.1:0", LogGroupName: "syslog"},
+			{Protocol: ProtocolTLS, Address: "127.0.0.1:0", LogGroupName: "syslog"},
+		},
+		Log: &testutil.Logger{},
+	}
+	assert.Error(t, p.Start(nil))
+	assert.Empty(t, p.listeners)
+}
+
+func TestPlugin_MaxSrcs(t *testing.T) {
+	p := &Plugin{
+		Listeners: []ListenerConfig{{Protocol: ProtocolUDP, Address: "127.0.0.1:0", LogGroupName: "syslog"}},
+		Log:       &testutil.Logger{},
+	}
+	require.NoError(t, p.Start(nil))
+	defer p.Stop()
+	l := p.listeners[0]
+	for i := 0; i < maxSrcsPerListener; i++ {
+		assert.NotNil(t, p.getSrc(l, "syslog", strconv.Itoa(i)))
+	}
+	assert.Nil(t, p.getSrc(l, "syslog", "overflow"))
+	assert.NotNil(t, p.getSrc(l, "syslog", "0"))
+	assert.Len(t, p.FindLogSrc(), maxSrcsPerListener)
+}
+
+func TestResolveTemplate(t *testing.T) {
+	r := &record{Hostname: "host:1", AppName: "my app", Facility: "local0"}
+	assert.Equal(t, "syslog/local0/my_app/-", resolveTemplate("syslog/{syslog_facility}/{syslog_app_name}/{syslog_severity}", r, logs.SanitizeLogGroupName))
+	assert.Equal(t, "host_1 my app", resolveTemplate("{syslog_hostname} {syslog_app_name}", r, logs.SanitizeLogStreamName))
+	assert.Equal(t, "{instance_id}", resolveTemplate("{instance_id}", r, logs.SanitizeLogStreamName))
+}
+
+func writeTestCert(t *testing.T) (string, string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, &template, &template, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	tmpDir := t.TempDir()
+	certFile := filepath.Join(tmpDir, "cert.pem")
+	keyFile := filepath.Join(tmpDir, "key.pem")
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600))
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600))
+	return certFile, keyFile
+}
@@ -0,0 +1,170 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package syslog
+
+import (
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/amazon-cloudwatch-agent/logs"
+	"github.com/aws/amazon-cloudwatch-agent/sdk/service/cloudwatchlogs"
+)
+
+const (
+	hostnamePlaceholder = "{syslog_hostname}"
+	appNamePlaceholder  = "{syslog_app_name}"
+	facilityPlaceholder = "{syslog_facility}"
+	severityPlaceholder = "{syslog_severity}"
+
+	defaultLogStreamName = hostnamePlaceholder
+	srcBufferSize        = 1000
+	dropWarnInterval     = time.Minute
+)
+
+type LogEvent struct {
+	msg string
+	t   time.Time
+}
+
+var _ logs.LogEvent = (*LogEvent)(nil)
+
+func (le LogEvent) Message() string {
+	return le.msg
+}
+
+func (le LogEvent) Time() time.Time {
+	return le.t
+}
+
+// Done is a no-op since messages received over the network cannot be
+// received again.
+func (le LogEvent) Done() {
+}
+
+// resolveTemplate replaces the syslog placeholders in the template with the
+// fields of the record. The sanitize function removes characters that are not
+// allowed in the resulting name.
+func resolveTemplate(template string, r *record, sanitize func(string) string) string {
+	if !strings.Contains(template, "{syslog_") {
+		return template
+	}
+	return strings.NewReplacer(
+		hostnamePlaceholder, sanitize(valueOrNil(r.Hostname)),
+		appNamePlaceholder, sanitize(valueOrNil(r.AppName)),
+		facilityPlaceholder, sanitize(valueOrNil(r.Facility)),
+		severityPlaceholder, sanitize(valueOrNil(r.Severity)),
+	).Replace(template)
+}
+
+func valueOrNil(s string) string {
+	if s == "" {
+		return nilValue
+	}
+	return s
+}
+
+// syslogSrc publishes the messages of a listener that are routed to the same
+// log group and stream.
+type syslogSrc struct {
+	group       string
+	stream      string
+	config      *ListenerConfig
+	destination string
+
+	events    chan logs.LogEvent
+	outputFn  func(logs.LogEvent)
+	done      chan struct{}
+	startOnce sync.Once
+	stopOnce  sync.Once
+
+	mu           sync.Mutex
+	dropped      int
+	lastDropWarn time.Time
+}
+
+var _ logs.LogSrc = (*syslogSrc)(nil)
+
+func newSyslogSrc(group, stream string, config *ListenerConfig, destination string) *syslogSrc {
+	return &syslogSrc{
+		group:       group,
+		stream:      stream,
+		config:      config,
+		destination: destination,
+		events:      make(chan logs.LogEvent, srcBufferSize),
+		done:        make(chan struct{}),
+	}
+}
+
+// publish queues the event until it is picked up by the output. The event is
+// dropped if the queue is full, as the listener cannot apply backpressure
+// to UDP senders.
+func (s *syslogSrc) publish(e logs.LogEvent) {
+	select {
+	case s.events <- e:
+		return
+	default:
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.dropped++
+	if time.Since(s.lastDropWarn) >= dropWarnInterval {
+		log.Printf("W! [syslog] Dropped %d messages for %s/%s because the output is not keeping up", s.dropped, s.group, s.stream)
+		s.dropped = 0
+		s.lastDropWarn = time.Now()
+	}
+}
+
+func (s *syslogSrc) SetOutput(fn func(logs.LogEvent)) {
+	if fn == nil {
+		return
+	}
+	s.outputFn = fn
+	s.startOnce.Do(func() { go s.run() })
+}
+
+func (s *syslogSrc) run() {
+	defer s.outputFn(nil)
+	for {
+		select {
+		case e := <-s.events:
+			s.outputFn(e)
+		case <-s.done:
+			return
+		}
+	}
+}
+
+func (s *syslogSrc) Group() string {
+	return s.group
+}
+
+func (s *syslogSrc) Stream() string {
+	return s.stream
+}
+
+func (s *syslogSrc) Description() string {
+	return "syslog " + s.config.Protocol + " " + s.config.Address
+}
+
+func (s *syslogSrc) Destination() string {
+	return s.destination
+}
+
+func (s *syslogSrc) Retention() int {
+	return s.config.Retention
+}
+
+func (s *syslogSrc) Class() string {
+	return s.config.LogGroupClass
+}
+
+func (s *syslogSrc) Stop() {
+	s.stopOnce.Do(func() { close(s.done) })
+}
+
+func (s *syslogSrc) Entity() *cloudwatchlogs.Entity {
+	return nil
+}
@@ -29,6 +29,9 @@ type aggregationDatum struct {
 	aggregationInterval time.Duration
 	distribution        distribution.Distribution
 	entity              cloudwatch.Entity
+	// statisticSet publishes the aggregated datum as a StatisticSet without
+	// the values and counts of its distribution.
+	statisticSet bool
 }
 
 type Aggregator interface {
@@ -42,14 +45,22 @@ type aggregator struct {
 	metricChan   chan<- *aggregationDatum
 	shutdownChan <-chan struct{}
 	wg           *sync.WaitGroup
+	preFlush     *preFlushAggregation
 }
 
 func NewAggregator(metricChan chan<- *aggregationDatum, shutdownChan <-chan struct{}, wg *sync.WaitGroup) Aggregator {
+	return newAggregator(metricChan, shutdownChan, wg, nil)
+}
+
+// newAggregator creates an aggregator that also aggregates the datums without
+// an aggregation interval according to the pre-flush aggregation, if not nil.
+func newAggregator(metricChan chan<- *aggregationDatum, shutdownChan <-chan struct{}, wg *sync.WaitGroup, preFlush *preFlushAggregation) *aggregator {
 	return &aggregator{
 		durationMap:  make(map[time.Duration]*durationAggregator),
 		metricChan:   metricChan,
 		shutdownChan: shutdownChan,
 		wg:           wg,
+		preFlush:     preFlush,
 	}
 }
 
@@ -62,11 +73,14 @@ func getAggregationKey(m *aggregationDatum, unixTime int64) string {
 		}
 		tmp[i] = fmt.Sprintf("%s=%s", *d.Name, *d.Value)
 	}
-	// Assume m.Dimensions was already sorted.
-	return fmt.Sprintf("%s:%s:%v", *m.MetricName, strings.Join(tmp, ","), unixTime)
+	// Assume m.Dimensions was already sorted. The datums of different entities
+	// are published separately, so they are not aggregated together.
+	return fmt.Sprintf("%s:%s:%s:%v", *m.MetricName, strings.Join(tmp, ","), entityToString(m.entity), unixTime)
 }
 
 func (agg *aggregator) AddMetric(m *aggregationDatum) {
+	requested := m.aggregationInterval != 0
+	agg.preFlush.apply(m)
 	if m.aggregationInterval == 0 {
 		// no aggregation interval field key, pass through directly.
 		agg.metricChan <- m
@@ -78,8 +92,9 @@ func (agg *aggregator) AddMetric(m *aggregationDatum) {
 		durationAgg = newDurationAggregator(aggDurationMapKey, agg.metricChan, agg.shutdownChan, agg.wg)
 		agg.durationMap[aggDurationMapKey] = durationAgg
 	}
-	// auto configure high resolution
-	if aggDurationMapKey < time.Minute {
+	// auto configure high resolution, unless the interval is only used to
+	// aggregate the datums before they are published
+	if requested && aggDurationMapKey < time.Minute {
 		m.SetStorageResolution(1)
 	}
 	durationAgg.addMetric(m)
@@ -110,78 +125,92 @@ func newDurationAggregator(durationInSeconds time.Duration,
 		aggregationChan:     make(chan *aggregationDatum, durationAggregationChanBufferSize),
 	}
 
+	durationAgg.wg.Add(1)
 	go durationAgg.aggregating()
 
 	return durationAgg
 }
 
 func (durationAgg *durationAggregator) aggregating() {
-	durationAgg.wg.Add(1)
-	// Sleep to align the interval to the wall clock.
-	// This initial sleep is not interrupted if the aggregator gets shutdown.
+	defer durationAgg.wg.Done()
+	// Wait to align the interval to the wall clock before the first flush.
 	now := time.Now()
-	time.Sleep(now.Truncate(durationAgg.aggregationDuration).Add(durationAgg.aggregationDuration).Sub(now))
-	durationAgg.ticker = time.NewTicker(durationAgg.aggregationDuration)
-	defer durationAgg.ticker.Stop()
+	align := time.NewTimer(now.Truncate(durationAgg.aggregationDuration).Add(durationAgg.aggregationDuration).Sub(now))
+	defer align.Stop()
+	var tick <-chan time.Time
 	for {
 		// There is no priority to select{}.
 		// If there is a new metric AND the shutdownChan is closed when this
 		// loop begins, then the behavior is random.
 		select {
 		case m := <-durationAgg.aggregationChan:
-			if m == nil || m.Timestamp == nil || m.MetricName == nil || m.Unit == nil {
-				log.Printf("E! cannot aggregate nil or partial datum")
-				continue
-			}
-			// https://docs.aws.amazon.com/AmazonCloudWatch/latest/APIReference/API_MetricDatum.html
-			aggregatedTime := m.Timestamp.Truncate(durationAgg.aggregationDuration)
-			metricMapKey := getAggregationKey(m, aggregatedTime.Unix())
-			aggregatedMetric, ok := durationAgg.metricMap[metricMapKey]
-			if !ok {
-				// First entry. Initialize it.
-				durationAgg.metricMap[metricMapKey] = m
-				if m.distribution == nil {
-					// Assume function pointer is always valid.
-					m.distribution = distribution.NewDistribution()
-					err := m.distribution.AddEntryWithUnit(*m.Value, 1, *m.Unit)
-					if err != nil {
-						if errors.Is(err, distribution.ErrUnsupportedValue) {
-							log.Printf("W! err %s, metric %s", err, *m.MetricName)
-						} else {
-							log.Printf("D! err %s, metric %s", err, *m.MetricName)
-						}
-					}
-				}
-				// Else the first entry has a distribution, so do nothing.
-			} else {
-				// Update an existing entry.
-				if m.distribution == nil {
-					err := aggregatedMetric.distribution.AddEntryWithUnit(*m.Value, 1, *m.Unit)
-					if err != nil {
-						log.Printf("W! err %s, metric %s", err, *m.MetricName)
-					}
-				} else {
-					aggregatedMetric.distribution.AddDistribution(m.distribution)
-				}
-			}
-		case <-durationAgg.ticker.C:
+			durationAgg.aggregate(m)
+		case <-align.C:
+			durationAgg.ticker = time.NewTicker(durationAgg.aggregationDuration)
+			defer durationAgg.ticker.Stop()
+			tick = durationAgg.ticker.C
+		case <-tick:
 			durationAgg.flush()
 		case <-durationAgg.shutdownChan:
 			log.Printf("D! CloudWatch: aggregating routine receives the shutdown signal, do the final flush now for aggregation interval %v", durationAgg.aggregationDuration)
+			// Aggregate the datums that were added before the shutdown.
+			for len(durationAgg.aggregationChan) > 0 {
+				durationAgg.aggregate(<-durationAgg.aggregationChan)
+			}
 			durationAgg.flush()
 			log.Printf("D! CloudWatch: aggregating routine receives the shutdown signal, exiting.")
-			durationAgg.wg.Done()
 			return
 		}
 	}
 }
 
+func (durationAgg *durationAggregator) aggregate(m *aggregationDatum) {
+	if m == nil || m.Timestamp == nil || m.MetricName == nil || m.Unit == nil {
+		log.Printf("E! cannot aggregate nil or partial datum")
+		return
+	}
+	// https://docs.aws.amazon.com/AmazonCloudWatch/latest/APIReference/API_MetricDatum.html
+	aggregatedTime := m.Timestamp.Truncate(durationAgg.aggregationDuration)
+	metricMapKey := getAggregationKey(m, aggregatedTime.Unix())
+	aggregatedMetric, ok := durationAgg.metricMap[metricMapKey]
+	if !ok {
+		// First entry. Initialize it.
+		durationAgg.metricMap[metricMapKey] = m
+		if m.distribution == nil {
+			// Assume function pointer is always valid.
+			m.distribution = distribution.NewDistribution()
+			err := m.distribution.AddEntryWithUnit(*m.Value, 1, *m.Unit)
+			if err != nil {
+				if errors.Is(err, distribution.ErrUnsupportedValue) {
+					log.Printf("W! err %s, metric %s", err, *m.MetricName)
+				} else {
+					log.Printf("D! err %s, metric %s", err, *m.MetricName)
+				}
+			}
+		}
+		// Else the first entry has a distribution, so do nothing.
+	} else {
+		// Update an existing entry.
+		if m.distribution == nil {
+			err := aggregatedMetric.distribution.AddEntryWithUnit(*m.Value, 1, *m.Unit)
+			if err != nil {
+				log.Printf("W! err %s, metric %s", err, *m.MetricName)
+			}
+		} else {
+			aggregatedMetric.distribution.AddDistribution(m.distribution)
+		}
+	}
+}
+
 func (durationAgg *durationAggregator) addMetric(m *aggregationDatum) {
 	durationAgg.aggregationChan <- m
 }
 
 func (durationAgg *durationAggregator) flush() {
 	for _, v := range durationAgg.metricMap {
+		if v.statisticSet {
+			v.toStatisticSet()
+		}
 		durationAgg.metricChan <- v
 	}
 	durationAgg.metricMap = make(map[string]*aggregationDatum)
@@ -9,6 +9,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/aws/aws-sdk-go/aws"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/aws/amazon-cloudwatch-agent/metric/distribution"
@@ -131,9 +132,110 @@ func TestAggregator_ShutdownBehavior(t *testing.T) {
 	assertNoMetricsInChan(t, metricChan)
 }
 
+func TestGetAggregationKey(t *testing.T) {
+	timestamp := time.Now()
+	tags := map[string]string{"d1key": "d1value"}
+	m1 := makeTestMetric("mname", 1, timestamp, tags, time.Second, "Percent")
+	m2 := makeTestMetric("mname", 1, timestamp, tags, time.Second, "Percent")
+	assert.Equal(t, getAggregationKey(m1, timestamp.Unix()), getAggregationKey(m2, timestamp.Unix()))
+	m2.entity = cloudwatch.Entity{KeyAttributes: map[string]*string{"Name": aws.String("service")}}
+	assert.NotEqual(t, getAggregationKey(m1, timestamp.Unix()), getAggregationKey(m2, timestamp.Unix()))
+}
+
 // TestDurationAggregator_aggregating verifies the metric's timetstamp is used to aggregate.
 // If the same metric appears multiple times in a single aggregation interval then just expect 1 aggregated metric.
 // If the same metric appears multiple times in different aggregation intervals then expect multiple aggregated metrics.
+func TestAggregator_PreFlushAggregation(t *testing.T) {
+	metricChan, shutdownChan, _ := testPreparation()
+	preFlush, err := newPreFlushAggregation(&PreFlushAggregationConfig{
+		Interval: time.Second,
+		Rules: []PreFlushAggregationRule{
+			{MetricNames: []string{"^stats_"}, Mode: PreFlushAggregationModeStatisticSet},
+			{MetricNames: []string{"^raw$"}, Mode: PreFlushAggregationModeNone},
+		},
+	}, time.Minute)
+	assert.NoError(t, err)
+	aggregator := newAggregator(metricChan, shutdownChan, &wg, preFlush)
+
+	timestamp := time.Now()
+	tags := map[string]string{"d1key": "d1value"}
+	summary := makeTestMetric("summary", 0, timestamp, tags, 0, "Seconds")
+	summary.Value = nil
+	summary.SetStatisticValues(&cloudwatch.StatisticSet{})
+	metrics := []*aggregationDatum{
+		makeTestMetric("latency", 1, timestamp, tags, 0, "Seconds"),
+		makeTestMetric("latency", 2, timestamp, tags, 0, "Seconds"),
+		makeTestMetric("stats_size", 3, timestamp, tags, 0, "Bytes"),
+		makeTestMetric("stats_size", 5, timestamp, tags, 0, "Bytes"),
+		makeTestMetric("raw", 1, timestamp, tags, 0, "Count"),
+		summary,
+	}
+	for _, m := range metrics {
+		aggregator.AddMetric(m)
+	}
+
+	// the points that are not aggregated pass through directly
+	for _, name := range []string{"raw", "summary"} {
+		select {
+		case m := <-metricChan:
+			assert.Equal(t, name, *m.MetricName)
+			assert.Equal(t, time.Duration(0), m.aggregationInterval)
+		default:
+			assert.Fail(t, "Got no metrics")
+		}
+	}
+
+	got := make(map[string]*aggregationDatum)
+	for len(got) < 2 {
+		select {
+		case m := <-metricChan:
+			got[*m.MetricName] = m
+		case <-time.After(3 * time.Second):
+			assert.FailNow(t, "We should've seen 2 metrics by now")
+		}
+	}
+	assertNoMetricsInChan(t, metricChan)
+
+	latency := got["latency"]
+	assert.Equal(t, float64(2), latency.distribution.SampleCount())
+	assert.Equal(t, float64(3), latency.distribution.Sum())
+	// the interval is not requested by the receiver, so the resolution is kept
+	assert.Nil(t, latency.StorageResolution)
+
+	size := got["stats_size"]
+	assert.Nil(t, size.distribution)
+	assert.Nil(t, size.Value)
+	assert.Equal(t, &cloudwatch.StatisticSet{
+		Maximum:     aws.Float64(5),
+		Minimum:     aws.Float64(3),
+		SampleCount: aws.Float64(2),
+		Sum:         aws.Float64(8),
+	}, size.StatisticValues)
+	assert.Equal(t, "Bytes", *size.Unit)
+
+	close(shutdownChan)
+	// Cleanup
+	wg.Wait()
+}
+
+func TestPreFlushAggregation_ReceiverInterval(t *testing.T) {
+	preFlush, err := newPreFlushAggregation(&PreFlushAggregationConfig{
+		Interval: time.Second,
+		Mode:     PreFlushAggregationModeStatisticSet,
+	}, time.Minute)
+	assert.NoError(t, err)
+
+	m := makeTestMetric("latency", 1, time.Now(), nil, 10*time.Second, "Seconds")
+	preFlush.apply(m)
+	assert.Equal(t, 10*time.Second, m.aggregationInterval)
+	assert.False(t, m.statisticSet)
+
+	m = makeTestMetric("latency", 1, time.Now(), nil, 0, "Seconds")
+	preFlush.apply(m)
+	assert.Equal(t, time.Second, m.aggregationInterval)
+	assert.True(t, m.statisticSet)
+}
+
 func TestDurationAggregator_aggregating(t *testing.T) {
 	distribution.NewDistribution = seh1.NewSEH1Distribution
 	aggregationInterval := 1 * time.Second
@@ -147,6 +249,7 @@ func TestDurationAggregator_aggregating(t *testing.T) {
 		metricMap:           make(map[string]*aggregationDatum),
 		aggregationChan:     make(chan *aggregationDatum, durationAggregationChanBufferSize),
 	}
+	wg.Add(1)
 	go durationAgg.aggregating()
 
 	timestamp := time.Now()
@@ -0,0 +1,194 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package cloudwatch
+
+import (
+	"log"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal/util/collections"
+	"github.com/aws/amazon-cloudwatch-agent/sdk/service/cloudwatch"
+)
+
+const (
+	defaultCardinalityRotationInterval = time.Hour
+	defaultCardinalityOverflowValue    = "Other"
+)
+
+// cardinalityLimiter tracks the unique dimension sets of each metric in the
+// current rotation window. Datums with a new dimension set over the limits
+// have their dimension values replaced with the overflow value.
+type cardinalityLimiter struct {
+	perMetric        int
+	perNamespace     int
+	rotationInterval time.Duration
+	overflowValue    string
+	rules            []cardinalityLimitRule
+	now              func() time.Time
+
+	mu          sync.Mutex
+	windowStart time.Time
+	metrics     map[string]*metricCardinality
+	total       int
+}
+
+type cardinalityLimitRule struct {
+	metricNames []*regexp.Regexp
+	limit       int
+}
+
+// metricCardinality is the dimension sets of a metric in the current window,
+// and what was dropped because of the limits.
+type metricCardinality struct {
+	dimensionSets collections.Set[string]
+	dropped       int
+	// example is the first dimension set that was dropped.
+	example string
+}
+
+// newCardinalityLimiter compiles the config. It returns nil if the config is
+// nil, so that no dimension sets are limited.
+func newCardinalityLimiter(cfg *CardinalityLimitConfig) (*cardinalityLimiter, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+	l := &cardinalityLimiter{
+		perMetric:        cfg.MaxDimensionSetsPerMetric,
+		perNamespace:     cfg.MaxDimensionSetsPerNamespace,
+		rotationInterval: cfg.RotationInterval,
+		overflowValue:    cfg.OverflowValue,
+		now:              time.Now,
+		metrics:          map[string]*metricCardinality{},
+	}
+	if l.rotationInterval == 0 {
+		l.rotationInterval = defaultCardinalityRotationInterval
+	}
+	if l.overflowValue == "" {
+		l.overflowValue = defaultCardinalityOverflowValue
+	}
+	for _, rule := range cfg.Rules {
+		r := cardinalityLimitRule{limit: rule.MaxDimensionSets}
+		for _, name := range rule.MetricNames {
+			re, err := regexp.Compile(name)
+			if err != nil {
+				return nil, err
+			}
+			r.metricNames = append(r.metricNames, re)
+		}
+		l.rules = append(l.rules, r)
+	}
+	l.windowStart = l.now()
+	return l, nil
+}
+
+// apply admits the dimension set of the datum if it was already seen in the
+// current window or if it is within the limits. Otherwise, the dimension
+// values of the datum are replaced with the overflow value. It returns
+// whether the dimension set was admitted.
+func (l *cardinalityLimiter) apply(m *aggregationDatum) bool {
+	if l == nil || m.MetricName == nil || len(m.Dimensions) == 0 {
+		return true
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.now().Sub(l.windowStart) >= l.rotationInterval {
+		l.rotate()
+	}
+
+	metricName := *m.MetricName
+	mc, ok := l.metrics[metricName]
+	if !ok {
+		mc = &metricCardinality{dimensionSets: collections.NewSet[string]()}
+		l.metrics[metricName] = mc
+	}
+	key := dimensionSetKey(m.Dimensions)
+	if mc.dimensionSets.Contains(key) {
+		return true
+	}
+	limit := l.limit(metricName)
+	if (limit == 0 || len(mc.dimensionSets) < limit) && (l.perNamespace == 0 || l.total < l.perNamespace) {
+		mc.dimensionSets.Add(key)
+		l.total++
+		return true
+	}
+
+	if mc.dropped == 0 {
+		mc.example = key
+		log.Printf("W! cloudwatch: metric %s exceeded the cardinality limit, publishing its new dimension sets with the value %q until the next rotation", metricName, l.overflowValue)
+	}
+	mc.dropped++
+	// The dimensions can be shared with other datums, so they are replaced
+	// instead of modified.
+	dimensions := make([]*cloudwatch.Dimension, len(m.Dimensions))
+	for i, d := range m.Dimensions {
+		dimensions[i] = &cloudwatch.Dimension{Name: d.Name, Value: aws.String(l.overflowValue)}
+	}
+	m.Dimensions = dimensions
+	return false
+}
+
+func (l *cardinalityLimiter) limit(metricName string) int {
+	for _, rule := range l.rules {
+		for _, re := range rule.metricNames {
+			if re.MatchString(metricName) {
+				return rule.limit
+			}
+		}
+	}
+	return l.perMetric
+}
+
+// rotate reports the metrics that had dimension sets dropped and starts a
+// new window.
+func (l *cardinalityLimiter) rotate() {
+	l.reportDropped()
+	l.metrics = map[string]*metricCardinality{}
+	l.total = 0
+	l.windowStart = l.now()
+}
+
+// reportDropped logs the number of datums that were published with the
+// overflow value for each metric in the current window.
+func (l *cardinalityLimiter) reportDropped() {
+	metricNames := make([]string, 0, len(l.metrics))
+	for metricName, mc := range l.metrics {
+		if mc.dropped > 0 {
+			metricNames = append(metricNames, metricName)
+		}
+	}
+	sort.Strings(metricNames)
+	for _, metricName := range metricNames {
+		mc := l.metrics[metricName]
+		log.Printf("W! cloudwatch: cardinality limit replaced the dimension values of %d datums of metric %s with %q since %s, it had %d dimension sets, first dropped dimension set: %s",
+			mc.dropped, metricName, l.overflowValue, l.windowStart.Format(time.RFC3339), len(mc.dimensionSets), mc.example)
+	}
+}
+
+// close reports what was dropped in the current window.
+func (l *cardinalityLimiter) close() {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.reportDropped()
+}
+
+func dimensionSetKey(dimensions []*cloudwatch.Dimension) string {
+	tmp := make([]string, 0, len(dimensions))
+	for _, d := range dimensions {
+		if d.Name == nil || d.Value == nil {
+			continue
+		}
+		tmp = append(tmp, *d.Name+"="+*d.Value)
+	}
+	// Assume the dimensions were already sorted.
+	return strings.Join(tmp, ",")
+}
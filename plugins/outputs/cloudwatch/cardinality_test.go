@@ -0,0 +1,82 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package cloudwatch
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCardinalityLimiter(t *testing.T) {
+	limiter, err := newCardinalityLimiter(&CardinalityLimitConfig{
+		MaxDimensionSetsPerMetric: 2,
+		Rules: []CardinalityLimitRule{
+			{MetricNames: []string{"^requests$"}, MaxDimensionSets: 1},
+		},
+	})
+	require.NoError(t, err)
+	now := time.Now()
+	limiter.now = func() time.Time { return now }
+	limiter.windowStart = now
+
+	timestamp := time.Now()
+	for _, id := range []string{"a", "b", "a"} {
+		m := makeTestMetric("latency", 1, timestamp, map[string]string{"host": "h1", "request_id": id}, 0, "Seconds")
+		assert.True(t, limiter.apply(m))
+		assert.Equal(t, id, *m.Dimensions[1].Value)
+	}
+	shared := makeTestMetric("latency", 1, timestamp, map[string]string{"host": "h1", "request_id": "c"}, 0, "Seconds")
+	original := shared.Dimensions
+	assert.False(t, limiter.apply(shared))
+	assert.Equal(t, "host", *shared.Dimensions[0].Name)
+	assert.Equal(t, "Other", *shared.Dimensions[0].Value)
+	assert.Equal(t, "request_id", *shared.Dimensions[1].Name)
+	assert.Equal(t, "Other", *shared.Dimensions[1].Value)
+	// the original dimensions are not modified
+	assert.Equal(t, "c", *original[1].Value)
+
+	// rules override the per metric limit
+	assert.True(t, limiter.apply(makeTestMetric("requests", 1, timestamp, map[string]string{"path": "/a"}, 0, "Count")))
+	assert.False(t, limiter.apply(makeTestMetric("requests", 1, timestamp, map[string]string{"path": "/b"}, 0, "Count")))
+	// metrics without dimensions are not limited
+	assert.True(t, limiter.apply(makeTestMetric("requests", 1, timestamp, nil, 0, "Count")))
+
+	assert.Equal(t, 1, limiter.metrics["latency"].dropped)
+	assert.Equal(t, "host=h1,request_id=c", limiter.metrics["latency"].example)
+	assert.Equal(t, 1, limiter.metrics["requests"].dropped)
+
+	// the dimension sets are forgotten after the rotation interval
+	now = now.Add(time.Hour)
+	assert.True(t, limiter.apply(makeTestMetric("latency", 1, timestamp, map[string]string{"host": "h1", "request_id": "c"}, 0, "Seconds")))
+	assert.Len(t, limiter.metrics, 1)
+	assert.Equal(t, 0, limiter.metrics["latency"].dropped)
+}
+
+func TestCardinalityLimiter_PerNamespace(t *testing.T) {
+	limiter, err := newCardinalityLimiter(&CardinalityLimitConfig{
+		MaxDimensionSetsPerNamespace: 2,
+		OverflowValue:                "overflow",
+	})
+	require.NoError(t, err)
+
+	timestamp := time.Now()
+	assert.True(t, limiter.apply(makeTestMetric("m1", 1, timestamp, map[string]string{"id": "1"}, 0, "Count")))
+	assert.True(t, limiter.apply(makeTestMetric("m2", 1, timestamp, map[string]string{"id": "1"}, 0, "Count")))
+	m := makeTestMetric("m3", 1, timestamp, map[string]string{"id": "1"}, 0, "Count")
+	assert.False(t, limiter.apply(m))
+	assert.Equal(t, "overflow", *m.Dimensions[0].Value)
+	// the dimension sets that were already seen are still admitted
+	assert.True(t, limiter.apply(makeTestMetric("m1", 1, timestamp, map[string]string{"id": "1"}, 0, "Count")))
+}
+
+func TestCardinalityLimiter_Nil(t *testing.T) {
+	limiter, err := newCardinalityLimiter(nil)
+	require.NoError(t, err)
+	assert.Nil(t, limiter)
+	assert.True(t, limiter.apply(makeTestMetric("m1", 1, time.Now(), map[string]string{"id": "1"}, 0, "Count")))
+	limiter.close()
+}
@@ -26,7 +26,9 @@ import (
 
 	configaws "github.com/aws/amazon-cloudwatch-agent/cfg/aws"
 	"github.com/aws/amazon-cloudwatch-agent/handlers"
+	"github.com/aws/amazon-cloudwatch-agent/internal/dryrun"
 	"github.com/aws/amazon-cloudwatch-agent/internal/publisher"
+	"github.com/aws/amazon-cloudwatch-agent/internal/reload"
 	"github.com/aws/amazon-cloudwatch-agent/internal/retryer"
 	"github.com/aws/amazon-cloudwatch-agent/internal/util/collections"
 	"github.com/aws/amazon-cloudwatch-agent/metric/distribution"
@@ -72,6 +74,16 @@ type CloudWatch struct {
 	aggregatorShutdownChan chan struct{}
 	aggregatorWaitGroup    sync.WaitGroup
 	lastRequestBytes       int
+	diskBuffer             *diskBuffer
+	preFlushAggregation    *preFlushAggregation
+	cardinalityLimiter     *cardinalityLimiter
+	summaryConverter       *summaryConverter
+	pushMetricDatumDone    chan struct{}
+	// original is the config before it is formatted by Start, which a
+	// reloaded config is compared with to reuse the exporter.
+	original Config
+	id       component.ID
+	started  bool
 }
 
 // Compile time interface check.
@@ -82,8 +94,33 @@ func (c *CloudWatch) Capabilities() consumer.Capabilities {
 }
 
 func (c *CloudWatch) Start(_ context.Context, host component.Host) error {
+	if c.started {
+		// Reused by the restarted collector service, see Shutdown.
+		return nil
+	}
+	preFlushAggregation, err := newPreFlushAggregation(c.config.PreFlushAggregation, c.config.ForceFlushInterval)
+	if err != nil {
+		return err
+	}
+	c.preFlushAggregation = preFlushAggregation
+	cardinalityLimiter, err := newCardinalityLimiter(c.config.CardinalityLimit)
+	if err != nil {
+		return err
+	}
+	c.cardinalityLimiter = cardinalityLimiter
+	var queue publisher.Queue = publisher.NewNonBlockingFifoQueue(metricChanBufferSize)
+	// The dry run neither replays the batches buffered by the agent nor
+	// buffers its own ones.
+	if c.config.DiskBuffer != nil && !dryrun.Enabled() {
+		diskBuffer, err := newDiskBuffer(c.config.DiskBuffer)
+		if err != nil {
+			return err
+		}
+		c.diskBuffer = diskBuffer
+		queue = diskBuffer
+	}
 	c.publisher, _ = publisher.NewPublisher(
-		publisher.NewNonBlockingFifoQueue(metricChanBufferSize),
+		queue,
 		maxConcurrentPublisher,
 		2*time.Second,
 		c.WriteToCloudWatch)
@@ -116,6 +153,7 @@ func (c *CloudWatch) Start(_ context.Context, host component.Host) error {
 	c.svc = svc
 	c.retryer = logThrottleRetryer
 	c.startRoutines()
+	c.started = true
 	return nil
 }
 
@@ -124,16 +162,39 @@ func (c *CloudWatch) startRoutines() {
 	c.metricChan = make(chan *aggregationDatum, metricChanBufferSize)
 	c.datumBatchChan = make(chan map[string][]*cloudwatch.MetricDatum, datumBatchChanBufferSize)
 	c.shutdownChan = make(chan struct{})
+	c.pushMetricDatumDone = make(chan struct{})
 	c.aggregatorShutdownChan = make(chan struct{})
-	c.aggregator = NewAggregator(c.metricChan, c.aggregatorShutdownChan, &c.aggregatorWaitGroup)
+	c.summaryConverter = newSummaryConverter(c.config.SummaryQuantiles)
+	c.aggregator = newAggregator(c.metricChan, c.aggregatorShutdownChan, &c.aggregatorWaitGroup, c.preFlushAggregation)
 	perRequestConstSize := overallConstPerRequestSize + len(c.config.Namespace) + namespaceOverheads
 	c.metricDatumBatch = newMetricDatumBatch(c.config.MaxDatumsPerCall, perRequestConstSize)
 	go c.pushMetricDatum()
 	go c.publish()
 }
 
-func (c *CloudWatch) Shutdown(ctx context.Context) error {
+// Shutdown keeps publishing if the collector service is restarted by a
+// reload, so the metrics that are queued or in the disk buffer are not held
+// back. The restarted service reuses the exporter if its config is unchanged,
+// otherwise it is released once the service has restarted, or as soon as a
+// new exporter uses the same disk buffer.
+func (c *CloudWatch) Shutdown(context.Context) error {
+	if c.started && reload.Park(c) {
+		return nil
+	}
+	return c.shutdown()
+}
+
+// Release shuts down the exporter that was kept publishing by Shutdown.
+func (c *CloudWatch) Release() {
+	_ = c.shutdown()
+}
+
+func (c *CloudWatch) shutdown() error {
 	log.Println("D! Stopping the CloudWatch output plugin")
+	// Flush the aggregated datums, so they are published, or printed by the
+	// dry run, rather than lost.
+	close(c.aggregatorShutdownChan)
+	c.aggregatorWaitGroup.Wait()
 	for i := 0; i < 5; i++ {
 		if len(c.metricChan) == 0 && len(c.datumBatchChan) == 0 {
 			break
@@ -146,8 +207,20 @@ func (c *CloudWatch) Shutdown(ctx context.Context) error {
 		log.Printf("D! CloudWatch Close, metricChan length = %v, datumBatchChan length = %v.", metricChanLen, datumBatchChanLen)
 	}
 	close(c.shutdownChan)
+	// Wait for the batch that is not full to be published, so no batch is
+	// queued after the remaining ones are moved.
+	c.waitPushMetricDatum()
+	if c.diskBuffer != nil {
+		// Stop handing out batches, so closing the publisher only waits for
+		// the ones in flight rather than draining the disk buffer. The
+		// remaining batches are moved into the disk buffer and published
+		// after the next start.
+		c.diskBuffer.close()
+		c.pushMetricDatumBatch()
+	}
 	c.publisher.Close()
 	c.retryer.Stop()
+	c.cardinalityLimiter.close()
 	log.Println("D! Stopped the CloudWatch output plugin")
 	return nil
 }
@@ -156,8 +229,9 @@ func (c *CloudWatch) Shutdown(ctx context.Context) error {
 // The actual publishing will occur in a long running goroutine.
 // This method can block when publishing is backed up.
 func (c *CloudWatch) ConsumeMetrics(ctx context.Context, metrics pmetric.Metrics) error {
-	datums := ConvertOtelMetrics(metrics)
+	datums := ConvertOtelMetrics(metrics, c.summaryConverter)
 	for _, d := range datums {
+		c.cardinalityLimiter.apply(d)
 		c.aggregator.AddMetric(d)
 	}
 	return nil
@@ -167,6 +241,7 @@ func (c *CloudWatch) ConsumeMetrics(ctx context.Context, metrics pmetric.Metrics
 // When a batch is full it is queued up for sending.
 // Even if the batch is not full it will still get sent after the flush interval.
 func (c *CloudWatch) pushMetricDatum() {
+	defer close(c.pushMetricDatumDone)
 	ticker := time.NewTicker(time.Second)
 	defer ticker.Stop()
 	for {
@@ -206,6 +281,10 @@ func (c *CloudWatch) pushMetricDatum() {
 				c.metricDatumBatch.clear()
 			}
 		case <-c.shutdownChan:
+			if len(c.metricDatumBatch.Partition) > 0 {
+				c.publisher.Publish(c.metricDatumBatch.Partition)
+				c.metricDatumBatch.clear()
+			}
 			return
 		}
 	}
@@ -325,6 +404,22 @@ func (c *CloudWatch) metricDatumBatchFull() bool {
 	return len(c.datumBatchChan) >= datumBatchChanBufferSize
 }
 
+// waitPushMetricDatum waits for pushMetricDatum to return after the shutdown.
+// Since publish has already returned, it receives the batches pushMetricDatum
+// may be blocked on, and keeps them if they are kept on shutdown.
+func (c *CloudWatch) waitPushMetricDatum() {
+	for {
+		select {
+		case <-c.pushMetricDatumDone:
+			return
+		case datumBatch := <-c.datumBatchChan:
+			if c.diskBuffer != nil || dryrun.Enabled() {
+				c.publisher.Publish(datumBatch)
+			}
+		}
+	}
+}
+
 // pushMetricDatumBatch will try receiving on the channel, and if successful,
 // then it publishes the received batch.
 func (c *CloudWatch) pushMetricDatumBatch() {
@@ -367,9 +462,18 @@ func createEntityMetricData(entityToMetrics map[string][]*cloudwatch.MetricDatum
 	return entityMetricData
 }
 
+// WriteToCloudWatch publishes a batch of datums. Batches from the disk buffer
+// are released back to it with the result, so failed ones can be replayed.
 func (c *CloudWatch) WriteToCloudWatch(req interface{}) {
-	entityToMetricDatum := req.(map[string][]*cloudwatch.MetricDatum)
+	if batch, ok := req.(*bufferedBatch); ok {
+		c.diskBuffer.done(batch, c.putMetricData(batch.datums))
+		return
+	}
+	_ = c.putMetricData(req.(map[string][]*cloudwatch.MetricDatum))
+}
 
+// putMetricData sends the datums with retries and returns the last error.
+func (c *CloudWatch) putMetricData(entityToMetricDatum map[string][]*cloudwatch.MetricDatum) error {
 	// PMD requires PutMetricData to have MetricData
 	metricData := entityToMetricDatum[""]
 	if _, ok := entityToMetricDatum[""]; !ok {
@@ -413,6 +517,7 @@ func (c *CloudWatch) WriteToCloudWatch(req interface{}) {
 	if err != nil {
 		log.Println("E! cloudwatch: WriteToCloudWatch failure, err: ", err)
 	}
+	return err
 }
 
 // BuildMetricDatum may just return the datum as-is.
@@ -441,6 +546,19 @@ func (c *CloudWatch) BuildMetricDatum(metric *aggregationDatum) (cloudwatch.Enti
 			continue
 		}
 		if len(distList) == 0 {
+			if metric.StatisticValues != nil {
+				// Pre-computed statistics, e.g. from a summary.
+				datum := &cloudwatch.MetricDatum{
+					MetricName:        metric.MetricName,
+					Dimensions:        dimensions,
+					Timestamp:         metric.Timestamp,
+					Unit:              metric.Unit,
+					StorageResolution: metric.StorageResolution,
+					StatisticValues:   metric.StatisticValues,
+				}
+				datums = append(datums, datum)
+				continue
+			}
 			if metric.Value == nil {
 				log.Printf("D! metric (%s) has nil value, dropping it", *metric.MetricName)
 				continue
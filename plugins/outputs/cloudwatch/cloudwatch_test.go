@@ -516,6 +516,22 @@ func TestPublish(t *testing.T) {
 	cw.Shutdown(ctx)
 }
 
+// TestShutdownFlushesAggregation verifies the aggregated datums are published
+// on shutdown rather than lost.
+func TestShutdownFlushesAggregation(t *testing.T) {
+	svc := new(mockCloudWatchClient)
+	svc.On("PutMetricData", mock.Anything).Return(&cloudwatch.PutMetricDataOutput{}, nil)
+	cw := newCloudWatchClient(svc, time.Minute)
+	cw.publisher, _ = publisher.NewPublisher(
+		publisher.NewNonBlockingFifoQueue(10),
+		10,
+		2*time.Second,
+		cw.WriteToCloudWatch)
+	cw.aggregator.AddMetric(makeTestMetric("mname", 1, time.Now(), map[string]string{"d1key": "d1value"}, time.Minute, "Percent"))
+	require.NoError(t, cw.Shutdown(context.Background()))
+	svc.AssertNumberOfCalls(t, "PutMetricData", 1)
+}
+
 func TestMiddleware(t *testing.T) {
 	t.Setenv("AWS_ACCESS_KEY_ID", "test")
 	t.Setenv("AWS_SECRET_ACCESS_KEY", "test")
@@ -618,7 +634,7 @@ func TestCreateEntityMetricData(t *testing.T) {
 	}
 	metrics := createTestMetrics(1, 1, 1, "s")
 	assert.Equal(t, 7, metrics.ResourceMetrics().At(0).Resource().Attributes().Len())
-	aggregations := ConvertOtelMetrics(metrics)
+	aggregations := ConvertOtelMetrics(metrics, newSummaryConverter(false))
 	assert.Equal(t, 0, metrics.ResourceMetrics().At(0).Resource().Attributes().Len())
 	entity, metricDatum := cw.BuildMetricDatum(aggregations[0])
 
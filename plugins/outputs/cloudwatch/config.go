@@ -5,6 +5,8 @@ package cloudwatch
 
 import (
 	"errors"
+	"fmt"
+	"regexp"
 	"time"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/resourcetotelemetry"
@@ -35,6 +37,103 @@ type Config struct {
 	ResourceToTelemetrySettings resourcetotelemetry.Settings `mapstructure:"resource_to_telemetry_conversion"`
 	// MiddlewareID is an ID for an extension that can be used to configure the AWS client.
 	MiddlewareID *component.ID `mapstructure:"middleware,omitempty"`
+	// SummaryQuantiles controls whether each quantile of a summary metric is
+	// also published as its own metric, e.g. latency_p99. The summary itself is
+	// always published as a StatisticSet.
+	SummaryQuantiles bool `mapstructure:"summary_quantiles,omitempty"`
+	// DiskBuffer enables a persistent queue for metric batches waiting to be
+	// published. If nil, batches are only kept in memory.
+	DiskBuffer *DiskBufferConfig `mapstructure:"disk_buffer,omitempty"`
+	// PreFlushAggregation aggregates the points of the same metric and
+	// dimensions that do not have an aggregation interval before they are
+	// published. If nil, each point is published as its own datum.
+	PreFlushAggregation *PreFlushAggregationConfig `mapstructure:"pre_flush_aggregation,omitempty"`
+	// CardinalityLimit caps the number of unique dimension sets that are
+	// published in each rotation interval. If nil, all dimension sets are
+	// published.
+	CardinalityLimit *CardinalityLimitConfig `mapstructure:"cardinality_limit,omitempty"`
+}
+
+// DiskBufferConfig configures the on-disk queue used to keep metric batches
+// across network outages and agent restarts.
+type DiskBufferConfig struct {
+	// Directory is where the batch files are stored.
+	Directory string `mapstructure:"directory"`
+	// MaxBytes is the maximum total size of the batch files. When exceeded,
+	// the oldest batches are dropped. Defaults to 100 MiB.
+	MaxBytes int64 `mapstructure:"max_bytes,omitempty"`
+	// MaxAge is how long a batch is kept before it is dropped without being
+	// published. Defaults to 14 days, which is the oldest timestamp that
+	// CloudWatch will accept.
+	MaxAge time.Duration `mapstructure:"max_age,omitempty"`
+}
+
+const (
+	// PreFlushAggregationModeDistribution publishes the aggregated points as
+	// values and counts with their statistics.
+	PreFlushAggregationModeDistribution = "distribution"
+	// PreFlushAggregationModeStatisticSet only publishes the statistics of the
+	// aggregated points.
+	PreFlushAggregationModeStatisticSet = "statistic_set"
+	// PreFlushAggregationModeNone publishes each point as its own datum.
+	PreFlushAggregationModeNone = "none"
+)
+
+// PreFlushAggregationConfig configures how the points of the same metric and
+// dimensions are aggregated before they are published.
+type PreFlushAggregationConfig struct {
+	// Interval is the period that the points are aggregated over. Defaults to
+	// the force flush interval.
+	Interval time.Duration `mapstructure:"interval,omitempty"`
+	// Mode is how the aggregated points are published. Defaults to
+	// distribution.
+	Mode string `mapstructure:"mode,omitempty"`
+	// Rules override the interval and mode of the metrics that match them.
+	// The first rule that matches a metric is applied.
+	Rules []PreFlushAggregationRule `mapstructure:"rules,omitempty"`
+}
+
+// PreFlushAggregationRule overrides the pre-flush aggregation of some metrics.
+type PreFlushAggregationRule struct {
+	// MetricNames are the regular expressions of the metric names that the
+	// rule applies to.
+	MetricNames []string `mapstructure:"metric_names"`
+	// Interval overrides the aggregation interval if set.
+	Interval time.Duration `mapstructure:"interval,omitempty"`
+	// Mode overrides the aggregation mode if set.
+	Mode string `mapstructure:"mode,omitempty"`
+}
+
+// CardinalityLimitConfig configures how many unique dimension sets are
+// published per metric and per namespace. The dimension values of the datums
+// over the limit are replaced with the overflow value, so they are published
+// in a single bucket instead of creating new metrics.
+type CardinalityLimitConfig struct {
+	// MaxDimensionSetsPerMetric is the number of dimension sets each metric
+	// can have. Zero means no limit.
+	MaxDimensionSetsPerMetric int `mapstructure:"max_dimension_sets_per_metric,omitempty"`
+	// MaxDimensionSetsPerNamespace is the number of dimension sets all the
+	// metrics of the namespace can have together. Zero means no limit.
+	MaxDimensionSetsPerNamespace int `mapstructure:"max_dimension_sets_per_namespace,omitempty"`
+	// RotationInterval is how often the dimension sets are forgotten and the
+	// dropped ones are reported. Defaults to 1 hour.
+	RotationInterval time.Duration `mapstructure:"rotation_interval,omitempty"`
+	// OverflowValue replaces the dimension values of the datums over the
+	// limit. Defaults to "Other".
+	OverflowValue string `mapstructure:"overflow_value,omitempty"`
+	// Rules override the per metric limit of the metrics that match them.
+	// The first rule that matches a metric is applied.
+	Rules []CardinalityLimitRule `mapstructure:"rules,omitempty"`
+}
+
+// CardinalityLimitRule overrides the per metric limit of some metrics.
+type CardinalityLimitRule struct {
+	// MetricNames are the regular expressions of the metric names that the
+	// rule applies to.
+	MetricNames []string `mapstructure:"metric_names"`
+	// MaxDimensionSets is the number of dimension sets each matching metric
+	// can have.
+	MaxDimensionSets int `mapstructure:"max_dimension_sets"`
 }
 
 var _ component.Config = (*Config)(nil)
@@ -50,5 +149,86 @@ func (c *Config) Validate() error {
 	if c.ForceFlushInterval < time.Millisecond {
 		return errors.New("'force_flush_interval' must be at least 1 millisecond")
 	}
+	if c.DiskBuffer != nil {
+		if c.DiskBuffer.Directory == "" {
+			return errors.New("'disk_buffer::directory' must be set")
+		}
+		if c.DiskBuffer.MaxBytes < 0 {
+			return errors.New("'disk_buffer::max_bytes' must not be negative")
+		}
+		if c.DiskBuffer.MaxAge < 0 {
+			return errors.New("'disk_buffer::max_age' must not be negative")
+		}
+	}
+	if c.PreFlushAggregation != nil {
+		if err := c.PreFlushAggregation.validate(); err != nil {
+			return err
+		}
+	}
+	if c.CardinalityLimit != nil {
+		return c.CardinalityLimit.validate()
+	}
+	return nil
+}
+
+func (c *CardinalityLimitConfig) validate() error {
+	if c.MaxDimensionSetsPerMetric < 0 {
+		return errors.New("'cardinality_limit::max_dimension_sets_per_metric' must not be negative")
+	}
+	if c.MaxDimensionSetsPerNamespace < 0 {
+		return errors.New("'cardinality_limit::max_dimension_sets_per_namespace' must not be negative")
+	}
+	if c.MaxDimensionSetsPerMetric == 0 && c.MaxDimensionSetsPerNamespace == 0 && len(c.Rules) == 0 {
+		return errors.New("'cardinality_limit' must set max_dimension_sets_per_metric, max_dimension_sets_per_namespace or rules")
+	}
+	if c.RotationInterval != 0 && c.RotationInterval < time.Minute {
+		return errors.New("'cardinality_limit::rotation_interval' must be at least 1 minute")
+	}
+	for i, rule := range c.Rules {
+		key := fmt.Sprintf("cardinality_limit::rules::%d", i)
+		if len(rule.MetricNames) == 0 {
+			return fmt.Errorf("'%s::metric_names' must be set", key)
+		}
+		for _, name := range rule.MetricNames {
+			if _, err := regexp.Compile(name); err != nil {
+				return fmt.Errorf("'%s::metric_names' has an invalid regular expression %q: %w", key, name, err)
+			}
+		}
+		if rule.MaxDimensionSets < 1 {
+			return fmt.Errorf("'%s::max_dimension_sets' must be at least 1", key)
+		}
+	}
+	return nil
+}
+
+func (c *PreFlushAggregationConfig) validate() error {
+	if err := validatePreFlushAggregation("pre_flush_aggregation", c.Interval, c.Mode); err != nil {
+		return err
+	}
+	for i, rule := range c.Rules {
+		key := fmt.Sprintf("pre_flush_aggregation::rules::%d", i)
+		if len(rule.MetricNames) == 0 {
+			return fmt.Errorf("'%s::metric_names' must be set", key)
+		}
+		for _, name := range rule.MetricNames {
+			if _, err := regexp.Compile(name); err != nil {
+				return fmt.Errorf("'%s::metric_names' has an invalid regular expression %q: %w", key, name, err)
+			}
+		}
+		if err := validatePreFlushAggregation(key, rule.Interval, rule.Mode); err != nil {
+			return err
+		}
+	}
 	return nil
 }
+
+func validatePreFlushAggregation(key string, interval time.Duration, mode string) error {
+	if interval != 0 && interval < time.Second {
+		return fmt.Errorf("'%s::interval' must be at least 1 second", key)
+	}
+	switch mode {
+	case "", PreFlushAggregationModeDistribution, PreFlushAggregationModeStatisticSet, PreFlushAggregationModeNone:
+		return nil
+	}
+	return fmt.Errorf("'%s::mode' must be one of %s, %s or %s", key, PreFlushAggregationModeDistribution, PreFlushAggregationModeStatisticSet, PreFlushAggregationModeNone)
+}
@@ -111,3 +111,76 @@ func TestConfigDropOriginConfigs(t *testing.T) {
 	assert.True(t, drop["cpu_usage"])
 	assert.True(t, drop["foo_bar"])
 }
+
+func TestConfigDiskBuffer(t *testing.T) {
+	factories, err := otelcoltest.NopFactories()
+	assert.NoError(t, err)
+	factory := NewFactory()
+	factories.Exporters[TypeStr] = factory
+
+	fp := filepath.Join("testdata", "missing_disk_buffer_directory.yaml")
+	_, err = otelcoltest.LoadConfigAndValidate(fp, factories)
+	assert.Error(t, err)
+
+	fp = filepath.Join("testdata", "disk_buffer.yaml")
+	c, err := otelcoltest.LoadConfigAndValidate(fp, factories)
+	assert.NoError(t, err)
+	c2, ok := c.Exporters[component.NewID(TypeStr)].(*Config)
+	assert.True(t, ok)
+	assert.Equal(t, &DiskBufferConfig{
+		Directory: "/tmp/cwagent/buffer",
+		MaxBytes:  1048576,
+		MaxAge:    6 * time.Hour,
+	}, c2.DiskBuffer)
+}
+
+func TestConfigPreFlushAggregation(t *testing.T) {
+	factories, err := otelcoltest.NopFactories()
+	assert.NoError(t, err)
+	factory := NewFactory()
+	factories.Exporters[TypeStr] = factory
+
+	fp := filepath.Join("testdata", "invalid_pre_flush_aggregation.yaml")
+	_, err = otelcoltest.LoadConfigAndValidate(fp, factories)
+	assert.Error(t, err)
+
+	fp = filepath.Join("testdata", "pre_flush_aggregation.yaml")
+	c, err := otelcoltest.LoadConfigAndValidate(fp, factories)
+	assert.NoError(t, err)
+	c2, ok := c.Exporters[component.NewID(TypeStr)].(*Config)
+	assert.True(t, ok)
+	assert.Equal(t, &PreFlushAggregationConfig{
+		Interval: 30 * time.Second,
+		Mode:     PreFlushAggregationModeStatisticSet,
+		Rules: []PreFlushAggregationRule{
+			{MetricNames: []string{"^latency_", "_ms$"}, Mode: PreFlushAggregationModeDistribution},
+			{MetricNames: []string{"^heartbeat$"}, Mode: PreFlushAggregationModeNone},
+		},
+	}, c2.PreFlushAggregation)
+}
+
+func TestConfigCardinalityLimit(t *testing.T) {
+	factories, err := otelcoltest.NopFactories()
+	assert.NoError(t, err)
+	factory := NewFactory()
+	factories.Exporters[TypeStr] = factory
+
+	fp := filepath.Join("testdata", "invalid_cardinality_limit.yaml")
+	_, err = otelcoltest.LoadConfigAndValidate(fp, factories)
+	assert.Error(t, err)
+
+	fp = filepath.Join("testdata", "cardinality_limit.yaml")
+	c, err := otelcoltest.LoadConfigAndValidate(fp, factories)
+	assert.NoError(t, err)
+	c2, ok := c.Exporters[component.NewID(TypeStr)].(*Config)
+	assert.True(t, ok)
+	assert.Equal(t, &CardinalityLimitConfig{
+		MaxDimensionSetsPerMetric:    100,
+		MaxDimensionSetsPerNamespace: 5000,
+		RotationInterval:             30 * time.Minute,
+		OverflowValue:                "Overflow",
+		Rules: []CardinalityLimitRule{
+			{MetricNames: []string{"^statsd_"}, MaxDimensionSets: 10},
+		},
+	}, c2.CardinalityLimit)
+}
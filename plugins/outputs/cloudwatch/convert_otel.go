@@ -5,7 +5,10 @@ package cloudwatch
 
 import (
 	"log"
+	"math"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
@@ -144,11 +147,225 @@ func ConvertOtelHistogramDataPoints(
 	return datums
 }
 
+// ConvertOtelExponentialHistogramDataPoints converts each datapoint in the
+// given slice to Distribution. Each bucket is added as its midpoint with the
+// bucket count as the weight.
+func ConvertOtelExponentialHistogramDataPoints(
+	dataPoints pmetric.ExponentialHistogramDataPointSlice,
+	name string,
+	unit string,
+	scale float64,
+	entity cloudwatch.Entity,
+) []*aggregationDatum {
+	datums := make([]*aggregationDatum, 0, dataPoints.Len())
+	for i := 0; i < dataPoints.Len(); i++ {
+		dp := dataPoints.At(i)
+		attrs := dp.Attributes()
+		storageResolution := checkHighResolution(&attrs)
+		aggregationInterval := getAggregationInterval(&attrs)
+		dimensions := ConvertOtelDimensions(attrs)
+		ad := aggregationDatum{
+			MetricDatum: cloudwatch.MetricDatum{
+				Dimensions:        dimensions,
+				MetricName:        aws.String(name),
+				Unit:              aws.String(unit),
+				Timestamp:         aws.Time(dp.Timestamp().AsTime()),
+				StorageResolution: aws.Int64(storageResolution),
+			},
+			aggregationInterval: aggregationInterval,
+			entity:              entity,
+		}
+		if scale != 1 {
+			scaled := pmetric.NewExponentialHistogramDataPoint()
+			scaleExponentialHistogramDataPoint(dp, scale, scaled)
+			dp = scaled
+		}
+		// Assume function pointer is valid.
+		ad.distribution = distribution.NewDistribution()
+		ad.distribution.ConvertFromOtelExponentialHistogram(dp, unit)
+		datums = append(datums, &ad)
+	}
+	return datums
+}
+
+// scaleExponentialHistogramDataPoint copies the datapoint into dest with every
+// value multiplied by scale. This is used when the unit is converted, e.g. from
+// microseconds to the CloudWatch standard unit of milliseconds.
+func scaleExponentialHistogramDataPoint(dp pmetric.ExponentialHistogramDataPoint, scale float64, dest pmetric.ExponentialHistogramDataPoint) {
+	dp.CopyTo(dest)
+	if dp.HasSum() {
+		dest.SetSum(dp.Sum() * scale)
+	}
+	if dp.HasMin() {
+		dest.SetMin(dp.Min() * scale)
+	}
+	if dp.HasMax() {
+		dest.SetMax(dp.Max() * scale)
+	}
+	// Multiplying every bucket boundary by scale is the same as shifting the
+	// bucket index by log_base(scale). Round to the nearest bucket.
+	shift := math.Round(math.Log2(scale) * math.Exp2(float64(dp.Scale())))
+	dest.Positive().SetOffset(dp.Positive().Offset() + int32(shift))
+	dest.Negative().SetOffset(dp.Negative().Offset() + int32(shift))
+}
+
+// summaryMaxStaleness is how long the previous datapoint of a summary series
+// is kept without a newer one before it is forgotten.
+const summaryMaxStaleness = time.Hour
+
+// summaryConverter converts summaries, whose count and sum are cumulative, to
+// the StatisticSets of the period since the previous datapoint of the series.
+type summaryConverter struct {
+	// quantiles controls whether each quantile is also converted to a datum.
+	quantiles bool
+	mu        sync.Mutex
+	previous  map[string]*summaryPoint
+	lastSweep time.Time
+}
+
+type summaryPoint struct {
+	start    pcommon.Timestamp
+	count    uint64
+	sum      float64
+	lastSeen time.Time
+}
+
+func newSummaryConverter(quantiles bool) *summaryConverter {
+	return &summaryConverter{
+		quantiles: quantiles,
+		previous:  make(map[string]*summaryPoint),
+		lastSweep: time.Now(),
+	}
+}
+
+// delta returns the count and sum since the previous datapoint of the series.
+// It returns false for the first datapoint of a series, or the first one after
+// the summary was reset, since it only sets the baseline.
+func (sc *summaryConverter) delta(key string, dp pmetric.SummaryDataPoint) (uint64, float64, bool) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	now := time.Now()
+	if now.Sub(sc.lastSweep) >= summaryMaxStaleness {
+		for k, p := range sc.previous {
+			if now.Sub(p.lastSeen) >= summaryMaxStaleness {
+				delete(sc.previous, k)
+			}
+		}
+		sc.lastSweep = now
+	}
+	prev, ok := sc.previous[key]
+	sc.previous[key] = &summaryPoint{start: dp.StartTimestamp(), count: dp.Count(), sum: dp.Sum(), lastSeen: now}
+	if !ok || prev.start != dp.StartTimestamp() || dp.Count() < prev.count {
+		return 0, 0, false
+	}
+	return dp.Count() - prev.count, dp.Sum() - prev.sum, true
+}
+
+// summaryKey identifies the series of a summary datapoint.
+func summaryKey(name string, dimensions []*cloudwatch.Dimension, entity cloudwatch.Entity) string {
+	parts := make([]string, 0, len(dimensions)+2)
+	parts = append(parts, name)
+	for _, d := range dimensions {
+		parts = append(parts, aws.StringValue(d.Name)+"="+aws.StringValue(d.Value))
+	}
+	parts = append(parts, entityToString(entity))
+	return strings.Join(parts, "\x00")
+}
+
+// ConvertOtelSummaryDataPoints converts each datapoint in the given slice to
+// a StatisticSet of the count and sum since the previous datapoint of the
+// series, so the first datapoint of a series is not published. The minimum
+// and maximum are the 0 and 1 quantiles if the summary has them. Otherwise,
+// they are the average, since the other quantiles are not bounds of the
+// period.
+// If the converter has quantiles set, each quantile is also converted to its
+// own datum named <name>_p<quantile*100>, e.g. latency_p99.
+func ConvertOtelSummaryDataPoints(
+	dataPoints pmetric.SummaryDataPointSlice,
+	name string,
+	unit string,
+	scale float64,
+	entity cloudwatch.Entity,
+	summaries *summaryConverter,
+) []*aggregationDatum {
+	datums := make([]*aggregationDatum, 0, dataPoints.Len())
+	for i := 0; i < dataPoints.Len(); i++ {
+		dp := dataPoints.At(i)
+		attrs := dp.Attributes()
+		storageResolution := checkHighResolution(&attrs)
+		// Summaries cannot be combined, so the aggregation interval is ignored.
+		getAggregationInterval(&attrs)
+		dimensions := ConvertOtelDimensions(attrs)
+		timestamp := aws.Time(dp.Timestamp().AsTime())
+		count, sum, ok := summaries.delta(summaryKey(name, dimensions, entity), dp)
+		if !ok {
+			log.Printf("D! cloudwatch: summary (%s) has no previous datapoint, using it as the baseline", name)
+		} else if count == 0 {
+			log.Printf("D! cloudwatch: summary (%s) has no new samples, dropping it", name)
+		} else {
+			sum *= scale
+			average := sum / float64(count)
+			minimum, maximum := average, average
+			quantiles := dp.QuantileValues()
+			for j := 0; j < quantiles.Len(); j++ {
+				q := quantiles.At(j)
+				switch q.Quantile() {
+				case 0:
+					minimum = math.Min(q.Value()*scale, average)
+				case 1:
+					maximum = math.Max(q.Value()*scale, average)
+				}
+			}
+			s := &cloudwatch.StatisticSet{}
+			s.SetSampleCount(float64(count))
+			s.SetSum(sum)
+			s.SetMinimum(minimum)
+			s.SetMaximum(maximum)
+			datums = append(datums, &aggregationDatum{
+				MetricDatum: cloudwatch.MetricDatum{
+					Dimensions:        dimensions,
+					MetricName:        aws.String(name),
+					Unit:              aws.String(unit),
+					Timestamp:         timestamp,
+					StatisticValues:   s,
+					StorageResolution: aws.Int64(storageResolution),
+				},
+				entity: entity,
+			})
+		}
+		if !summaries.quantiles {
+			continue
+		}
+		for j := 0; j < dp.QuantileValues().Len(); j++ {
+			q := dp.QuantileValues().At(j)
+			datums = append(datums, &aggregationDatum{
+				MetricDatum: cloudwatch.MetricDatum{
+					Dimensions:        dimensions,
+					MetricName:        aws.String(quantileMetricName(name, q.Quantile())),
+					Unit:              aws.String(unit),
+					Timestamp:         timestamp,
+					Value:             aws.Float64(q.Value() * scale),
+					StorageResolution: aws.Int64(storageResolution),
+				},
+				entity: entity,
+			})
+		}
+	}
+	return datums
+}
+
+// quantileMetricName returns the metric name for a summary quantile, e.g.
+// latency_p99 for the 0.99 quantile of latency.
+func quantileMetricName(name string, quantile float64) string {
+	return name + "_p" + strconv.FormatFloat(quantile*100, 'f', -1, 64)
+}
+
 // ConvertOtelMetric creates a list of datums from the datapoints in the given
 // metric and returns it. Only supports the metric DataTypes that we plan to use.
 // Intentionally not caching previous values and converting cumulative to delta.
 // Instead use cumulativetodeltaprocessor which supports monotonic cumulative sums.
-func ConvertOtelMetric(m pmetric.Metric, entity cloudwatch.Entity) []*aggregationDatum {
+// Summaries are the exception, since the processor does not convert them.
+func ConvertOtelMetric(m pmetric.Metric, entity cloudwatch.Entity, summaries *summaryConverter) []*aggregationDatum {
 	name := m.Name()
 	unit, scale, err := cloudwatchutil.ToStandardUnit(m.Unit())
 	if err != nil {
@@ -161,13 +378,17 @@ func ConvertOtelMetric(m pmetric.Metric, entity cloudwatch.Entity) []*aggregatio
 		return ConvertOtelNumberDataPoints(m.Sum().DataPoints(), name, unit, scale, entity)
 	case pmetric.MetricTypeHistogram:
 		return ConvertOtelHistogramDataPoints(m.Histogram().DataPoints(), name, unit, scale, entity)
+	case pmetric.MetricTypeExponentialHistogram:
+		return ConvertOtelExponentialHistogramDataPoints(m.ExponentialHistogram().DataPoints(), name, unit, scale, entity)
+	case pmetric.MetricTypeSummary:
+		return ConvertOtelSummaryDataPoints(m.Summary().DataPoints(), name, unit, scale, entity, summaries)
 	default:
 		log.Printf("E! cloudwatch: Unsupported type, %s", m.Type())
 	}
 	return []*aggregationDatum{}
 }
 
-func ConvertOtelMetrics(m pmetric.Metrics) []*aggregationDatum {
+func ConvertOtelMetrics(m pmetric.Metrics, summaries *summaryConverter) []*aggregationDatum {
 	datums := make([]*aggregationDatum, 0, m.DataPointCount())
 	for i := 0; i < m.ResourceMetrics().Len(); i++ {
 		entity := entityattributes.CreateCloudWatchEntityFromAttributes(m.ResourceMetrics().At(i).Resource().Attributes())
@@ -176,7 +397,7 @@ func ConvertOtelMetrics(m pmetric.Metrics) []*aggregationDatum {
 			metrics := scopeMetrics.At(j).Metrics()
 			for k := 0; k < metrics.Len(); k++ {
 				metric := metrics.At(k)
-				newDatums := ConvertOtelMetric(metric, entity)
+				newDatums := ConvertOtelMetric(metric, entity, summaries)
 				datums = append(datums, newDatums...)
 
 			}
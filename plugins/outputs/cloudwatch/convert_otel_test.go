@@ -11,6 +11,7 @@ import (
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/pmetric"
 
@@ -168,7 +169,7 @@ func checkDatum(
 func TestConvertOtelMetrics_NoDimensions(t *testing.T) {
 	for i := 0; i < 100; i++ {
 		metrics := createTestMetrics(i, i, 0, "Bytes")
-		datums := ConvertOtelMetrics(metrics)
+		datums := ConvertOtelMetrics(metrics, newSummaryConverter(false))
 		// Expect nummetrics * numDatapointsPerMetric
 		assert.Equal(t, i*i, len(datums))
 
@@ -189,7 +190,7 @@ func TestConvertOtelMetrics_Histogram(t *testing.T) {
 			distribution.NewDistribution = regular.NewRegularDistribution
 		}
 		metrics := createTestHistogram(i, i, 0, "Bytes")
-		datums := ConvertOtelMetrics(metrics)
+		datums := ConvertOtelMetrics(metrics, newSummaryConverter(false))
 		// Expect nummetrics * numDatapointsPerMetric
 		assert.Equal(t, i*i, len(datums))
 
@@ -205,7 +206,7 @@ func TestConvertOtelMetrics_Dimensions(t *testing.T) {
 	for i := 0; i < 100; i++ {
 		// 1 data point per metric, but vary the number dimensions.
 		metrics := createTestMetrics(i, 1, i, "s")
-		datums := ConvertOtelMetrics(metrics)
+		datums := ConvertOtelMetrics(metrics, newSummaryConverter(false))
 		// Expect nummetrics * numDatapointsPerMetric
 		assert.Equal(t, i, len(datums))
 
@@ -223,7 +224,7 @@ func TestConvertOtelMetrics_Dimensions(t *testing.T) {
 
 func TestConvertOtelMetrics_Entity(t *testing.T) {
 	metrics := createTestMetrics(1, 1, 1, "s")
-	datums := ConvertOtelMetrics(metrics)
+	datums := ConvertOtelMetrics(metrics, newSummaryConverter(false))
 	expectedEntity := cloudwatch.Entity{
 		KeyAttributes: map[string]*string{
 			"Type":         aws.String("Service"),
@@ -242,9 +243,137 @@ func TestConvertOtelMetrics_Entity(t *testing.T) {
 
 }
 
+func TestConvertOtelMetrics_ExponentialHistogram(t *testing.T) {
+	distribution.NewDistribution = regular.NewRegularDistribution
+	metrics := pmetric.NewMetrics()
+	m := metrics.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
+	m.SetName("latency")
+	m.SetUnit("ms")
+	dp := m.SetEmptyExponentialHistogram().DataPoints().AppendEmpty()
+	dp.SetTimestamp(pcommon.NewTimestampFromTime(time.Now()))
+	dp.Attributes().PutStr("key0", "val0")
+	dp.SetScale(0)
+	dp.SetZeroCount(1)
+	dp.Positive().SetOffset(1)
+	// (2, 4], (4, 8]
+	dp.Positive().BucketCounts().FromRaw([]uint64{2, 3})
+	dp.SetCount(6)
+	dp.SetSum(25)
+	dp.SetMin(0)
+	dp.SetMax(7)
+
+	datums := ConvertOtelMetrics(metrics, newSummaryConverter(false))
+	assert.Len(t, datums, 1)
+	d := datums[0]
+	assert.Equal(t, "latency", *d.MetricName)
+	assert.Equal(t, "Milliseconds", *d.Unit)
+	assert.Len(t, d.Dimensions, 1)
+	assert.Equal(t, 6.0, d.distribution.SampleCount())
+	assert.Equal(t, 25.0, d.distribution.Sum())
+	assert.Equal(t, 0.0, d.distribution.Minimum())
+	assert.Equal(t, 7.0, d.distribution.Maximum())
+	values, counts := d.distribution.ValuesAndCounts()
+	got := map[float64]float64{}
+	for i := range values {
+		got[values[i]] = counts[i]
+	}
+	assert.Equal(t, map[float64]float64{0: 1, 3: 2, 6: 3}, got)
+}
+
+func TestScaleExponentialHistogramDataPoint(t *testing.T) {
+	dp := pmetric.NewExponentialHistogramDataPoint()
+	dp.SetScale(1)
+	dp.Positive().SetOffset(20)
+	dp.Positive().BucketCounts().FromRaw([]uint64{1})
+	dp.SetSum(1024)
+	dp.SetMax(1100)
+	scaled := pmetric.NewExponentialHistogramDataPoint()
+	scaleExponentialHistogramDataPoint(dp, 1.0/1024, scaled)
+	// 2^-10 is 20 buckets at scale 1.
+	assert.EqualValues(t, 0, scaled.Positive().Offset())
+	assert.Equal(t, 1.0, scaled.Sum())
+	assert.InDelta(t, 1100.0/1024, scaled.Max(), 1e-9)
+	assert.False(t, scaled.HasMin())
+}
+
+func TestConvertOtelMetrics_Summary(t *testing.T) {
+	start := pcommon.NewTimestampFromTime(time.Now().Add(-time.Hour))
+	newSummary := func(count uint64, sum float64, quantiles map[float64]float64) pmetric.Metrics {
+		metrics := pmetric.NewMetrics()
+		ms := metrics.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics()
+		m := ms.AppendEmpty()
+		m.SetName("duration")
+		m.SetUnit("s")
+		dps := m.SetEmptySummary().DataPoints()
+		dp := dps.AppendEmpty()
+		dp.SetStartTimestamp(start)
+		dp.SetTimestamp(pcommon.NewTimestampFromTime(time.Now()))
+		dp.SetCount(count)
+		dp.SetSum(sum)
+		for _, q := range []float64{0, 0.5, 0.99, 0.999, 1} {
+			if v, ok := quantiles[q]; ok {
+				qv := dp.QuantileValues().AppendEmpty()
+				qv.SetQuantile(q)
+				qv.SetValue(v)
+			}
+		}
+		return metrics
+	}
+	summaries := newSummaryConverter(false)
+
+	// The first datapoint is the baseline of the cumulative count and sum.
+	assert.Empty(t, ConvertOtelMetrics(newSummary(4, 10, nil), summaries))
+	// Without the 0 and 1 quantiles, the average is the best guess for min
+	// and max, since the other quantiles are not bounds of the period.
+	datums := ConvertOtelMetrics(newSummary(8, 16, map[float64]float64{0.5: 2, 0.99: 5}), summaries)
+	require.Len(t, datums, 1)
+	assert.Equal(t, "Seconds", *datums[0].Unit)
+	assert.Nil(t, datums[0].Value)
+	assert.Nil(t, datums[0].distribution)
+	assert.Equal(t, cloudwatch.StatisticSet{
+		SampleCount: aws.Float64(4),
+		Sum:         aws.Float64(6),
+		Minimum:     aws.Float64(1.5),
+		Maximum:     aws.Float64(1.5),
+	}, *datums[0].StatisticValues)
+	datums = ConvertOtelMetrics(newSummary(12, 28, map[float64]float64{0: 1, 0.5: 2, 1: 6}), summaries)
+	require.Len(t, datums, 1)
+	assert.Equal(t, cloudwatch.StatisticSet{
+		SampleCount: aws.Float64(4),
+		Sum:         aws.Float64(12),
+		Minimum:     aws.Float64(1),
+		Maximum:     aws.Float64(6),
+	}, *datums[0].StatisticValues)
+	// Nothing to publish without new samples.
+	assert.Empty(t, ConvertOtelMetrics(newSummary(12, 28, nil), summaries))
+	// A reset count is the new baseline.
+	assert.Empty(t, ConvertOtelMetrics(newSummary(2, 3, nil), summaries))
+
+	summaries = newSummaryConverter(true)
+	metrics := newSummary(4, 10, map[float64]float64{0.5: 2, 0.99: 5, 0.999: 6})
+	datums = ConvertOtelMetrics(metrics, summaries)
+	// The quantiles are published from the first datapoint.
+	assert.Len(t, datums, 3)
+	var names []string
+	for _, d := range datums {
+		names = append(names, *d.MetricName)
+	}
+	assert.Equal(t, []string{"duration_p50", "duration_p99", "duration_p99.9"}, names)
+	assert.Equal(t, 5.0, *datums[1].Value)
+
+	datums = ConvertOtelMetrics(newSummary(6, 14, map[float64]float64{0.5: 2}), summaries)
+	assert.Len(t, datums, 2)
+	cw := &CloudWatch{config: &Config{MaxValuesPerDatum: defaultMaxValuesPerDatum}}
+	_, built := cw.BuildMetricDatum(datums[0])
+	assert.Len(t, built, 1)
+	assert.Equal(t, datums[0].StatisticValues, built[0].StatisticValues)
+	assert.Nil(t, built[0].Value)
+	assert.Nil(t, built[0].Values)
+}
+
 func TestInvalidMetric(t *testing.T) {
 	m := pmetric.NewMetric()
 	m.SetName("name")
 	m.SetUnit("unit")
-	assert.Empty(t, ConvertOtelMetric(m, cloudwatch.Entity{}))
+	assert.Empty(t, ConvertOtelMetric(m, cloudwatch.Entity{}, newSummaryConverter(false)))
 }
@@ -0,0 +1,278 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package cloudwatch
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal/publisher"
+	"github.com/aws/amazon-cloudwatch-agent/sdk/service/cloudwatch"
+)
+
+const (
+	defaultDiskBufferMaxBytes = 100 * 1024 * 1024
+	defaultDiskBufferMaxAge   = 14 * 24 * time.Hour
+	diskBufferFileExt         = ".batch"
+	diskBufferTempFileExt     = ".tmp"
+)
+
+// bufferedBatch is a batch of datums that has been written to the disk buffer.
+// The datums are only loaded while the batch is being published.
+type bufferedBatch struct {
+	id      uint64
+	size    int64
+	created time.Time
+	datums  map[string][]*cloudwatch.MetricDatum
+}
+
+// diskBuffer is a publisher.Queue that writes every batch to its own file
+// before it is published. A batch file is only removed once CloudWatch has
+// accepted it, the failure is not retryable, or the batch has aged out, so
+// batches survive network outages and agent restarts. Batches are dequeued in
+// the order they were enqueued, including those left over from a previous run.
+type diskBuffer struct {
+	dir      string
+	maxBytes int64
+	maxAge   time.Duration
+
+	mu      sync.Mutex
+	pending []*bufferedBatch
+	size    int64
+	nextID  uint64
+	closed  bool
+}
+
+var _ publisher.Queue = (*diskBuffer)(nil)
+
+func newDiskBuffer(cfg *DiskBufferConfig) (*diskBuffer, error) {
+	b := &diskBuffer{
+		dir:      cfg.Directory,
+		maxBytes: cfg.MaxBytes,
+		maxAge:   cfg.MaxAge,
+	}
+	if b.maxBytes <= 0 {
+		b.maxBytes = defaultDiskBufferMaxBytes
+	}
+	if b.maxAge <= 0 {
+		b.maxAge = defaultDiskBufferMaxAge
+	}
+	if err := os.MkdirAll(b.dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create disk buffer directory %s: %w", b.dir, err)
+	}
+	if err := b.load(); err != nil {
+		return nil, err
+	}
+	if len(b.pending) > 0 {
+		log.Printf("I! cloudwatch: found %d buffered batches (%d bytes) in %s, publishing them first",
+			len(b.pending), b.size, b.dir)
+	}
+	return b, nil
+}
+
+// load restores the batches left in the directory by a previous run.
+func (b *diskBuffer) load() error {
+	entries, err := os.ReadDir(b.dir)
+	if err != nil {
+		return fmt.Errorf("failed to read disk buffer directory %s: %w", b.dir, err)
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() {
+			continue
+		}
+		if strings.HasSuffix(name, diskBufferTempFileExt) {
+			// Incomplete write from a previous run.
+			_ = os.Remove(filepath.Join(b.dir, name))
+			continue
+		}
+		if !strings.HasSuffix(name, diskBufferFileExt) {
+			continue
+		}
+		id, err := strconv.ParseUint(strings.TrimSuffix(name, diskBufferFileExt), 10, 64)
+		if err != nil {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		b.pending = append(b.pending, &bufferedBatch{id: id, size: info.Size(), created: info.ModTime()})
+		b.size += info.Size()
+		if id >= b.nextID {
+			b.nextID = id + 1
+		}
+	}
+	sort.Slice(b.pending, func(i, j int) bool {
+		return b.pending[i].id < b.pending[j].id
+	})
+	return nil
+}
+
+func (b *diskBuffer) path(id uint64) string {
+	return filepath.Join(b.dir, fmt.Sprintf("%020d%s", id, diskBufferFileExt))
+}
+
+// Enqueue writes the batch to disk. If the buffer is over its size limit, the
+// oldest batches that are not being published are dropped.
+func (b *diskBuffer) Enqueue(req interface{}) {
+	datums, ok := req.(map[string][]*cloudwatch.MetricDatum)
+	if !ok {
+		log.Printf("E! cloudwatch: unexpected request type %T for disk buffer", req)
+		return
+	}
+	content, err := json.Marshal(datums)
+	if err != nil {
+		log.Printf("E! cloudwatch: unable to encode metric batch for disk buffer, dropping it: %v", err)
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	id := b.nextID
+	b.nextID++
+	if err = writeFileAtomic(b.path(id), content); err != nil {
+		log.Printf("E! cloudwatch: unable to write metric batch to disk buffer, dropping it: %v", err)
+		return
+	}
+	b.pending = append(b.pending, &bufferedBatch{id: id, size: int64(len(content)), created: time.Now()})
+	b.size += int64(len(content))
+	for b.size > b.maxBytes && len(b.pending) > 1 {
+		log.Printf("W! cloudwatch: disk buffer exceeds %d bytes, dropping oldest metric batch", b.maxBytes)
+		b.remove(b.pending[0])
+		b.pending = b.pending[1:]
+	}
+}
+
+// Dequeue returns the oldest batch that has not expired. The batch stays on
+// disk until done is called for it.
+func (b *diskBuffer) Dequeue() (interface{}, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for !b.closed && len(b.pending) > 0 {
+		batch := b.pending[0]
+		b.pending = b.pending[1:]
+		if time.Since(batch.created) > b.maxAge {
+			log.Printf("W! cloudwatch: buffered metric batch is older than %v, dropping it", b.maxAge)
+			b.remove(batch)
+			continue
+		}
+		content, err := os.ReadFile(b.path(batch.id))
+		if err == nil {
+			err = json.Unmarshal(content, &batch.datums)
+		}
+		if err != nil {
+			log.Printf("E! cloudwatch: unable to read buffered metric batch, dropping it: %v", err)
+			b.remove(batch)
+			continue
+		}
+		return batch, true
+	}
+	return nil, false
+}
+
+// done releases a batch returned by Dequeue. If the batch failed with an
+// error that is worth retrying, it is put back in order for a later attempt.
+// Otherwise, it is removed from disk.
+func (b *diskBuffer) done(batch *bufferedBatch, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err != nil && isRetryableError(err) {
+		batch.datums = nil
+		i := sort.Search(len(b.pending), func(i int) bool {
+			return b.pending[i].id > batch.id
+		})
+		b.pending = append(b.pending, nil)
+		copy(b.pending[i+1:], b.pending[i:])
+		b.pending[i] = batch
+		return
+	}
+	b.remove(batch)
+}
+
+// close stops handing out batches. Whatever is left stays on disk for the
+// next start.
+func (b *diskBuffer) close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.closed = true
+}
+
+// remove deletes the batch file. The caller must hold the lock.
+func (b *diskBuffer) remove(batch *bufferedBatch) {
+	if err := os.Remove(b.path(batch.id)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		log.Printf("W! cloudwatch: unable to remove buffered metric batch: %v", err)
+	}
+	b.size -= batch.size
+}
+
+// writeFileAtomic writes to a temporary file first so a crash never leaves a
+// partially written batch behind. The file is synced before the rename and the
+// directory after it, so the batch survives a power loss once it returns.
+func writeFileAtomic(path string, content []byte) error {
+	tmp := path + diskBufferTempFileExt
+	f, err := os.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(content)
+	if err == nil {
+		err = f.Sync()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp, path)
+	}
+	if err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+	if err = syncDir(filepath.Dir(path)); err != nil {
+		log.Printf("W! cloudwatch: unable to sync disk buffer directory: %v", err)
+	}
+	return nil
+}
+
+// syncDir flushes the directory entries, e.g. of a renamed file, to disk.
+// Windows does not sync directories, the rename is flushed with the file.
+func syncDir(dir string) error {
+	if runtime.GOOS == "windows" {
+		return nil
+	}
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	return d.Sync()
+}
+
+// isRetryableError returns true if the PutMetricData failure is likely to be
+// transient, e.g. the network is down or the request was throttled.
+func isRetryableError(err error) bool {
+	var awsErr awserr.Error
+	if !errors.As(err, &awsErr) {
+		return true
+	}
+	switch awsErr.Code() {
+	case cloudwatch.ErrCodeLimitExceededFault, cloudwatch.ErrCodeInternalServiceFault:
+		return true
+	}
+	return request.IsErrorRetryable(err) || request.IsErrorThrottle(err)
+}
@@ -0,0 +1,146 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package cloudwatch
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aws/amazon-cloudwatch-agent/sdk/service/cloudwatch"
+)
+
+func makeBufferedRequest(name string, ts time.Time) map[string][]*cloudwatch.MetricDatum {
+	return map[string][]*cloudwatch.MetricDatum{
+		"": {{
+			MetricName: aws.String(name),
+			Timestamp:  aws.Time(ts),
+			Value:      aws.Float64(1),
+			Dimensions: []*cloudwatch.Dimension{{Name: aws.String("host"), Value: aws.String("h")}},
+		}},
+	}
+}
+
+func dequeueMetricName(t *testing.T, b *diskBuffer) (*bufferedBatch, string) {
+	t.Helper()
+	req, ok := b.Dequeue()
+	require.True(t, ok)
+	batch := req.(*bufferedBatch)
+	return batch, *batch.datums[""][0].MetricName
+}
+
+func TestDiskBuffer_ReplayInOrderAfterRestart(t *testing.T) {
+	dir := t.TempDir()
+	ts := time.Date(2024, 1, 2, 3, 4, 5, 6, time.UTC)
+	b, err := newDiskBuffer(&DiskBufferConfig{Directory: dir})
+	require.NoError(t, err)
+	b.Enqueue(makeBufferedRequest("m1", ts))
+	b.Enqueue(makeBufferedRequest("m2", ts))
+	b.Enqueue(makeBufferedRequest("m3", ts))
+
+	// First batch is in flight when the agent stops.
+	_, name := dequeueMetricName(t, b)
+	assert.Equal(t, "m1", name)
+	b.close()
+	_, ok := b.Dequeue()
+	assert.False(t, ok)
+
+	restarted, err := newDiskBuffer(&DiskBufferConfig{Directory: dir})
+	require.NoError(t, err)
+	for _, want := range []string{"m1", "m2", "m3"} {
+		batch, name := dequeueMetricName(t, restarted)
+		assert.Equal(t, want, name)
+		assert.True(t, ts.Equal(*batch.datums[""][0].Timestamp))
+		restarted.done(batch, nil)
+	}
+	_, ok = restarted.Dequeue()
+	assert.False(t, ok)
+	files, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Empty(t, files)
+	assert.EqualValues(t, 0, restarted.size)
+
+	// New batches continue after the replayed ones.
+	restarted.Enqueue(makeBufferedRequest("m4", ts))
+	_, err = os.Stat(filepath.Join(dir, "00000000000000000003.batch"))
+	assert.NoError(t, err)
+}
+
+func TestDiskBuffer_Done(t *testing.T) {
+	b, err := newDiskBuffer(&DiskBufferConfig{Directory: t.TempDir()})
+	require.NoError(t, err)
+	b.Enqueue(makeBufferedRequest("m1", time.Now()))
+	b.Enqueue(makeBufferedRequest("m2", time.Now()))
+	b.Enqueue(makeBufferedRequest("m3", time.Now()))
+
+	first, _ := dequeueMetricName(t, b)
+	second, _ := dequeueMetricName(t, b)
+	// Retryable failures are put back in their original order.
+	b.done(second, errors.New("connection refused"))
+	b.done(first, awserr.New(cloudwatch.ErrCodeLimitExceededFault, "", nil))
+	_, name := dequeueMetricName(t, b)
+	assert.Equal(t, "m1", name)
+	_, name = dequeueMetricName(t, b)
+	assert.Equal(t, "m2", name)
+	third, name := dequeueMetricName(t, b)
+	assert.Equal(t, "m3", name)
+
+	// Non-retryable failures are dropped.
+	b.done(third, awserr.New(cloudwatch.ErrCodeInvalidParameterValueException, "", nil))
+	_, err = os.Stat(b.path(third.id))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestDiskBuffer_MaxBytes(t *testing.T) {
+	dir := t.TempDir()
+	b, err := newDiskBuffer(&DiskBufferConfig{Directory: dir, MaxBytes: 1})
+	require.NoError(t, err)
+	b.Enqueue(makeBufferedRequest("m1", time.Now()))
+	b.Enqueue(makeBufferedRequest("m2", time.Now()))
+	// Only the newest batch is kept.
+	_, name := dequeueMetricName(t, b)
+	assert.Equal(t, "m2", name)
+	_, ok := b.Dequeue()
+	assert.False(t, ok)
+	files, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, files, 1)
+}
+
+func TestDiskBuffer_MaxAge(t *testing.T) {
+	dir := t.TempDir()
+	b, err := newDiskBuffer(&DiskBufferConfig{Directory: dir, MaxAge: time.Hour})
+	require.NoError(t, err)
+	b.Enqueue(makeBufferedRequest("m1", time.Now()))
+	b.Enqueue(makeBufferedRequest("m2", time.Now()))
+	old := time.Now().Add(-2 * time.Hour)
+	require.NoError(t, os.Chtimes(b.path(0), old, old))
+	// Leftover from an interrupted write.
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "00000000000000000005.batch.tmp"), []byte("{"), 0600))
+
+	restarted, err := newDiskBuffer(&DiskBufferConfig{Directory: dir, MaxAge: time.Hour})
+	require.NoError(t, err)
+	_, name := dequeueMetricName(t, restarted)
+	assert.Equal(t, "m2", name)
+	_, ok := restarted.Dequeue()
+	assert.False(t, ok)
+	files, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, files, 1)
+}
+
+func TestIsRetryableError(t *testing.T) {
+	assert.True(t, isRetryableError(errors.New("dial tcp: connection refused")))
+	assert.True(t, isRetryableError(awserr.New(cloudwatch.ErrCodeInternalServiceFault, "", nil)))
+	assert.True(t, isRetryableError(awserr.New("RequestError", "send request failed", errors.New("connection reset"))))
+	assert.True(t, isRetryableError(awserr.New("Throttling", "", nil)))
+	assert.False(t, isRetryableError(awserr.New(cloudwatch.ErrCodeInvalidParameterValueException, "", nil)))
+}
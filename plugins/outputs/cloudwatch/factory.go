@@ -6,11 +6,14 @@ package cloudwatch
 
 import (
 	"context"
+	"reflect"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/resourcetotelemetry"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/exporter"
 	"go.opentelemetry.io/collector/exporter/exporterhelper"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal/reload"
 )
 
 const (
@@ -46,9 +49,28 @@ func createMetricsExporter(
 	settings exporter.Settings,
 	config component.Config,
 ) (exporter.Metrics, error) {
-	cw := &CloudWatch{
-		config: config.(*Config),
-		logger: settings.Logger,
+	cfg := config.(*Config)
+	// Reuse the exporter kept publishing while the collector service restarts.
+	cw, ok := reload.Reclaim(func(cw *CloudWatch) bool {
+		return cw.id == settings.ID && reflect.DeepEqual(cw.original, *cfg)
+	})
+	if !ok {
+		// A parked exporter that buffers to the same directory is shut down
+		// first, which moves its remaining batches into the disk buffer that
+		// the new exporter then opens and replays.
+		if cfg.DiskBuffer != nil {
+			if parked, ok := reload.Reclaim(func(cw *CloudWatch) bool {
+				return cw.config.DiskBuffer != nil && cw.config.DiskBuffer.Directory == cfg.DiskBuffer.Directory
+			}); ok {
+				parked.Release()
+			}
+		}
+		cw = &CloudWatch{
+			config:   cfg,
+			original: *cfg,
+			id:       settings.ID,
+			logger:   settings.Logger,
+		}
 	}
 	exp, err := exporterhelper.NewMetrics(
 		ctx,
@@ -6,12 +6,16 @@ package cloudwatch
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/exporter/exportertest"
 	"go.opentelemetry.io/collector/pipeline"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal/publisher"
+	"github.com/aws/amazon-cloudwatch-agent/internal/reload"
 )
 
 func TestCreateDefaultConfig(t *testing.T) {
@@ -38,3 +42,53 @@ func TestCreateExporter(t *testing.T) {
 	assert.Equal(t, err, pipeline.ErrSignalNotSupported)
 	assert.Nil(t, tLogs)
 }
+
+func TestCreateExporterReusesParkedExporter(t *testing.T) {
+	reload.Enable()
+	defer reload.Disable()
+
+	settings := exportertest.NewNopSettings(TypeStr)
+	parked := &CloudWatch{
+		config:   &Config{Namespace: "CWAgent"},
+		original: Config{Namespace: "CWAgent"},
+		id:       settings.ID,
+		started:  true,
+	}
+	assert.True(t, reload.Park(parked))
+
+	_, err := createMetricsExporter(context.Background(), settings, &Config{Namespace: "Other"})
+	assert.NoError(t, err)
+	reclaimed, ok := reload.Reclaim(func(cw *CloudWatch) bool { return cw == parked })
+	assert.True(t, ok, "an exporter with another config does not reuse the parked one")
+	assert.True(t, reload.Park(reclaimed))
+
+	_, err = createMetricsExporter(context.Background(), settings, &Config{Namespace: "CWAgent"})
+	assert.NoError(t, err)
+	_, ok = reload.Reclaim(func(cw *CloudWatch) bool { return cw == parked })
+	assert.False(t, ok, "an exporter with the same config reuses the parked one")
+}
+
+func TestCreateExporterReleasesParkedExporterWithSameDiskBuffer(t *testing.T) {
+	reload.Enable()
+	defer reload.Disable()
+
+	settings := exportertest.NewNopSettings(TypeStr)
+	diskBuffer := &DiskBufferConfig{Directory: t.TempDir()}
+	parked := newCloudWatchClient(new(mockCloudWatchClient), time.Minute)
+	parked.publisher, _ = publisher.NewPublisher(publisher.NewNonBlockingFifoQueue(10), 10, time.Second, parked.WriteToCloudWatch)
+	parked.config.DiskBuffer = diskBuffer
+	parked.original = *parked.config
+	parked.id = settings.ID
+	parked.started = true
+	assert.True(t, reload.Park(parked))
+
+	_, err := createMetricsExporter(context.Background(), settings, &Config{Namespace: "Other", DiskBuffer: diskBuffer})
+	assert.NoError(t, err)
+	_, ok := reload.Reclaim(func(cw *CloudWatch) bool { return cw == parked })
+	assert.False(t, ok, "the parked exporter does not keep the disk buffer of the new one")
+	select {
+	case <-parked.shutdownChan:
+	default:
+		assert.Fail(t, "the parked exporter is shut down")
+	}
+}
@@ -0,0 +1,115 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package cloudwatch
+
+import (
+	"regexp"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+
+	"github.com/aws/amazon-cloudwatch-agent/sdk/service/cloudwatch"
+)
+
+// preFlushAggregation decides the aggregation interval and mode of the datums
+// that receivers did not set an aggregation interval for.
+type preFlushAggregation struct {
+	interval time.Duration
+	mode     string
+	rules    []preFlushAggregationRule
+}
+
+type preFlushAggregationRule struct {
+	metricNames []*regexp.Regexp
+	interval    time.Duration
+	mode        string
+}
+
+// newPreFlushAggregation compiles the config. It returns nil if the config is
+// nil, so that no datums are aggregated.
+func newPreFlushAggregation(cfg *PreFlushAggregationConfig, forceFlushInterval time.Duration) (*preFlushAggregation, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+	p := &preFlushAggregation{
+		interval: cfg.Interval,
+		mode:     cfg.Mode,
+	}
+	if p.interval == 0 {
+		p.interval = forceFlushInterval
+	}
+	if p.mode == "" {
+		p.mode = PreFlushAggregationModeDistribution
+	}
+	for _, rule := range cfg.Rules {
+		r := preFlushAggregationRule{interval: rule.Interval, mode: rule.Mode}
+		for _, name := range rule.MetricNames {
+			re, err := regexp.Compile(name)
+			if err != nil {
+				return nil, err
+			}
+			r.metricNames = append(r.metricNames, re)
+		}
+		p.rules = append(p.rules, r)
+	}
+	return p, nil
+}
+
+// apply sets the aggregation interval of the datum and whether it is published
+// as a StatisticSet. Datums whose aggregation interval the receiver already set
+// are left as they are, and datums with pre-computed statistics, e.g. from
+// summaries, are not aggregated.
+func (p *preFlushAggregation) apply(m *aggregationDatum) {
+	if p == nil || m.MetricName == nil || m.aggregationInterval != 0 {
+		return
+	}
+	interval, mode := p.interval, p.mode
+	for _, rule := range p.rules {
+		if rule.matches(*m.MetricName) {
+			if rule.interval != 0 {
+				interval = rule.interval
+			}
+			if rule.mode != "" {
+				mode = rule.mode
+			}
+			break
+		}
+	}
+	if mode == PreFlushAggregationModeNone {
+		return
+	}
+	if m.Value == nil && m.distribution == nil {
+		return
+	}
+	m.aggregationInterval = interval
+	m.statisticSet = mode == PreFlushAggregationModeStatisticSet
+}
+
+func (r *preFlushAggregationRule) matches(metricName string) bool {
+	for _, re := range r.metricNames {
+		if re.MatchString(metricName) {
+			return true
+		}
+	}
+	return false
+}
+
+// toStatisticSet replaces the distribution of the datum with its statistics,
+// so the datum is published without its values and counts.
+func (m *aggregationDatum) toStatisticSet() {
+	if m.distribution == nil || m.distribution.Size() == 0 {
+		return
+	}
+	if m.distribution.Unit() != "" {
+		m.SetUnit(m.distribution.Unit())
+	}
+	m.SetStatisticValues(&cloudwatch.StatisticSet{
+		Maximum:     aws.Float64(m.distribution.Maximum()),
+		Minimum:     aws.Float64(m.distribution.Minimum()),
+		SampleCount: aws.Float64(m.distribution.SampleCount()),
+		Sum:         aws.Float64(m.distribution.Sum()),
+	})
+	m.Value = nil
+	m.distribution = nil
+}
@@ -125,6 +125,8 @@ func payload(datum *cloudwatch.MetricDatum) (size int) {
 	valuesCountsLen := len(datum.Values)
 	if valuesCountsLen != 0 {
 		size += valuesCountsLen*valuesCountsOverheads + statisticsSize
+	} else if datum.StatisticValues != nil {
+		size += statisticsSize
 	} else {
 		size += valueOverheads
 	}
@@ -6,6 +6,7 @@ package cloudwatchlogs
 import (
 	"encoding/json"
 	"fmt"
+	"maps"
 	"regexp"
 	"strings"
 	"sync"
@@ -24,7 +25,9 @@ import (
 	"github.com/aws/amazon-cloudwatch-agent/extension/agenthealth/handler/useragent"
 	"github.com/aws/amazon-cloudwatch-agent/handlers"
 	"github.com/aws/amazon-cloudwatch-agent/internal"
+	"github.com/aws/amazon-cloudwatch-agent/internal/deadletter"
 	"github.com/aws/amazon-cloudwatch-agent/internal/retryer"
+	"github.com/aws/amazon-cloudwatch-agent/internal/selftelemetry"
 	"github.com/aws/amazon-cloudwatch-agent/logs"
 	"github.com/aws/amazon-cloudwatch-agent/plugins/outputs/cloudwatchlogs/internal/pusher"
 	"github.com/aws/amazon-cloudwatch-agent/sdk/service/cloudwatchlogs"
@@ -72,13 +75,28 @@ type CloudWatchLogs struct {
 
 	ForceFlushInterval internal.Duration `toml:"force_flush_interval"` // unit is second
 
+	// Settings applied to the log groups when the agent creates them. The log
+	// sources with their own settings override them, and add to the tags.
+	KMSKeyID             string                   `toml:"kms_key_id"`
+	LogGroupTags         map[string]string        `toml:"log_group_tags"`
+	SubscriptionFilter   *logs.SubscriptionFilter `toml:"subscription_filter"`
+	DataProtectionPolicy string                   `toml:"data_protection_policy"`
+
+	// Directory the log events that could not be published are written to.
+	// Dead letters are disabled if not set.
+	DeadLetterDir      string `toml:"dead_letter_dir"`
+	DeadLetterMaxBytes int64  `toml:"dead_letter_max_bytes"`
+
 	Log telegraf.Logger `toml:"-"`
 
 	pusherStopChan  chan struct{}
 	pusherWaitGroup sync.WaitGroup
 	cwDests         sync.Map
+	destsMu         sync.Mutex
+	groupSettings   sync.Map
 	workerPool      pusher.WorkerPool
 	targetManager   pusher.TargetManager
+	deadLetter      *deadletter.Spool
 	once            sync.Once
 	middleware      awsmiddleware.Middleware
 }
@@ -88,20 +106,37 @@ func (c *CloudWatchLogs) Connect() error {
 }
 
 func (c *CloudWatchLogs) Close() error {
+	c.destsMu.Lock()
 	close(c.pusherStopChan)
-	c.pusherWaitGroup.Wait()
-
 	c.cwDests.Range(func(_, value interface{}) bool {
 		if d, ok := value.(*cwDest); ok {
-			d.Stop()
+			d.stopPusher()
 		}
 		return true
 	})
+	c.destsMu.Unlock()
+	c.cwDests.Range(func(_, value interface{}) bool {
+		if d, ok := value.(*cwDest); ok {
+			d.wg.Wait()
+		}
+		return true
+	})
+	// Wait for the released dests, which are stopped in the background.
+	c.pusherWaitGroup.Wait()
 
+	// The batches of the stopped queues are sent before the series of their
+	// streams are deleted.
 	if c.workerPool != nil {
 		c.workerPool.Stop()
 	}
 
+	c.cwDests.Range(func(_, value interface{}) bool {
+		if d, ok := value.(*cwDest); ok {
+			d.Stop()
+		}
+		return true
+	})
+
 	return nil
 }
 
@@ -129,9 +164,80 @@ func (c *CloudWatchLogs) CreateDest(group, stream string, retention int, logGrou
 		Retention: retention,
 		Class:     logGroupClass,
 	}
-	return c.getDest(t, logSrc)
+	c.setLogGroupSettings(group, logSrc)
+	c.destsMu.Lock()
+	defer c.destsMu.Unlock()
+	cwd := c.getDest(t, logSrc)
+	cwd.refs++
+	return cwd
+}
+
+// setLogGroupSettings keeps the log group settings of the source merged with
+// the ones of the output. The settings of a source replace the ones kept for
+// the log group, e.g. on reload, but a source without settings of its own
+// does not replace the ones of another source.
+func (c *CloudWatchLogs) setLogGroupSettings(group string, logSrc logs.LogSrc) {
+	var srcSettings *logs.LogGroupSettings
+	if p, ok := logSrc.(logs.LogGroupSettingsProvider); ok {
+		srcSettings = p.LogGroupSettings()
+	}
+	if srcSettings == nil {
+		if _, ok := c.groupSettings.Load(group); ok {
+			return
+		}
+	}
+	settings := c.mergeLogGroupSettings(srcSettings)
+	if settings == nil {
+		return
+	}
+	if srcSettings == nil {
+		c.groupSettings.LoadOrStore(group, settings)
+	} else {
+		c.groupSettings.Store(group, settings)
+	}
 }
 
+// getLogGroupSettings returns the settings of the log group, or nil if there
+// are none.
+func (c *CloudWatchLogs) getLogGroupSettings(group string) *logs.LogGroupSettings {
+	if settings, ok := c.groupSettings.Load(group); ok {
+		return settings.(*logs.LogGroupSettings)
+	}
+	return nil
+}
+
+func (c *CloudWatchLogs) mergeLogGroupSettings(srcSettings *logs.LogGroupSettings) *logs.LogGroupSettings {
+	settings := logs.LogGroupSettings{
+		KMSKeyID:             c.KMSKeyID,
+		Tags:                 maps.Clone(c.LogGroupTags),
+		SubscriptionFilter:   c.SubscriptionFilter,
+		DataProtectionPolicy: c.DataProtectionPolicy,
+	}
+	if srcSettings != nil {
+		if srcSettings.KMSKeyID != "" {
+			settings.KMSKeyID = srcSettings.KMSKeyID
+		}
+		if len(srcSettings.Tags) > 0 {
+			if settings.Tags == nil {
+				settings.Tags = make(map[string]string, len(srcSettings.Tags))
+			}
+			maps.Copy(settings.Tags, srcSettings.Tags)
+		}
+		if srcSettings.SubscriptionFilter != nil {
+			settings.SubscriptionFilter = srcSettings.SubscriptionFilter
+		}
+		if srcSettings.DataProtectionPolicy != "" {
+			settings.DataProtectionPolicy = srcSettings.DataProtectionPolicy
+		}
+	}
+	if settings.KMSKeyID == "" && len(settings.Tags) == 0 && settings.SubscriptionFilter == nil && settings.DataProtectionPolicy == "" {
+		return nil
+	}
+	return &settings
+}
+
+// getDest returns the cwDest of the target, which is created the first time.
+// Must be called with the destsMu held.
 func (c *CloudWatchLogs) getDest(t pusher.Target, logSrc logs.LogSrc) *cwDest {
 	if cwd, ok := c.cwDests.Load(t); ok {
 		return cwd.(*cwDest)
@@ -148,14 +254,45 @@ func (c *CloudWatchLogs) getDest(t pusher.Target, logSrc logs.LogSrc) *cwDest {
 		if c.Concurrency > 1 {
 			c.workerPool = pusher.NewWorkerPool(c.Concurrency)
 		}
-		c.targetManager = pusher.NewTargetManager(c.Log, client)
+		c.targetManager = pusher.NewTargetManager(c.Log, client, c.getLogGroupSettings)
+		if c.DeadLetterDir != "" {
+			var err error
+			if c.deadLetter, err = deadletter.NewSpool(c.DeadLetterDir, c.DeadLetterMaxBytes); err != nil {
+				c.Log.Errorf("Unable to create dead letter spool %v, dropped log events will be missing: %v", c.DeadLetterDir, err)
+			}
+		}
 	})
-	p := pusher.NewPusher(c.Log, t, client, c.targetManager, logSrc, c.workerPool, c.ForceFlushInterval.Duration, maxRetryTimeout, c.pusherStopChan, &c.pusherWaitGroup)
-	cwd := &cwDest{pusher: p, retryer: logThrottleRetryer}
+	cwd := &cwDest{retryer: logThrottleRetryer, stop: make(chan struct{})}
+	cwd.pusher = pusher.NewPusher(c.Log, t, client, c.targetManager, logSrc, c.workerPool, c.ForceFlushInterval.Duration, maxRetryTimeout, c.deadLetter, cwd.stop, &cwd.wg)
+	cwd.release = func() { c.releaseDest(t, cwd) }
+	select {
+	case <-c.pusherStopChan:
+		cwd.stopPusher()
+	default:
+	}
 	c.cwDests.Store(t, cwd)
 	return cwd
 }
 
+// releaseDest stops the pusher of the cwDest once none of the sources that
+// created it publish to it, unless the structured logs are published to it.
+func (c *CloudWatchLogs) releaseDest(t pusher.Target, cwd *cwDest) {
+	c.destsMu.Lock()
+	defer c.destsMu.Unlock()
+	cwd.refs--
+	if cwd.refs > 0 || cwd.structured {
+		return
+	}
+	c.cwDests.CompareAndDelete(t, cwd)
+	cwd.stopPusher()
+	c.pusherWaitGroup.Add(1)
+	go func() {
+		defer c.pusherWaitGroup.Done()
+		cwd.wg.Wait()
+		cwd.Stop()
+	}()
+}
+
 func (c *CloudWatchLogs) createClient(retryer aws.RequestRetryer) *cloudwatchlogs.CloudWatchLogs {
 	credentialConfig := &configaws.CredentialConfig{
 		Region:    c.Region,
@@ -191,7 +328,12 @@ func (c *CloudWatchLogs) writeMetricAsStructuredLog(m telegraf.Metric) {
 	if err != nil {
 		c.Log.Errorf("Failed to find target: %v", err)
 	}
+	c.destsMu.Lock()
 	cwd := c.getDest(t, nil)
+	if cwd != nil {
+		cwd.structured = true
+	}
+	c.destsMu.Unlock()
 	if cwd == nil {
 		c.Log.Warnf("unable to find log destination, group: %v, stream: %v", t.Group, t.Stream)
 		return
@@ -223,6 +365,7 @@ func (c *CloudWatchLogs) getTargetFromMetric(m telegraf.Metric) (pusher.Target,
 		logStream = c.LogStreamName
 	}
 
+	c.setLogGroupSettings(logGroup, nil)
 	return pusher.Target{Group: logGroup, Stream: logStream, Class: util.StandardLogGroupClass, Retention: -1}, nil
 }
 
@@ -322,8 +465,21 @@ type cwDest struct {
 	isEMF   bool
 	stopped bool
 	retryer *retryer.LogThrottleRetryer
+
+	stop     chan struct{}
+	stopOnce sync.Once
+	wg       sync.WaitGroup
+	release  func()
+	// refs is the number of times the dest was created by CreateDest and not
+	// released, guarded by the destsMu of the output.
+	refs int
+	// structured is set if the structured logs are published to the dest,
+	// which is then never released.
+	structured bool
 }
 
+var _ logs.ReleasableLogDest = (*cwDest)(nil)
+
 func (cd *cwDest) Publish(events []logs.LogEvent) error {
 	for _, e := range events {
 		if !cd.isEMF {
@@ -340,9 +496,21 @@ func (cd *cwDest) Publish(events []logs.LogEvent) error {
 	return nil
 }
 
+// Release stops the pusher once the dest is no longer published to.
+func (cd *cwDest) Release() {
+	cd.release()
+}
+
+func (cd *cwDest) stopPusher() {
+	cd.stopOnce.Do(func() {
+		close(cd.stop)
+	})
+}
+
 func (cd *cwDest) Stop() {
 	cd.retryer.Stop()
 	cd.stopped = true
+	selftelemetry.DeleteLogStream(cd.pusher.Group, cd.pusher.Stream)
 }
 
 func (cd *cwDest) AddEvent(e logs.LogEvent) {
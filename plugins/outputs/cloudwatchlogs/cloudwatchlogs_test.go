@@ -4,13 +4,21 @@
 package cloudwatchlogs
 
 import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/influxdata/telegraf/testutil"
 	"github.com/stretchr/testify/require"
 
+	"github.com/aws/amazon-cloudwatch-agent/internal"
+	"github.com/aws/amazon-cloudwatch-agent/internal/deadletter"
 	"github.com/aws/amazon-cloudwatch-agent/logs"
+	"github.com/aws/amazon-cloudwatch-agent/plugins/outputs/cloudwatchlogs/internal/pusher"
 	"github.com/aws/amazon-cloudwatch-agent/tool/util"
 )
 
@@ -102,3 +110,144 @@ func TestDuplicateDestination(t *testing.T) {
 	// Then the destination for cloudwatchlogs endpoint would be the same
 	require.Equal(t, d1, d2)
 }
+
+func TestReleaseDestination(t *testing.T) {
+	c := &CloudWatchLogs{
+		Log:            testutil.Logger{Name: "test"},
+		AccessKey:      "access_key",
+		SecretKey:      "secret_key",
+		cwDests:        sync.Map{},
+		pusherStopChan: make(chan struct{}),
+	}
+	d1 := c.CreateDest("G1", "S1", -1, "", nil).(*cwDest)
+	d2 := c.CreateDest("G1", "S1", -1, "", nil).(*cwDest)
+	require.Same(t, d1, d2)
+
+	// The dest is stopped once each source that created it released it.
+	d1.Release()
+	_, ok := c.cwDests.Load(pusher.Target{Group: "G1", Stream: "S1", Retention: -1})
+	require.True(t, ok)
+	d2.Release()
+	_, ok = c.cwDests.Load(pusher.Target{Group: "G1", Stream: "S1", Retention: -1})
+	require.False(t, ok)
+	d1.wg.Wait()
+
+	// A dest that the structured logs are published to is not released.
+	d3 := c.CreateDest("G1", "S1", -1, "", nil).(*cwDest)
+	require.NotSame(t, d1, d3)
+	c.destsMu.Lock()
+	c.getDest(pusher.Target{Group: "G1", Stream: "S1", Retention: -1}, nil).structured = true
+	c.destsMu.Unlock()
+	d3.Release()
+	_, ok = c.cwDests.Load(pusher.Target{Group: "G1", Stream: "S1", Retention: -1})
+	require.True(t, ok)
+
+	require.NoError(t, c.Close())
+	require.True(t, d1.stopped)
+	require.True(t, d3.stopped)
+}
+
+type settingsSrc struct {
+	logs.LogSrc
+	settings *logs.LogGroupSettings
+}
+
+func (s *settingsSrc) LogGroupSettings() *logs.LogGroupSettings {
+	return s.settings
+}
+
+func TestLogGroupSettings(t *testing.T) {
+	filter := &logs.SubscriptionFilter{DestinationARN: "arn:aws:lambda:us-east-1:123456789012:function:f"}
+	c := &CloudWatchLogs{
+		KMSKeyID:           "default-key",
+		LogGroupTags:       map[string]string{"team": "platform", "env": "prod"},
+		SubscriptionFilter: filter,
+	}
+	c.setLogGroupSettings("default", nil)
+	require.Equal(t, &logs.LogGroupSettings{
+		KMSKeyID:           "default-key",
+		Tags:               map[string]string{"team": "platform", "env": "prod"},
+		SubscriptionFilter: filter,
+	}, c.getLogGroupSettings("default"))
+
+	src := &settingsSrc{settings: &logs.LogGroupSettings{
+		KMSKeyID:             "file-key",
+		Tags:                 map[string]string{"team": "security"},
+		DataProtectionPolicy: `{"Name":"policy"}`,
+	}}
+	c.setLogGroupSettings("file", src)
+	settings := c.getLogGroupSettings("file")
+	require.Equal(t, &logs.LogGroupSettings{
+		KMSKeyID:             "file-key",
+		Tags:                 map[string]string{"team": "security", "env": "prod"},
+		SubscriptionFilter:   filter,
+		DataProtectionPolicy: `{"Name":"policy"}`,
+	}, settings)
+	// a source without settings of its own keeps the ones of the log group
+	c.setLogGroupSettings("file", nil)
+	require.Same(t, settings, c.getLogGroupSettings("file"))
+	require.Equal(t, map[string]string{"team": "platform", "env": "prod"}, c.LogGroupTags)
+
+	empty := &CloudWatchLogs{}
+	empty.setLogGroupSettings("group", &settingsSrc{})
+	require.Nil(t, empty.getLogGroupSettings("group"))
+}
+
+func TestCreateDestWithLogGroupSettings(t *testing.T) {
+	c := &CloudWatchLogs{
+		Log:            testutil.Logger{Name: "test"},
+		AccessKey:      "access_key",
+		SecretKey:      "secret_key",
+		cwDests:        sync.Map{},
+		pusherStopChan: make(chan struct{}),
+	}
+	// the same log group and stream with the settings of two sources
+	d1 := c.CreateDest("G", "S", -1, util.StandardLogGroupClass, &settingsSrc{settings: &logs.LogGroupSettings{KMSKeyID: "first"}})
+	d2 := c.CreateDest("G", "S", -1, util.StandardLogGroupClass, &settingsSrc{settings: &logs.LogGroupSettings{KMSKeyID: "second"}})
+	require.Same(t, d1, d2)
+	require.Equal(t, "second", c.getLogGroupSettings("G").KMSKeyID)
+}
+
+func TestReplayDeadLetters(t *testing.T) {
+	var published atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.Header.Get("X-Amz-Target"), ".PutLogEvents") {
+			published.Add(1)
+		}
+		_, _ = w.Write([]byte("{}"))
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	spool, err := deadletter.NewSpool(dir, 0)
+	require.NoError(t, err)
+	require.NoError(t, spool.Write(deadletter.Record{
+		Group:  "G1",
+		Stream: "S1",
+		Reason: "retries_exhausted",
+		Time:   time.Now(),
+		Events: []deadletter.Event{
+			{Timestamp: time.Now().UnixMilli(), Message: "first"},
+			{Timestamp: time.Now().UnixMilli(), Message: "second"},
+		},
+	}))
+
+	c := &CloudWatchLogs{
+		Log:                testutil.Logger{Name: "test"},
+		Region:             "us-east-1",
+		EndpointOverride:   server.URL,
+		AccessKey:          "access_key",
+		SecretKey:          "secret_key",
+		ForceFlushInterval: internal.Duration{Duration: 10 * time.Millisecond},
+		cwDests:            sync.Map{},
+		pusherStopChan:     make(chan struct{}),
+	}
+	result, err := c.ReplayDeadLetters(dir, time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, ReplayResult{Replayed: 1}, result)
+	require.EqualValues(t, 1, published.Load())
+
+	paths, err := deadletter.List(dir)
+	require.NoError(t, err)
+	require.Empty(t, paths)
+}
@@ -0,0 +1,118 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package cloudwatchlogs
+
+import (
+	"os"
+	"sync/atomic"
+	"time"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal/deadletter"
+	"github.com/aws/amazon-cloudwatch-agent/plugins/outputs/cloudwatchlogs/internal/pusher"
+)
+
+// ReplayResult is the outcome of replaying a dead letter directory.
+type ReplayResult struct {
+	// Replayed is the number of records whose events were all published or
+	// written to the spool again. These records are removed.
+	Replayed int
+	// Kept is the number of records that are left in the directory.
+	Kept int
+}
+
+// ReplayDeadLetters publishes the events of each record in the directory to
+// the log group and stream the record was meant for, using the same pusher as
+// the agent. Events that are still not published are written to the directory
+// again as new records, so nothing is lost if the replay is interrupted. Waits
+// up to the timeout for the events to be published and closes the output.
+func (c *CloudWatchLogs) ReplayDeadLetters(dir string, timeout time.Duration) (ReplayResult, error) {
+	var result ReplayResult
+	paths, err := deadletter.List(dir)
+	if err != nil {
+		return result, err
+	}
+	c.DeadLetterDir = dir
+
+	var replays []*replay
+	for _, path := range paths {
+		record, err := deadletter.Read(path)
+		if err != nil {
+			c.Log.Errorf("Unable to replay dead letter record: %v", err)
+			result.Kept++
+			continue
+		}
+		r := newReplay(path, len(record.Events))
+		replays = append(replays, r)
+		if len(record.Events) == 0 {
+			continue
+		}
+		cwd := c.getDest(pusher.Target{Group: record.Group, Stream: record.Stream, Class: record.Class, Retention: -1}, nil)
+		cwd.pusher.Sender.SetRetryDuration(timeout)
+		for _, e := range record.Events {
+			cwd.AddEvent(&replayEvent{msg: e.Message, t: time.UnixMilli(e.Timestamp), replay: r})
+		}
+	}
+
+	deadline := time.After(timeout)
+	for _, r := range replays {
+		select {
+		case <-r.done:
+		case <-deadline:
+		}
+	}
+	// batches that are still being retried are written to the spool on close
+	if err = c.Close(); err != nil {
+		return result, err
+	}
+
+	for _, r := range replays {
+		if r.pending.Load() > 0 {
+			result.Kept++
+			continue
+		}
+		if err = os.Remove(r.path); err != nil {
+			c.Log.Errorf("Unable to remove replayed dead letter record %v: %v", r.path, err)
+			result.Kept++
+			continue
+		}
+		result.Replayed++
+	}
+	return result, nil
+}
+
+// replay tracks the events of a record that are not done yet.
+type replay struct {
+	path    string
+	pending atomic.Int32
+	done    chan struct{}
+}
+
+func newReplay(path string, events int) *replay {
+	r := &replay{path: path, done: make(chan struct{})}
+	r.pending.Store(int32(events))
+	if events == 0 {
+		close(r.done)
+	}
+	return r
+}
+
+type replayEvent struct {
+	msg    string
+	t      time.Time
+	replay *replay
+}
+
+func (e *replayEvent) Message() string {
+	return e.msg
+}
+
+func (e *replayEvent) Time() time.Time {
+	return e.t
+}
+
+func (e *replayEvent) Done() {
+	if e.replay.pending.Add(-1) == 0 {
+		close(e.replay.done)
+	}
+}
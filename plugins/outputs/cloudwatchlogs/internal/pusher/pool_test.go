@@ -108,7 +108,7 @@ func TestSenderPool(t *testing.T) {
 	stop := make(chan struct{})
 	mockService := new(mockLogsService)
 	mockService.On("PutLogEvents", mock.Anything).Return(&cloudwatchlogs.PutLogEventsOutput{}, nil)
-	s := newSender(logger, mockService, nil, time.Second, stop)
+	s := newSender(logger, mockService, nil, time.Second, nil, stop)
 	p := NewWorkerPool(12)
 	sp := newSenderPool(p, s)
 
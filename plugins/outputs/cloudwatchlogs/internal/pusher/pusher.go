@@ -9,6 +9,7 @@ import (
 
 	"github.com/influxdata/telegraf"
 
+	"github.com/aws/amazon-cloudwatch-agent/internal/deadletter"
 	"github.com/aws/amazon-cloudwatch-agent/logs"
 )
 
@@ -23,7 +24,7 @@ type Pusher struct {
 }
 
 // NewPusher creates a new Pusher instance with a new Queue and Sender. Calls PutRetentionPolicy using the
-// TargetManager.
+// TargetManager. The events that are dropped are written to the deadLetter spool if it is not nil.
 func NewPusher(
 	logger telegraf.Logger,
 	target Target,
@@ -33,10 +34,11 @@ func NewPusher(
 	workerPool WorkerPool,
 	flushTimeout time.Duration,
 	retryDuration time.Duration,
+	deadLetter *deadletter.Spool,
 	stop <-chan struct{},
 	wg *sync.WaitGroup,
 ) *Pusher {
-	s := createSender(logger, service, targetManager, workerPool, retryDuration, stop)
+	s := createSender(logger, service, targetManager, workerPool, retryDuration, deadLetter, stop)
 	q := newQueue(logger, target, flushTimeout, entityProvider, s, stop, wg)
 	targetManager.PutRetentionPolicy(target)
 	return &Pusher{
@@ -56,9 +58,10 @@ func createSender(
 	targetManager TargetManager,
 	workerPool WorkerPool,
 	retryDuration time.Duration,
+	deadLetter *deadletter.Spool,
 	stop <-chan struct{},
 ) Sender {
-	s := newSender(logger, service, targetManager, retryDuration, stop)
+	s := newSender(logger, service, targetManager, retryDuration, deadLetter, stop)
 	if workerPool == nil {
 		return s
 	}
@@ -85,6 +85,7 @@ func setupPusher(t *testing.T, workerPool WorkerPool, stop chan struct{}, wg *sy
 		workerPool,
 		time.Second,
 		time.Minute,
+		nil,
 		stop,
 		wg,
 	)
@@ -9,7 +9,9 @@ import (
 	"time"
 
 	"github.com/influxdata/telegraf"
+	"github.com/prometheus/client_golang/prometheus"
 
+	"github.com/aws/amazon-cloudwatch-agent/internal/selftelemetry"
 	"github.com/aws/amazon-cloudwatch-agent/logs"
 	"github.com/aws/amazon-cloudwatch-agent/profiler"
 )
@@ -40,6 +42,8 @@ type queue struct {
 	initNonBlockingChOnce sync.Once
 	startNonBlockCh       chan struct{}
 	wg                    *sync.WaitGroup
+
+	depth prometheus.Gauge
 }
 
 func newQueue(
@@ -64,6 +68,7 @@ func newQueue(
 		stop:            stop,
 		startNonBlockCh: make(chan struct{}),
 		wg:              wg,
+		depth:           selftelemetry.LogsQueueDepth.WithLabelValues(target.Group, target.Stream),
 	}
 	q.flushTimeout.Store(flushTimeout)
 	q.wg.Add(1)
@@ -75,8 +80,10 @@ func newQueue(
 func (q *queue) AddEvent(e logs.LogEvent) {
 	if !hasValidTime(e) {
 		q.logger.Errorf("The log entry in (%v/%v) with timestamp (%v) comparing to the current time (%v) is out of accepted time range. Discard the log entry.", q.target.Group, q.target.Stream, e.Time(), time.Now())
+		q.addDropped(selftelemetry.DropReasonInvalidTime, 1)
 		return
 	}
+	q.depth.Inc()
 	q.eventsCh <- e
 }
 
@@ -85,6 +92,7 @@ func (q *queue) AddEvent(e logs.LogEvent) {
 func (q *queue) AddEventNonBlocking(e logs.LogEvent) {
 	if !hasValidTime(e) {
 		q.logger.Errorf("The log entry in (%v/%v) with timestamp (%v) comparing to the current time (%v) is out of accepted time range. Discard the log entry.", q.target.Group, q.target.Stream, e.Time(), time.Now())
+		q.addDropped(selftelemetry.DropReasonInvalidTime, 1)
 		return
 	}
 
@@ -94,13 +102,16 @@ func (q *queue) AddEventNonBlocking(e logs.LogEvent) {
 	})
 
 	// Drain the channel until new event can be added
+	q.depth.Inc()
 	for {
 		select {
 		case q.nonBlockingEventsCh <- e:
 			return
 		default:
 			<-q.nonBlockingEventsCh
+			q.depth.Dec()
 			q.addStats("emfMetricDrop", 1)
+			q.addDropped(selftelemetry.DropReasonQueueFull, 1)
 		}
 	}
 }
@@ -182,6 +193,11 @@ func (q *queue) addStats(statsName string, value float64) {
 	profiler.Profiler.AddStats(statsKey, value)
 }
 
+// addDropped counts events that will not be published.
+func (q *queue) addDropped(reason string, count int) {
+	selftelemetry.LogsEventsDropped.WithLabelValues(q.target.Group, q.target.Stream, reason).Add(float64(count))
+}
+
 // manageFlushTimer manages the flush timer for the queue. Needed since the timer Stop/Reset functions cannot
 // be called concurrently.
 func (q *queue) manageFlushTimer() {
@@ -66,6 +66,22 @@ func (s *stubLogsService) DescribeLogGroups(in *cloudwatchlogs.DescribeLogGroups
 	return nil, nil
 }
 
+func (s *stubLogsService) AssociateKmsKey(*cloudwatchlogs.AssociateKmsKeyInput) (*cloudwatchlogs.AssociateKmsKeyOutput, error) {
+	return nil, nil
+}
+
+func (s *stubLogsService) TagResource(*cloudwatchlogs.TagResourceInput) (*cloudwatchlogs.TagResourceOutput, error) {
+	return nil, nil
+}
+
+func (s *stubLogsService) PutSubscriptionFilter(*cloudwatchlogs.PutSubscriptionFilterInput) (*cloudwatchlogs.PutSubscriptionFilterOutput, error) {
+	return nil, nil
+}
+
+func (s *stubLogsService) PutDataProtectionPolicy(*cloudwatchlogs.PutDataProtectionPolicyInput) (*cloudwatchlogs.PutDataProtectionPolicyOutput, error) {
+	return nil, nil
+}
+
 func TestAddSingleEvent_WithAccountId(t *testing.T) {
 	t.Parallel()
 	var wg sync.WaitGroup
@@ -674,11 +690,11 @@ func testPreparationWithLogger(
 ) (chan struct{}, *queue) {
 	t.Helper()
 	stop := make(chan struct{})
-	tm := NewTargetManager(logger, service)
-	s := newSender(logger, service, tm, retryDuration, stop)
+	tm := NewTargetManager(logger, service, nil)
+	s := newSender(logger, service, tm, retryDuration, nil, stop)
 	q := newQueue(
 		logger,
-		Target{"G", "S", util.StandardLogGroupClass, retention},
+		Target{Group: "G", Stream: "S", Class: util.StandardLogGroupClass, Retention: retention},
 		flushTimeout,
 		entityProvider,
 		s,
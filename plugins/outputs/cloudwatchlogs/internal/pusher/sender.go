@@ -8,9 +8,12 @@ import (
 	"sync/atomic"
 	"time"
 
+	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/influxdata/telegraf"
 
+	"github.com/aws/amazon-cloudwatch-agent/internal/deadletter"
+	"github.com/aws/amazon-cloudwatch-agent/internal/selftelemetry"
 	"github.com/aws/amazon-cloudwatch-agent/sdk/service/cloudwatchlogs"
 )
 
@@ -20,6 +23,10 @@ type cloudWatchLogsService interface {
 	CreateLogGroup(input *cloudwatchlogs.CreateLogGroupInput) (*cloudwatchlogs.CreateLogGroupOutput, error)
 	PutRetentionPolicy(input *cloudwatchlogs.PutRetentionPolicyInput) (*cloudwatchlogs.PutRetentionPolicyOutput, error)
 	DescribeLogGroups(input *cloudwatchlogs.DescribeLogGroupsInput) (*cloudwatchlogs.DescribeLogGroupsOutput, error)
+	AssociateKmsKey(input *cloudwatchlogs.AssociateKmsKeyInput) (*cloudwatchlogs.AssociateKmsKeyOutput, error)
+	TagResource(input *cloudwatchlogs.TagResourceInput) (*cloudwatchlogs.TagResourceOutput, error)
+	PutSubscriptionFilter(input *cloudwatchlogs.PutSubscriptionFilterInput) (*cloudwatchlogs.PutSubscriptionFilterOutput, error)
+	PutDataProtectionPolicy(input *cloudwatchlogs.PutDataProtectionPolicyInput) (*cloudwatchlogs.PutDataProtectionPolicyOutput, error)
 }
 
 type Sender interface {
@@ -32,6 +39,7 @@ type sender struct {
 	service       cloudWatchLogsService
 	retryDuration atomic.Value
 	targetManager TargetManager
+	deadLetter    *deadletter.Spool
 	logger        telegraf.Logger
 	stop          <-chan struct{}
 }
@@ -41,12 +49,14 @@ func newSender(
 	service cloudWatchLogsService,
 	targetManager TargetManager,
 	retryDuration time.Duration,
+	deadLetter *deadletter.Spool,
 	stop <-chan struct{},
 ) Sender {
 	s := &sender{
 		logger:        logger,
 		service:       service,
 		targetManager: targetManager,
+		deadLetter:    deadLetter,
 		stop:          stop,
 	}
 	s.retryDuration.Store(retryDuration)
@@ -59,6 +69,7 @@ func (s *sender) Send(batch *logEventBatch) {
 	if len(batch.events) == 0 {
 		return
 	}
+	defer selftelemetry.LogsQueueDepth.WithLabelValues(batch.Group, batch.Stream).Sub(float64(len(batch.events)))
 	input := batch.build()
 	startTime := time.Now()
 
@@ -68,18 +79,11 @@ func (s *sender) Send(batch *logEventBatch) {
 		output, err := s.service.PutLogEvents(input)
 		if err == nil {
 			if output.RejectedLogEventsInfo != nil {
-				info := output.RejectedLogEventsInfo
-				if info.TooOldLogEventEndIndex != nil {
-					s.logger.Warnf("%d log events for log '%s/%s' are too old", *info.TooOldLogEventEndIndex, batch.Group, batch.Stream)
-				}
-				if info.TooNewLogEventStartIndex != nil {
-					s.logger.Warnf("%d log events for log '%s/%s' are too new", *info.TooNewLogEventStartIndex, batch.Group, batch.Stream)
-				}
-				if info.ExpiredLogEventEndIndex != nil {
-					s.logger.Warnf("%d log events for log '%s/%s' are expired", *info.ExpiredLogEventEndIndex, batch.Group, batch.Stream)
-				}
+				s.handleRejected(batch, input.LogEvents, output.RejectedLogEventsInfo)
 			}
 			batch.done()
+			selftelemetry.LogsEventsSent.WithLabelValues(batch.Group, batch.Stream).Add(float64(len(batch.events)))
+			selftelemetry.LogsBytesSent.WithLabelValues(batch.Group, batch.Stream).Add(float64(batch.bufferedSize))
 			s.logger.Debugf("Pusher published %v log events to group: %v stream: %v with size %v KB in %v.", len(batch.events), batch.Group, batch.Stream, batch.bufferedSize/1024, time.Since(startTime))
 			return
 		}
@@ -87,6 +91,7 @@ func (s *sender) Send(batch *logEventBatch) {
 		var awsErr awserr.Error
 		if !errors.As(err, &awsErr) {
 			s.logger.Errorf("Non aws error received when sending logs to %v/%v: %v. CloudWatch agent will not retry and logs will be missing!", batch.Group, batch.Stream, err)
+			s.drop(batch, input.LogEvents, selftelemetry.DropReasonUnretryable, err)
 			return
 		}
 
@@ -99,6 +104,7 @@ func (s *sender) Send(batch *logEventBatch) {
 		case *cloudwatchlogs.InvalidParameterException,
 			*cloudwatchlogs.DataAlreadyAcceptedException:
 			s.logger.Errorf("%v, will not retry the request", e)
+			s.drop(batch, input.LogEvents, selftelemetry.DropReasonUnretryable, err)
 			return
 		default:
 			s.logger.Errorf("Aws error received when sending logs to %v/%v: %v", batch.Group, batch.Stream, awsErr)
@@ -116,14 +122,17 @@ func (s *sender) Send(batch *logEventBatch) {
 
 		if time.Since(startTime)+wait > s.RetryDuration() {
 			s.logger.Errorf("All %v retries to %v/%v failed for PutLogEvents, request dropped.", retryCountShort+retryCountLong-1, batch.Group, batch.Stream)
+			s.drop(batch, input.LogEvents, selftelemetry.DropReasonRetriesExhausted, err)
 			return
 		}
 
 		s.logger.Warnf("Retried %v time, going to sleep %v before retrying.", retryCountShort+retryCountLong-1, wait)
+		selftelemetry.LogsRetries.WithLabelValues(batch.Group, batch.Stream).Inc()
 
 		select {
 		case <-s.stop:
 			s.logger.Errorf("Stop requested after %v retries to %v/%v failed for PutLogEvents, request dropped.", retryCountShort+retryCountLong-1, batch.Group, batch.Stream)
+			s.drop(batch, input.LogEvents, selftelemetry.DropReasonStopped, err)
 			return
 		case <-time.After(wait):
 		}
@@ -139,3 +148,83 @@ func (s *sender) SetRetryDuration(retryDuration time.Duration) {
 func (s *sender) RetryDuration() time.Duration {
 	return s.retryDuration.Load().(time.Duration)
 }
+
+// handleRejected drops the events of a published batch that CloudWatch Logs
+// rejected.
+func (s *sender) handleRejected(batch *logEventBatch, events []*cloudwatchlogs.InputLogEvent, info *cloudwatchlogs.RejectedLogEventsInfo) {
+	// the expired events are also too old, so they are only spooled once
+	var expiredEnd int
+	if info.ExpiredLogEventEndIndex != nil {
+		expiredEnd = clampIndex(*info.ExpiredLogEventEndIndex, len(events))
+	}
+	if info.TooOldLogEventEndIndex != nil {
+		s.logger.Warnf("%d log events for log '%s/%s' are too old", *info.TooOldLogEventEndIndex, batch.Group, batch.Stream)
+		if tooOldEnd := clampIndex(*info.TooOldLogEventEndIndex, len(events)); tooOldEnd > expiredEnd {
+			s.dropRejected(batch, events[expiredEnd:tooOldEnd], selftelemetry.DropReasonTooOld)
+		}
+	}
+	if info.TooNewLogEventStartIndex != nil {
+		s.logger.Warnf("%d log events for log '%s/%s' are too new", *info.TooNewLogEventStartIndex, batch.Group, batch.Stream)
+		s.dropRejected(batch, events[clampIndex(*info.TooNewLogEventStartIndex, len(events)):], selftelemetry.DropReasonTooNew)
+	}
+	if info.ExpiredLogEventEndIndex != nil {
+		s.logger.Warnf("%d log events for log '%s/%s' are expired", *info.ExpiredLogEventEndIndex, batch.Group, batch.Stream)
+		s.dropRejected(batch, events[:expiredEnd], selftelemetry.DropReasonExpired)
+	}
+}
+
+func (s *sender) dropRejected(batch *logEventBatch, events []*cloudwatchlogs.InputLogEvent, reason string) {
+	if len(events) == 0 {
+		return
+	}
+	addDropped(batch, reason, len(events))
+	s.spool(batch, events, reason, nil)
+}
+
+// drop counts the events of a batch that will not be published and writes
+// them to the dead letter spool. Once they are in the spool, the batch is done.
+func (s *sender) drop(batch *logEventBatch, events []*cloudwatchlogs.InputLogEvent, reason string, err error) {
+	addDropped(batch, reason, len(events))
+	if s.spool(batch, events, reason, err) {
+		batch.done()
+	}
+}
+
+// spool writes the events to the dead letter spool if there is one. Returns
+// true if the events were written.
+func (s *sender) spool(batch *logEventBatch, events []*cloudwatchlogs.InputLogEvent, reason string, err error) bool {
+	if s.deadLetter == nil || len(events) == 0 {
+		return false
+	}
+	record := deadletter.Record{
+		Group:  batch.Group,
+		Stream: batch.Stream,
+		Class:  batch.Class,
+		Reason: reason,
+		Time:   time.Now(),
+		Events: make([]deadletter.Event, len(events)),
+	}
+	if err != nil {
+		record.Error = err.Error()
+	}
+	for i, e := range events {
+		record.Events[i] = deadletter.Event{Timestamp: aws.Int64Value(e.Timestamp), Message: aws.StringValue(e.Message)}
+	}
+	if writeErr := s.deadLetter.Write(record); writeErr != nil {
+		s.logger.Errorf("Unable to write %d log events for %v/%v to the dead letter spool %v: %v", len(events), batch.Group, batch.Stream, s.deadLetter.Dir(), writeErr)
+		return false
+	}
+	s.logger.Warnf("Wrote %d log events for %v/%v to the dead letter spool %v", len(events), batch.Group, batch.Stream, s.deadLetter.Dir())
+	selftelemetry.LogsEventsDeadLettered.WithLabelValues(batch.Group, batch.Stream, reason).Add(float64(len(events)))
+	return true
+}
+
+// addDropped counts the events of a batch that will not be published.
+func addDropped(batch *logEventBatch, reason string, count int) {
+	selftelemetry.LogsEventsDropped.WithLabelValues(batch.Group, batch.Stream, reason).Add(float64(count))
+}
+
+// clampIndex limits the index returned by the service to the events of the batch.
+func clampIndex(index int64, n int) int {
+	return int(max(0, min(index, int64(n))))
+}
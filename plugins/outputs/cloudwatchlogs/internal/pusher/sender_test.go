@@ -10,8 +10,13 @@ import (
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
+	promtestutil "github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 
+	"github.com/aws/amazon-cloudwatch-agent/internal/deadletter"
+	"github.com/aws/amazon-cloudwatch-agent/internal/selftelemetry"
 	"github.com/aws/amazon-cloudwatch-agent/sdk/service/cloudwatchlogs"
 	"github.com/aws/amazon-cloudwatch-agent/tool/testutil"
 )
@@ -45,6 +50,26 @@ func (m *mockLogsService) DescribeLogGroups(input *cloudwatchlogs.DescribeLogGro
 	return args.Get(0).(*cloudwatchlogs.DescribeLogGroupsOutput), args.Error(1)
 }
 
+func (m *mockLogsService) AssociateKmsKey(input *cloudwatchlogs.AssociateKmsKeyInput) (*cloudwatchlogs.AssociateKmsKeyOutput, error) {
+	args := m.Called(input)
+	return args.Get(0).(*cloudwatchlogs.AssociateKmsKeyOutput), args.Error(1)
+}
+
+func (m *mockLogsService) TagResource(input *cloudwatchlogs.TagResourceInput) (*cloudwatchlogs.TagResourceOutput, error) {
+	args := m.Called(input)
+	return args.Get(0).(*cloudwatchlogs.TagResourceOutput), args.Error(1)
+}
+
+func (m *mockLogsService) PutSubscriptionFilter(input *cloudwatchlogs.PutSubscriptionFilterInput) (*cloudwatchlogs.PutSubscriptionFilterOutput, error) {
+	args := m.Called(input)
+	return args.Get(0).(*cloudwatchlogs.PutSubscriptionFilterOutput), args.Error(1)
+}
+
+func (m *mockLogsService) PutDataProtectionPolicy(input *cloudwatchlogs.PutDataProtectionPolicyInput) (*cloudwatchlogs.PutDataProtectionPolicyOutput, error) {
+	args := m.Called(input)
+	return args.Get(0).(*cloudwatchlogs.PutDataProtectionPolicyOutput), args.Error(1)
+}
+
 type mockTargetManager struct {
 	mock.Mock
 }
@@ -75,7 +100,7 @@ func TestSender(t *testing.T) {
 		mockManager := new(mockTargetManager)
 		mockService.On("PutLogEvents", mock.Anything).Return(&cloudwatchlogs.PutLogEventsOutput{RejectedLogEventsInfo: rejectedInfo}, nil).Once()
 
-		s := newSender(logger, mockService, mockManager, time.Second, make(chan struct{}))
+		s := newSender(logger, mockService, mockManager, time.Second, nil, make(chan struct{}))
 		s.Send(batch)
 
 		mockService.AssertExpectations(t)
@@ -93,7 +118,7 @@ func TestSender(t *testing.T) {
 		mockManager.On("InitTarget", mock.Anything).Return(nil).Once()
 		mockService.On("PutLogEvents", mock.Anything).Return(&cloudwatchlogs.PutLogEventsOutput{}, nil).Once()
 
-		s := newSender(logger, mockService, mockManager, time.Second, make(chan struct{}))
+		s := newSender(logger, mockService, mockManager, time.Second, nil, make(chan struct{}))
 		s.Send(batch)
 
 		mockService.AssertExpectations(t)
@@ -109,7 +134,7 @@ func TestSender(t *testing.T) {
 		mockService.On("PutLogEvents", mock.Anything).
 			Return(&cloudwatchlogs.PutLogEventsOutput{}, &cloudwatchlogs.InvalidParameterException{}).Once()
 
-		s := newSender(logger, mockService, mockManager, time.Second, make(chan struct{}))
+		s := newSender(logger, mockService, mockManager, time.Second, nil, make(chan struct{}))
 		s.Send(batch)
 
 		mockService.AssertExpectations(t)
@@ -124,7 +149,7 @@ func TestSender(t *testing.T) {
 		mockService.On("PutLogEvents", mock.Anything).
 			Return(&cloudwatchlogs.PutLogEventsOutput{}, &cloudwatchlogs.DataAlreadyAcceptedException{}).Once()
 
-		s := newSender(logger, mockService, mockManager, time.Second, make(chan struct{}))
+		s := newSender(logger, mockService, mockManager, time.Second, nil, make(chan struct{}))
 		s.Send(batch)
 
 		mockService.AssertExpectations(t)
@@ -139,7 +164,7 @@ func TestSender(t *testing.T) {
 		mockService.On("PutLogEvents", mock.Anything).
 			Return(&cloudwatchlogs.PutLogEventsOutput{}, errors.New("test")).Once()
 
-		s := newSender(logger, mockService, mockManager, time.Second, make(chan struct{}))
+		s := newSender(logger, mockService, mockManager, time.Second, nil, make(chan struct{}))
 		s.Send(batch)
 
 		mockService.AssertExpectations(t)
@@ -156,7 +181,7 @@ func TestSender(t *testing.T) {
 		mockService.On("PutLogEvents", mock.Anything).
 			Return(&cloudwatchlogs.PutLogEventsOutput{}, nil).Once()
 
-		s := newSender(logger, mockService, mockManager, time.Second, make(chan struct{}))
+		s := newSender(logger, mockService, mockManager, time.Second, nil, make(chan struct{}))
 		s.Send(batch)
 
 		mockService.AssertExpectations(t)
@@ -171,7 +196,7 @@ func TestSender(t *testing.T) {
 		mockService.On("PutLogEvents", mock.Anything).
 			Return(&cloudwatchlogs.PutLogEventsOutput{}, awserr.New("SomeAWSError", "Some AWS error", nil)).Once()
 
-		s := newSender(logger, mockService, mockManager, 100*time.Millisecond, make(chan struct{}))
+		s := newSender(logger, mockService, mockManager, 100*time.Millisecond, nil, make(chan struct{}))
 		s.Send(batch)
 
 		mockService.AssertExpectations(t)
@@ -187,7 +212,7 @@ func TestSender(t *testing.T) {
 			Return(&cloudwatchlogs.PutLogEventsOutput{}, awserr.New("SomeAWSError", "Some AWS error", nil)).Once()
 
 		stopCh := make(chan struct{})
-		s := newSender(logger, mockService, mockManager, time.Second, stopCh)
+		s := newSender(logger, mockService, mockManager, time.Second, nil, stopCh)
 
 		go func() {
 			time.Sleep(50 * time.Millisecond)
@@ -199,3 +224,123 @@ func TestSender(t *testing.T) {
 		mockService.AssertExpectations(t)
 	})
 }
+
+func TestSenderSelfTelemetry(t *testing.T) {
+	logger := testutil.NewNopLogger()
+
+	t.Run("Sent", func(t *testing.T) {
+		batch := newLogEventBatch(Target{Group: "TelemetrySent", Stream: "S"}, nil)
+		batch.append(newLogEvent(time.Now(), "Test message", nil))
+		batch.append(newLogEvent(time.Now(), "Test message", nil))
+		selftelemetry.LogsQueueDepth.WithLabelValues("TelemetrySent", "S").Add(2)
+
+		mockService := new(mockLogsService)
+		mockService.On("PutLogEvents", mock.Anything).
+			Return(&cloudwatchlogs.PutLogEventsOutput{}, awserr.New("SomeAWSError", "Some AWS error", nil)).Once()
+		mockService.On("PutLogEvents", mock.Anything).Return(&cloudwatchlogs.PutLogEventsOutput{}, nil).Once()
+
+		s := newSender(logger, mockService, new(mockTargetManager), time.Second, nil, make(chan struct{}))
+		s.Send(batch)
+
+		assert.Equal(t, float64(0), promtestutil.ToFloat64(selftelemetry.LogsQueueDepth.WithLabelValues("TelemetrySent", "S")))
+		assert.Equal(t, float64(1), promtestutil.ToFloat64(selftelemetry.LogsRetries.WithLabelValues("TelemetrySent", "S")))
+		assert.Equal(t, float64(2), promtestutil.ToFloat64(selftelemetry.LogsEventsSent.WithLabelValues("TelemetrySent", "S")))
+		assert.Equal(t, float64(batch.bufferedSize), promtestutil.ToFloat64(selftelemetry.LogsBytesSent.WithLabelValues("TelemetrySent", "S")))
+	})
+
+	t.Run("Dropped", func(t *testing.T) {
+		batch := newLogEventBatch(Target{Group: "TelemetryDropped", Stream: "S"}, nil)
+		batch.append(newLogEvent(time.Now(), "Test message", nil))
+		selftelemetry.LogsQueueDepth.WithLabelValues("TelemetryDropped", "S").Inc()
+
+		mockService := new(mockLogsService)
+		mockService.On("PutLogEvents", mock.Anything).
+			Return(&cloudwatchlogs.PutLogEventsOutput{}, &cloudwatchlogs.InvalidParameterException{}).Once()
+
+		s := newSender(logger, mockService, new(mockTargetManager), time.Second, nil, make(chan struct{}))
+		s.Send(batch)
+
+		assert.Equal(t, float64(0), promtestutil.ToFloat64(selftelemetry.LogsQueueDepth.WithLabelValues("TelemetryDropped", "S")))
+		assert.Equal(t, float64(1), promtestutil.ToFloat64(selftelemetry.LogsEventsDropped.WithLabelValues("TelemetryDropped", "S", selftelemetry.DropReasonUnretryable)))
+		assert.Equal(t, float64(0), promtestutil.ToFloat64(selftelemetry.LogsEventsSent.WithLabelValues("TelemetryDropped", "S")))
+	})
+}
+
+func TestSenderDeadLetter(t *testing.T) {
+	logger := testutil.NewNopLogger()
+
+	t.Run("Dropped", func(t *testing.T) {
+		spool, err := deadletter.NewSpool(t.TempDir(), 0)
+		require.NoError(t, err)
+		batch := newLogEventBatch(Target{Group: "DeadLetterDropped", Stream: "S", Class: "STANDARD"}, nil)
+		now := time.Now()
+		batch.append(newLogEvent(now, "first", nil))
+		batch.append(newLogEvent(now, "second", nil))
+		var done bool
+		batch.addDoneCallback(func() { done = true })
+
+		mockService := new(mockLogsService)
+		mockService.On("PutLogEvents", mock.Anything).
+			Return(&cloudwatchlogs.PutLogEventsOutput{}, &cloudwatchlogs.InvalidParameterException{}).Once()
+
+		s := newSender(logger, mockService, new(mockTargetManager), time.Second, spool, make(chan struct{}))
+		s.Send(batch)
+
+		assert.True(t, done, "batch should be done once the events are in the spool")
+		paths, err := deadletter.List(spool.Dir())
+		require.NoError(t, err)
+		require.Len(t, paths, 1)
+		record, err := deadletter.Read(paths[0])
+		require.NoError(t, err)
+		assert.Equal(t, "DeadLetterDropped", record.Group)
+		assert.Equal(t, "S", record.Stream)
+		assert.Equal(t, "STANDARD", record.Class)
+		assert.Equal(t, selftelemetry.DropReasonUnretryable, record.Reason)
+		assert.NotEmpty(t, record.Error)
+		assert.Equal(t, []deadletter.Event{
+			{Timestamp: now.UnixMilli(), Message: "first"},
+			{Timestamp: now.UnixMilli(), Message: "second"},
+		}, record.Events)
+		assert.Equal(t, float64(2), promtestutil.ToFloat64(selftelemetry.LogsEventsDeadLettered.WithLabelValues("DeadLetterDropped", "S", selftelemetry.DropReasonUnretryable)))
+	})
+
+	t.Run("Rejected", func(t *testing.T) {
+		spool, err := deadletter.NewSpool(t.TempDir(), 0)
+		require.NoError(t, err)
+		batch := newLogEventBatch(Target{Group: "DeadLetterRejected", Stream: "S"}, nil)
+		now := time.Now()
+		for i, message := range []string{"expired", "too old", "ok", "too new"} {
+			batch.append(newLogEvent(now.Add(time.Duration(i)*time.Millisecond), message, nil))
+		}
+
+		mockService := new(mockLogsService)
+		mockService.On("PutLogEvents", mock.Anything).Return(&cloudwatchlogs.PutLogEventsOutput{
+			RejectedLogEventsInfo: &cloudwatchlogs.RejectedLogEventsInfo{
+				ExpiredLogEventEndIndex:  aws.Int64(1),
+				TooOldLogEventEndIndex:   aws.Int64(2),
+				TooNewLogEventStartIndex: aws.Int64(3),
+			},
+		}, nil).Once()
+
+		s := newSender(logger, mockService, new(mockTargetManager), time.Second, spool, make(chan struct{}))
+		s.Send(batch)
+
+		paths, err := deadletter.List(spool.Dir())
+		require.NoError(t, err)
+		require.Len(t, paths, 3)
+		got := map[string][]string{}
+		for _, path := range paths {
+			record, err := deadletter.Read(path)
+			require.NoError(t, err)
+			for _, e := range record.Events {
+				got[record.Reason] = append(got[record.Reason], e.Message)
+			}
+		}
+		assert.Equal(t, map[string][]string{
+			selftelemetry.DropReasonExpired: {"expired"},
+			selftelemetry.DropReasonTooOld:  {"too old"},
+			selftelemetry.DropReasonTooNew:  {"too new"},
+		}, got)
+		assert.Equal(t, float64(1), promtestutil.ToFloat64(selftelemetry.LogsEventsDropped.WithLabelValues("DeadLetterRejected", "S", selftelemetry.DropReasonTooNew)))
+	})
+}
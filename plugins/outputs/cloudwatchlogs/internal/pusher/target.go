@@ -12,6 +12,7 @@ import (
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/influxdata/telegraf"
 
+	"github.com/aws/amazon-cloudwatch-agent/logs"
 	"github.com/aws/amazon-cloudwatch-agent/sdk/service/cloudwatchlogs"
 )
 
@@ -24,6 +25,8 @@ const (
 	baseRetryDelay      = 1 * time.Second
 	maxRetryDelayTarget = 10 * time.Second
 	numBackoffRetries   = 5
+
+	defaultSubscriptionFilterName = "amazon-cloudwatch-agent"
 )
 
 type Target struct {
@@ -31,6 +34,14 @@ type Target struct {
 	Retention            int
 }
 
+// groupSettingsUpdate is a log group to apply the settings to. The log group
+// is nil if the agent just created it with its KMS key and tags.
+type groupSettingsUpdate struct {
+	target   Target
+	settings *logs.LogGroupSettings
+	logGroup *cloudwatchlogs.LogGroup
+}
+
 type TargetManager interface {
 	InitTarget(target Target) error
 	PutRetentionPolicy(target Target)
@@ -39,26 +50,34 @@ type TargetManager interface {
 type targetManager struct {
 	logger  telegraf.Logger
 	service cloudWatchLogsService
+	// groupSettings returns the settings of a log group, nil if there are none.
+	groupSettings func(group string) *logs.LogGroupSettings
 	// cache of initialized targets
 	cache    map[Target]time.Time
 	cacheTTL time.Duration
 	mu       sync.Mutex
 	dlg      chan Target
 	prp      chan Target
+	pgs      chan groupSettingsUpdate
 }
 
-func NewTargetManager(logger telegraf.Logger, service cloudWatchLogsService) TargetManager {
+// NewTargetManager creates a TargetManager that applies the settings returned
+// by groupSettings to the log groups, which may be nil if there are none.
+func NewTargetManager(logger telegraf.Logger, service cloudWatchLogsService, groupSettings func(group string) *logs.LogGroupSettings) TargetManager {
 	tm := &targetManager{
-		logger:   logger,
-		service:  service,
-		cache:    make(map[Target]time.Time),
-		cacheTTL: cacheTTL,
-		dlg:      make(chan Target, retentionChannelSize),
-		prp:      make(chan Target, retentionChannelSize),
+		logger:        logger,
+		service:       service,
+		groupSettings: groupSettings,
+		cache:         make(map[Target]time.Time),
+		cacheTTL:      cacheTTL,
+		dlg:           make(chan Target, retentionChannelSize),
+		prp:           make(chan Target, retentionChannelSize),
+		pgs:           make(chan groupSettingsUpdate, retentionChannelSize),
 	}
 
 	go tm.processDescribeLogGroup()
 	go tm.processPutRetentionPolicy()
+	go tm.processPutGroupSettings()
 	return tm
 }
 
@@ -74,14 +93,18 @@ func (m *targetManager) InitTarget(target Target) error {
 		if err != nil {
 			return err
 		}
-		if target.Retention > 0 {
-			if newGroup {
+		if newGroup {
+			if target.Retention > 0 {
 				m.logger.Debugf("sending new log group %v to prp channel", target.Group)
 				m.prp <- target
-			} else {
-				m.logger.Debugf("sending existing log group %v to dlg channel", target.Group)
-				m.dlg <- target
 			}
+			if settings := m.settings(target.Group); settings != nil {
+				m.logger.Debugf("sending new log group %v to pgs channel", target.Group)
+				m.pgs <- groupSettingsUpdate{target: target, settings: settings}
+			}
+		} else if target.Retention > 0 || m.settings(target.Group) != nil {
+			m.logger.Debugf("sending existing log group %v to dlg channel", target.Group)
+			m.dlg <- target
 		}
 		m.cache[target] = time.Now()
 	}
@@ -90,7 +113,7 @@ func (m *targetManager) InitTarget(target Target) error {
 
 func (m *targetManager) PutRetentionPolicy(target Target) {
 	// new pusher will call this so start with dlg
-	if target.Retention > 0 {
+	if target.Retention > 0 || m.settings(target.Group) != nil {
 		m.logger.Debugf("sending log group %v to dlg channel by pusher", target.Group)
 		m.dlg <- target
 	}
@@ -142,15 +165,20 @@ func (m *targetManager) isResourceCreated(err error, resourceName string) bool {
 }
 
 func (m *targetManager) createLogGroup(t Target) error {
-	var input *cloudwatchlogs.CreateLogGroupInput
+	input := &cloudwatchlogs.CreateLogGroupInput{
+		LogGroupName: &t.Group,
+	}
 	if t.Class != "" {
-		input = &cloudwatchlogs.CreateLogGroupInput{
-			LogGroupName:  &t.Group,
-			LogGroupClass: &t.Class,
+		input.LogGroupClass = &t.Class
+	}
+	// the group is encrypted and tagged when it is created, the other settings
+	// need the group to exist
+	if settings := m.settings(t.Group); settings != nil {
+		if settings.KMSKeyID != "" {
+			input.KmsKeyId = aws.String(settings.KMSKeyID)
 		}
-	} else {
-		input = &cloudwatchlogs.CreateLogGroupInput{
-			LogGroupName: &t.Group,
+		if len(settings.Tags) > 0 {
+			input.Tags = aws.StringMap(settings.Tags)
 		}
 	}
 	_, err := m.service.CreateLogGroup(input)
@@ -222,6 +250,10 @@ func (m *targetManager) updateTargetBatch(targets map[string]Target) {
 				m.logger.Debugf("queueing log group %v to update retention policy", target.Group)
 				m.prp <- target
 			}
+			if settings := m.settings(target.Group); settings != nil {
+				m.logger.Debugf("queueing log group %v to update settings", target.Group)
+				m.pgs <- groupSettingsUpdate{target: target, settings: settings, logGroup: logGroups}
+			}
 		}
 		break
 	}
@@ -229,22 +261,24 @@ func (m *targetManager) updateTargetBatch(targets map[string]Target) {
 
 func (m *targetManager) processPutRetentionPolicy() {
 	for target := range m.prp {
-		var updated bool
-		for attempt := 0; attempt < numBackoffRetries; attempt++ {
-			err := m.updateRetentionPolicy(target)
-			if err == nil {
-				updated = true
-				break
-			}
+		m.retryWithBackoff(target, "update retention policy", func() error {
+			return m.updateRetentionPolicy(target)
+		})
+	}
+}
 
-			m.logger.Debugf("retrying to update retention policy for target (%v) %v: %v", attempt, target, err)
-			time.Sleep(m.calculateBackoff(attempt))
+// retryWithBackoff calls fn until it succeeds or it has been attempted numBackoffRetries times.
+func (m *targetManager) retryWithBackoff(target Target, action string, fn func() error) {
+	for attempt := 0; attempt < numBackoffRetries; attempt++ {
+		err := fn()
+		if err == nil {
+			return
 		}
 
-		if !updated {
-			m.logger.Errorf("failed to update retention policy for target %v after %d attempts", target, numBackoffRetries)
-		}
+		m.logger.Debugf("retrying to %v for target (%v) %v: %v", action, attempt, target, err)
+		time.Sleep(m.calculateBackoff(attempt))
 	}
+	m.logger.Errorf("failed to %v for target %v after %d attempts", action, target, numBackoffRetries)
 }
 
 func (m *targetManager) updateRetentionPolicy(target Target) error {
@@ -261,6 +295,82 @@ func (m *targetManager) updateRetentionPolicy(target Target) error {
 	return nil
 }
 
+func (m *targetManager) processPutGroupSettings() {
+	for update := range m.pgs {
+		m.updateGroupSettings(update)
+	}
+}
+
+// updateGroupSettings applies the settings that the log group does not have
+// yet. The subscription filter is put every time, since putting the same
+// filter again does not change it.
+func (m *targetManager) updateGroupSettings(update groupSettingsUpdate) {
+	target, settings, logGroup := update.target, update.settings, update.logGroup
+	if logGroup != nil {
+		if settings.KMSKeyID != "" {
+			if logGroup.KmsKeyId == nil {
+				m.retryWithBackoff(target, "associate KMS key", func() error {
+					_, err := m.service.AssociateKmsKey(&cloudwatchlogs.AssociateKmsKeyInput{
+						LogGroupName: aws.String(target.Group),
+						KmsKeyId:     aws.String(settings.KMSKeyID),
+					})
+					return err
+				})
+			} else if *logGroup.KmsKeyId != settings.KMSKeyID {
+				m.logger.Warnf("log group %v is encrypted with KMS key %v instead of %v", target.Group, *logGroup.KmsKeyId, settings.KMSKeyID)
+			}
+		}
+		if len(settings.Tags) > 0 && logGroup.LogGroupArn != nil {
+			m.retryWithBackoff(target, "tag log group", func() error {
+				_, err := m.service.TagResource(&cloudwatchlogs.TagResourceInput{
+					ResourceArn: logGroup.LogGroupArn,
+					Tags:        aws.StringMap(settings.Tags),
+				})
+				return err
+			})
+		}
+	}
+	if filter := settings.SubscriptionFilter; filter != nil {
+		m.retryWithBackoff(target, "put subscription filter", func() error {
+			return m.putSubscriptionFilter(target.Group, filter)
+		})
+	}
+	if settings.DataProtectionPolicy != "" && (logGroup == nil || aws.StringValue(logGroup.DataProtectionStatus) != cloudwatchlogs.DataProtectionStatusActivated) {
+		m.retryWithBackoff(target, "put data protection policy", func() error {
+			_, err := m.service.PutDataProtectionPolicy(&cloudwatchlogs.PutDataProtectionPolicyInput{
+				LogGroupIdentifier: aws.String(target.Group),
+				PolicyDocument:     aws.String(settings.DataProtectionPolicy),
+			})
+			return err
+		})
+	}
+}
+
+// settings returns the settings of the log group, nil if there are none.
+func (m *targetManager) settings(group string) *logs.LogGroupSettings {
+	if m.groupSettings == nil {
+		return nil
+	}
+	return m.groupSettings(group)
+}
+
+func (m *targetManager) putSubscriptionFilter(group string, filter *logs.SubscriptionFilter) error {
+	input := &cloudwatchlogs.PutSubscriptionFilterInput{
+		LogGroupName:   aws.String(group),
+		FilterName:     aws.String(filter.FilterName),
+		FilterPattern:  aws.String(filter.FilterPattern),
+		DestinationArn: aws.String(filter.DestinationARN),
+	}
+	if filter.FilterName == "" {
+		input.FilterName = aws.String(defaultSubscriptionFilterName)
+	}
+	if filter.RoleARN != "" {
+		input.RoleArn = aws.String(filter.RoleARN)
+	}
+	_, err := m.service.PutSubscriptionFilter(input)
+	return err
+}
+
 func (m *targetManager) calculateBackoff(retryCount int) time.Duration {
 	delay := baseRetryDelay
 	if retryCount < numBackoffRetries {
@@ -15,6 +15,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 
+	"github.com/aws/amazon-cloudwatch-agent/logs"
 	"github.com/aws/amazon-cloudwatch-agent/sdk/service/cloudwatchlogs"
 	"github.com/aws/amazon-cloudwatch-agent/tool/testutil"
 )
@@ -28,7 +29,7 @@ func TestTargetManager(t *testing.T) {
 		mockService := new(mockLogsService)
 		mockService.On("CreateLogStream", mock.Anything).Return(&cloudwatchlogs.CreateLogStreamOutput{}, nil).Once()
 
-		manager := NewTargetManager(logger, mockService)
+		manager := NewTargetManager(logger, mockService, nil)
 		err := manager.InitTarget(target)
 
 		assert.NoError(t, err)
@@ -45,7 +46,7 @@ func TestTargetManager(t *testing.T) {
 		mockService.On("CreateLogGroup", mock.Anything).Return(&cloudwatchlogs.CreateLogGroupOutput{}, nil).Once()
 		mockService.On("CreateLogStream", mock.Anything).Return(&cloudwatchlogs.CreateLogStreamOutput{}, &cloudwatchlogs.ResourceAlreadyExistsException{}).Once()
 
-		manager := NewTargetManager(logger, mockService)
+		manager := NewTargetManager(logger, mockService, nil)
 		err := manager.InitTarget(target)
 
 		assert.NoError(t, err)
@@ -62,7 +63,7 @@ func TestTargetManager(t *testing.T) {
 		mockService.On("CreateLogGroup", mock.Anything).Return(&cloudwatchlogs.CreateLogGroupOutput{}, &cloudwatchlogs.ResourceAlreadyExistsException{}).Once()
 		mockService.On("CreateLogStream", mock.Anything).Return(&cloudwatchlogs.CreateLogStreamOutput{}, nil).Once()
 
-		manager := NewTargetManager(logger, mockService)
+		manager := NewTargetManager(logger, mockService, nil)
 		err := manager.InitTarget(target)
 
 		assert.NoError(t, err)
@@ -79,7 +80,7 @@ func TestTargetManager(t *testing.T) {
 		mockService.On("CreateLogGroup", mock.Anything).Return(&cloudwatchlogs.CreateLogGroupOutput{}, &cloudwatchlogs.ResourceAlreadyExistsException{}).Once()
 		mockService.On("CreateLogStream", mock.Anything).Return(&cloudwatchlogs.CreateLogStreamOutput{}, &cloudwatchlogs.AccessDeniedException{}).Once()
 
-		manager := NewTargetManager(logger, mockService)
+		manager := NewTargetManager(logger, mockService, nil)
 		err := manager.InitTarget(target)
 
 		assert.Error(t, err)
@@ -96,7 +97,7 @@ func TestTargetManager(t *testing.T) {
 		mockService.On("CreateLogGroup", mock.Anything).Return(&cloudwatchlogs.CreateLogGroupOutput{}, nil).Once()
 		mockService.On("CreateLogStream", mock.Anything).Return(&cloudwatchlogs.CreateLogStreamOutput{}, &cloudwatchlogs.ResourceAlreadyExistsException{}).Once()
 
-		manager := NewTargetManager(logger, mockService)
+		manager := NewTargetManager(logger, mockService, nil)
 		err := manager.InitTarget(target)
 
 		assert.NoError(t, err)
@@ -113,7 +114,7 @@ func TestTargetManager(t *testing.T) {
 		mockService.On("CreateLogGroup", mock.Anything).
 			Return(&cloudwatchlogs.CreateLogGroupOutput{}, awserr.New("SomeAWSError", "Failed to create log group", nil)).Once()
 
-		manager := NewTargetManager(logger, mockService)
+		manager := NewTargetManager(logger, mockService, nil)
 		err := manager.InitTarget(target)
 
 		assert.Error(t, err)
@@ -138,7 +139,7 @@ func TestTargetManager(t *testing.T) {
 		}, nil).Once()
 		mockService.On("PutRetentionPolicy", mock.Anything).Return(&cloudwatchlogs.PutRetentionPolicyOutput{}, nil).Once()
 
-		manager := NewTargetManager(logger, mockService)
+		manager := NewTargetManager(logger, mockService, nil)
 		err := manager.InitTarget(target)
 		assert.NoError(t, err)
 		// Wait for async operations to complete
@@ -163,7 +164,7 @@ func TestTargetManager(t *testing.T) {
 			},
 		}, nil).Once()
 
-		manager := NewTargetManager(logger, mockService)
+		manager := NewTargetManager(logger, mockService, nil)
 		err := manager.InitTarget(target)
 		assert.NoError(t, err)
 		time.Sleep(7 * time.Second)
@@ -181,7 +182,7 @@ func TestTargetManager(t *testing.T) {
 		mockService.On("DescribeLogGroups", mock.Anything).
 			Return(&cloudwatchlogs.DescribeLogGroupsOutput{}, &cloudwatchlogs.ResourceNotFoundException{}).Times(numBackoffRetries)
 
-		manager := NewTargetManager(logger, mockService)
+		manager := NewTargetManager(logger, mockService, nil)
 		err := manager.InitTarget(target)
 		assert.NoError(t, err)
 		time.Sleep(30 * time.Second)
@@ -208,7 +209,7 @@ func TestTargetManager(t *testing.T) {
 			Return(&cloudwatchlogs.PutRetentionPolicyOutput{},
 				awserr.New("SomeAWSError", "Failed to set retention policy", nil)).Times(numBackoffRetries)
 
-		manager := NewTargetManager(logger, mockService)
+		manager := NewTargetManager(logger, mockService, nil)
 		err := manager.InitTarget(target)
 		assert.NoError(t, err)
 		time.Sleep(30 * time.Second)
@@ -221,7 +222,7 @@ func TestTargetManager(t *testing.T) {
 
 		mockService := new(mockLogsService)
 
-		manager := NewTargetManager(logger, mockService)
+		manager := NewTargetManager(logger, mockService, nil)
 		manager.PutRetentionPolicy(target)
 
 		mockService.AssertNotCalled(t, "PutRetentionPolicy", mock.Anything)
@@ -242,7 +243,7 @@ func TestTargetManager(t *testing.T) {
 			return &cloudwatchlogs.CreateLogStreamOutput{}, nil
 		}
 
-		manager := NewTargetManager(logger, service)
+		manager := NewTargetManager(logger, service, nil)
 		var wg sync.WaitGroup
 		for i := 0; i < 50; i++ {
 			wg.Add(1)
@@ -268,7 +269,7 @@ func TestTargetManager(t *testing.T) {
 			return &cloudwatchlogs.CreateLogStreamOutput{}, nil
 		}
 
-		manager := NewTargetManager(logger, service)
+		manager := NewTargetManager(logger, service, nil)
 		manager.(*targetManager).cacheTTL = 50 * time.Millisecond
 		for i := 0; i < 10; i++ {
 			err := manager.InitTarget(target)
@@ -293,7 +294,7 @@ func TestTargetManager(t *testing.T) {
 		mockService := new(mockLogsService)
 		mockService.On("CreateLogStream", mock.Anything).Return(&cloudwatchlogs.CreateLogStreamOutput{}, nil).Once()
 
-		manager := NewTargetManager(logger, mockService)
+		manager := NewTargetManager(logger, mockService, nil)
 		err := manager.InitTarget(target)
 		assert.NoError(t, err)
 
@@ -316,7 +317,7 @@ func TestTargetManager(t *testing.T) {
 			return *input.LogGroupName == target.Group && *input.RetentionInDays == int64(target.Retention)
 		})).Return(&cloudwatchlogs.PutRetentionPolicyOutput{}, nil).Once()
 
-		manager := NewTargetManager(logger, mockService)
+		manager := NewTargetManager(logger, mockService, nil)
 		err := manager.InitTarget(target)
 		assert.NoError(t, err)
 
@@ -326,6 +327,107 @@ func TestTargetManager(t *testing.T) {
 		assertCacheLen(t, manager, 1)
 	})
 
+	t.Run("NewLogGroup/Settings", func(t *testing.T) {
+		settings := &logs.LogGroupSettings{
+			KMSKeyID:             "arn:aws:kms:us-east-1:123456789012:key/abc",
+			Tags:                 map[string]string{"team": "security"},
+			SubscriptionFilter:   &logs.SubscriptionFilter{DestinationARN: "arn:aws:lambda:us-east-1:123456789012:function:f"},
+			DataProtectionPolicy: `{"Name":"policy"}`,
+		}
+		target := Target{Group: "G", Stream: "S"}
+
+		mockService := new(mockLogsService)
+		mockService.On("CreateLogStream", mock.Anything).Return(&cloudwatchlogs.CreateLogStreamOutput{}, awserr.New(cloudwatchlogs.ErrCodeResourceNotFoundException, "Log group not found", nil)).Once()
+		mockService.On("CreateLogGroup", mock.MatchedBy(func(input *cloudwatchlogs.CreateLogGroupInput) bool {
+			return *input.KmsKeyId == settings.KMSKeyID && *input.Tags["team"] == "security"
+		})).Return(&cloudwatchlogs.CreateLogGroupOutput{}, nil).Once()
+		mockService.On("CreateLogStream", mock.Anything).Return(&cloudwatchlogs.CreateLogStreamOutput{}, nil).Once()
+		mockService.On("PutSubscriptionFilter", mock.MatchedBy(func(input *cloudwatchlogs.PutSubscriptionFilterInput) bool {
+			return *input.FilterName == defaultSubscriptionFilterName && *input.FilterPattern == "" && input.RoleArn == nil
+		})).Return(&cloudwatchlogs.PutSubscriptionFilterOutput{}, nil).Once()
+		mockService.On("PutDataProtectionPolicy", mock.MatchedBy(func(input *cloudwatchlogs.PutDataProtectionPolicyInput) bool {
+			return *input.LogGroupIdentifier == target.Group && *input.PolicyDocument == settings.DataProtectionPolicy
+		})).Return(&cloudwatchlogs.PutDataProtectionPolicyOutput{}, nil).Once()
+
+		manager := NewTargetManager(logger, mockService, func(string) *logs.LogGroupSettings { return settings })
+		err := manager.InitTarget(target)
+		assert.NoError(t, err)
+
+		time.Sleep(100 * time.Millisecond)
+		mockService.AssertExpectations(t)
+		mockService.AssertNotCalled(t, "DescribeLogGroups")
+		mockService.AssertNotCalled(t, "AssociateKmsKey")
+		mockService.AssertNotCalled(t, "TagResource")
+		assertCacheLen(t, manager, 1)
+	})
+
+	t.Run("ExistingLogGroup/Settings", func(t *testing.T) {
+		t.Parallel()
+		settings := &logs.LogGroupSettings{
+			KMSKeyID:             "arn:aws:kms:us-east-1:123456789012:key/abc",
+			Tags:                 map[string]string{"team": "security"},
+			DataProtectionPolicy: `{"Name":"policy"}`,
+		}
+		target := Target{Group: "G", Stream: "S"}
+
+		mockService := new(mockLogsService)
+		mockService.On("CreateLogStream", mock.Anything).Return(&cloudwatchlogs.CreateLogStreamOutput{}, nil).Once()
+		mockService.On("DescribeLogGroups", mock.Anything).Return(&cloudwatchlogs.DescribeLogGroupsOutput{
+			LogGroups: []*cloudwatchlogs.LogGroup{
+				{
+					LogGroupName: aws.String(target.Group),
+					LogGroupArn:  aws.String("arn:aws:logs:us-east-1:123456789012:log-group:G"),
+				},
+			},
+		}, nil).Once()
+		mockService.On("AssociateKmsKey", mock.MatchedBy(func(input *cloudwatchlogs.AssociateKmsKeyInput) bool {
+			return *input.LogGroupName == target.Group && *input.KmsKeyId == settings.KMSKeyID
+		})).Return(&cloudwatchlogs.AssociateKmsKeyOutput{}, nil).Once()
+		mockService.On("TagResource", mock.MatchedBy(func(input *cloudwatchlogs.TagResourceInput) bool {
+			return *input.ResourceArn == "arn:aws:logs:us-east-1:123456789012:log-group:G" && *input.Tags["team"] == "security"
+		})).Return(&cloudwatchlogs.TagResourceOutput{}, nil).Once()
+		mockService.On("PutDataProtectionPolicy", mock.Anything).Return(&cloudwatchlogs.PutDataProtectionPolicyOutput{}, nil).Once()
+
+		manager := NewTargetManager(logger, mockService, func(string) *logs.LogGroupSettings { return settings })
+		err := manager.InitTarget(target)
+		assert.NoError(t, err)
+		time.Sleep(7 * time.Second)
+		mockService.AssertExpectations(t)
+		mockService.AssertNotCalled(t, "PutRetentionPolicy")
+		mockService.AssertNotCalled(t, "PutSubscriptionFilter")
+		assertCacheLen(t, manager, 1)
+	})
+
+	t.Run("ExistingLogGroup/Settings/NoChange", func(t *testing.T) {
+		t.Parallel()
+		settings := &logs.LogGroupSettings{
+			KMSKeyID:             "arn:aws:kms:us-east-1:123456789012:key/abc",
+			DataProtectionPolicy: `{"Name":"policy"}`,
+		}
+		target := Target{Group: "G", Stream: "S"}
+
+		mockService := new(mockLogsService)
+		mockService.On("CreateLogStream", mock.Anything).Return(&cloudwatchlogs.CreateLogStreamOutput{}, nil).Once()
+		mockService.On("DescribeLogGroups", mock.Anything).Return(&cloudwatchlogs.DescribeLogGroupsOutput{
+			LogGroups: []*cloudwatchlogs.LogGroup{
+				{
+					LogGroupName:         aws.String(target.Group),
+					KmsKeyId:             aws.String(settings.KMSKeyID),
+					DataProtectionStatus: aws.String(cloudwatchlogs.DataProtectionStatusActivated),
+				},
+			},
+		}, nil).Once()
+
+		manager := NewTargetManager(logger, mockService, func(string) *logs.LogGroupSettings { return settings })
+		err := manager.InitTarget(target)
+		assert.NoError(t, err)
+		time.Sleep(7 * time.Second)
+		mockService.AssertExpectations(t)
+		mockService.AssertNotCalled(t, "AssociateKmsKey")
+		mockService.AssertNotCalled(t, "PutDataProtectionPolicy")
+		assertCacheLen(t, manager, 1)
+	})
+
 	t.Run("NewLogGroup/RetentionError", func(t *testing.T) {
 		t.Parallel()
 		target := Target{Group: "G", Stream: "S", Retention: 7}
@@ -337,7 +439,7 @@ func TestTargetManager(t *testing.T) {
 		// fails but should retry
 		mockService.On("PutRetentionPolicy", mock.Anything).Return(&cloudwatchlogs.PutRetentionPolicyOutput{}, awserr.New("InternalError", "Internal error", nil)).Times(numBackoffRetries)
 
-		manager := NewTargetManager(logger, mockService)
+		manager := NewTargetManager(logger, mockService, nil)
 		err := manager.InitTarget(target)
 		assert.NoError(t, err)
 
@@ -362,7 +464,7 @@ func TestDescribeLogGroupsBatching(t *testing.T) {
 			LogGroups: []*cloudwatchlogs.LogGroup{},
 		}, nil).Once()
 
-		manager := NewTargetManager(logger, mockService)
+		manager := NewTargetManager(logger, mockService, nil)
 		tm := manager.(*targetManager)
 
 		for i := 0; i < logGroupIdentifierLimit; i++ {
@@ -397,7 +499,7 @@ func TestDescribeLogGroupsBatching(t *testing.T) {
 			LogGroups: []*cloudwatchlogs.LogGroup{},
 		}, nil).Once()
 
-		manager := NewTargetManager(logger, mockService)
+		manager := NewTargetManager(logger, mockService, nil)
 		tm := manager.(*targetManager)
 
 		for i := 0; i < 125; i++ {
@@ -426,7 +528,7 @@ func TestDescribeLogGroupsBatching(t *testing.T) {
 			LogGroups: []*cloudwatchlogs.LogGroup{},
 		}, nil).Once()
 
-		manager := NewTargetManager(logger, mockService)
+		manager := NewTargetManager(logger, mockService, nil)
 		tm := manager.(*targetManager)
 
 		for i := 0; i < 5; i++ {
@@ -454,7 +556,7 @@ func TestDescribeLogGroupsBatching(t *testing.T) {
 			LogGroups: []*cloudwatchlogs.LogGroup{},
 		}, nil).Once()
 
-		manager := NewTargetManager(logger, mockService)
+		manager := NewTargetManager(logger, mockService, nil)
 		tm := manager.(*targetManager)
 
 		batch := make(map[string]Target)
@@ -489,7 +591,7 @@ func TestDescribeLogGroupsBatching(t *testing.T) {
 			return *input.LogGroupName == "group-1" && *input.RetentionInDays == 7
 		})).Return(&cloudwatchlogs.PutRetentionPolicyOutput{}, nil).Once()
 
-		manager := NewTargetManager(logger, mockService)
+		manager := NewTargetManager(logger, mockService, nil)
 		tm := manager.(*targetManager)
 
 		// Create a batch with two targets, one needing retention update
@@ -516,7 +618,7 @@ func TestDescribeLogGroupsBatching(t *testing.T) {
 				LogGroups: []*cloudwatchlogs.LogGroup{},
 			}, nil).Once()
 
-		manager := NewTargetManager(logger, mockService)
+		manager := NewTargetManager(logger, mockService, nil)
 		tm := manager.(*targetManager)
 
 		// Create a batch with one target
@@ -9,10 +9,12 @@ import (
 	_ "github.com/aws/amazon-cloudwatch-agent/plugins/processors/k8sdecorator"
 
 	// Enabled cloudwatch-agent input plugins
+	_ "github.com/aws/amazon-cloudwatch-agent/plugins/inputs/journald"
 	_ "github.com/aws/amazon-cloudwatch-agent/plugins/inputs/logfile"
 	_ "github.com/aws/amazon-cloudwatch-agent/plugins/inputs/nvidia_smi"
 	_ "github.com/aws/amazon-cloudwatch-agent/plugins/inputs/prometheus"
 	_ "github.com/aws/amazon-cloudwatch-agent/plugins/inputs/statsd"
+	_ "github.com/aws/amazon-cloudwatch-agent/plugins/inputs/syslog"
 	_ "github.com/aws/amazon-cloudwatch-agent/plugins/inputs/win_perf_counters"
 	_ "github.com/aws/amazon-cloudwatch-agent/plugins/inputs/windows_event_log"
 
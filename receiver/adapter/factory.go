@@ -15,6 +15,8 @@ import (
 	"go.opentelemetry.io/collector/receiver"
 	otelscraper "go.opentelemetry.io/collector/scraper"
 	"go.opentelemetry.io/collector/scraper/scraperhelper"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal/util/collections"
 )
 
 const (
@@ -23,11 +25,15 @@ const (
 
 type Adapter struct {
 	telegrafConfig *telegrafconfig.Config
+	initialized    collections.Set[*models.RunningInput]
 }
 
-func NewAdapter(telegrafConfig *telegrafconfig.Config) Adapter {
+// NewAdapter adapts the inputs of the telegraf config. The initialized inputs
+// are not initialized again, e.g. the ones kept running by a reload.
+func NewAdapter(telegrafConfig *telegrafconfig.Config, initialized ...*models.RunningInput) Adapter {
 	return Adapter{
 		telegrafConfig: telegrafConfig,
+		initialized:    collections.NewSet(initialized...),
 	}
 }
 
@@ -85,6 +91,9 @@ func (a Adapter) createMetricsReceiver(ctx context.Context, settings receiver.Se
 func (a Adapter) initializeInput(pluginName, pluginAlias string) (*models.RunningInput, error) {
 	for _, ri := range a.telegrafConfig.Inputs {
 		if TelegrafPrefix+ri.Config.Name == pluginName && ri.Config.Alias == pluginAlias {
+			if a.initialized.Contains(ri) {
+				return ri, nil
+			}
 
 			err := ri.Init()
 			if err != nil {
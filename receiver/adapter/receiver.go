@@ -13,6 +13,7 @@ import (
 	"go.opentelemetry.io/collector/pdata/pmetric"
 	"go.uber.org/zap"
 
+	"github.com/aws/amazon-cloudwatch-agent/internal/reload"
 	"github.com/aws/amazon-cloudwatch-agent/receiver/adapter/accumulator"
 )
 
@@ -26,6 +27,7 @@ type AdaptedReceiver struct {
 	ctx         context.Context
 	consumer    consumer.Metrics
 	accumulator accumulator.OtelAccumulator
+	running     *runningServiceInput
 }
 
 func newAdaptedReceiver(input *models.RunningInput, ctx context.Context, consumer consumer.Metrics, logger *zap.Logger) *AdaptedReceiver {
@@ -48,10 +50,21 @@ func (r *AdaptedReceiver) start(_ context.Context, _ component.Host) error {
 	// Service Input differs from a regular plugin in that it operates a background service while Telegraf/CWAgent is running
 	// https://github.com/influxdata/telegraf/blob/d67f75e55765d364ad0aabe99382656cb5b51014/docs/INPUTS.md#service-input-plugins
 	if serviceInput, ok := r.input.Input.(telegraf.ServiceInput); ok {
-		if err := serviceInput.Start(r.accumulator); err != nil {
+		// A service input kept running by a reload is already started.
+		running, reclaimed := reload.Reclaim(func(running *runningServiceInput) bool {
+			return running.input == r.input
+		})
+		if !reclaimed {
+			running = newRunningServiceInput(r.input)
+		}
+		running.acc.attach(r.accumulator)
+		if reclaimed {
+			r.logger.Debug("Reusing the running adapter input", zap.String("receiver", r.input.Config.Name))
+		} else if err := serviceInput.Start(running.acc); err != nil {
 			r.accumulator.AddError(err)
 			return err
 		}
+		r.running = running
 	}
 
 	return nil
@@ -74,8 +87,11 @@ func (r *AdaptedReceiver) scrape(_ context.Context) (pmetric.Metrics, error) {
 
 func (r *AdaptedReceiver) shutdown(_ context.Context) error {
 	r.logger.Debug("Shutdown adapter", zap.String("receiver", r.input.Config.Name))
-	if serviceInput, ok := r.input.Input.(telegraf.ServiceInput); ok {
-		serviceInput.Stop()
+	if r.running != nil {
+		r.running.acc.detach()
+		if !reload.Park(r.running) {
+			r.running.Release()
+		}
 	}
 
 	return nil
@@ -6,13 +6,16 @@ package adapter
 import (
 	"context"
 	"testing"
+	"time"
 
+	"github.com/influxdata/telegraf"
 	"github.com/influxdata/telegraf/models"
 	"github.com/stretchr/testify/assert"
 	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/consumer/consumertest"
 	"go.uber.org/zap"
 
+	"github.com/aws/amazon-cloudwatch-agent/internal/reload"
 	"github.com/aws/amazon-cloudwatch-agent/receiver/adapter/accumulator"
 )
 
@@ -49,3 +52,53 @@ func Test_AdaptedReceiver_WithEmptyMetrics_ServiceInput(t *testing.T) {
 	err = adaptedReceiver.shutdown(ctx)
 	as.NoError(err)
 }
+
+type countingServiceInput struct {
+	accumulator.TestServiceRunningInput
+	acc    telegraf.Accumulator
+	starts int
+	stops  int
+}
+
+func (c *countingServiceInput) Start(acc telegraf.Accumulator) error {
+	c.acc = acc
+	c.starts++
+	return nil
+}
+
+func (c *countingServiceInput) Stop() {
+	c.stops++
+}
+
+func Test_AdaptedReceiver_ServiceInputKeptOnReload(t *testing.T) {
+	as := assert.New(t)
+	reload.Enable()
+	defer reload.Disable()
+
+	ctx := context.Background()
+	input := &countingServiceInput{}
+	ri := models.NewRunningInput(input, &models.InputConfig{Name: "statsd"})
+	as.NoError(ri.Config.Filter.Compile())
+
+	first := &consumertest.MetricsSink{}
+	receiver := newAdaptedReceiver(ri, ctx, first, zap.NewNop())
+	as.NoError(receiver.start(ctx, componenttest.NewNopHost()))
+	as.NoError(receiver.shutdown(ctx))
+	as.Equal(0, input.stops)
+
+	// the metrics received while the collector service restarts are held
+	input.acc.AddGauge("statsd", map[string]any{"value": 1}, nil, time.Now())
+
+	second := &consumertest.MetricsSink{}
+	receiver = newAdaptedReceiver(ri, ctx, second, zap.NewNop())
+	as.NoError(receiver.start(ctx, componenttest.NewNopHost()))
+	as.Equal(1, input.starts)
+	as.Equal(0, first.DataPointCount())
+	as.Equal(1, second.DataPointCount())
+
+	reload.Release()
+	as.Equal(0, input.stops)
+	as.NoError(receiver.shutdown(ctx))
+	reload.Release()
+	as.Equal(1, input.stops)
+}
@@ -0,0 +1,116 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package adapter
+
+import (
+	"sync"
+	"time"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/models"
+)
+
+// maxPendingCalls is how many calls to the accumulator are kept while a
+// service input is parked, e.g. the statsd packets received while the
+// collector service restarts.
+const maxPendingCalls = 10000
+
+// runningServiceInput is a started service input. It is parked instead of
+// stopped when the collector service restarts, so the restarted receiver of
+// the same input can keep it running, e.g. without closing the statsd
+// listener.
+type runningServiceInput struct {
+	input *models.RunningInput
+	acc   *forwardingAccumulator
+}
+
+func newRunningServiceInput(input *models.RunningInput) *runningServiceInput {
+	return &runningServiceInput{input: input, acc: &forwardingAccumulator{}}
+}
+
+// Release stops the service input once it is not used by the restarted
+// collector service.
+func (r *runningServiceInput) Release() {
+	r.input.Input.(telegraf.ServiceInput).Stop()
+}
+
+// forwardingAccumulator is the accumulator a service input is started with.
+// It forwards to the accumulator of the receiver that currently runs the
+// input, and holds the calls while there is none.
+type forwardingAccumulator struct {
+	mu      sync.Mutex
+	target  telegraf.Accumulator
+	pending []func(telegraf.Accumulator)
+}
+
+var _ telegraf.Accumulator = (*forwardingAccumulator)(nil)
+
+// attach forwards to the accumulator, starting with the calls that were
+// held.
+func (f *forwardingAccumulator) attach(target telegraf.Accumulator) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, call := range f.pending {
+		call(target)
+	}
+	f.pending = nil
+	f.target = target
+}
+
+func (f *forwardingAccumulator) detach() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.target = nil
+}
+
+func (f *forwardingAccumulator) forward(call func(telegraf.Accumulator)) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.target != nil {
+		call(f.target)
+	} else if len(f.pending) < maxPendingCalls {
+		f.pending = append(f.pending, call)
+	}
+}
+
+func (f *forwardingAccumulator) AddFields(measurement string, fields map[string]interface{}, tags map[string]string, t ...time.Time) {
+	f.forward(func(acc telegraf.Accumulator) { acc.AddFields(measurement, fields, tags, t...) })
+}
+
+func (f *forwardingAccumulator) AddGauge(measurement string, fields map[string]interface{}, tags map[string]string, t ...time.Time) {
+	f.forward(func(acc telegraf.Accumulator) { acc.AddGauge(measurement, fields, tags, t...) })
+}
+
+func (f *forwardingAccumulator) AddCounter(measurement string, fields map[string]interface{}, tags map[string]string, t ...time.Time) {
+	f.forward(func(acc telegraf.Accumulator) { acc.AddCounter(measurement, fields, tags, t...) })
+}
+
+func (f *forwardingAccumulator) AddSummary(measurement string, fields map[string]interface{}, tags map[string]string, t ...time.Time) {
+	f.forward(func(acc telegraf.Accumulator) { acc.AddSummary(measurement, fields, tags, t...) })
+}
+
+func (f *forwardingAccumulator) AddHistogram(measurement string, fields map[string]interface{}, tags map[string]string, t ...time.Time) {
+	f.forward(func(acc telegraf.Accumulator) { acc.AddHistogram(measurement, fields, tags, t...) })
+}
+
+func (f *forwardingAccumulator) AddMetric(m telegraf.Metric) {
+	f.forward(func(acc telegraf.Accumulator) { acc.AddMetric(m) })
+}
+
+func (f *forwardingAccumulator) SetPrecision(precision time.Duration) {
+	f.forward(func(acc telegraf.Accumulator) { acc.SetPrecision(precision) })
+}
+
+func (f *forwardingAccumulator) AddError(err error) {
+	f.forward(func(acc telegraf.Accumulator) { acc.AddError(err) })
+}
+
+func (f *forwardingAccumulator) WithTracking(maxTracked int) telegraf.TrackingAccumulator {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.target == nil {
+		return nil
+	}
+	return f.target.WithTracking(maxTracked)
+}
@@ -0,0 +1,89 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package configprovider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/confmap"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal/util/collections"
+)
+
+const (
+	exportersKey = "exporters"
+	pipelinesKey = "service::pipelines"
+)
+
+// dryRunExporterType replaces the exporters whose requests are not captured
+// by the dry run.
+var dryRunExporterType = component.MustNewType("debug")
+
+// dryRunKeptExporterTypes are the exporters that are kept in a dry run,
+// either because their requests to CloudWatch are captured, or because they
+// do not send anything.
+var dryRunKeptExporterTypes = collections.NewSet("awscloudwatch", "debug", "nop")
+
+type dryRunConverter struct{}
+
+// NewDryRunConverterFactory creates a converter that replaces the exporters of
+// the pipelines with debug exporters, except for the ones that are kept in a
+// dry run.
+func NewDryRunConverterFactory() confmap.ConverterFactory {
+	return confmap.NewConverterFactory(func(confmap.ConverterSettings) confmap.Converter {
+		return dryRunConverter{}
+	})
+}
+
+func (dryRunConverter) Convert(_ context.Context, conf *confmap.Conf) error {
+	exporters, ok := conf.Get(exportersKey).(map[string]any)
+	if !ok {
+		return nil
+	}
+	replaced := map[string]string{}
+	debugExporters := map[string]any{}
+	for id := range exporters {
+		var exporterID component.ID
+		if err := exporterID.UnmarshalText([]byte(id)); err != nil {
+			return fmt.Errorf("invalid exporter %s: %w", id, err)
+		}
+		if dryRunKeptExporterTypes.Contains(exporterID.Type().String()) {
+			continue
+		}
+		debugID := component.NewIDWithName(dryRunExporterType, strings.ReplaceAll(id, "/", "_"))
+		replaced[id] = debugID.String()
+		debugExporters[debugID.String()] = map[string]any{"verbosity": "detailed"}
+	}
+	if len(replaced) == 0 {
+		return nil
+	}
+
+	pipelines := map[string]any{}
+	existing, _ := conf.Get(pipelinesKey).(map[string]any)
+	for name, value := range existing {
+		pipeline, ok := value.(map[string]any)
+		if !ok {
+			continue
+		}
+		ids, ok := pipeline[exportersKey].([]any)
+		if !ok {
+			continue
+		}
+		var exporterIDs []any
+		for _, id := range ids {
+			if debugID, ok := replaced[fmt.Sprint(id)]; ok {
+				id = debugID
+			}
+			exporterIDs = append(exporterIDs, id)
+		}
+		pipelines[name] = map[string]any{exportersKey: exporterIDs}
+	}
+	return conf.Merge(confmap.NewFromStringMap(map[string]any{
+		exportersKey: debugExporters,
+		"service":    map[string]any{"pipelines": pipelines},
+	}))
+}
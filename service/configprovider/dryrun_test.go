@@ -0,0 +1,50 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package configprovider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/confmap"
+)
+
+func TestDryRunConverter(t *testing.T) {
+	conf := confmap.NewFromStringMap(map[string]any{
+		"exporters": map[string]any{
+			"awscloudwatch":               map[string]any{"namespace": "CWAgent"},
+			"awsemf/containerinsights":    map[string]any{"namespace": "ContainerInsights"},
+			"prometheusremotewrite/amp":   map[string]any{},
+			"debug/application_signals":   map[string]any{},
+			"awsxray/application_signals": map[string]any{},
+		},
+		"service": map[string]any{
+			"pipelines": map[string]any{
+				"metrics/host": map[string]any{
+					"receivers": []any{"telegraf_cpu"},
+					"exporters": []any{"awscloudwatch"},
+				},
+				"metrics/containerinsights": map[string]any{
+					"receivers": []any{"awscontainerinsightreceiver"},
+					"exporters": []any{"awsemf/containerinsights", "prometheusremotewrite/amp"},
+				},
+				"traces/application_signals": map[string]any{
+					"receivers": []any{"otlp/application_signals"},
+					"exporters": []any{"awsxray/application_signals", "debug/application_signals"},
+				},
+			},
+		},
+	})
+	converter := NewDryRunConverterFactory().Create(confmap.ConverterSettings{})
+	require.NoError(t, converter.Convert(context.Background(), conf))
+
+	assert.Equal(t, []any{"awscloudwatch"}, conf.Get("service::pipelines::metrics/host::exporters"))
+	assert.Equal(t, []any{"debug/awsemf_containerinsights", "debug/prometheusremotewrite_amp"}, conf.Get("service::pipelines::metrics/containerinsights::exporters"))
+	assert.Equal(t, []any{"debug/awsxray_application_signals", "debug/application_signals"}, conf.Get("service::pipelines::traces/application_signals::exporters"))
+	assert.Equal(t, []any{"awscontainerinsightreceiver"}, conf.Get("service::pipelines::metrics/containerinsights::receivers"))
+	assert.Equal(t, "detailed", conf.Get("exporters::debug/awsemf_containerinsights::verbosity"))
+	assert.Equal(t, "CWAgent", conf.Get("exporters::awscloudwatch::namespace"))
+}
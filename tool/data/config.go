@@ -67,5 +67,5 @@ func (conf *Config) SatisfiedWithCurrentConfig(context *runtime.Context) bool {
 	_, resultMap := conf.ToMap(context)
 	byteArray := util.SerializeResultMapToJsonByteArray(resultMap)
 	fmt.Printf("Current config as follows:\n%s\n", string(byteArray))
-	return util.Yes("Are you satisfied with the above config? Note: it can be manually customized after the wizard completes to add additional items.")
+	return util.Yes("satisfied", "Are you satisfied with the above config? Note: it can be manually customized after the wizard completes to add additional items.")
 }
@@ -47,3 +47,10 @@ func (config *Logs) AddWindowsEvent(eventName, logGroupName, logStream, eventFor
 	}
 	config.LogsCollect.AddWindowsEvent(eventName, logGroupName, logStream, eventFormat, eventLevels, retention, logGroupClass)
 }
+
+func (config *Logs) AddLogFileConfig(fileConfig *logs.Config) {
+	if config.LogsCollect == nil {
+		config.LogsCollect = &logs.Collection{}
+	}
+	config.LogsCollect.AddLogFileConfig(fileConfig)
+}
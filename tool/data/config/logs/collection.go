@@ -43,3 +43,10 @@ func (config *Collection) AddLogFile(filePath, logGroupName, logStreamName strin
 	}
 	config.Files.AddLogFile(filePath, logGroupName, logStreamName, timestampFormat, timezone, multiLineStartPattern, encoding, retention, logGroupClass)
 }
+
+func (config *Collection) AddLogFileConfig(fileConfig *Config) {
+	if config.Files == nil {
+		config.Files = &Files{}
+	}
+	config.Files.AddLogFileConfig(fileConfig)
+}
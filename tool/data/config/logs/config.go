@@ -13,6 +13,8 @@ type Config struct {
 	TimestampFormat       string `timestamp_format`
 	Timezone              string `timezone`
 	MultiLineStartPattern string `multi_line_start_pattern`
+	MultiLinePreset       string `json:"multi_line_preset"`
+	Blacklist             string `json:"blacklist"`
 	Encoding              string `encoding`
 	Retention             int    `retention_in_days`
 }
@@ -30,6 +32,12 @@ func (config *Config) ToMap(ctx *runtime.Context) (string, map[string]interface{
 	if config.MultiLineStartPattern != "" {
 		resultMap["multi_line_start_pattern"] = config.MultiLineStartPattern
 	}
+	if config.MultiLinePreset != "" {
+		resultMap["multi_line_preset"] = config.MultiLinePreset
+	}
+	if config.Blacklist != "" {
+		resultMap["blacklist"] = config.Blacklist
+	}
 	if config.LogStream != "" {
 		resultMap["log_stream_name"] = config.LogStream
 	}
@@ -20,6 +20,8 @@ func TestConfig_ToMap(t *testing.T) {
 		TimestampFormat:       "%H:%M:%S %y %b %d",
 		Timezone:              "UTC",
 		MultiLineStartPattern: "{timestamp_format}",
+		MultiLinePreset:       "java",
+		Blacklist:             "^messages\\.gz$",
 	}
 	ctx := &runtime.Context{}
 	key, value := conf.ToMap(ctx)
@@ -31,6 +33,8 @@ func TestConfig_ToMap(t *testing.T) {
 		"timestamp_format":         "%H:%M:%S %y %b %d",
 		"timezone":                 "UTC",
 		"multi_line_start_pattern": "{timestamp_format}",
+		"multi_line_preset":        "java",
+		"blacklist":                "^messages\\.gz$",
 	},
 		value)
 }
@@ -53,3 +53,9 @@ func (config *Files) AddLogFile(filePath, logGroupName, logStreamName string, ti
 	}
 	config.FileConfigs = append(config.FileConfigs, singleFile)
 }
+
+// AddLogFileConfig adds a log file that is already configured, e.g. by a
+// migration that sets more than AddLogFile does.
+func (config *Files) AddLogFileConfig(fileConfig *Config) {
+	config.FileConfigs = append(config.FileConfigs, fileConfig)
+}
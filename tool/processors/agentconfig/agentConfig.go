@@ -34,12 +34,12 @@ func whichRunAsUser(ctx *runtime.Context, config *data.Config) {
 		return
 	}
 
-	answer := util.Choice("Which user are you planning to run the agent?",
+	answer := util.Choice("agent.run_as_user", "Which user are you planning to run the agent?",
 		1,
 		[]string{RUNASUSER_CWAGENT, RUNASUSER_ROOT, RUNASUSER_OTHERS})
 
 	if answer == RUNASUSER_OTHERS {
-		answer = util.Ask("Please specify your own user(remember the user must exist before the agent running):")
+		answer = util.Ask("agent.custom_user", "Please specify your own user(remember the user must exist before the agent running):")
 	}
 	config.AgentConf().Runasuser = answer
 }
@@ -50,7 +50,7 @@ func whichOS(ctx *runtime.Context) {
 			defaultOption = i + 1
 		}
 	}
-	answer := util.Choice("On which OS are you planning to use the agent?", defaultOption, opts)
+	answer := util.Choice("os", "On which OS are you planning to use the agent?", defaultOption, opts)
 	ctx.OsParameter = answer
 }
 
@@ -60,7 +60,7 @@ func isEC2(ctx *runtime.Context, conf *data.Config) {
 	if defaultRegion == "" {
 		defaultOption = 2
 	}
-	answer := util.Choice("Are you using EC2 or On-Premises hosts?",
+	answer := util.Choice("host", "Are you using EC2 or On-Premises hosts?",
 		defaultOption,
 		[]string{"EC2", "On-Premises"})
 	ctx.IsOnPrem = answer == "On-Premises"
@@ -85,7 +85,7 @@ func (p *processor) NextProcessor(ctx *runtime.Context, config *data.Config) int
 }
 
 func whichDefaultConfig() string {
-	answer := util.Choice(
+	answer := util.Choice("metrics.default_config",
 		"Which default metrics config do you want?",
 		1,
 		[]string{"Basic", "Standard", "Advanced", "None"})
@@ -93,29 +93,29 @@ func whichDefaultConfig() string {
 }
 
 func wantMonitorAnyHostMetrics() bool {
-	return util.Yes("Do you want to monitor any host metrics? e.g. CPU, memory, etc.")
+	return util.Yes("metrics", "Do you want to monitor any host metrics? e.g. CPU, memory, etc.")
 }
 
 func wantPerInstanceMetrics(ctx *runtime.Context) {
-	ctx.WantPerInstanceMetrics = util.Yes("Do you want to monitor cpu metrics per core?")
+	ctx.WantPerInstanceMetrics = util.Yes("metrics.per_core", "Do you want to monitor cpu metrics per core?")
 }
 
 func wantEC2TagDimensions(ctx *runtime.Context) {
 	if ctx.IsOnPrem {
 		return
 	}
-	ctx.WantEC2TagDimensions = util.Yes("Do you want to add ec2 dimensions (ImageId, InstanceId, InstanceType, AutoScalingGroupName) into all of your metrics if the info is available?")
+	ctx.WantEC2TagDimensions = util.Yes("metrics.ec2_dimensions", "Do you want to add ec2 dimensions (ImageId, InstanceId, InstanceType, AutoScalingGroupName) into all of your metrics if the info is available?")
 }
 
 func wantEC2AggregateDimensions(ctx *runtime.Context) {
 	if ctx.IsOnPrem {
 		return
 	}
-	ctx.WantAggregateDimensions = util.Yes("Do you want to aggregate ec2 dimensions (InstanceId)?")
+	ctx.WantAggregateDimensions = util.Yes("metrics.aggregate_dimensions", "Do you want to aggregate ec2 dimensions (InstanceId)?")
 }
 
 func metricsCollectInterval(ctx *runtime.Context) {
-	answer := util.Choice("Would you like to collect your metrics at high resolution (sub-minute resolution)? This enables sub-minute resolution for all metrics, but you can customize for specific metrics in the output json file.", 4, []string{"1s", "10s", "30s", "60s"})
+	answer := util.Choice("metrics.interval", "Would you like to collect your metrics at high resolution (sub-minute resolution)? This enables sub-minute resolution for all metrics, but you can customize for specific metrics in the output json file.", 4, []string{"1s", "10s", "30s", "60s"})
 	if val, err := strconv.Atoi(answer[:len(answer)-1]); err == nil {
 		ctx.MetricsCollectionInterval = val
 	} else {
@@ -0,0 +1,81 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package fluent
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"regexp"
+
+	"github.com/aws/amazon-cloudwatch-agent/tool/data"
+	"github.com/aws/amazon-cloudwatch-agent/tool/data/config"
+	"github.com/aws/amazon-cloudwatch-agent/tool/processors"
+	"github.com/aws/amazon-cloudwatch-agent/tool/processors/question/logs"
+	"github.com/aws/amazon-cloudwatch-agent/tool/runtime"
+	"github.com/aws/amazon-cloudwatch-agent/tool/util"
+)
+
+const (
+	anyExistingFluentConfigQuestion    = "Do you have any existing Fluent Bit or Fluentd configuration file to import for migration?"
+	filePathFluentConfigQuestion       = "What is the file path for the existing Fluent Bit or Fluentd configuration file?"
+	DefaultFilePathFluentConfiguration = "/etc/fluent-bit/fluent-bit.conf"
+)
+
+// fluentdConfig matches the directives of a Fluentd config, e.g. <source>,
+// which a Fluent Bit config does not have.
+var fluentdConfig = regexp.MustCompile(`(?m)^\s*<`)
+
+var Processor processors.Processor = &processor{}
+
+type processor struct{}
+
+func (p *processor) Process(ctx *runtime.Context, config *data.Config) {
+	if ctx.HasExistingFluentConfig || util.No("migration.fluent", anyExistingFluentConfigQuestion) {
+		filePath := ctx.ConfigFilePath
+		if filePath == "" {
+			filePath = util.AskWithDefault("migration.fluent_path", filePathFluentConfigQuestion, DefaultFilePathFluentConfiguration)
+		}
+		unmapped, err := Migrate(filePath, config.LogsConf())
+		if err != nil {
+			log.Panicf("E! Error in reading the Fluent Bit or Fluentd config from file %s: %v", filePath, err)
+		}
+		if len(unmapped) > 0 {
+			fmt.Printf("The following settings of %s are not migrated:\n", filePath)
+			for _, setting := range unmapped {
+				fmt.Printf("Warning: %v\n", setting)
+			}
+		}
+	}
+}
+
+func (p *processor) NextProcessor(ctx *runtime.Context, config *data.Config) interface{} {
+	return logs.Processor
+}
+
+// Migrate adds the log files that the tail inputs of a Fluent Bit config, or
+// the tail sources of a Fluentd config, send to CloudWatch Logs to the logs
+// config. It returns the settings that are not migrated.
+func Migrate(filePath string, logsConfig *config.Logs) ([]Setting, error) {
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	var m *migration
+	if fluentdConfig.Match(content) {
+		directives, err := loadFluentd(filePath, 0)
+		if err != nil {
+			return nil, err
+		}
+		m = migrateFluentd(directives)
+	} else {
+		sections, err := loadFluentBit(filePath, map[string]string{}, 0)
+		if err != nil {
+			return nil, err
+		}
+		m = migrateFluentBit(filePath, sections)
+	}
+	m.addTo(logsConfig)
+	return m.unmapped, nil
+}
@@ -0,0 +1,70 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package fluent
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/aws/amazon-cloudwatch-agent/tool/data"
+	"github.com/aws/amazon-cloudwatch-agent/tool/processors/question/logs"
+	"github.com/aws/amazon-cloudwatch-agent/tool/runtime"
+	"github.com/aws/amazon-cloudwatch-agent/tool/testutil"
+	"github.com/aws/amazon-cloudwatch-agent/tool/util"
+)
+
+func TestProcessor_Process(t *testing.T) {
+	inputChan := testutil.SetUpTestInputStream()
+	ctx := new(runtime.Context)
+	conf := new(data.Config)
+
+	dir := writeFiles(t, map[string]string{
+		"fluent-bit.conf": `
+[INPUT]
+    Name tail
+    Tag  messages
+    Path /var/log/messages
+
+[OUTPUT]
+    Name           cloudwatch_logs
+    Match          *
+    log_group_name /var/log/messages
+`,
+	})
+	expectedMap := map[string]interface{}{
+		"logs": map[string]interface{}{
+			"logs_collected": map[string]interface{}{
+				"files": map[string]interface{}{
+					"collect_list": []map[string]interface{}{
+						{
+							"file_path":         "/var/log/messages",
+							"log_group_name":    "/var/log/messages",
+							"retention_in_days": -1,
+						},
+					},
+				},
+			},
+		},
+	}
+
+	testutil.Type(inputChan, "1", filepath.Join(dir, "fluent-bit.conf"))
+
+	Processor.Process(ctx, conf)
+	_, resultMap := conf.ToMap(ctx)
+	assert.Equal(t, expectedMap, resultMap)
+}
+
+func TestProcessor_NextProcessor(t *testing.T) {
+	assert.Equal(t, logs.Processor, Processor.NextProcessor(nil, nil))
+}
+
+func TestAnyExistingFluentConfigFileToImport(t *testing.T) {
+	inputChan := testutil.SetUpTestInputStream()
+
+	testutil.Type(inputChan, "", "1")
+	assert.Equal(t, false, util.No("migration.fluent", anyExistingFluentConfigQuestion))
+	assert.Equal(t, true, util.No("migration.fluent", anyExistingFluentConfigQuestion))
+}
@@ -0,0 +1,401 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package fluent
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// maxIncludeDepth is how deep the includes of a config are followed, which
+// stops an include loop.
+const maxIncludeDepth = 10
+
+var fluentBitVariable = regexp.MustCompile(`\$\{([^}]+)\}`)
+
+// A fluentBitSection is a section of a Fluent Bit config, e.g. an [INPUT].
+// The keys are lower case, as Fluent Bit does not tell them apart by case.
+type fluentBitSection struct {
+	name    string
+	entries [][2]string
+}
+
+func (s *fluentBitSection) get(key string) string {
+	for _, entry := range s.entries {
+		if entry[0] == key {
+			return entry[1]
+		}
+	}
+	return ""
+}
+
+func (s *fluentBitSection) has(key string) bool {
+	for _, entry := range s.entries {
+		if entry[0] == key {
+			return true
+		}
+	}
+	return false
+}
+
+// title names the section in the report.
+func (s *fluentBitSection) title() string {
+	title := fmt.Sprintf("[%s] %s", s.name, s.get("name"))
+	if tag := s.get("tag"); tag != "" {
+		title += fmt.Sprintf(" (Tag %s)", tag)
+	} else if match := s.get("match"); match != "" {
+		title += fmt.Sprintf(" (Match %s)", match)
+	}
+	return title
+}
+
+func (s *fluentBitSection) isPlugin(name string) bool {
+	return strings.EqualFold(s.get("name"), name)
+}
+
+// loadFluentBit loads the sections of a classic or YAML Fluent Bit config,
+// including the files it includes.
+func loadFluentBit(path string, variables map[string]string, depth int) ([]*fluentBitSection, error) {
+	if depth > maxIncludeDepth {
+		return nil, fmt.Errorf("%s is included more than %d levels deep", path, maxIncludeDepth)
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if ext := filepath.Ext(path); ext == ".yaml" || ext == ".yml" {
+		return parseFluentBitYAML(path, content, variables, depth)
+	}
+	return parseFluentBitClassic(path, content, variables, depth)
+}
+
+func parseFluentBitClassic(path string, content []byte, variables map[string]string, depth int) ([]*fluentBitSection, error) {
+	var sections []*fluentBitSection
+	var current *fluentBitSection
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, _ := strings.Cut(strings.Join(strings.Fields(line), " "), " ")
+		value = expandFluentBitVariables(value, variables)
+		switch {
+		case strings.EqualFold(key, "@INCLUDE"):
+			included, err := loadIncludes(path, value, func(includePath string) ([]*fluentBitSection, error) {
+				return loadFluentBit(includePath, variables, depth+1)
+			})
+			if err != nil {
+				return nil, err
+			}
+			sections = append(sections, included...)
+			current = nil
+		case strings.EqualFold(key, "@SET"):
+			name, setValue, _ := strings.Cut(value, "=")
+			variables[strings.TrimSpace(name)] = strings.TrimSpace(setValue)
+		case strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]"):
+			current = &fluentBitSection{name: strings.ToUpper(strings.TrimSpace(line[1 : len(line)-1]))}
+			sections = append(sections, current)
+		case current == nil:
+			return nil, fmt.Errorf("%s:%d: %s is not in a section", path, lineNumber, key)
+		default:
+			current.entries = append(current.entries, [2]string{strings.ToLower(key), value})
+		}
+	}
+	return sections, scanner.Err()
+}
+
+// fluentBitYAMLSections are the sections of the lists of a YAML config.
+var fluentBitYAMLSections = map[string]string{
+	"inputs":            "INPUT",
+	"filters":           "FILTER",
+	"outputs":           "OUTPUT",
+	"parsers":           "PARSER",
+	"multiline_parsers": "MULTILINE_PARSER",
+}
+
+func parseFluentBitYAML(path string, content []byte, variables map[string]string, depth int) ([]*fluentBitSection, error) {
+	var doc struct {
+		Env              map[string]string                   `yaml:"env"`
+		Includes         []string                            `yaml:"includes"`
+		Service          map[string]interface{}              `yaml:"service"`
+		Pipeline         map[string][]map[string]interface{} `yaml:"pipeline"`
+		Parsers          []map[string]interface{}            `yaml:"parsers"`
+		MultilineParsers []map[string]interface{}            `yaml:"multiline_parsers"`
+	}
+	if err := yaml.Unmarshal(content, &doc); err != nil {
+		return nil, fmt.Errorf("unable to parse %s: %w", path, err)
+	}
+	for name, value := range doc.Env {
+		variables[name] = value
+	}
+	var sections []*fluentBitSection
+	for _, include := range doc.Includes {
+		included, err := loadIncludes(path, include, func(includePath string) ([]*fluentBitSection, error) {
+			return loadFluentBit(includePath, variables, depth+1)
+		})
+		if err != nil {
+			return nil, err
+		}
+		sections = append(sections, included...)
+	}
+	if doc.Service != nil {
+		sections = append(sections, fluentBitYAMLSection("SERVICE", doc.Service, variables))
+	}
+	lists := map[string][]map[string]interface{}{
+		"parsers":           doc.Parsers,
+		"multiline_parsers": doc.MultilineParsers,
+	}
+	for name, list := range doc.Pipeline {
+		lists[name] = list
+	}
+	for _, list := range []string{"inputs", "filters", "outputs", "parsers", "multiline_parsers"} {
+		for _, values := range lists[list] {
+			sections = append(sections, fluentBitYAMLSection(fluentBitYAMLSections[list], values, variables))
+		}
+	}
+	return sections, nil
+}
+
+// fluentBitYAMLSection returns the section of the values of a YAML config. A
+// list is joined by commas as it is in a classic config, and a nested value
+// such as the processors of an input is kept empty, so it is reported.
+func fluentBitYAMLSection(name string, values map[string]interface{}, variables map[string]string) *fluentBitSection {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	section := &fluentBitSection{name: name}
+	for _, key := range keys {
+		var s string
+		switch v := values[key].(type) {
+		case []interface{}:
+			items := make([]string, len(v))
+			for i, item := range v {
+				items[i] = fmt.Sprint(item)
+			}
+			s = strings.Join(items, ",")
+		case map[string]interface{}:
+		case nil:
+		default:
+			s = fmt.Sprint(v)
+		}
+		section.entries = append(section.entries, [2]string{strings.ToLower(key), expandFluentBitVariables(s, variables)})
+	}
+	return section
+}
+
+// expandFluentBitVariables replaces the ${name} of the value by the variable
+// set in the config, or by the environment variable.
+func expandFluentBitVariables(value string, variables map[string]string) string {
+	return fluentBitVariable.ReplaceAllStringFunc(value, func(variable string) string {
+		name := variable[2 : len(variable)-1]
+		if value, ok := variables[name]; ok {
+			return value
+		}
+		return os.Getenv(name)
+	})
+}
+
+// loadIncludes loads the files that match the pattern of an include, which
+// is relative to the directory of the including file.
+func loadIncludes[T any](path, pattern string, load func(string) ([]T, error)) ([]T, error) {
+	if !filepath.IsAbs(pattern) {
+		pattern = filepath.Join(filepath.Dir(path), pattern)
+	}
+	matches, err := filepath.Glob(pattern)
+	if err != nil {
+		return nil, err
+	}
+	if len(matches) == 0 && !strings.ContainsAny(pattern, "*?[") {
+		return nil, fmt.Errorf("the file %s included by %s does not exist", pattern, path)
+	}
+	var loaded []T
+	for _, match := range matches {
+		included, err := load(match)
+		if err != nil {
+			return nil, err
+		}
+		loaded = append(loaded, included...)
+	}
+	return loaded, nil
+}
+
+// ignoredFluentBitKeys are the keys that tune how Fluent Bit runs, which the
+// agent does not need to be migrated.
+var ignoredFluentBitKeys = map[string]bool{
+	"name": true, "alias": true, "tag": true, "match": true, "match_regex": true,
+	"db": true, "db.sync": true, "db.locking": true, "db.journal_mode": true, "db.compare_filename": true,
+	"refresh_interval": true, "rotate_wait": true, "mem_buf_limit": true, "buffer_chunk_size": true,
+	"buffer_max_size": true, "inotify_watcher": true, "storage.type": true, "skip_empty_lines": true,
+	"storage.pause_on_chunks_overlimit": true, "threaded": true, "workers": true, "retry_limit": true,
+	"auto_create_group": true, "multiline.key_content": true, "mode": true,
+}
+
+// migrateFluentBit migrates the tail inputs, the multiline filters and the
+// CloudWatch Logs outputs of a Fluent Bit config.
+func migrateFluentBit(path string, sections []*fluentBitSection) *migration {
+	m := &migration{}
+	parsers := map[string]*fluentBitSection{}
+	addParsers := func(sections []*fluentBitSection) {
+		for _, section := range sections {
+			if section.name == "PARSER" {
+				parsers[section.get("name")] = section
+			}
+		}
+	}
+	addParsers(sections)
+	for _, section := range sections {
+		if section.name != "SERVICE" || section.get("parsers_file") == "" {
+			continue
+		}
+		parsersFile := section.get("parsers_file")
+		if !filepath.IsAbs(parsersFile) {
+			parsersFile = filepath.Join(filepath.Dir(path), parsersFile)
+		}
+		parserSections, err := loadFluentBit(parsersFile, map[string]string{}, 1)
+		if err != nil {
+			m.report(section.title(), "parsers_file", fmt.Sprintf("the parsers are not migrated: %v", err))
+			continue
+		}
+		addParsers(parserSections)
+	}
+
+	var inputs int
+	for _, section := range sections {
+		switch {
+		case section.name == "INPUT" && section.isPlugin("tail"):
+			m.tails = append(m.tails, m.fluentBitTail(section, inputs, parsers))
+			inputs++
+		case section.name == "INPUT":
+			m.report(section.title(), "", "only the tail inputs are migrated")
+			inputs++
+		case section.name == "OUTPUT" && (section.isPlugin("cloudwatch_logs") || section.isPlugin("cloudwatch")):
+			m.routes = append(m.routes, &route{
+				section: section.title(),
+				match:   m.fluentBitMatch(section),
+				output:  m.fluentBitOutput(section),
+			})
+		case section.name == "OUTPUT":
+			m.report(section.title(), "", "only the CloudWatch Logs outputs are migrated")
+		}
+	}
+	// The filters apply to the tails once their tags are known.
+	for _, section := range sections {
+		switch {
+		case section.name == "FILTER" && section.isPlugin("multiline"):
+			m.fluentBitMultilineFilter(section)
+		case section.name == "FILTER":
+			m.report(section.title(), "", "the filter is not migrated")
+		}
+	}
+	return m
+}
+
+func (m *migration) fluentBitTail(section *fluentBitSection, index int, parsers map[string]*fluentBitSection) *tail {
+	t := &tail{section: section.title(), tag: section.get("tag")}
+	if t.tag == "" {
+		t.tag = fmt.Sprintf("tail.%d", index)
+	}
+	multiline := strings.EqualFold(section.get("multiline"), "on")
+	for _, entry := range section.entries {
+		key, value := entry[0], entry[1]
+		switch {
+		case key == "path":
+			t.paths = append(t.paths, splitList(value)...)
+		case key == "exclude_path":
+			t.excludePaths = append(t.excludePaths, splitList(value)...)
+		case key == "multiline.parser":
+			m.setMultiLinePreset(t, t.section, key, splitList(value))
+		case key == "multiline":
+		case key == "parser_firstline" && multiline:
+			if parser, ok := parsers[value]; ok && parser.get("regex") != "" {
+				m.setMultiLineStartPattern(t, t.section, key, parser.get("regex"))
+			} else {
+				m.report(t.section, key, fmt.Sprintf("the regex of the parser %s is not found", value))
+			}
+		case strings.HasPrefix(key, "parser_") && multiline:
+		case key == "parser":
+			m.report(t.section, key, "the agent sends the lines unparsed, only the time format of the parser is migrated")
+			if parser, ok := parsers[value]; ok && parser.get("time_format") != "" {
+				m.setTimestampFormat(t, t.section, key, parser.get("time_format"))
+			}
+		case key == "read_from_head":
+			if !strings.EqualFold(value, "true") && !strings.EqualFold(value, "on") {
+				m.report(t.section, key, "the agent reads the files from the beginning when it first finds them")
+			}
+		case !ignoredFluentBitKeys[key]:
+			m.report(t.section, key, "has no equivalent in the agent config")
+		}
+	}
+	return t
+}
+
+func (m *migration) fluentBitOutput(section *fluentBitSection) *cloudWatchOutput {
+	output := &cloudWatchOutput{retention: -1}
+	for _, entry := range section.entries {
+		key, value := entry[0], entry[1]
+		switch {
+		case key == "log_group_name":
+			output.logGroupName = m.logGroupOrStreamName(section.title(), key, value)
+		case key == "log_stream_name":
+			output.logStreamName = m.logGroupOrStreamName(section.title(), key, value)
+		case key == "log_retention_days":
+			output.retention = m.retention(section.title(), key, value)
+		case key == "log_stream_prefix":
+			m.report(section.title(), key, "the log streams are named by the log_stream_name of the agent instead")
+		case !ignoredFluentBitKeys[key]:
+			m.report(section.title(), key, "has no equivalent in the agent config")
+		}
+	}
+	return output
+}
+
+// fluentBitMultilineFilter applies the parsers of a multiline filter to the
+// tails it matches.
+func (m *migration) fluentBitMultilineFilter(section *fluentBitSection) {
+	match := m.fluentBitMatch(section)
+	for _, t := range m.tails {
+		for _, path := range t.paths {
+			if match(expandTag(t.tag, path)) {
+				m.setMultiLinePreset(t, section.title(), "multiline.parser", splitList(section.get("multiline.parser")))
+				break
+			}
+		}
+	}
+}
+
+// fluentBitMatch returns the function that matches the tags of the Match or
+// Match_Regex of a section.
+func (m *migration) fluentBitMatch(section *fluentBitSection) func(string) bool {
+	if section.has("match_regex") {
+		re, err := regexp.Compile(section.get("match_regex"))
+		if err != nil {
+			m.report(section.title(), "match_regex", fmt.Sprintf("the regexp is not supported by the agent: %v", err))
+			return func(string) bool { return false }
+		}
+		return re.MatchString
+	}
+	re := regexp.MustCompile("^" + strings.ReplaceAll(regexp.QuoteMeta(section.get("match")), `\*`, ".*") + "$")
+	return re.MatchString
+}
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
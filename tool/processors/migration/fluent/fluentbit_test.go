@@ -0,0 +1,200 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package fluent
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aws/amazon-cloudwatch-agent/tool/data/config"
+	"github.com/aws/amazon-cloudwatch-agent/tool/data/config/logs"
+)
+
+func writeFiles(t *testing.T, files map[string]string) string {
+	dir := t.TempDir()
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0600))
+	}
+	return dir
+}
+
+func TestMigrateFluentBit(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"fluent-bit.conf": `
+[SERVICE]
+    Flush        5
+    Parsers_File parsers.conf
+
+@SET group=/app/logs
+@INCLUDE inputs.conf
+
+[FILTER]
+    Name             multiline
+    Match            app.*
+    multiline.parser java
+
+[FILTER]
+    Name   grep
+    Match  *
+    Regex  log error
+
+[OUTPUT]
+    Name               cloudwatch_logs
+    Match              app.*
+    region             us-east-1
+    log_group_name     ${group}
+    log_stream_name    app
+    log_retention_days 30
+    auto_create_group  On
+
+[OUTPUT]
+    Name              cloudwatch_logs
+    Match             nginx
+    log_group_name    /nginx
+    log_stream_prefix host-
+
+[OUTPUT]
+    Name  es
+    Match *
+`,
+		"inputs.conf": `
+[INPUT]
+    Name         tail
+    Tag          app.*
+    Path         /var/log/app/*.log, /opt/app/logs/*.log
+    Exclude_Path /var/log/app/*.gz,/tmp/*.bak
+    DB           /var/fluent-bit/app.db
+
+[INPUT]
+    Name             tail
+    Tag              nginx
+    Path             /var/log/nginx/access.log
+    Multiline        On
+    Parser_Firstline first
+    Read_from_Head   false
+
+[INPUT]
+    Name   tail
+    Path   /var/log/syslog
+    Parser syslog
+    Key    message
+
+[INPUT]
+    Name cpu
+`,
+		"parsers.conf": `
+[PARSER]
+    Name   first
+    Format regex
+    Regex  ^(?<time>\d{4}-\d{2}-\d{2})
+
+[PARSER]
+    Name        syslog
+    Format      regex
+    Regex       ^(?<time>[^ ]* {1,2}[^ ]* [^ ]*) (?<message>.*)$
+    Time_Key    time
+    Time_Format %b %d %H:%M:%S
+`,
+	})
+	logsConfig := new(config.Logs)
+	unmapped, err := Migrate(filepath.Join(dir, "fluent-bit.conf"), logsConfig)
+	require.NoError(t, err)
+
+	assert.Equal(t, []*logs.Config{
+		{
+			FilePath:        "/var/log/app/*.log",
+			LogGroup:        "/app/logs",
+			LogStream:       "app",
+			MultiLinePreset: "java",
+			Blacklist:       `^(?:.*\.gz|.*\.bak)$`,
+			Retention:       30,
+		},
+		{
+			FilePath:        "/opt/app/logs/*.log",
+			LogGroup:        "/app/logs",
+			LogStream:       "app",
+			MultiLinePreset: "java",
+			Blacklist:       `^(?:.*\.gz|.*\.bak)$`,
+			Retention:       30,
+		},
+		{
+			FilePath:              "/var/log/nginx/access.log",
+			LogGroup:              "/nginx",
+			MultiLineStartPattern: `^(?<time>\d{4}-\d{2}-\d{2})`,
+			Retention:             -1,
+		},
+		{
+			FilePath:        "/var/log/syslog",
+			LogGroup:        "syslog",
+			TimestampFormat: "%b %d %H:%M:%S",
+			Retention:       -1,
+		},
+	}, logsConfig.LogsCollect.Files.FileConfigs)
+
+	assert.Equal(t, []Setting{
+		{Section: "[INPUT] tail (Tag nginx)", Key: "read_from_head", Reason: "the agent reads the files from the beginning when it first finds them"},
+		{Section: "[INPUT] tail", Key: "parser", Reason: "the agent sends the lines unparsed, only the time format of the parser is migrated"},
+		{Section: "[INPUT] tail", Key: "key", Reason: "has no equivalent in the agent config"},
+		{Section: "[INPUT] cpu", Reason: "only the tail inputs are migrated"},
+		{Section: "[OUTPUT] cloudwatch_logs (Match app.*)", Key: "region", Reason: "has no equivalent in the agent config"},
+		{Section: "[OUTPUT] cloudwatch_logs (Match nginx)", Key: "log_stream_prefix", Reason: "the log streams are named by the log_stream_name of the agent instead"},
+		{Section: "[OUTPUT] es (Match *)", Reason: "only the CloudWatch Logs outputs are migrated"},
+		{Section: "[FILTER] grep (Match *)", Reason: "the filter is not migrated"},
+		{Section: "[INPUT] tail (Tag app.*)", Key: "exclude_path", Reason: "/tmp/*.bak is applied to the file names in all the paths"},
+		{Section: "[INPUT] tail", Reason: "/var/log/syslog is not sent to a CloudWatch Logs output"},
+	}, unmapped)
+}
+
+func TestMigrateFluentBitYAML(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"fluent-bit.yaml": `
+env:
+  stream: web
+pipeline:
+  inputs:
+    - name: tail
+      tag: web
+      path: /var/log/web.log
+      multiline.parser: [docker, python]
+  outputs:
+    - name: cloudwatch_logs
+      match: "*"
+      log_group_name: /web/$(tag)
+      log_stream_name: ${stream}
+`,
+	})
+	logsConfig := new(config.Logs)
+	unmapped, err := Migrate(filepath.Join(dir, "fluent-bit.yaml"), logsConfig)
+	require.NoError(t, err)
+
+	assert.Equal(t, []*logs.Config{
+		{
+			FilePath:        "/var/log/web.log",
+			LogGroup:        "web.log",
+			MultiLinePreset: "python",
+			Retention:       -1,
+		},
+	}, logsConfig.LogsCollect.Files.FileConfigs)
+	assert.Equal(t, []Setting{
+		{Section: "[INPUT] tail (Tag web)", Key: "multiline.parser", Reason: "the agent has no preset for the multiline parser docker"},
+		{Section: "[OUTPUT] cloudwatch_logs (Match *)", Key: "log_group_name", Reason: "the agent cannot fill in the template /web/$(tag)"},
+	}, unmapped)
+}
+
+func TestLoadFluentBitError(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"no-section.conf": "Name tail\n",
+		"include.conf":    "@INCLUDE missing.conf\n",
+	})
+	_, err := Migrate(filepath.Join(dir, "no-section.conf"), new(config.Logs))
+	assert.ErrorContains(t, err, "Name is not in a section")
+	_, err = Migrate(filepath.Join(dir, "include.conf"), new(config.Logs))
+	assert.ErrorContains(t, err, "missing.conf included by")
+	_, err = Migrate(filepath.Join(dir, "missing.conf"), new(config.Logs))
+	assert.Error(t, err)
+}
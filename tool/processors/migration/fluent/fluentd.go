@@ -0,0 +1,377 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package fluent
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// A fluentdDirective is a directive of a Fluentd config, e.g. a <source>,
+// with its parameters and the directives nested in it.
+type fluentdDirective struct {
+	name     string
+	arg      string
+	params   [][2]string
+	children []*fluentdDirective
+}
+
+func (d *fluentdDirective) get(key string) string {
+	for _, param := range d.params {
+		if param[0] == key {
+			return param[1]
+		}
+	}
+	return ""
+}
+
+// title names the directive in the report.
+func (d *fluentdDirective) title() string {
+	title := "<" + d.name
+	if d.arg != "" {
+		title += " " + d.arg
+	}
+	title += ">"
+	if pluginType := d.get("@type"); pluginType != "" {
+		title += " " + pluginType
+	}
+	if tag := d.get("tag"); tag != "" {
+		title += fmt.Sprintf(" (tag %s)", tag)
+	}
+	return title
+}
+
+// loadFluentd loads the directives of a Fluentd config, including the files
+// it includes.
+func loadFluentd(path string, depth int) ([]*fluentdDirective, error) {
+	if depth > maxIncludeDepth {
+		return nil, fmt.Errorf("%s is included more than %d levels deep", path, maxIncludeDepth)
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	root := &fluentdDirective{}
+	stack := []*fluentdDirective{root}
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		current := stack[len(stack)-1]
+		switch {
+		case line == "" || strings.HasPrefix(line, "#"):
+		case strings.HasPrefix(line, "</") && strings.HasSuffix(line, ">"):
+			if name := strings.TrimSpace(line[2 : len(line)-1]); len(stack) == 1 || name != current.name {
+				return nil, fmt.Errorf("%s:%d: %s does not close a directive", path, lineNumber, line)
+			}
+			stack = stack[:len(stack)-1]
+		case strings.HasPrefix(line, "<") && strings.HasSuffix(line, ">"):
+			name, arg, _ := strings.Cut(strings.TrimSpace(line[1:len(line)-1]), " ")
+			directive := &fluentdDirective{name: name, arg: strings.TrimSpace(arg)}
+			current.children = append(current.children, directive)
+			stack = append(stack, directive)
+		case strings.HasPrefix(line, "@include "):
+			included, err := loadIncludes(path, strings.TrimSpace(strings.TrimPrefix(line, "@include ")), func(includePath string) ([]*fluentdDirective, error) {
+				return loadFluentd(includePath, depth+1)
+			})
+			if err != nil {
+				return nil, err
+			}
+			current.children = append(current.children, included...)
+		default:
+			key, value, _ := strings.Cut(line, " ")
+			current.params = append(current.params, [2]string{key, fluentdValue(strings.TrimSpace(value))})
+		}
+	}
+	if len(stack) > 1 {
+		return nil, fmt.Errorf("%s: <%s> is not closed", path, stack[len(stack)-1].name)
+	}
+	return root.children, scanner.Err()
+}
+
+// fluentdValue removes the quotes of a quoted value.
+func fluentdValue(value string) string {
+	if len(value) >= 2 && value[0] == '"' && value[len(value)-1] == '"' {
+		if unquoted, err := strconv.Unquote(value); err == nil {
+			return unquoted
+		}
+	}
+	if len(value) >= 2 && value[0] == '\'' && value[len(value)-1] == '\'' {
+		return value[1 : len(value)-1]
+	}
+	return value
+}
+
+// fluentdRegexp returns the Go regexp of a /regexp/ value.
+func fluentdRegexp(value string) string {
+	end := strings.LastIndex(value, "/")
+	if !strings.HasPrefix(value, "/") || end < 1 {
+		return value
+	}
+	var flags string
+	for _, flag := range value[end+1:] {
+		switch flag {
+		case 'i':
+			flags += "i"
+		case 'm':
+			// The m of Ruby is the s of Go, . matches a new line.
+			flags += "s"
+		}
+	}
+	if flags != "" {
+		return "(?" + flags + ")" + value[1:end]
+	}
+	return value[1:end]
+}
+
+// fluentdList returns the items of an array value, which is either JSON or
+// separated by commas.
+func fluentdList(value string) []string {
+	var items []string
+	if err := json.Unmarshal([]byte(value), &items); err == nil {
+		return items
+	}
+	return splitList(value)
+}
+
+var strftimeDirective = regexp.MustCompile(`%[A-Za-z]`)
+
+// ignoredFluentdParams are the parameters that tune how Fluentd runs, which
+// the agent does not need to be migrated.
+var ignoredFluentdParams = map[string]bool{
+	"@type": true, "@id": true, "@label": true, "@log_level": true, "tag": true,
+	"pos_file": true, "pos_file_compaction_interval": true, "refresh_interval": true, "rotate_wait": true,
+	"enable_watch_timer": true, "enable_stat_watcher": true, "follow_inodes": true, "read_lines_limit": true,
+	"auto_create_stream": true, "key": true, "separator": true, "flush_interval": true, "timeout_label": true,
+}
+
+// migrateFluentd migrates the tail sources, the concat filters and the
+// CloudWatch Logs matches of a Fluentd config. Each <label> has its own
+// filters and matches, for the sources with that @label.
+func migrateFluentd(directives []*fluentdDirective) *migration {
+	m := &migration{firstRouteOnly: true}
+	type labeledFilter struct {
+		directive *fluentdDirective
+		label     string
+	}
+	var filters []labeledFilter
+	var walk func(directives []*fluentdDirective, label string)
+	walk = func(directives []*fluentdDirective, label string) {
+		for _, d := range directives {
+			switch {
+			case d.name == "source" && d.get("@type") == "tail":
+				m.tails = append(m.tails, m.fluentdTail(d))
+			case d.name == "source":
+				m.report(d.title(), "", "only the tail sources are migrated")
+			case d.name == "match":
+				m.routes = append(m.routes, &route{
+					section: d.title(),
+					label:   label,
+					match:   fluentdMatch(d.arg),
+					output:  m.fluentdOutput(d),
+				})
+			case d.name == "filter":
+				filters = append(filters, labeledFilter{directive: d, label: label})
+			case d.name == "label" && d.arg != "@FLUENT_LOG":
+				walk(d.children, d.arg)
+			case d.name == "label", d.name == "system":
+			default:
+				m.report(d.title(), "", "the directive is not migrated")
+			}
+		}
+	}
+	walk(directives, "")
+	// The filters apply to the tails once their tags are known.
+	for _, filter := range filters {
+		if filter.directive.get("@type") != "concat" {
+			m.report(filter.directive.title(), "", "the filter is not migrated")
+			continue
+		}
+		m.fluentdConcatFilter(filter.directive, filter.label)
+	}
+	return m
+}
+
+func (m *migration) fluentdTail(d *fluentdDirective) *tail {
+	t := &tail{section: d.title(), tag: d.get("tag"), label: d.get("@label")}
+	for _, param := range d.params {
+		key, value := param[0], param[1]
+		switch {
+		case key == "path":
+			for _, path := range splitList(value) {
+				if strftimeDirective.MatchString(path) {
+					m.report(t.section, key, fmt.Sprintf("the time of %s is replaced by a wildcard, the agent reads the latest file", path))
+					path = strftimeDirective.ReplaceAllString(path, "*")
+				}
+				t.paths = append(t.paths, path)
+			}
+		case key == "exclude_path":
+			t.excludePaths = append(t.excludePaths, fluentdList(value)...)
+		case key == "read_from_head":
+			if value != "true" {
+				m.report(t.section, key, "the agent reads the files from the beginning when it first finds them")
+			}
+		case !ignoredFluentdParams[key]:
+			m.report(t.section, key, "has no equivalent in the agent config")
+		}
+	}
+	for _, child := range d.children {
+		if child.name != "parse" {
+			m.report(t.section, "<"+child.name+">", "has no equivalent in the agent config")
+			continue
+		}
+		parseType := child.get("@type")
+		if parseType != "none" {
+			m.report(t.section, "<parse>", "the agent sends the lines unparsed, only the first line and time formats are migrated")
+		}
+		if parseType == "multiline" && child.get("format_firstline") != "" {
+			m.setMultiLineStartPattern(t, t.section, "format_firstline", fluentdRegexp(child.get("format_firstline")))
+		}
+		if timeFormat := child.get("time_format"); timeFormat != "" {
+			m.setTimestampFormat(t, t.section, "time_format", timeFormat)
+		}
+	}
+	return t
+}
+
+// fluentdOutput returns the output of a <match>, which is nil if it is not a
+// CloudWatch Logs one, or a copy to one.
+func (m *migration) fluentdOutput(d *fluentdDirective) *cloudWatchOutput {
+	switch d.get("@type") {
+	case "cloudwatch_logs":
+	case "copy":
+		var outputs []*cloudWatchOutput
+		for _, store := range d.children {
+			if store.name == "store" && store.get("@type") == "cloudwatch_logs" {
+				outputs = append(outputs, m.fluentdOutput(store))
+			} else if store.name == "store" {
+				m.report(d.title(), store.title(), "only the CloudWatch Logs outputs are migrated")
+			}
+		}
+		if len(outputs) > 1 {
+			m.report(d.title(), "", "the events are copied to more than one CloudWatch Logs output, only the first one is migrated")
+		}
+		if len(outputs) == 0 {
+			return nil
+		}
+		return outputs[0]
+	default:
+		m.report(d.title(), "", "only the CloudWatch Logs outputs are migrated")
+		return nil
+	}
+	output := &cloudWatchOutput{retention: -1}
+	for _, param := range d.params {
+		key, value := param[0], param[1]
+		switch {
+		case key == "log_group_name":
+			output.logGroupName = m.logGroupOrStreamName(d.title(), key, value)
+		case key == "log_stream_name":
+			output.logStreamName = m.logGroupOrStreamName(d.title(), key, value)
+		case key == "retention_in_days":
+			output.retention = m.retention(d.title(), key, value)
+		case key == "use_tag_as_group", key == "use_tag_as_stream", key == "log_group_name_key", key == "log_stream_name_key":
+			m.report(d.title(), key, "the agent names the log groups and streams in its config, not by the events")
+		case !ignoredFluentdParams[key]:
+			m.report(d.title(), key, "has no equivalent in the agent config")
+		}
+	}
+	for _, child := range d.children {
+		if child.name != "buffer" {
+			m.report(d.title(), "<"+child.name+">", "has no equivalent in the agent config")
+		}
+	}
+	return output
+}
+
+// fluentdConcatFilter applies the start of the multiline events of a concat
+// filter to the tails it matches.
+func (m *migration) fluentdConcatFilter(d *fluentdDirective, label string) {
+	match := fluentdMatch(d.arg)
+	for _, t := range m.tails {
+		if t.label != label {
+			continue
+		}
+		for _, path := range t.paths {
+			if match(expandTag(t.tag, path)) {
+				if pattern := d.get("multiline_start_regexp"); pattern != "" {
+					m.setMultiLineStartPattern(t, d.title(), "multiline_start_regexp", fluentdRegexp(pattern))
+				}
+				break
+			}
+		}
+	}
+	for _, param := range d.params {
+		if key := param[0]; key != "multiline_start_regexp" && !ignoredFluentdParams[key] {
+			m.report(d.title(), key, "has no equivalent in the agent config")
+		}
+	}
+}
+
+// fluentdMatch returns the function that matches the tags of the patterns of
+// a <match> or <filter>. A * matches a part of the tag, a ** matches zero or
+// more parts, and {a,b} matches either a or b.
+func fluentdMatch(patterns string) func(string) bool {
+	var res []*regexp.Regexp
+	for _, pattern := range strings.Fields(patterns) {
+		res = append(res, regexp.MustCompile("^"+fluentdPatternRegexp(pattern)+"$"))
+	}
+	if len(res) == 0 {
+		res = append(res, regexp.MustCompile(".*"))
+	}
+	return func(tag string) bool {
+		for _, re := range res {
+			if re.MatchString(tag) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+func fluentdPatternRegexp(pattern string) string {
+	var b strings.Builder
+	parts := strings.Split(pattern, ".")
+	for i, part := range parts {
+		if part == "**" {
+			switch {
+			case len(parts) == 1:
+				b.WriteString(".*")
+			case i == 0:
+				b.WriteString(`(?:.*\.)?`)
+			default:
+				b.WriteString(`(?:\..*)?`)
+			}
+			continue
+		}
+		if i > 0 && !(i == 1 && parts[0] == "**") {
+			b.WriteString(`\.`)
+		}
+		inBraces := false
+		for _, r := range part {
+			switch {
+			case r == '*':
+				b.WriteString(`[^.]*`)
+			case r == '{':
+				inBraces = true
+				b.WriteString("(?:")
+			case r == '}' && inBraces:
+				inBraces = false
+				b.WriteString(")")
+			case r == ',' && inBraces:
+				b.WriteString("|")
+			default:
+				b.WriteString(regexp.QuoteMeta(string(r)))
+			}
+		}
+		if inBraces {
+			b.WriteString(")")
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,223 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package fluent
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aws/amazon-cloudwatch-agent/tool/data/config"
+	"github.com/aws/amazon-cloudwatch-agent/tool/data/config/logs"
+)
+
+func TestMigrateFluentd(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"td-agent.conf": `
+<system>
+  log_level info
+</system>
+
+@include sources.conf
+
+<filter app.**>
+  @type concat
+  key log
+  multiline_start_regexp /^\d{4}-\d{2}-\d{2}/
+</filter>
+
+<filter app.**>
+  @type record_transformer
+</filter>
+
+<match app.**>
+  @type cloudwatch_logs
+  region us-east-1
+  log_group_name "/app/logs"
+  log_stream_name app
+  retention_in_days 14
+  auto_create_stream true
+  <buffer>
+    flush_interval 5s
+  </buffer>
+</match>
+
+<match audit>
+  @type copy
+  <store>
+    @type cloudwatch_logs
+    log_group_name /audit
+    use_tag_as_stream true
+  </store>
+  <store>
+    @type s3
+  </store>
+</match>
+
+<match **>
+  @type forward
+</match>
+
+<label @JAVA>
+  <match **>
+    @type cloudwatch_logs
+    log_group_name /java
+    retention_in_days 10
+  </match>
+</label>
+`,
+		"sources.conf": `
+<source>
+  @type tail
+  tag app.*
+  path /var/log/app/*.log
+  exclude_path ["/var/log/app/*.gz"]
+  pos_file /var/log/td-agent/app.pos
+  read_from_head true
+  <parse>
+    @type none
+  </parse>
+</source>
+
+<source>
+  @type tail
+  tag audit
+  path /var/log/audit/audit-%Y%m%d.log
+  <parse>
+    @type regexp
+    expression /^(?<time>[^ ]+) (?<message>.*)$/
+    time_format %Y-%m-%dT%H:%M:%S.%L%z
+  </parse>
+</source>
+
+<source>
+  @type tail
+  @label @JAVA
+  tag java
+  path /opt/java/app.log
+  read_from_head true
+  <parse>
+    @type multiline
+    format_firstline /^\[\w+\]/
+    format1 /^\[(?<level>\w+)\] (?<message>.*)/
+  </parse>
+</source>
+
+<source>
+  @type tail
+  tag other
+  path /var/log/other.log
+  read_from_head true
+  <parse>
+    @type none
+  </parse>
+</source>
+
+<source>
+  @type forward
+  port 24224
+</source>
+`,
+	})
+	logsConfig := new(config.Logs)
+	unmapped, err := Migrate(filepath.Join(dir, "td-agent.conf"), logsConfig)
+	require.NoError(t, err)
+
+	assert.Equal(t, []*logs.Config{
+		{
+			FilePath:              "/var/log/app/*.log",
+			LogGroup:              "/app/logs",
+			LogStream:             "app",
+			MultiLineStartPattern: `^\d{4}-\d{2}-\d{2}`,
+			Blacklist:             `^(?:.*\.gz)$`,
+			Retention:             14,
+		},
+		{
+			FilePath:        "/var/log/audit/audit-***.log",
+			LogGroup:        "/audit",
+			TimestampFormat: "%Y-%m-%dT%H:%M:%S%f%z",
+			Retention:       -1,
+		},
+		{
+			FilePath:              "/opt/java/app.log",
+			LogGroup:              "/java",
+			MultiLineStartPattern: `^\[\w+\]`,
+			Retention:             -1,
+		},
+		{
+			FilePath:  "/var/log/other.log",
+			LogGroup:  "other.log",
+			Retention: -1,
+		},
+	}, logsConfig.LogsCollect.Files.FileConfigs)
+
+	assert.Equal(t, []Setting{
+		{Section: "<source> tail (tag audit)", Key: "path", Reason: "the time of /var/log/audit/audit-%Y%m%d.log is replaced by a wildcard, the agent reads the latest file"},
+		{Section: "<source> tail (tag audit)", Key: "<parse>", Reason: "the agent sends the lines unparsed, only the first line and time formats are migrated"},
+		{Section: "<source> tail (tag java)", Key: "<parse>", Reason: "the agent sends the lines unparsed, only the first line and time formats are migrated"},
+		{Section: "<source> forward", Reason: "only the tail sources are migrated"},
+		{Section: "<match app.**> cloudwatch_logs", Key: "region", Reason: "has no equivalent in the agent config"},
+		{Section: "<store> cloudwatch_logs", Key: "use_tag_as_stream", Reason: "the agent names the log groups and streams in its config, not by the events"},
+		{Section: "<match audit> copy", Key: "<store> s3", Reason: "only the CloudWatch Logs outputs are migrated"},
+		{Section: "<match **> forward", Reason: "only the CloudWatch Logs outputs are migrated"},
+		{Section: "<match **> cloudwatch_logs", Key: "retention_in_days", Reason: "10 is not a valid retention of CloudWatch Logs"},
+		{Section: "<filter app.**> record_transformer", Reason: "the filter is not migrated"},
+		{Section: "<source> tail (tag other)", Reason: "/var/log/other.log is not sent to a CloudWatch Logs output"},
+	}, unmapped)
+}
+
+func TestFluentdMatch(t *testing.T) {
+	testCases := map[string]struct {
+		pattern string
+		matched []string
+		other   []string
+	}{
+		"Exact":           {pattern: "app", matched: []string{"app"}, other: []string{"app.a", "ap"}},
+		"Wildcard":        {pattern: "app.*", matched: []string{"app.a"}, other: []string{"app", "app.a.b"}},
+		"TrailingParts":   {pattern: "app.**", matched: []string{"app", "app.a", "app.a.b"}, other: []string{"apps.a"}},
+		"LeadingParts":    {pattern: "**.log", matched: []string{"log", "var.log"}, other: []string{"var.logs"}},
+		"All":             {pattern: "**", matched: []string{"a", "a.b"}},
+		"Alternatives":    {pattern: "{app,web}.*", matched: []string{"app.a", "web.a"}, other: []string{"db.a"}},
+		"SeveralPatterns": {pattern: "app web.**", matched: []string{"app", "web.a"}, other: []string{"app.a"}},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			match := fluentdMatch(testCase.pattern)
+			for _, tag := range testCase.matched {
+				assert.True(t, match(tag), tag)
+			}
+			for _, tag := range testCase.other {
+				assert.False(t, match(tag), tag)
+			}
+		})
+	}
+}
+
+func TestLoadFluentdError(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"unclosed.conf":   "<source>\n  @type tail\n",
+		"mismatched.conf": "<source>\n</match>\n",
+	})
+	_, err := Migrate(filepath.Join(dir, "unclosed.conf"), new(config.Logs))
+	assert.ErrorContains(t, err, "<source> is not closed")
+	_, err = Migrate(filepath.Join(dir, "mismatched.conf"), new(config.Logs))
+	assert.ErrorContains(t, err, "</match> does not close a directive")
+}
+
+func TestDefaultLogGroupName(t *testing.T) {
+	testCases := map[string]string{
+		"/var/log/other.log":       "other.log",
+		"/var/log/my app.log":      "my_app.log",
+		"/var/log/app-*.log":       "app-_.log",
+		"/var/log/*.log":           "log",
+		"/var/log/app[0-9]?.log":   "app_0-9_.log",
+		"/var/log/*":               "fluent",
+		"/var/log/containers/*/**": "fluent",
+	}
+	for path, expected := range testCases {
+		assert.Equal(t, expected, defaultLogGroupName(path), path)
+	}
+}
@@ -0,0 +1,285 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package fluent
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/aws/amazon-cloudwatch-agent/tool/data/config"
+	"github.com/aws/amazon-cloudwatch-agent/tool/data/config/logs"
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+// A Setting is a setting of the Fluent Bit or Fluentd config that is not
+// migrated, because the agent has no equivalent of it.
+type Setting struct {
+	// Section is the section or directive of the setting, e.g. "[INPUT] tail".
+	Section string
+	// Key is the key of the setting, or empty if it is the whole section.
+	Key    string
+	Reason string
+}
+
+func (s Setting) String() string {
+	if s.Key == "" {
+		return fmt.Sprintf("%s: %s", s.Section, s.Reason)
+	}
+	return fmt.Sprintf("%s %s: %s", s.Section, s.Key, s.Reason)
+}
+
+// A tail is the tail input of Fluent Bit, or the tail source of Fluentd.
+type tail struct {
+	section      string
+	tag          string
+	label        string
+	paths        []string
+	excludePaths []string
+	// Only one of the multiline settings is set.
+	multiLineStartPattern string
+	multiLinePreset       string
+	timestampFormat       string
+}
+
+// A route sends the events of the tags it matches to an output, which is nil
+// if it is not a CloudWatch Logs one.
+type route struct {
+	section string
+	label   string
+	match   func(tag string) bool
+	output  *cloudWatchOutput
+}
+
+type cloudWatchOutput struct {
+	logGroupName  string
+	logStreamName string
+	retention     int
+}
+
+// A migration is what a Fluent Bit or Fluentd config is migrated from.
+type migration struct {
+	tails  []*tail
+	routes []*route
+	// firstRouteOnly is set if an event is only sent to the first route that
+	// matches its tag, as Fluentd does, rather than to all of them.
+	firstRouteOnly bool
+	unmapped       []Setting
+}
+
+func (m *migration) report(section, key, reason string) {
+	m.unmapped = append(m.unmapped, Setting{Section: section, Key: key, Reason: reason})
+}
+
+// addTo adds a log file for each path of the tails, sent to the log group of
+// the CloudWatch Logs output that its tag is routed to.
+func (m *migration) addTo(logsConfig *config.Logs) {
+	for _, t := range m.tails {
+		blacklist := m.blacklist(t)
+		for _, path := range t.paths {
+			fileConfig := &logs.Config{
+				FilePath:              path,
+				MultiLineStartPattern: t.multiLineStartPattern,
+				MultiLinePreset:       t.multiLinePreset,
+				TimestampFormat:       t.timestampFormat,
+				Blacklist:             blacklist,
+				Retention:             -1,
+			}
+			outputs := m.outputsOf(t.label, expandTag(t.tag, path))
+			if len(outputs) > 1 {
+				m.report(t.section, "", fmt.Sprintf("%s is sent to %d CloudWatch Logs outputs, only the first one is migrated", path, len(outputs)))
+			}
+			if len(outputs) > 0 && outputs[0].logGroupName != "" {
+				fileConfig.LogGroup = outputs[0].logGroupName
+				fileConfig.LogStream = outputs[0].logStreamName
+				fileConfig.Retention = outputs[0].retention
+			} else {
+				if len(outputs) == 0 {
+					m.report(t.section, "", fmt.Sprintf("%s is not sent to a CloudWatch Logs output", path))
+				}
+				fileConfig.LogGroup = defaultLogGroupName(path)
+			}
+			logsConfig.AddLogFileConfig(fileConfig)
+		}
+	}
+}
+
+// defaultLogGroup is the log group of the paths whose file names have no
+// character a log group name can have, e.g. "/var/log/*".
+const defaultLogGroup = "fluent"
+
+// invalidLogGroupNameChars are the characters a log group name cannot have.
+var invalidLogGroupNameChars = regexp.MustCompile(`[^A-Za-z0-9._/#-]+`)
+
+// defaultLogGroupName names the log group of a path that is not sent to a
+// CloudWatch Logs output after its file name, as the wizard does. The glob
+// characters and the other characters a log group name cannot have are
+// replaced, so "/var/log/app-*.log" is sent to "app-_.log".
+func defaultLogGroupName(path string) string {
+	name := strings.Trim(invalidLogGroupNameChars.ReplaceAllString(filepath.Base(path), "_"), "._")
+	if name == "" {
+		return defaultLogGroup
+	}
+	return name
+}
+
+func (m *migration) outputsOf(label, tag string) []*cloudWatchOutput {
+	var outputs []*cloudWatchOutput
+	for _, r := range m.routes {
+		if r.label != label || !r.match(tag) {
+			continue
+		}
+		if r.output != nil {
+			outputs = append(outputs, r.output)
+		}
+		if m.firstRouteOnly {
+			break
+		}
+	}
+	return outputs
+}
+
+// blacklist returns the pattern of the file names the tail excludes. The
+// agent matches it against the names of the files only, so an exclude path in
+// another directory than the paths is reported.
+func (m *migration) blacklist(t *tail) string {
+	if len(t.excludePaths) == 0 {
+		return ""
+	}
+	dirs := map[string]bool{}
+	for _, path := range t.paths {
+		dirs[filepath.Dir(path)] = true
+	}
+	patterns := make([]string, 0, len(t.excludePaths))
+	for _, excludePath := range t.excludePaths {
+		if dir := filepath.Dir(excludePath); dir != "." && !dirs[dir] {
+			m.report(t.section, "exclude_path", fmt.Sprintf("%s is applied to the file names in all the paths", excludePath))
+		}
+		patterns = append(patterns, globRegexp(filepath.Base(excludePath)))
+	}
+	return "^(?:" + strings.Join(patterns, "|") + ")$"
+}
+
+// expandTag replaces the * of the tag of a tail by the path of the file, with
+// its slashes replaced by dots, as Fluent Bit and Fluentd do.
+func expandTag(tag, path string) string {
+	if !strings.Contains(tag, "*") {
+		return tag
+	}
+	return strings.ReplaceAll(tag, "*", strings.ReplaceAll(strings.TrimPrefix(path, "/"), "/", "."))
+}
+
+// globRegexp returns the regexp of a glob of file names.
+func globRegexp(glob string) string {
+	var b strings.Builder
+	for _, r := range glob {
+		switch r {
+		case '*':
+			b.WriteString(".*")
+		case '?':
+			b.WriteString(".")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	return b.String()
+}
+
+// multiLinePresets are the built-in multiline parsers of Fluent Bit that the
+// agent has a preset for.
+var multiLinePresets = map[string]string{
+	"go":     "go",
+	"java":   "java",
+	"python": "python",
+	"ruby":   "ruby",
+}
+
+// setMultiLinePreset sets the preset of the first multiline parser that has
+// one, and reports the others.
+func (m *migration) setMultiLinePreset(t *tail, section, key string, parsers []string) {
+	for _, parser := range parsers {
+		preset, ok := multiLinePresets[strings.ToLower(parser)]
+		switch {
+		case !ok:
+			m.report(section, key, fmt.Sprintf("the agent has no preset for the multiline parser %s", parser))
+		case t.multiLinePreset != "" || t.multiLineStartPattern != "":
+			m.report(section, key, fmt.Sprintf("the multiline parser %s is not used, a file has only one multiline rule", parser))
+		default:
+			t.multiLinePreset = preset
+		}
+	}
+}
+
+// setMultiLineStartPattern sets the regexp of the first line of the log
+// events, which is reported if Go cannot compile it.
+func (m *migration) setMultiLineStartPattern(t *tail, section, key, pattern string) {
+	if _, err := regexp.Compile(pattern); err != nil {
+		m.report(section, key, fmt.Sprintf("the regexp is not supported by the agent: %v", err))
+		return
+	}
+	if t.multiLinePreset != "" || t.multiLineStartPattern != "" {
+		m.report(section, key, "not used, a file has only one multiline rule")
+		return
+	}
+	t.multiLineStartPattern = pattern
+}
+
+// setTimestampFormat sets the timestamp format of a strptime time format, if
+// its directives are supported by the agent.
+func (m *migration) setTimestampFormat(t *tail, section, key, format string) {
+	if timestampFormat, ok := timestampFormat(format); ok {
+		t.timestampFormat = timestampFormat
+	} else {
+		m.report(section, key, fmt.Sprintf("the time format %s is not supported by the agent", format))
+	}
+}
+
+var (
+	fractionalSeconds = regexp.MustCompile(`\.%[369]?[LN]`)
+	timeDirective     = regexp.MustCompile(`%-?.`)
+	// timestampDirectives are the directives of the timestamp_format of the
+	// agent.
+	timestampDirectives = map[string]bool{
+		"%B": true, "%b": true, "%-m": true, "%m": true, "%A": true, "%a": true,
+		"%-d": true, "%d": true, "%H": true, "%-I": true, "%I": true, "%-M": true,
+		"%M": true, "%-S": true, "%S": true, "%Y": true, "%y": true, "%p": true,
+		"%Z": true, "%z": true, "%f": true,
+	}
+)
+
+func timestampFormat(format string) (string, bool) {
+	format = fractionalSeconds.ReplaceAllString(format, "%f")
+	format = strings.NewReplacer("%e", "%d", "%T", "%H:%M:%S", "%F", "%Y-%m-%d").Replace(format)
+	for _, directive := range timeDirective.FindAllString(format, -1) {
+		if !timestampDirectives[directive] {
+			return "", false
+		}
+	}
+	return format, true
+}
+
+// retention returns the retention of the log group, or -1 if it is not set
+// or is not valid.
+func (m *migration) retention(section, key, value string) int {
+	for _, valid := range translator.ValidRetentionInDays {
+		if value == valid {
+			retention, _ := strconv.Atoi(value)
+			return retention
+		}
+	}
+	m.report(section, key, fmt.Sprintf("%s is not a valid retention of CloudWatch Logs", value))
+	return -1
+}
+
+// logGroupOrStreamName returns the name, or reports it if it is a template
+// the agent cannot fill in.
+func (m *migration) logGroupOrStreamName(section, key, name string) string {
+	if strings.Contains(name, "$") {
+		m.report(section, key, fmt.Sprintf("the agent cannot fill in the template %s", name))
+		return ""
+	}
+	return name
+}
@@ -11,7 +11,7 @@ import (
 	"github.com/aws/amazon-cloudwatch-agent/tool/data"
 	"github.com/aws/amazon-cloudwatch-agent/tool/data/config"
 	"github.com/aws/amazon-cloudwatch-agent/tool/processors"
-	"github.com/aws/amazon-cloudwatch-agent/tool/processors/question/logs"
+	"github.com/aws/amazon-cloudwatch-agent/tool/processors/migration/fluent"
 	"github.com/aws/amazon-cloudwatch-agent/tool/runtime"
 	"github.com/aws/amazon-cloudwatch-agent/tool/util"
 )
@@ -28,17 +28,17 @@ var Processor processors.Processor = &processor{}
 type processor struct{}
 
 func (p *processor) Process(ctx *runtime.Context, config *data.Config) {
-	if ctx.HasExistingLinuxConfig || util.No(anyExistingLinuxConfigQuestion) {
+	if ctx.HasExistingLinuxConfig || util.No("migration.awslogs", anyExistingLinuxConfigQuestion) {
 		filePath := ctx.ConfigFilePath
 		if filePath == "" {
-			filePath = util.AskWithDefault(filePathLinuxConfigQuestion, DefaultFilePathLinuxConfiguration)
+			filePath = util.AskWithDefault("migration.awslogs_path", filePathLinuxConfigQuestion, DefaultFilePathLinuxConfiguration)
 		}
 		processConfigFromPythonConfigParserFile(filePath, config.LogsConf())
 	}
 }
 
 func (p *processor) NextProcessor(ctx *runtime.Context, config *data.Config) interface{} {
-	return logs.Processor
+	return fluent.Processor
 }
 
 func processConfigFromPythonConfigParserFile(filePath string, logsConfig *config.Logs) {
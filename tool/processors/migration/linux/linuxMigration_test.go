@@ -11,7 +11,7 @@ import (
 
 	"github.com/aws/amazon-cloudwatch-agent/tool/data"
 	"github.com/aws/amazon-cloudwatch-agent/tool/data/config"
-	"github.com/aws/amazon-cloudwatch-agent/tool/processors/question/logs"
+	"github.com/aws/amazon-cloudwatch-agent/tool/processors/migration/fluent"
 	"github.com/aws/amazon-cloudwatch-agent/tool/runtime"
 	"github.com/aws/amazon-cloudwatch-agent/tool/testutil"
 	"github.com/aws/amazon-cloudwatch-agent/tool/util"
@@ -69,23 +69,23 @@ func TestProcessor_Process(t *testing.T) {
 }
 
 func TestProcessor_NextProcessor(t *testing.T) {
-	assert.Equal(t, logs.Processor, Processor.NextProcessor(nil, nil))
+	assert.Equal(t, fluent.Processor, Processor.NextProcessor(nil, nil))
 }
 
 func TestAnyExistingLogAgentConfigFileToImport(t *testing.T) {
 	inputChan := testutil.SetUpTestInputStream()
 
 	testutil.Type(inputChan, "", "1")
-	assert.Equal(t, false, util.No(anyExistingLinuxConfigQuestion))
-	assert.Equal(t, true, util.No(anyExistingLinuxConfigQuestion))
+	assert.Equal(t, false, util.No("migration.awslogs", anyExistingLinuxConfigQuestion))
+	assert.Equal(t, true, util.No("migration.awslogs", anyExistingLinuxConfigQuestion))
 }
 
 func TestFilePathForTheExistingConfigFile(t *testing.T) {
 	inputChan := testutil.SetUpTestInputStream()
 
 	testutil.Type(inputChan, "", "/var/test.conf")
-	assert.Equal(t, "/var/awslogs/etc/awslogs.conf", util.AskWithDefault(filePathLinuxConfigQuestion, DefaultFilePathLinuxConfiguration))
-	assert.Equal(t, "/var/test.conf", util.AskWithDefault(filePathLinuxConfigQuestion, DefaultFilePathLinuxConfiguration))
+	assert.Equal(t, "/var/awslogs/etc/awslogs.conf", util.AskWithDefault("migration.awslogs_path", filePathLinuxConfigQuestion, DefaultFilePathLinuxConfiguration))
+	assert.Equal(t, "/var/test.conf", util.AskWithDefault("migration.awslogs_path", filePathLinuxConfigQuestion, DefaultFilePathLinuxConfiguration))
 }
 
 func TestProcessConfigFromPythonConfigParserFile(t *testing.T) {
@@ -30,7 +30,7 @@ func (p *processor) Process(ctx *runtime.Context, config *data.Config) {
 }
 
 func (p *processor) NextProcessor(ctx *runtime.Context, config *data.Config) interface{} {
-	if util.No(anyExistingLinuxConfigQuestion) {
+	if util.No("migration.awslogs", anyExistingLinuxConfigQuestion) {
 		migrateOldAgentConfig()
 		return ssm.Processor
 	}
@@ -40,7 +40,7 @@ func (p *processor) NextProcessor(ctx *runtime.Context, config *data.Config) int
 func migrateOldAgentConfig() {
 	// 1 - parse the old config
 	var oldConfig OldSsmCwConfig
-	absPath := util.AskWithDefault(filePathWindowsConfigQuestion, DefaultFilePathWindowsConfiguration)
+	absPath := util.AskWithDefault("migration.awslogs_path", filePathWindowsConfigQuestion, DefaultFilePathWindowsConfiguration)
 	if file, err := os.ReadFile(absPath); err == nil {
 		if err := json.Unmarshal(file, &oldConfig); err != nil {
 			fmt.Fprintf(os.Stderr, "Failed to parse the provided configuration file. Error details: %v", err)
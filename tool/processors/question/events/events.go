@@ -44,7 +44,7 @@ func (p *processor) NextProcessor(ctx *runtime.Context, config *data.Config) int
 }
 
 func monitorEvents(ctx *runtime.Context, config *data.Config) {
-	yes := util.Yes(fmt.Sprintf("Do you want to monitor any %s?", WindowsEventLog))
+	yes := util.Yes("events", fmt.Sprintf("Do you want to monitor any %s?", WindowsEventLog))
 	if !yes {
 		return
 	}
@@ -52,12 +52,12 @@ func monitorEvents(ctx *runtime.Context, config *data.Config) {
 	eventFormatDefaultOption := 1
 	for {
 		logsConf := config.LogsConf()
-		eventName := util.AskWithDefault(fmt.Sprintf("%s name:", WindowsEventLog), "System")
+		eventName := util.AskWithDefault("events.name", fmt.Sprintf("%s name:", WindowsEventLog), "System")
 
 		availableEventLevels := []string{VERBOSE, INFORMATION, WARNING, ERROR, CRITICAL}
 		eventLevels := []string{}
 		for _, eventLevel := range availableEventLevels {
-			yes = util.Yes(
+			yes = util.Yes("events.level",
 				fmt.Sprintf("Do you want to monitor %s level events for %s %s ?",
 					eventLevel,
 					WindowsEventLog,
@@ -67,17 +67,17 @@ func monitorEvents(ctx *runtime.Context, config *data.Config) {
 			}
 		}
 
-		logGroupName := util.AskWithDefault("Log group name:", eventName)
+		logGroupName := util.AskWithDefault("events.log_group_name", "Log group name:", eventName)
 
 		logStreamNameHint := "{instance_id}"
 		if ctx.IsOnPrem {
 			logStreamNameHint = "{hostname}"
 		}
 
-		logStreamName := util.AskWithDefault("Log stream name:", logStreamNameHint)
+		logStreamName := util.AskWithDefault("events.log_stream_name", "Log stream name:", logStreamNameHint)
 
 		logGroupDefaultOption := 1
-		logGroupClass := util.Choice("Which log group class would you like to have for this log group?", logGroupDefaultOption, []string{util.StandardLogGroupClass, util.InfrequentAccessLogGroupClass})
+		logGroupClass := util.Choice("events.log_group_class", "Which log group class would you like to have for this log group?", logGroupDefaultOption, []string{util.StandardLogGroupClass, util.InfrequentAccessLogGroupClass})
 		if logGroupClass == util.StandardLogGroupClass {
 			logGroupClass = util.StandardLogGroupClass
 			logGroupDefaultOption = 1
@@ -86,7 +86,7 @@ func monitorEvents(ctx *runtime.Context, config *data.Config) {
 			logGroupDefaultOption = 2
 		}
 
-		eventFormat := util.Choice("In which format do you want to store windows event to CloudWatch Logs?", eventFormatDefaultOption, []string{EventFormatXMLDescription, EventFormatPlainTextDescription})
+		eventFormat := util.Choice("events.format", "In which format do you want to store windows event to CloudWatch Logs?", eventFormatDefaultOption, []string{EventFormatXMLDescription, EventFormatPlainTextDescription})
 		if eventFormat == EventFormatXMLDescription {
 			eventFormat = EventFormatXML
 			eventFormatDefaultOption = 1
@@ -95,7 +95,7 @@ func monitorEvents(ctx *runtime.Context, config *data.Config) {
 			eventFormatDefaultOption = 2
 		}
 		keys := translator.ValidRetentionInDays
-		retentionInDays := util.Choice("Log Group Retention in days", 1, keys)
+		retentionInDays := util.Choice("events.retention", "Log Group Retention in days", 1, keys)
 		retention := -1
 
 		i, err := strconv.Atoi(retentionInDays)
@@ -104,7 +104,7 @@ func monitorEvents(ctx *runtime.Context, config *data.Config) {
 		}
 		logsConf.AddWindowsEvent(eventName, logGroupName, logStreamName, eventFormat, eventLevels, retention, logGroupClass)
 
-		yes = util.Yes(fmt.Sprintf("Do you want to specify any additional %s to monitor?", WindowsEventLog))
+		yes = util.Yes("events.more", fmt.Sprintf("Do you want to specify any additional %s to monitor?", WindowsEventLog))
 		if !yes {
 			return
 		}
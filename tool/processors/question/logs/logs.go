@@ -40,24 +40,24 @@ func monitorLogs(ctx *runtime.Context, config *data.Config) {
 	} else {
 		question = "Do you want to monitor any log files?"
 	}
-	yes := util.Yes(question)
+	yes := util.Yes("logs", question)
 	if !yes {
 		return
 	}
 	for {
 		logsConf := config.LogsConf()
-		logFilePath := util.Ask("Log file path:")
+		logFilePath := util.Ask("logs.file_path", "Log file path:")
 		logGroupNameHint := strings.Replace(filepath.Base(logFilePath), " ", "_", -1)
-		logGroupName := util.AskWithDefault("Log group name:", logGroupNameHint)
-		logGroupClass := util.Choice("Log group class:", 1, []string{util.StandardLogGroupClass, util.InfrequentAccessLogGroupClass})
+		logGroupName := util.AskWithDefault("logs.log_group_name", "Log group name:", logGroupNameHint)
+		logGroupClass := util.Choice("logs.log_group_class", "Log group class:", 1, []string{util.StandardLogGroupClass, util.InfrequentAccessLogGroupClass})
 		logStreamNameHint := "{instance_id}"
 		if ctx.IsOnPrem {
 			logStreamNameHint = "{hostname}"
 		}
-		logStreamName := util.AskWithDefault("Log stream name:", logStreamNameHint)
+		logStreamName := util.AskWithDefault("logs.log_stream_name", "Log stream name:", logStreamNameHint)
 
 		keys := translator.ValidRetentionInDays
-		retentionInDays := util.Choice("Log Group Retention in days", 1, keys)
+		retentionInDays := util.Choice("logs.retention", "Log Group Retention in days", 1, keys)
 		retention := -1
 
 		i, err := strconv.Atoi(retentionInDays)
@@ -65,7 +65,7 @@ func monitorLogs(ctx *runtime.Context, config *data.Config) {
 			retention = i
 		}
 		logsConf.AddLogFile(logFilePath, logGroupName, logStreamName, "", "", "", "", retention, logGroupClass)
-		yes = util.Yes("Do you want to specify any additional log files to monitor?")
+		yes = util.Yes("logs.more", "Do you want to specify any additional log files to monitor?")
 		if !yes {
 			return
 		}
@@ -40,24 +40,24 @@ func monitorMetrics(ctx *runtime.Context, config *data.Config) {
 
 func monitorWindowsMetrics(ctx *runtime.Context, config *data.Config) {
 	metrics := config.MetricsConf().Collection()
-	yes := util.Yes("Do you want to monitor processor status?")
+	yes := util.Yes("metrics.processor", "Do you want to monitor processor status?")
 	if yes {
 		metrics.WinProcessor = new(windows.Processor)
 		metrics.WinProcessor.Enable()
 	}
-	yes = util.Yes("Do you want to monitor memory status?")
+	yes = util.Yes("metrics.memory", "Do you want to monitor memory status?")
 	if yes {
 		metrics.WinMemory = new(windows.Memory)
 		metrics.WinMemory.Enable()
 	}
-	yes = util.Yes("Do you want to monitor disk status?")
+	yes = util.Yes("metrics.disk", "Do you want to monitor disk status?")
 	if yes {
 		metrics.WinLogicalDisk = new(windows.LogicalDisk)
 		metrics.WinLogicalDisk.Enable()
 		metrics.WinPhysicalDisk = new(windows.PhysicalDisk)
 		metrics.WinPhysicalDisk.Enable()
 	}
-	yes = util.Yes("Do you want to monitor network status?")
+	yes = util.Yes("metrics.network", "Do you want to monitor network status?")
 	if yes {
 		metrics.WinNetworkInterface = new(windows.NetworkInterface)
 		metrics.WinNetworkInterface.Enable()
@@ -66,7 +66,7 @@ func monitorWindowsMetrics(ctx *runtime.Context, config *data.Config) {
 		metrics.WinTCPv6 = new(windows.TCPv6)
 		metrics.WinTCPv6.Enable()
 	}
-	yes = util.Yes("Do you want to monitor paging file status?")
+	yes = util.Yes("metrics.paging_file", "Do you want to monitor paging file status?")
 	if yes {
 		metrics.WinPagingFile = new(windows.PagingFile)
 		metrics.WinPagingFile.Enable()
@@ -75,31 +75,31 @@ func monitorWindowsMetrics(ctx *runtime.Context, config *data.Config) {
 
 func monitorLinuxMetrics(ctx *runtime.Context, config *data.Config) {
 	metrics := config.MetricsConf().Collection()
-	yes := util.Yes("Do you want to monitor CPU status?")
+	yes := util.Yes("metrics.cpu", "Do you want to monitor CPU status?")
 	if yes {
 		metrics.CPU = new(linux.CPU)
 		metrics.CPU.Enable()
 	}
-	yes = util.Yes("Do you want to monitor memory status?")
+	yes = util.Yes("metrics.memory", "Do you want to monitor memory status?")
 	if yes {
 		metrics.Memory = new(linux.Memory)
 		metrics.Memory.Enable()
 	}
-	yes = util.Yes("Do you want to monitor disk status?")
+	yes = util.Yes("metrics.disk", "Do you want to monitor disk status?")
 	if yes {
 		metrics.Disk = new(linux.Disk)
 		metrics.Disk.Enable()
 		metrics.DiskIO = new(linux.DiskIO)
 		metrics.DiskIO.Enable()
 	}
-	yes = util.Yes("Do you want to monitor network status?")
+	yes = util.Yes("metrics.network", "Do you want to monitor network status?")
 	if yes {
 		metrics.Net = new(linux.Net)
 		metrics.Net.Enable()
 		metrics.NetStat = new(linux.NetStat)
 		metrics.NetStat.Enable()
 	}
-	yes = util.Yes("Do you want to monitor swap status?")
+	yes = util.Yes("metrics.swap", "Do you want to monitor swap status?")
 	if yes {
 		metrics.Swap = new(linux.Swap)
 		metrics.Swap.Enable()
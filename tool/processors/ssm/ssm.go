@@ -30,7 +30,7 @@ var Processor processors.Processor = &processor{}
 type processor struct{}
 
 func (p *processor) Process(ctx *runtime.Context, config *data.Config) {
-	answer := util.Yes("Do you want to store the config in the SSM parameter store?")
+	answer := util.Yes("ssm", "Do you want to store the config in the SSM parameter store?")
 	if !answer {
 		return
 	}
@@ -42,6 +42,10 @@ func (p *processor) Process(ctx *runtime.Context, config *data.Config) {
 	var err error
 	for i := 0; i <= defaultRetryCount; i++ {
 		creds := determineCreds(ctx)
+		if util.AnswerFileErr() != nil {
+			// Not sent with the defaults of the questions that failed.
+			return
+		}
 		err = sendConfigToParameterStore(serializedConfig, parameterStoreName, region, creds)
 		if err == nil {
 			fmt.Printf("Successfully put config to parameter store %s.\n", parameterStoreName)
@@ -88,7 +92,7 @@ func determineCreds(ctx *runtime.Context) *credentials.Credentials {
 	if len(accessKeys) > 0 {
 		accessKeys = append(accessKeys, "Other")
 
-		answer := util.Choice("Which AWS credential should be used to send json config to parameter store?", 1, accessKeys)
+		answer := util.Choice("ssm.credentials", "Which AWS credential should be used to send json config to parameter store?", 1, accessKeys)
 		if answer == sdkAccessKeyDesc {
 			return sdkCreds
 		} else if answer == fileAccessKeyDesc {
@@ -99,15 +103,15 @@ func determineCreds(ctx *runtime.Context) *credentials.Credentials {
 }
 
 func askCreds() *credentials.Credentials {
-	accessKey := util.Ask("Please provide credentials to upload the json config file to parameter store.\nAWS Access Key:")
-	secretKey := util.Ask("AWS Secret Key:")
+	accessKey := util.Ask("ssm.access_key", "Please provide credentials to upload the json config file to parameter store.\nAWS Access Key:")
+	secretKey := util.Ask("ssm.secret_key", "AWS Secret Key:")
 	creds := credentials.NewStaticCredentials(accessKey, secretKey, "")
 	return creds
 }
 
 func determineParameterStoreName(ctx *runtime.Context) string {
 	defaultParameterStoreName := "AmazonCloudWatch-" + ctx.OsParameter
-	parameterStoreName := util.AskWithDefault("What parameter store name do you want to use to store your config? (Use 'AmazonCloudWatch-' prefix if you use our managed AWS policy)", defaultParameterStoreName)
+	parameterStoreName := util.AskWithDefault("ssm.parameter_name", "What parameter store name do you want to use to store your config? (Use 'AmazonCloudWatch-' prefix if you use our managed AWS policy)", defaultParameterStoreName)
 	return parameterStoreName
 }
 
@@ -121,7 +125,7 @@ func determineRegion(ctx *runtime.Context) string {
 	if region == "" {
 		region = "us-east-1"
 	}
-	region = util.AskWithDefault("Which region do you want to store the config in the parameter store?", region)
+	region = util.AskWithDefault("ssm.region", "Which region do you want to store the config in the parameter store?", region)
 	return region
 }
 
@@ -19,7 +19,7 @@ var Processor processors.Processor = &processor{}
 type processor struct{}
 
 func (p *processor) Process(ctx *runtime.Context, config *data.Config) {
-	yes := util.Yes("Do you want to turn on StatsD daemon?")
+	yes := util.Yes("statsd", "Do you want to turn on StatsD daemon?")
 	if yes {
 		collection := config.MetricsConf().Collection()
 		collection.StatsD = new(statsd.StatsD)
@@ -34,18 +34,18 @@ func (p *processor) NextProcessor(ctx *runtime.Context, config *data.Config) int
 }
 
 func whichPort(config *statsd.StatsD) {
-	answer := util.AskWithDefault("Which port do you want StatsD daemon to listen to?", "8125")
+	answer := util.AskWithDefault("statsd.port", "Which port do you want StatsD daemon to listen to?", "8125")
 	answer = ":" + answer
 	config.ServiceAddress = answer
 }
 
 func whichMetricsCollectionInterval(config *statsd.StatsD) {
-	answer := util.Choice("What is the collect interval for StatsD daemon?", 1, []string{"10s", "30s", "60s"})
+	answer := util.Choice("statsd.collect_interval", "What is the collect interval for StatsD daemon?", 1, []string{"10s", "30s", "60s"})
 	config.MetricsCollectionInterval, _ = strconv.Atoi(answer[:2])
 }
 
 func whichMetricsAggregationInterval(config *statsd.StatsD) {
-	answer := util.Choice("What is the aggregation interval for metrics collected by StatsD daemon?",
+	answer := util.Choice("statsd.aggregation_interval", "What is the aggregation interval for metrics collected by StatsD daemon?",
 		4, []string{"Do not aggregate", "10s", "30s", "60s"})
 	if answer != "Do not aggregate" {
 		config.MetricsAggregationInterval, _ = strconv.Atoi(answer[:2])
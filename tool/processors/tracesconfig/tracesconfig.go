@@ -33,7 +33,7 @@ func (p *processor) Process(ctx *runtime.Context, cfg *data.Config) {
 	}
 
 	if !ctx.TracesOnly {
-		yes := util.Yes("Do you want the CloudWatch agent to also retrieve X-ray traces?")
+		yes := util.Yes("traces", "Do you want the CloudWatch agent to also retrieve X-ray traces?")
 		if !yes {
 			return
 		}
@@ -73,7 +73,7 @@ func userBuildsTracesConfig(tracesConfig *config.Traces) *config.Traces {
 }
 
 func whichUDPPort(tracesConfig *config.Traces) {
-	answer := util.AskWithDefault("Which UDP port do you want XRay daemon to listen to?", "2000")
+	answer := util.AskWithDefault("traces.udp_port", "Which UDP port do you want XRay daemon to listen to?", "2000")
 	num, err := strconv.Atoi(answer)
 	if err != nil || num < 0 {
 		tracesConfig.TracesCollected.Xray.BindAddress = addr + ":2000"
@@ -83,7 +83,7 @@ func whichUDPPort(tracesConfig *config.Traces) {
 
 }
 func whichTCPPort(tracesConfig *config.Traces) {
-	answer := util.AskWithDefault("Which TCP port do you want XRay daemon to listen to?", "2000")
+	answer := util.AskWithDefault("traces.tcp_port", "Which TCP port do you want XRay daemon to listen to?", "2000")
 	num, err := strconv.Atoi(answer)
 	if err != nil || num < 0 {
 		tracesConfig.TracesCollected.Xray.BindAddress = addr + ":2000"
@@ -94,7 +94,7 @@ func whichTCPPort(tracesConfig *config.Traces) {
 }
 
 func chooseBufferSize(tracesConfig *config.Traces) {
-	answer := util.AskWithDefault("Enter Total Buffer Size in MB (minimum 3)", "3")
+	answer := util.AskWithDefault("traces.buffer_size_mb", "Enter Total Buffer Size in MB (minimum 3)", "3")
 	bufferSize, err := strconv.Atoi(answer)
 	if err != nil || bufferSize < 3 {
 		fmt.Println("Buffer size set to 3 because input smaller than 3 or not a number")
@@ -104,7 +104,7 @@ func chooseBufferSize(tracesConfig *config.Traces) {
 }
 
 func chooseConcurrency(tracesConfig *config.Traces) {
-	answer := util.AskWithDefault("Enter the maximum number of concurrent calls to AWS X-Ray to upload segment documents: ", "8")
+	answer := util.AskWithDefault("traces.concurrency", "Enter the maximum number of concurrent calls to AWS X-Ray to upload segment documents: ", "8")
 	concurrency, err := strconv.Atoi(answer)
 	if err != nil || concurrency < 0 {
 		fmt.Println("Concurrency set to default value of 8 because input smaller than 0 or not a number")
@@ -114,7 +114,7 @@ func chooseConcurrency(tracesConfig *config.Traces) {
 }
 
 func chooseRegion(tracesConfig *config.Traces) {
-	answer := util.Ask("Enter the AWS Region to send segments to AWS X-Ray service (Optional)")
+	answer := util.Ask("traces.region", "Enter the AWS Region to send segments to AWS X-Ray service (Optional)")
 	tracesConfig.RegionOverride = answer
 
 }
@@ -138,7 +138,7 @@ func updateUserConfig(tracesConfig *config.Traces) *config.Traces {
 		for i := 0; i < len(fieldOptions); i++ {
 			fmt.Println(fieldOptions[i])
 		}
-		answer := util.Ask("")
+		answer := util.Ask("traces.update_field", "")
 		if answer == "" {
 			//Exit if user does not input anything
 			break
@@ -154,10 +154,10 @@ func updateUserConfig(tracesConfig *config.Traces) *config.Traces {
 		}
 		switch option {
 		case 1, 2, 5, 8, 9, 10, 11:
-			newValue := util.Ask("Enter value you would like to update to: (Enter nothing to remove)")
+			newValue := util.Ask("traces.update_value", "Enter value you would like to update to: (Enter nothing to remove)")
 			updateStringValueInConfig(tracesConfig, option, newValue)
 		case 3, 4:
-			answer := util.Ask("Enter value you would like to update to: (Enter nothing to remove)")
+			answer := util.Ask("traces.update_value", "Enter value you would like to update to: (Enter nothing to remove)")
 
 			newValue, err := strconv.Atoi(answer)
 			if err != nil {
@@ -168,7 +168,7 @@ func updateUserConfig(tracesConfig *config.Traces) *config.Traces {
 			}
 
 		case 6, 7:
-			answer := util.Ask("Enter value you would like to update to: (Enter nothing to remove)")
+			answer := util.Ask("traces.update_value", "Enter value you would like to update to: (Enter nothing to remove)")
 			newValue, err := strconv.ParseBool(answer)
 			if err != nil {
 				fmt.Println("Wrong Input! Input has go be a bool")
@@ -279,7 +279,7 @@ func generateTracesConfiguration(ctx *runtime.Context) (*config.Traces, error) {
 		return nil, err
 	}
 	if len(processes) == 0 {
-		yes := util.Yes(anyExistingDaemonConfiguration)
+		yes := util.Yes("traces.daemon", anyExistingDaemonConfiguration)
 		if yes {
 			return askUserInput(tracesFile, nil, yes)
 		} else { //user can build config if they do not have a traces file
@@ -291,7 +291,7 @@ func generateTracesConfiguration(ctx *runtime.Context) (*config.Traces, error) {
 	var chosenProcess xraydaemonmigration.Process
 	if len(processes) > 1 {
 		cmdlines := getCmdlines(processes)
-		chosenCmdlineIndex := util.ChoiceIndex("Multiple active X-Ray Daemons detected.\nWhich of the configurations would you like to import?", 1, cmdlines)
+		chosenCmdlineIndex := util.ChoiceIndex("traces.daemon_cmdline", "Multiple active X-Ray Daemons detected.\nWhich of the configurations would you like to import?", 1, cmdlines)
 		chosenProcess = processes[chosenCmdlineIndex]
 	} else {
 		fmt.Println("Detected X-Ray Daemon. The wizard will now attempt to import its configuration.")
@@ -323,7 +323,7 @@ func generateTracesConfiguration(ctx *runtime.Context) (*config.Traces, error) {
 	//incorrect configFilePath, user can decide to give path of default config will be used
 	if err != nil {
 		fmt.Println("Unable to import configuration from Detected Daemon")
-		yes := util.Yes(anyExistingDaemonConfiguration)
+		yes := util.Yes("traces.daemon", anyExistingDaemonConfiguration)
 		return askUserInput(tracesFile, chosenProcess, yes)
 	}
 	return xraydaemonmigration.ConvertYamlToJson(yamlFile, chosenProcess)
@@ -332,7 +332,7 @@ func generateTracesConfiguration(ctx *runtime.Context) (*config.Traces, error) {
 func askUserInput(tracesFile *config.Traces, chosenProcess xraydaemonmigration.Process, userHasImportConfig bool) (*config.Traces, error) {
 
 	if userHasImportConfig {
-		configFilePath := util.Ask(filePathXrayConfigQuestion)
+		configFilePath := util.Ask("traces.daemon_config_path", filePathXrayConfigQuestion)
 		yamlFile, err := os.ReadFile(configFilePath)
 		//error reading filepath given by user, using default config
 		if err != nil {
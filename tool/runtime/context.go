@@ -16,6 +16,9 @@ type Context struct {
 	HasExistingLinuxConfig bool
 	ConfigFilePath         string
 
+	//fluent bit and fluentd migration
+	HasExistingFluentConfig bool
+
 	//windows migration
 	WindowsNonInteractiveMigration bool
 
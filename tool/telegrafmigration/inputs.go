@@ -0,0 +1,383 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package telegrafmigration
+
+import (
+	"fmt"
+	"maps"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/aws/amazon-cloudwatch-agent/tool/util"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/metrics/config"
+)
+
+// inputConverters convert the options specific to each telegraf input that the
+// agent has a plugin of, under the same name in metrics_collected. They return
+// false if the input is not converted.
+var inputConverters = map[string]func(in *input) bool{
+	"cpu":        convertCPU,
+	"disk":       convertDisk,
+	"diskio":     convertDiskIO,
+	"ethtool":    convertEthtool,
+	"mem":        convertBasic,
+	"net":        convertNet,
+	"netstat":    convertBasic,
+	"nvidia_smi": convertNvidiaSmi,
+	"processes":  convertBasic,
+	"procstat":   convertProcstat,
+	"statsd":     convertStatsd,
+	"swap":       convertBasic,
+}
+
+// An input is the conversion of a telegraf input to a plugin of the agent.
+type input struct {
+	c       *conversion
+	name    string
+	section string
+	options map[string]interface{}
+	plugin  map[string]interface{}
+	// converted are the options that are converted, or dropped since the agent
+	// already does what they ask for.
+	converted map[string]bool
+}
+
+func (c *conversion) convertInput(name string, options map[string]interface{}) {
+	section := fmt.Sprintf("[[inputs.%s]]", name)
+	if name == "prometheus" {
+		c.convertPrometheus(section, options)
+		return
+	}
+	converter, ok := inputConverters[name]
+	if !ok {
+		c.report(section, "", "the agent has no equivalent of the input")
+		return
+	}
+	// procstat is the only plugin of the agent that has a list of instances.
+	if _, ok := c.metricsCollected[name]; ok && name != "procstat" {
+		c.report(section, "", "only the first instance of the input is converted")
+		return
+	}
+	in := &input{
+		c:         c,
+		name:      name,
+		section:   section,
+		options:   options,
+		plugin:    map[string]interface{}{},
+		converted: map[string]bool{},
+	}
+	if !converter(in) {
+		return
+	}
+	for _, key := range slices.Sorted(maps.Keys(options)) {
+		if !in.converted[key] {
+			c.report(section, key, "has no equivalent in the agent config")
+		}
+	}
+	if name == "procstat" {
+		procstats, _ := c.metricsCollected[name].([]map[string]interface{})
+		c.metricsCollected[name] = append(procstats, in.plugin)
+	} else {
+		c.metricsCollected[name] = in.plugin
+	}
+}
+
+func convertBasic(in *input) bool {
+	return in.convertCommon(fields(in.name))
+}
+
+func convertCPU(in *input) bool {
+	if in.bool("percpu", true) {
+		in.plugin[util.MapKeyInstances] = []string{"*"}
+	}
+	in.plugin["totalcpu"] = in.bool("totalcpu", true)
+	collectCPUTime := in.bool("collect_cpu_time", false)
+	reportActive := in.bool("report_active", false)
+	in.drop("core_tags", false)
+	// The translator turns the time and active fields of the measurement back
+	// into collect_cpu_time and report_active.
+	var cpuFields []string
+	for _, field := range fields(in.name) {
+		if (collectCPUTime || strings.HasPrefix(field, "usage_")) && (reportActive || !strings.HasSuffix(field, "_active")) {
+			cpuFields = append(cpuFields, field)
+		}
+	}
+	return in.convertCommon(cpuFields)
+}
+
+func convertDisk(in *input) bool {
+	in.copyStrings("mount_points", util.MapKeyInstances)
+	in.copyStrings("ignore_fs", "ignore_file_system_types")
+	if tags, ok := in.strings("tagexclude"); ok {
+		for _, tag := range tags {
+			switch tag {
+			case "device":
+				in.plugin["drop_device"] = true
+			case "mode":
+				// The agent always drops the mode tag of disk.
+			default:
+				in.c.report(in.section, "tagexclude", fmt.Sprintf("the agent only drops the device tag, not %s", tag))
+			}
+		}
+	}
+	return in.convertCommon(fields(in.name))
+}
+
+func convertDiskIO(in *input) bool {
+	in.copyStrings("devices", util.MapKeyInstances)
+	in.drop("skip_serial_number", true)
+	return in.convertCommon(fields(in.name))
+}
+
+func convertNet(in *input) bool {
+	in.copyStrings("interfaces", util.MapKeyInstances)
+	// The protocol stats are not among the fields the agent collects.
+	in.drop("ignore_protocol_stats", nil)
+	return in.convertCommon(fields(in.name))
+}
+
+func convertProcstat(in *input) bool {
+	for _, key := range []string{"pid_file", "exe", "pattern"} {
+		in.copyString(key, key)
+	}
+	if len(in.plugin) == 0 {
+		in.c.report(in.section, "", "the agent only finds the processes by pid_file, exe or pattern")
+		return false
+	}
+	in.drop("pid_finder", nil)
+	return in.convertCommon(fields(in.name))
+}
+
+func convertNvidiaSmi(in *input) bool {
+	in.drop("bin_path", "/usr/bin/nvidia-smi")
+	in.drop("timeout", nil)
+	return in.convertCommon(fields(in.name))
+}
+
+// convertEthtool converts the input of the ethtool plugin, whose fields depend
+// on the driver of the interfaces, so the field filter is kept as it is.
+func convertEthtool(in *input) bool {
+	in.copyStrings("interface_include", "interface_include")
+	in.copyStrings("interface_exclude", "interface_exclude")
+	in.copyStrings("fieldpass", "metrics_include")
+	in.copyStrings("fieldinclude", "metrics_include")
+	in.drop("down_interfaces", "expose")
+	if _, ok := in.take("interval"); ok {
+		in.c.report(in.section, "interval", "the agent collects ethtool at the interval of the agent")
+	}
+	in.dimensions()
+	return true
+}
+
+func convertStatsd(in *input) bool {
+	address := "service_address"
+	if protocol, ok := in.string("protocol"); ok && strings.HasPrefix(protocol, "tcp") {
+		address = "tcp_service_address"
+	}
+	in.copyString("service_address", address)
+	in.copyString("metric_separator", "metric_separator")
+	in.copyInt("allowed_pending_messages", "allowed_pending_messages")
+	in.copyInt("max_tcp_connections", "max_tcp_connections")
+	in.drop("datadog_extensions", true)
+	in.drop("parse_data_dog_tags", true)
+	in.interval()
+	if len(in.c.globalTags) > 0 {
+		in.c.report("[global_tags]", "", "the agent does not add them to the statsd metrics")
+	}
+	return true
+}
+
+// fields returns the fields of an input that the agent collects. The EBS
+// fields of diskio are collected from the NVMe devices rather than by the
+// input, so they are left out.
+func fields(name string) []string {
+	var inputFields []string
+	for _, field := range config.Registered_Metrics_Linux[name] {
+		if name == "diskio" && strings.HasPrefix(field, "ebs_") || slices.Contains(inputFields, field) {
+			continue
+		}
+		inputFields = append(inputFields, field)
+	}
+	return inputFields
+}
+
+// convertCommon converts the fields, the interval and the tags of the input,
+// which all the plugins that have a measurement share.
+func (in *input) convertCommon(inputFields []string) bool {
+	if !in.measurement(inputFields) {
+		return false
+	}
+	in.interval()
+	in.dimensions()
+	return true
+}
+
+// measurement sets the measurement of the plugin to the fields of the input
+// that pass its field filters. It returns false if none of them do.
+func (in *input) measurement(inputFields []string) bool {
+	for _, key := range []string{"fieldpass", "fieldinclude"} {
+		if patterns, ok := in.strings(key); ok {
+			inputFields = in.filterFields(key, inputFields, patterns, true)
+		}
+	}
+	for _, key := range []string{"fielddrop", "fieldexclude"} {
+		if patterns, ok := in.strings(key); ok {
+			inputFields = in.filterFields(key, inputFields, patterns, false)
+		}
+	}
+	if len(inputFields) == 0 {
+		in.c.report(in.section, "", "the agent collects none of the fields of the input")
+		return false
+	}
+	in.plugin[util.MapKeyMeasurement] = inputFields
+	return true
+}
+
+// filterFields keeps the fields that match any of the glob patterns if pass is
+// set, or the ones that match none of them otherwise. A pattern to pass that
+// matches no field is reported, since the agent cannot collect what it names.
+func (in *input) filterFields(key string, inputFields, patterns []string, pass bool) []string {
+	matched := map[string]bool{}
+	var filtered []string
+	for _, field := range inputFields {
+		match := false
+		for _, pattern := range patterns {
+			if ok, _ := path.Match(pattern, field); ok {
+				matched[pattern] = true
+				match = true
+			}
+		}
+		if match == pass {
+			filtered = append(filtered, field)
+		}
+	}
+	if pass {
+		for _, pattern := range patterns {
+			if !matched[pattern] {
+				in.c.report(in.section, key, fmt.Sprintf("%s matches none of the fields that the agent collects", pattern))
+			}
+		}
+	}
+	return filtered
+}
+
+// interval sets the collection interval of the plugin to the one of the input.
+func (in *input) interval() {
+	if value, ok := in.take("interval"); ok {
+		if seconds, ok := in.c.seconds(in.section, "interval", value); ok {
+			in.plugin[util.MapKeyMetricsCollectionInterval] = seconds
+		}
+	}
+}
+
+// dimensions sets the dimensions of the plugin to the global tags, and to the
+// tags of the input, which take precedence.
+func (in *input) dimensions() {
+	dimensions := map[string]interface{}{}
+	for key, value := range in.c.globalTags {
+		dimensions[key] = value
+	}
+	if value, ok := in.take("tags"); ok {
+		tags, _ := value.(map[string]interface{})
+		for _, key := range slices.Sorted(maps.Keys(tags)) {
+			if s, ok := tags[key].(string); ok {
+				dimensions[key] = s
+			} else {
+				in.c.report(in.section, "tags."+key, fmt.Sprintf("%v is not a string", tags[key]))
+			}
+		}
+	}
+	if len(dimensions) > 0 {
+		in.plugin["append_dimensions"] = dimensions
+	}
+}
+
+// take returns the value of an option, and marks it as converted.
+func (in *input) take(key string) (interface{}, bool) {
+	value, ok := in.options[key]
+	if ok {
+		in.converted[key] = true
+	}
+	return value, ok
+}
+
+// drop marks an option as converted if the agent already does what its value
+// asks for, which it does for any value if value is nil.
+func (in *input) drop(key string, value interface{}) {
+	if v, ok := in.options[key]; ok && (value == nil || v == value) {
+		in.converted[key] = true
+	}
+}
+
+func (in *input) bool(key string, defaultValue bool) bool {
+	value, ok := in.take(key)
+	if !ok {
+		return defaultValue
+	}
+	b, ok := value.(bool)
+	if !ok {
+		in.c.report(in.section, key, fmt.Sprintf("%v is not a boolean", value))
+		return defaultValue
+	}
+	return b
+}
+
+func (in *input) string(key string) (string, bool) {
+	value, ok := in.take(key)
+	if !ok {
+		return "", false
+	}
+	s, ok := value.(string)
+	if !ok {
+		in.c.report(in.section, key, fmt.Sprintf("%v is not a string", value))
+	}
+	return s, ok && s != ""
+}
+
+func (in *input) strings(key string) ([]string, bool) {
+	value, ok := in.take(key)
+	if !ok {
+		return nil, false
+	}
+	list, ok := value.([]interface{})
+	ss := make([]string, 0, len(list))
+	for _, item := range list {
+		s, isString := item.(string)
+		if !isString {
+			ok = false
+			break
+		}
+		ss = append(ss, s)
+	}
+	if !ok {
+		in.c.report(in.section, key, fmt.Sprintf("%v is not a list of strings", value))
+		return nil, false
+	}
+	return ss, len(ss) > 0
+}
+
+func (in *input) copyString(key, pluginKey string) {
+	if s, ok := in.string(key); ok {
+		in.plugin[pluginKey] = s
+	}
+}
+
+func (in *input) copyStrings(key, pluginKey string) {
+	if ss, ok := in.strings(key); ok {
+		in.plugin[pluginKey] = ss
+	}
+}
+
+func (in *input) copyInt(key, pluginKey string) {
+	value, ok := in.take(key)
+	if !ok {
+		return
+	}
+	if i, ok := value.(int64); ok && i > 0 {
+		in.plugin[pluginKey] = int(i)
+	} else {
+		in.c.report(in.section, key, fmt.Sprintf("%v is not a positive integer", value))
+	}
+}
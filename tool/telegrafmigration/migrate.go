@@ -0,0 +1,228 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package telegrafmigration
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/BurntSushi/toml"
+)
+
+// A Setting is a setting of the telegraf config that is not converted,
+// because the agent has no equivalent of it.
+type Setting struct {
+	// Section is the table of the setting, e.g. "[[inputs.cpu]]".
+	Section string
+	// Key is the key of the setting, or empty if it is the whole table.
+	Key    string
+	Reason string
+}
+
+func (s Setting) String() string {
+	if s.Key == "" {
+		return fmt.Sprintf("%s: %s", s.Section, s.Reason)
+	}
+	return fmt.Sprintf("%s %s: %s", s.Section, s.Key, s.Reason)
+}
+
+// telegrafConfig is the part of a telegraf config that is converted. The
+// plugins are kept as tables, since each plugin has its own options.
+type telegrafConfig struct {
+	Agent       map[string]interface{}              `toml:"agent"`
+	GlobalTags  map[string]string                   `toml:"global_tags"`
+	Inputs      map[string][]map[string]interface{} `toml:"inputs"`
+	Outputs     map[string][]map[string]interface{} `toml:"outputs"`
+	Processors  map[string][]map[string]interface{} `toml:"processors"`
+	Aggregators map[string][]map[string]interface{} `toml:"aggregators"`
+}
+
+// A conversion is what a telegraf config is converted to.
+type conversion struct {
+	agent            map[string]interface{}
+	metrics          map[string]interface{}
+	metricsCollected map[string]interface{}
+	globalTags       map[string]string
+	unconverted      []Setting
+	// prometheusConfigPath is where the Prometheus scrape config of the
+	// prometheus inputs is written, and scrapeConfigs are its scrape configs.
+	prometheusConfigPath string
+	scrapeConfigs        []map[string]interface{}
+}
+
+func (c *conversion) report(section, key, reason string) {
+	c.unconverted = append(c.unconverted, Setting{Section: section, Key: key, Reason: reason})
+}
+
+// Convert converts the inputs of a telegraf config that the agent also has,
+// with the agent settings and the CloudWatch output, to an agent JSON config.
+// The prometheus inputs are converted to a Prometheus scrape config, which the
+// agent config refers to by prometheusConfigPath, or are not converted if it
+// is empty. It returns the settings that are not converted.
+func Convert(content []byte, prometheusConfigPath string) (agentConfig, prometheusConfig map[string]interface{}, unconverted []Setting, err error) {
+	var tc telegrafConfig
+	md, err := toml.Decode(string(content), &tc)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	c := &conversion{
+		agent:                map[string]interface{}{},
+		metrics:              map[string]interface{}{},
+		metricsCollected:     map[string]interface{}{},
+		globalTags:           tc.GlobalTags,
+		prometheusConfigPath: prometheusConfigPath,
+	}
+	c.convertAgent(tc.Agent)
+	for _, name := range slices.Sorted(maps.Keys(tc.Inputs)) {
+		for _, options := range tc.Inputs[name] {
+			c.convertInput(name, options)
+		}
+	}
+	c.convertOutputs(tc.Outputs)
+	for _, name := range slices.Sorted(maps.Keys(tc.Processors)) {
+		c.report(fmt.Sprintf("[[processors.%s]]", name), "", "the processors are not converted")
+	}
+	for _, name := range slices.Sorted(maps.Keys(tc.Aggregators)) {
+		c.report(fmt.Sprintf("[[aggregators.%s]]", name), "", "the aggregators are not converted")
+	}
+	for _, key := range md.Undecoded() {
+		if len(key) == 1 {
+			c.report(fmt.Sprintf("[%s]", key), "", "has no equivalent in the agent config")
+		}
+	}
+
+	agentConfig = map[string]interface{}{}
+	if len(c.agent) > 0 {
+		agentConfig["agent"] = c.agent
+	}
+	if len(c.metricsCollected) > 0 {
+		c.metrics["metrics_collected"] = c.metricsCollected
+		agentConfig["metrics"] = c.metrics
+	}
+	if len(c.scrapeConfigs) > 0 {
+		var plugin map[string]interface{}
+		plugin, prometheusConfig = c.prometheusConfigs()
+		agentConfig["logs"] = map[string]interface{}{
+			"metrics_collected": map[string]interface{}{"prometheus": plugin},
+		}
+	}
+	return agentConfig, prometheusConfig, c.unconverted, nil
+}
+
+// agentDefaults are the values the agent sets the telegraf agent settings to.
+// A setting is dropped if it has the same value, or any value if it is nil.
+var agentDefaults = map[string]interface{}{
+	"collection_jitter":   "0s",
+	"flush_jitter":        "0s",
+	"hostname":            "",
+	"logtarget":           nil,
+	"metric_batch_size":   int64(1000),
+	"metric_buffer_limit": int64(10000),
+	"precision":           "",
+	"quiet":               nil,
+	"round_interval":      false,
+}
+
+func (c *conversion) convertAgent(options map[string]interface{}) {
+	const section = "[agent]"
+	for _, key := range slices.Sorted(maps.Keys(options)) {
+		value := options[key]
+		switch key {
+		case "interval":
+			if seconds, ok := c.seconds(section, key, value); ok {
+				c.agent["metrics_collection_interval"] = seconds
+			}
+		case "flush_interval":
+			if seconds, ok := c.seconds(section, key, value); ok {
+				c.metrics["force_flush_interval"] = seconds
+			}
+		case "debug", "omit_hostname":
+			if b, ok := value.(bool); ok {
+				c.agent[key] = b
+			} else {
+				c.report(section, key, fmt.Sprintf("%v is not a boolean", value))
+			}
+		case "logfile":
+			// The agent logs to stdout rather than stderr if it is empty.
+			if s, ok := value.(string); ok && s != "" {
+				c.agent[key] = s
+			}
+		default:
+			defaultValue, ok := agentDefaults[key]
+			switch {
+			case !ok:
+				c.report(section, key, "has no equivalent in the agent config")
+			case defaultValue != nil && value != defaultValue:
+				c.report(section, key, fmt.Sprintf("the agent sets it to %v", defaultValue))
+			}
+		}
+	}
+}
+
+func (c *conversion) convertOutputs(outputs map[string][]map[string]interface{}) {
+	converted := false
+	for _, name := range slices.Sorted(maps.Keys(outputs)) {
+		for _, options := range outputs[name] {
+			section := fmt.Sprintf("[[outputs.%s]]", name)
+			switch {
+			case name != "cloudwatch":
+				c.report(section, "", "only the CloudWatch output is converted")
+			case converted:
+				c.report(section, "", "only the first CloudWatch output is converted")
+			default:
+				converted = true
+				c.convertCloudWatchOutput(section, options)
+			}
+		}
+	}
+}
+
+func (c *conversion) convertCloudWatchOutput(section string, options map[string]interface{}) {
+	for _, key := range slices.Sorted(maps.Keys(options)) {
+		value := options[key]
+		s, isString := value.(string)
+		switch {
+		case key == "region" && isString:
+			c.agent["region"] = s
+		case key == "namespace" && isString:
+			c.metrics["namespace"] = s
+		case key == "endpoint_url" && isString:
+			c.metrics["endpoint_override"] = s
+		case key == "role_arn" && isString:
+			c.metrics["credentials"] = map[string]interface{}{"role_arn": s}
+		case key == "high_resolution_metrics":
+			if value != false {
+				c.report(section, key, "the agent publishes high resolution metrics for the inputs collected more often than every minute")
+			}
+		default:
+			c.report(section, key, "has no equivalent in the agent config")
+		}
+	}
+}
+
+// seconds returns the whole seconds of a telegraf duration, which is either a
+// string such as "10s" or an integer of seconds.
+func (c *conversion) seconds(section, key string, value interface{}) (int, bool) {
+	var d time.Duration
+	switch v := value.(type) {
+	case string:
+		var err error
+		if d, err = time.ParseDuration(v); err != nil {
+			c.report(section, key, fmt.Sprintf("%s is not a duration", v))
+			return 0, false
+		}
+	case int64:
+		d = time.Duration(v) * time.Second
+	default:
+		c.report(section, key, fmt.Sprintf("%v is not a duration", value))
+		return 0, false
+	}
+	if d < time.Second || d%time.Second != 0 {
+		c.report(section, key, fmt.Sprintf("the agent only supports whole seconds, not %v", value))
+		return 0, false
+	}
+	return int(d / time.Second), true
+}
@@ -0,0 +1,111 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package telegrafmigration
+
+import (
+	"fmt"
+	"maps"
+	"net/url"
+	"slices"
+
+	"github.com/aws/amazon-cloudwatch-agent/tool/util"
+)
+
+// defaultPrometheusLogGroup is the log group of the embedded metric format
+// logs that the prometheus plugin of the agent sends the metrics as.
+const defaultPrometheusLogGroup = "prometheus"
+
+// convertPrometheus converts a prometheus input, such as the one that scrapes
+// node_exporter, to the scrape configs of the Prometheus scrape config of the
+// agent. The endpoints of its urls are the static targets of one scrape config
+// per scheme and metrics path. The agent collects Prometheus metrics in the
+// logs section, and sends them to CloudWatch as embedded metric format logs.
+func (c *conversion) convertPrometheus(section string, options map[string]interface{}) {
+	if c.prometheusConfigPath == "" {
+		c.report(section, "", "the path of the Prometheus scrape config of the agent is not set")
+		return
+	}
+	in := &input{
+		c:         c,
+		name:      "prometheus",
+		section:   section,
+		options:   options,
+		plugin:    map[string]interface{}{},
+		converted: map[string]bool{},
+	}
+	urls, _ := in.strings("urls")
+	var scrapeConfigs []map[string]interface{}
+	for _, rawURL := range urls {
+		u, err := url.Parse(rawURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || u.User != nil || u.RawQuery != "" {
+			c.report(section, "urls", fmt.Sprintf("the agent only scrapes the http or https URLs without credentials or a query, not %s", rawURL))
+			continue
+		}
+		metricsPath := u.Path
+		if metricsPath == "" {
+			metricsPath = "/metrics"
+		}
+		i := slices.IndexFunc(scrapeConfigs, func(scrapeConfig map[string]interface{}) bool {
+			return scrapeConfig["scheme"] == u.Scheme && scrapeConfig["metrics_path"] == metricsPath
+		})
+		if i < 0 {
+			scrapeConfigs = append(scrapeConfigs, map[string]interface{}{
+				"scheme":       u.Scheme,
+				"metrics_path": metricsPath,
+			})
+			i = len(scrapeConfigs) - 1
+		}
+		targets, _ := scrapeConfigs[i]["targets"].([]string)
+		scrapeConfigs[i]["targets"] = append(targets, u.Host)
+	}
+	if len(scrapeConfigs) == 0 {
+		c.report(section, "", "the agent only scrapes the endpoints set by urls")
+		return
+	}
+	// The telegraf metrics have the same fields whatever the version is.
+	in.drop("metric_version", nil)
+	in.interval()
+	in.dimensions()
+	for _, key := range slices.Sorted(maps.Keys(options)) {
+		if !in.converted[key] {
+			c.report(section, key, "has no equivalent in the agent config")
+		}
+	}
+
+	for _, scrapeConfig := range scrapeConfigs {
+		staticConfig := map[string]interface{}{"targets": scrapeConfig["targets"]}
+		delete(scrapeConfig, "targets")
+		if labels, ok := in.plugin["append_dimensions"]; ok {
+			staticConfig["labels"] = labels
+		}
+		scrapeConfig["static_configs"] = []map[string]interface{}{staticConfig}
+		if seconds, ok := in.plugin[util.MapKeyMetricsCollectionInterval]; ok {
+			scrapeConfig["scrape_interval"] = fmt.Sprintf("%ds", seconds)
+		}
+		// The job names are unique, as Prometheus requires.
+		scrapeConfig["job_name"] = "prometheus"
+		if len(c.scrapeConfigs) > 0 {
+			scrapeConfig["job_name"] = fmt.Sprintf("prometheus_%d", len(c.scrapeConfigs)+1)
+		}
+		c.scrapeConfigs = append(c.scrapeConfigs, scrapeConfig)
+	}
+}
+
+// prometheusConfigs returns the prometheus plugin of the agent and its
+// Prometheus scrape config, which scrapes the endpoints at the interval of the
+// agent unless the input sets its own.
+func (c *conversion) prometheusConfigs() (map[string]interface{}, map[string]interface{}) {
+	plugin := map[string]interface{}{
+		"prometheus_config_path": c.prometheusConfigPath,
+		"log_group_name":         defaultPrometheusLogGroup,
+	}
+	if namespace, ok := c.metrics["namespace"]; ok {
+		plugin["emf_processor"] = map[string]interface{}{"metric_namespace": namespace}
+	}
+	prometheusConfig := map[string]interface{}{"scrape_configs": c.scrapeConfigs}
+	if seconds, ok := c.agent["metrics_collection_interval"]; ok {
+		prometheusConfig["global"] = map[string]interface{}{"scrape_interval": fmt.Sprintf("%ds", seconds)}
+	}
+	return plugin, prometheusConfig
+}
@@ -0,0 +1,177 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package telegrafmigration
+
+import (
+	"encoding/json"
+	"os"
+	"path"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+	"github.com/aws/amazon-cloudwatch-agent/translator/config"
+	"github.com/aws/amazon-cloudwatch-agent/translator/context"
+	_ "github.com/aws/amazon-cloudwatch-agent/translator/registerrules"
+	"github.com/aws/amazon-cloudwatch-agent/translator/tocwconfig/totomlconfig"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/agent"
+)
+
+// TestConvertTranslate checks that the config-translator turns the converted
+// config back into the telegraf inputs of the original config.
+func TestConvertTranslate(t *testing.T) {
+	content, err := os.ReadFile(filepath.Join("testdata", "telegraf.conf"))
+	require.NoError(t, err)
+	var original telegrafConfig
+	_, err = toml.Decode(string(content), &original)
+	require.NoError(t, err)
+
+	agentConfig, _, _, err := Convert(content, testPrometheusConfigPath)
+	require.NoError(t, err)
+	translated := translateToToml(t, agentConfig)
+
+	translatedAgent := translated["agent"].(map[string]interface{})
+	assert.Equal(t, original.Agent["interval"], translatedAgent["interval"])
+	for _, key := range []string{"debug", "logfile", "omit_hostname"} {
+		assert.Equal(t, original.Agent[key], translatedAgent[key], key)
+	}
+
+	translatedInputs := translated["inputs"].(map[string]interface{})
+	for name, instances := range original.Inputs {
+		if name == "prometheus" {
+			// The endpoints are scraped by the Prometheus scrape config.
+			translatedInstances, _ := translatedInputs[name].([]map[string]interface{})
+			require.Len(t, translatedInstances, 1)
+			assert.Equal(t, testPrometheusConfigPath, translatedInstances[0]["prometheus_config_path"])
+			continue
+		}
+		if _, ok := inputConverters[name]; !ok {
+			assert.NotContains(t, translatedInputs, name)
+			continue
+		}
+		translatedInstances, _ := translatedInputs[name].([]map[string]interface{})
+		for _, options := range instances {
+			translatedOptions := findInstance(name, options, translatedInstances)
+			if translatedOptions == nil {
+				assert.Equal(t, "procstat", name, "only a procstat without a pid_file, exe or pattern is not converted")
+				continue
+			}
+			assertInstance(t, name, options, translatedOptions, original.GlobalTags)
+		}
+	}
+}
+
+// translateToToml translates the agent config as config-translator does, and
+// decodes the TOML config it writes.
+func translateToToml(t *testing.T, agentConfig map[string]interface{}) map[string]interface{} {
+	t.Helper()
+	// The translator looks up the EC2 metadata in the EC2 mode.
+	context.ResetContext()
+	context.CurrentContext().SetMode(config.ModeOnPremise)
+	agent.Global_Config = *new(agent.Agent)
+	translator.SetTargetPlatform("linux")
+	t.Cleanup(translator.ResetMessages)
+
+	// The translator reads the agent config from JSON.
+	content, err := json.Marshal(agentConfig)
+	require.NoError(t, err)
+	var input interface{}
+	require.NoError(t, json.Unmarshal(content, &input))
+
+	_, val := new(translate.Translator).ApplyRule(input)
+	require.True(t, translator.IsTranslateSuccess(), "%v", translator.ErrorMessages)
+	var translated map[string]interface{}
+	_, err = toml.Decode(totomlconfig.ToTomlConfig(val), &translated)
+	require.NoError(t, err)
+	return translated
+}
+
+// findInstance returns the translated instance of a telegraf input, which is
+// found by its pid_file, exe or pattern for procstat.
+func findInstance(name string, options map[string]interface{}, translatedInstances []map[string]interface{}) map[string]interface{} {
+	for _, translatedOptions := range translatedInstances {
+		if name != "procstat" {
+			return translatedOptions
+		}
+		for _, key := range []string{"pid_file", "exe", "pattern"} {
+			if options[key] != nil && options[key] == translatedOptions[key] {
+				return translatedOptions
+			}
+		}
+	}
+	return nil
+}
+
+func assertInstance(t *testing.T, name string, options, translatedOptions map[string]interface{}, globalTags map[string]string) {
+	t.Helper()
+	for _, key := range []string{"percpu", "totalcpu", "collect_cpu_time", "report_active", "mount_points", "ignore_fs", "devices", "interfaces",
+		"pid_file", "exe", "pattern", "service_address", "metric_separator", "allowed_pending_messages"} {
+		if value, ok := options[key]; ok {
+			translatedValue := translatedOptions[key]
+			if translatedValue == nil && value == false {
+				translatedValue = false
+			}
+			assert.Equal(t, value, translatedValue, "%s %s", name, key)
+		}
+	}
+	if interval, ok := options["interval"]; ok {
+		assert.Equal(t, duration(t, interval), duration(t, translatedOptions["interval"]), "%s interval", name)
+	}
+	if tagExclude, ok := options["tagexclude"]; ok {
+		assert.Subset(t, translatedOptions["tagexclude"], tagExclude, "%s tagexclude", name)
+	}
+
+	tags, _ := options["tags"].(map[string]interface{})
+	translatedTags, _ := translatedOptions["tags"].(map[string]interface{})
+	for key, value := range globalTags {
+		if _, ok := tags[key]; !ok && name != "statsd" {
+			assert.Equal(t, value, translatedTags[key], "%s global tag %s", name, key)
+		}
+	}
+	for key, value := range tags {
+		assert.Equal(t, value, translatedTags[key], "%s tag %s", name, key)
+	}
+
+	// The fields the agent collects are the ones that pass the field filters.
+	if name == "ethtool" || name == "statsd" {
+		return
+	}
+	fieldPass, _ := translatedOptions["fieldpass"].([]interface{})
+	assert.NotEmpty(t, fieldPass, name)
+	for _, field := range fieldPass {
+		for _, key := range []string{"fieldpass", "fieldinclude"} {
+			if patterns, ok := options[key].([]interface{}); ok {
+				assert.True(t, matchesAny(field.(string), patterns), "%s %s does not pass %s", name, field, key)
+			}
+		}
+		for _, key := range []string{"fielddrop", "fieldexclude"} {
+			if patterns, ok := options[key].([]interface{}); ok {
+				assert.False(t, matchesAny(field.(string), patterns), "%s %s does not pass %s", name, field, key)
+			}
+		}
+	}
+}
+
+func matchesAny(field string, patterns []interface{}) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern.(string), field); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func duration(t *testing.T, value interface{}) time.Duration {
+	t.Helper()
+	s, _ := value.(string)
+	d, err := time.ParseDuration(s)
+	require.NoError(t, err)
+	return d
+}
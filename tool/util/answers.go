@@ -0,0 +1,179 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package util
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// An AnswerFile answers the questions of the wizard instead of the user, so
+// the wizard runs without a terminal. The keys of the file are the IDs of the
+// questions, e.g. "logs.file_path", which do not change with their wording. A
+// question that is asked more than once, e.g. for each log file, is answered
+// by the items of a list in turn. An empty answer chooses the default of the
+// question.
+//
+// Once a question is not answered, or answered with a value that is not
+// valid, the remaining questions are answered with their defaults, or with no
+// for the yes or no questions so the wizard does not loop, and Err returns
+// the error. The wizard then stops before its next step.
+type AnswerFile struct {
+	answers map[string][]string
+	asked   map[string]int
+	err     error
+}
+
+// An AnswerError is a question the answer file does not answer, or answers
+// with a value that is not valid.
+type AnswerError struct {
+	ID          string
+	Question    string
+	Answer      string
+	ValidValues []string
+	missing     bool
+}
+
+func (e *AnswerError) Error() string {
+	question := e.ID
+	if e.Question != "" {
+		question = fmt.Sprintf("%s (%q)", e.ID, e.Question)
+	}
+	if e.missing {
+		return fmt.Sprintf("the answer file does not answer %s", question)
+	}
+	return fmt.Sprintf("%q is not a valid answer to %s, the valid answers are %s or their numbers",
+		e.Answer, question, strings.Join(e.ValidValues, ", "))
+}
+
+var answerFile *AnswerFile
+
+// LoadAnswerFile loads a YAML or JSON answer file.
+func LoadAnswerFile(path string) (*AnswerFile, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var values map[string]interface{}
+	if err = yaml.Unmarshal(content, &values); err != nil {
+		return nil, fmt.Errorf("unable to parse the answer file %s: %w", path, err)
+	}
+	a := &AnswerFile{answers: map[string][]string{}, asked: map[string]int{}}
+	for id, value := range values {
+		id = strings.TrimSpace(id)
+		if items, ok := value.([]interface{}); ok {
+			for _, item := range items {
+				a.answers[id] = append(a.answers[id], answerString(item))
+			}
+		} else {
+			a.answers[id] = []string{answerString(value)}
+		}
+	}
+	return a, nil
+}
+
+func answerString(value interface{}) string {
+	if value == nil {
+		return ""
+	}
+	return fmt.Sprint(value)
+}
+
+// UseAnswerFile answers the questions from the answer file instead of stdin.
+func UseAnswerFile(a *AnswerFile) {
+	answerFile = a
+}
+
+// AnswerFileErr returns the error of the answer file in use, if any.
+func AnswerFileErr() error {
+	if answerFile == nil {
+		return nil
+	}
+	return answerFile.Err()
+}
+
+// Err returns the first question that the answer file does not answer, or
+// answers with a value that is not valid, as an *AnswerError.
+func (a *AnswerFile) Err() error {
+	return a.err
+}
+
+// next returns the answer to the question, which is printed with the question
+// so the output reads like the interactive one. It returns false if the file
+// does not answer the question, or failed to answer a previous one.
+func (a *AnswerFile) next(id, question string) (string, bool) {
+	if a.err != nil {
+		return "", false
+	}
+	answers := a.answers[id]
+	i := a.asked[id]
+	if i >= len(answers) {
+		a.err = &AnswerError{ID: id, Question: strings.TrimSpace(question), missing: true}
+		return "", false
+	}
+	a.asked[id] = i + 1
+	fmt.Printf("%s\n%s\n", question, answers[i])
+	return answers[i], true
+}
+
+// Unused returns the IDs of the questions whose answers were not all used,
+// e.g. because the ID is misspelled or the question is not asked with the
+// other answers.
+func (a *AnswerFile) Unused() []string {
+	var unused []string
+	for id, answers := range a.answers {
+		if a.asked[id] < len(answers) {
+			unused = append(unused, id)
+		}
+	}
+	sort.Strings(unused)
+	return unused
+}
+
+// chooseAnswer returns the index of the valid value the answer file chooses,
+// by value or by number. A question that it fails to answer is answered with
+// its default, or with no if it is a yes or no question, so that the loops
+// of the wizard it controls end.
+func (a *AnswerFile) chooseAnswer(id, question string, defaultOption int, validValues []string) int {
+	yesOrNo := len(validValues) == 2 && validValues[0] == "yes" && validValues[1] == "no"
+	if answer, ok := a.next(id, question); ok {
+		if i, ok := matchAnswer(answer, defaultOption, validValues, yesOrNo); ok {
+			return i
+		}
+		a.err = &AnswerError{ID: id, Question: strings.TrimSpace(question), Answer: answer, ValidValues: validValues}
+	}
+	if yesOrNo {
+		return 1
+	}
+	return max(0, min(defaultOption, len(validValues))-1)
+}
+
+func matchAnswer(answer string, defaultOption int, validValues []string, yesOrNo bool) (int, bool) {
+	if answer == "" {
+		answer = strconv.Itoa(defaultOption)
+	}
+	if option, err := strconv.Atoi(answer); err == nil && option > 0 && option <= len(validValues) {
+		return option - 1, true
+	}
+	for i, value := range validValues {
+		if strings.EqualFold(answer, value) {
+			return i, true
+		}
+	}
+	// YAML and JSON booleans answer yes or no questions
+	if yesOrNo {
+		switch strings.ToLower(answer) {
+		case "true":
+			return 0, true
+		case "false":
+			return 1, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,91 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package util
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func useAnswers(t *testing.T, content string) *AnswerFile {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "answers.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	answers, err := LoadAnswerFile(path)
+	require.NoError(t, err)
+	UseAnswerFile(answers)
+	t.Cleanup(func() { UseAnswerFile(nil) })
+	return answers
+}
+
+func TestAnswerFile(t *testing.T) {
+	answers := useAnswers(t, `
+interval: 2
+class: infrequent_access
+on: true
+off: false
+port: 8125
+default_port:
+logs.file_path: [/var/log/messages, /var/log/secure]
+never_asked: yes
+`)
+	assert.Equal(t, "30s", Choice("interval", "Which interval?", 1, []string{"10s", "30s", "60s"}))
+	assert.Equal(t, 1, ChoiceIndex("class", "Which class?", 1, []string{StandardLogGroupClass, InfrequentAccessLogGroupClass}))
+	assert.True(t, Yes("on", "Turn on?"))
+	assert.False(t, Yes("off", "Turn off?"))
+	assert.Equal(t, "8125", AskWithDefault("port", "Port?", "2000"))
+	assert.Equal(t, "2000", AskWithDefault("default_port", "Default port?", "2000"))
+	// The answers do not depend on the wording of the questions.
+	assert.Equal(t, "/var/log/messages", Ask("logs.file_path", "Log file path:"))
+	assert.Equal(t, "/var/log/secure", Ask("logs.file_path", "Path of the log file:"))
+	assert.NoError(t, answers.Err())
+	assert.Equal(t, []string{"never_asked"}, answers.Unused())
+
+	assert.Equal(t, "", Ask("logs.file_path", "Log file path:"))
+	assert.EqualError(t, AnswerFileErr(), `the answer file does not answer logs.file_path ("Log file path:")`)
+	var answerErr *AnswerError
+	require.ErrorAs(t, answers.Err(), &answerErr)
+	assert.Equal(t, "logs.file_path", answerErr.ID)
+
+	// Once the answer file fails, the questions are answered with their
+	// defaults, or with no, and the first error is kept.
+	assert.False(t, Yes("on", "Turn on?"))
+	assert.Equal(t, "2000", AskWithDefault("port", "Port?", "2000"))
+	assert.Equal(t, "30s", Choice("interval", "Which interval?", 2, []string{"10s", "30s", "60s"}))
+	assert.Equal(t, answerErr, answers.Err())
+}
+
+func TestAnswerFileMissing(t *testing.T) {
+	answers := useAnswers(t, `{}`)
+	assert.Equal(t, "", Ask("user", "Which user?\n"))
+	assert.EqualError(t, answers.Err(), `the answer file does not answer user ("Which user?")`)
+
+	answers = useAnswers(t, `{}`)
+	assert.Equal(t, "", Ask("user", ""))
+	assert.EqualError(t, answers.Err(), `the answer file does not answer user`)
+}
+
+func TestAnswerFileInvalidAnswer(t *testing.T) {
+	answers := useAnswers(t, `{"interval": "5s"}`)
+	assert.Equal(t, "30s", Choice("interval", "Which interval?", 2, []string{"10s", "30s", "60s"}))
+	assert.EqualError(t, answers.Err(), `"5s" is not a valid answer to interval ("Which interval?"), the valid answers are 10s, 30s, 60s or their numbers`)
+
+	answers = useAnswers(t, `{"on": 3}`)
+	assert.False(t, Yes("on", "Turn on?"))
+	assert.Error(t, answers.Err())
+}
+
+func TestLoadAnswerFileError(t *testing.T) {
+	_, err := LoadAnswerFile(filepath.Join(t.TempDir(), "missing.yaml"))
+	assert.Error(t, err)
+
+	path := filepath.Join(t.TempDir(), "answers.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("- not a map"), 0644))
+	_, err = LoadAnswerFile(path)
+	assert.ErrorContains(t, err, "unable to parse the answer file")
+}
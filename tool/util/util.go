@@ -246,17 +246,25 @@ func AddToMap(ctx *runtime.Context, resultMap map[string]interface{}, obj interf
 	}
 }
 
-func Yes(question string) bool {
-	answer := Choice(question, 1, []string{"yes", "no"})
+// Yes asks a question that defaults to yes. The id of a question is the key
+// that answers it in an answer file, see AnswerFile.
+func Yes(id, question string) bool {
+	answer := Choice(id, question, 1, []string{"yes", "no"})
 	return answer == "yes"
 }
 
-func No(question string) bool {
-	answer := Choice(question, 2, []string{"yes", "no"})
+func No(id, question string) bool {
+	answer := Choice(id, question, 2, []string{"yes", "no"})
 	return answer == "yes"
 }
 
-func AskWithDefault(question, defaultValue string) string {
+func AskWithDefault(id, question, defaultValue string) string {
+	if answerFile != nil {
+		if answer, ok := answerFile.next(id, question); ok && answer != "" {
+			return answer
+		}
+		return defaultValue
+	}
 	for {
 		var answer string
 		fmt.Printf("%s\ndefault choice: [%s]\n\r", question, defaultValue)
@@ -270,12 +278,19 @@ func AskWithDefault(question, defaultValue string) string {
 	}
 }
 
-func Ask(question string) string {
-	return Choice(question, 0, nil)
+func Ask(id, question string) string {
+	return Choice(id, question, 0, nil)
 }
 
 // defaultOption value starts from 1
-func Choice(question string, defaultOption int, validValues []string) string {
+func Choice(id, question string, defaultOption int, validValues []string) string {
+	if answerFile != nil {
+		if validValues == nil {
+			answer, _ := answerFile.next(id, question)
+			return answer
+		}
+		return validValues[answerFile.chooseAnswer(id, question, defaultOption, validValues)]
+	}
 	for {
 		var answer string
 		options := ""
@@ -309,7 +324,10 @@ func Choice(question string, defaultOption int, validValues []string) string {
 }
 
 // ChoiceIndex returns index of choice chosen
-func ChoiceIndex(question string, defaultOption int, validValues []string) int {
+func ChoiceIndex(id, question string, defaultOption int, validValues []string) int {
+	if answerFile != nil {
+		return answerFile.chooseAnswer(id, question, defaultOption, validValues)
+	}
 	for {
 		var answer string
 		options := ""
@@ -334,6 +352,9 @@ func ChoiceIndex(question string, defaultOption int, validValues []string) int {
 	}
 }
 func EnterToExit() {
+	if answerFile != nil {
+		return
+	}
 	fmt.Println("Please press Enter to exit...")
 	stdin.Scanln()
 }
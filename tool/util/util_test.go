@@ -68,20 +68,20 @@ func TestYes(t *testing.T) {
 	inputChan := testutil.SetUpTestInputStream()
 
 	testutil.Type(inputChan, "")
-	assert.True(t, Yes("Some question"))
+	assert.True(t, Yes("id", "Some question"))
 
 	testutil.Type(inputChan, "2")
-	assert.False(t, Yes("Some question"))
+	assert.False(t, Yes("id", "Some question"))
 }
 
 func TestNo(t *testing.T) {
 	inputChan := testutil.SetUpTestInputStream()
 
 	testutil.Type(inputChan, "")
-	assert.False(t, No("Some question"))
+	assert.False(t, No("id", "Some question"))
 
 	testutil.Type(inputChan, "1")
-	assert.True(t, No("Some question"))
+	assert.True(t, No("id", "Some question"))
 }
 
 func TestAskWithDefault(t *testing.T) {
@@ -89,13 +89,13 @@ func TestAskWithDefault(t *testing.T) {
 
 	testutil.Type(inputChan, "")
 
-	parsedAnswer := AskWithDefault("Question", "DefaultAnswer")
+	parsedAnswer := AskWithDefault("id", "Question", "DefaultAnswer")
 
 	assert.Equal(t, "DefaultAnswer", parsedAnswer)
 
 	testutil.Type(inputChan, "Answer")
 
-	parsedAnswer = AskWithDefault("Question", "DefaultAnswer")
+	parsedAnswer = AskWithDefault("id", "Question", "DefaultAnswer")
 
 	assert.Equal(t, "Answer", parsedAnswer)
 }
@@ -105,7 +105,7 @@ func TestAsk(t *testing.T) {
 
 	testutil.Type(inputChan, "Answer")
 
-	parsedAnswer := Ask("Question")
+	parsedAnswer := Ask("id", "Question")
 
 	assert.Equal(t, "Answer", parsedAnswer)
 }
@@ -115,13 +115,13 @@ func TestChoice(t *testing.T) {
 
 	testutil.Type(inputChan, "")
 
-	parsedAnswer := Choice("Question", 1, []string{"validValue1", "validValue2"})
+	parsedAnswer := Choice("id", "Question", 1, []string{"validValue1", "validValue2"})
 
 	assert.Equal(t, "validValue1", parsedAnswer)
 
 	testutil.Type(inputChan, "InvalidAnswer", "2")
 
-	parsedAnswer = Choice("Question", 1, []string{"validValue1", "validValue2"})
+	parsedAnswer = Choice("id", "Question", 1, []string{"validValue1", "validValue2"})
 
 	assert.Equal(t, "validValue2", parsedAnswer)
 }
@@ -131,13 +131,13 @@ func TestChoiceIndex(t *testing.T) {
 
 	testutil.Type(inputChan, "")
 
-	parsedAnswer := ChoiceIndex("Question", 1, []string{"validValue1", "validValue2"})
+	parsedAnswer := ChoiceIndex("id", "Question", 1, []string{"validValue1", "validValue2"})
 
 	assert.Equal(t, 0, parsedAnswer)
 
 	testutil.Type(inputChan, "InvalidAnswer", "2")
 
-	parsedAnswer = ChoiceIndex("Question", 1, []string{"validValue1", "validValue2"})
+	parsedAnswer = ChoiceIndex("id", "Question", 1, []string{"validValue1", "validValue2"})
 
 	assert.Equal(t, 1, parsedAnswer)
 }
@@ -4,6 +4,7 @@
 package cmdutil
 
 import (
+	"errors"
 	"fmt"
 	"log"
 	"os"
@@ -24,7 +25,9 @@ import (
 	"github.com/aws/amazon-cloudwatch-agent/translator/tocwconfig/totomlconfig"
 	"github.com/aws/amazon-cloudwatch-agent/translator/tocwconfig/toyamlconfig"
 	"github.com/aws/amazon-cloudwatch-agent/translator/translate"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/agent"
 	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/pipeline"
 	translatorUtil "github.com/aws/amazon-cloudwatch-agent/translator/util"
 )
 
@@ -219,6 +222,43 @@ func GenerateMergedJsonConfigMap(ctx *context.Context) (map[string]interface{},
 	return mergedJsonConfigMap, nil
 }
 
+// TranslateJsonFileToConfigs translates a single json config file, merged with
+// the default config, to the TOML and YAML configs. The YAML config is nil when
+// there are no OTel pipelines. The state of the translator is reset afterwards,
+// so that another json config can be translated in the same process.
+func TranslateJsonFileToConfigs(ctx *context.Context, jsonConfigFilePath string) (interface{}, interface{}, error) {
+	jsonConfigMap, err := translatorUtil.GetJsonMapFromFile(jsonConfigFilePath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to get json config file %v with error: %v", jsonConfigFilePath, err)
+	}
+	defaultConfig, err := translatorUtil.GetDefaultJsonConfigMap(ctx.Os(), ctx.Mode())
+	if err != nil {
+		return nil, nil, err
+	}
+	mergedJsonConfigMap, err := jsonconfig.MergeJsonConfigMaps(map[string]map[string]interface{}{jsonConfigFilePath: jsonConfigMap}, defaultConfig, ctx.MultiConfig())
+	if err != nil {
+		return nil, nil, err
+	}
+	checkSchema(mergedJsonConfigMap)
+
+	defer func() {
+		translator.ResetMessages()
+		agent.Global_Config = *new(agent.Agent)
+	}()
+	tomlConfig, err := TranslateJsonMapToTomlConfig(mergedJsonConfigMap)
+	if err != nil {
+		return nil, nil, err
+	}
+	yamlConfig, err := TranslateJsonMapToYamlConfig(mergedJsonConfigMap)
+	if errors.Is(err, pipeline.ErrNoPipelines) {
+		return tomlConfig, nil, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return tomlConfig, yamlConfig, nil
+}
+
 func TranslateJsonMapToTomlConfig(jsonConfigValue interface{}) (interface{}, error) {
 	r := new(translate.Translator)
 	_, val := r.ApplyRule(jsonConfigValue)
@@ -0,0 +1,367 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+// Package configdiff reports what changes between two translated agent
+// configurations.
+package configdiff
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal/util/collections"
+)
+
+const (
+	inputsKey          = "inputs"
+	fieldPassKey       = "fieldpass"
+	tagsKey            = "tags"
+	logGroupNameKey    = "log_group_name"
+	fileConfigKey      = "file_config"
+	eventConfigKey     = "event_config"
+	logfileInput       = "logfile"
+	eventLogInput      = "windows_event_log"
+	receiverPrefix     = "telegraf_"
+	metricsPipeline    = "metrics"
+	internalTagPrefix  = "aws:"
+	ec2TaggerType      = "ec2tagger"
+	cloudWatchType     = "awscloudwatch"
+	metadataTagsKey    = "ec2_metadata_tags"
+	instanceTagsKey    = "ec2_instance_tag_keys"
+	rollupKey          = "rollup_dimensions"
+	dropOriginalKey    = "drop_original_metrics"
+	allFieldsSuffix    = "_*"
+	noRollupDimensions = "(none)"
+)
+
+// Summary is what a translated configuration collects and publishes.
+type Summary struct {
+	Pipelines  collections.Set[string]
+	Receivers  collections.Set[string]
+	Metrics    collections.Set[string]
+	Dimensions collections.Set[string]
+	Rollups    collections.Set[string]
+	LogGroups  collections.Set[string]
+	// MetricCount is the estimated number of metrics published to CloudWatch
+	// per collection interval, counting one resource (e.g. one disk) for
+	// each plugin.
+	MetricCount int
+	// Unestimated are the plugins and receivers whose metrics are only known
+	// once they are received, such as statsd, and are not in MetricCount.
+	Unestimated collections.Set[string]
+}
+
+// Summarize summarizes the TOML and YAML configurations translated from the
+// same JSON configuration. Either of them can be nil.
+func Summarize(tomlConfig, yamlConfig any) (*Summary, error) {
+	tomlMap, err := normalize(tomlConfig)
+	if err != nil {
+		return nil, fmt.Errorf("invalid TOML configuration: %w", err)
+	}
+	yamlMap, err := normalize(yamlConfig)
+	if err != nil {
+		return nil, fmt.Errorf("invalid YAML configuration: %w", err)
+	}
+	s := &Summary{
+		Pipelines:   collections.NewSet[string](),
+		Receivers:   collections.NewSet[string](),
+		Metrics:     collections.NewSet[string](),
+		Dimensions:  collections.NewSet[string](),
+		Rollups:     collections.NewSet[string](),
+		LogGroups:   collections.NewSet[string](),
+		Unestimated: collections.NewSet[string](),
+	}
+	metrics := s.summarizeInputs(tomlMap)
+	rollups, dropped := s.summarizeComponents(yamlMap)
+	for _, metric := range metrics {
+		s.MetricCount += 1 + rollups
+		if dropped.Contains(metric) {
+			s.MetricCount--
+		}
+	}
+	return s, nil
+}
+
+// summarizeInputs adds the metrics, dimensions and log groups of the
+// telegraf inputs and returns the metrics that are counted.
+func (s *Summary) summarizeInputs(tomlMap map[string]any) []string {
+	var counted []string
+	inputs, _ := tomlMap[inputsKey].(map[string]any)
+	for name, value := range inputs {
+		for _, input := range toMaps(value) {
+			switch name {
+			case logfileInput:
+				s.addLogGroups(input[fileConfigKey])
+			case eventLogInput:
+				s.addLogGroups(input[eventConfigKey])
+			default:
+				for tag := range toMap(input[tagsKey]) {
+					if !strings.HasPrefix(tag, internalTagPrefix) {
+						s.Dimensions.Add(tag)
+					}
+				}
+				fields := toStrings(input[fieldPassKey])
+				if len(fields) == 0 {
+					s.Metrics.Add(name + allFieldsSuffix)
+					s.Unestimated.Add(name)
+					continue
+				}
+				for _, field := range fields {
+					metric := name + "_" + field
+					s.Metrics.Add(metric)
+					counted = append(counted, metric)
+				}
+			}
+		}
+	}
+	return counted
+}
+
+func (s *Summary) addLogGroups(value any) {
+	for _, config := range toMaps(value) {
+		if logGroup, ok := config[logGroupNameKey].(string); ok && logGroup != "" {
+			s.LogGroups.Add(logGroup)
+		}
+	}
+}
+
+// summarizeComponents adds the pipelines, receivers, dimensions and log
+// groups of the OTel components. It returns how many rollups are published
+// for each metric and the metrics whose original dimensions are dropped.
+func (s *Summary) summarizeComponents(yamlMap map[string]any) (int, collections.Set[string]) {
+	service := toMap(yamlMap["service"])
+	for name, value := range toMap(service["pipelines"]) {
+		s.Pipelines.Add(name)
+		receivers := toStrings(toMap(value)["receivers"])
+		s.Receivers.Add(receivers...)
+		if componentType(name) != metricsPipeline {
+			continue
+		}
+		for _, receiver := range receivers {
+			if !strings.HasPrefix(receiver, receiverPrefix) {
+				s.Unestimated.Add(receiver)
+			}
+		}
+	}
+	for id, value := range toMap(yamlMap["processors"]) {
+		if componentType(id) != ec2TaggerType {
+			continue
+		}
+		processor := toMap(value)
+		s.Dimensions.Add(toStrings(processor[metadataTagsKey])...)
+		s.Dimensions.Add(toStrings(processor[instanceTagsKey])...)
+	}
+	dropped := collections.NewSet[string]()
+	for id, value := range toMap(yamlMap["exporters"]) {
+		exporter := toMap(value)
+		if logGroup, ok := exporter[logGroupNameKey].(string); ok && logGroup != "" {
+			s.LogGroups.Add(logGroup)
+		}
+		if componentType(id) != cloudWatchType {
+			continue
+		}
+		for _, rollup := range toSlice(exporter[rollupKey]) {
+			dimensions := toStrings(rollup)
+			sort.Strings(dimensions)
+			if len(dimensions) == 0 {
+				s.Rollups.Add(noRollupDimensions)
+			} else {
+				s.Rollups.Add(strings.Join(dimensions, ","))
+			}
+		}
+		for metric, drop := range toMap(exporter[dropOriginalKey]) {
+			if drop == true {
+				dropped.Add(metric)
+			}
+		}
+	}
+	return len(s.Rollups), dropped
+}
+
+// Section is a kind of item that is added to or removed from the
+// configuration.
+type Section struct {
+	Name    string
+	Added   []string
+	Removed []string
+}
+
+// Diff is what changes from the current to the proposed configuration.
+type Diff struct {
+	Sections            []Section
+	CurrentMetricCount  int
+	ProposedMetricCount int
+	CurrentUnestimated  []string
+	ProposedUnestimated []string
+}
+
+// Compare returns what changes from the current to the proposed summary.
+func Compare(current, proposed *Summary) *Diff {
+	return &Diff{
+		Sections: []Section{
+			compareSets("Pipelines", current.Pipelines, proposed.Pipelines),
+			compareSets("Receivers", current.Receivers, proposed.Receivers),
+			compareSets("Metrics", current.Metrics, proposed.Metrics),
+			compareSets("Dimensions", current.Dimensions, proposed.Dimensions),
+			compareSets("Dimension rollups", current.Rollups, proposed.Rollups),
+			compareSets("Log groups", current.LogGroups, proposed.LogGroups),
+		},
+		CurrentMetricCount:  current.MetricCount,
+		ProposedMetricCount: proposed.MetricCount,
+		CurrentUnestimated:  sorted(current.Unestimated),
+		ProposedUnestimated: sorted(proposed.Unestimated),
+	}
+}
+
+func compareSets(name string, current, proposed collections.Set[string]) Section {
+	section := Section{Name: name}
+	for _, item := range sorted(proposed) {
+		if !current.Contains(item) {
+			section.Added = append(section.Added, item)
+		}
+	}
+	for _, item := range sorted(current) {
+		if !proposed.Contains(item) {
+			section.Removed = append(section.Removed, item)
+		}
+	}
+	return section
+}
+
+// HasChanges is true if anything is added or removed.
+func (d *Diff) HasChanges() bool {
+	for _, section := range d.Sections {
+		if len(section.Added) > 0 || len(section.Removed) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Write writes the report of the diff.
+func (d *Diff) Write(w io.Writer) error {
+	var b strings.Builder
+	if !d.HasChanges() {
+		b.WriteString("No changes to the pipelines, receivers, metrics, dimensions or log groups.\n")
+	}
+	for _, section := range d.Sections {
+		if len(section.Added) == 0 && len(section.Removed) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "%s:\n", section.Name)
+		for _, item := range section.Added {
+			fmt.Fprintf(&b, "  + %s\n", item)
+		}
+		for _, item := range section.Removed {
+			fmt.Fprintf(&b, "  - %s\n", item)
+		}
+	}
+	fmt.Fprintf(&b, "Estimated metrics published per interval: %d -> %d (%+d)\n",
+		d.CurrentMetricCount, d.ProposedMetricCount, d.ProposedMetricCount-d.CurrentMetricCount)
+	b.WriteString("  Each metric is counted once per dimension rollup, for one resource of its plugin (e.g. one disk or one CPU).\n")
+	if len(d.CurrentUnestimated) > 0 || len(d.ProposedUnestimated) > 0 {
+		fmt.Fprintf(&b, "  Not estimated: %s -> %s\n", joinOrNone(d.CurrentUnestimated), joinOrNone(d.ProposedUnestimated))
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// LoadFiles loads a deployed TOML configuration and the YAML configuration
+// translated with it. The YAML configuration is optional, since it is not
+// written when there are no OTel pipelines.
+func LoadFiles(tomlPath, yamlPath string) (any, any, error) {
+	var tomlConfig map[string]any
+	if _, err := toml.DecodeFile(tomlPath, &tomlConfig); err != nil {
+		return nil, nil, fmt.Errorf("unable to read TOML configuration %s: %w", tomlPath, err)
+	}
+	content, err := os.ReadFile(yamlPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return tomlConfig, nil, nil
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to read YAML configuration %s: %w", yamlPath, err)
+	}
+	var yamlConfig map[string]any
+	if err = yaml.Unmarshal(content, &yamlConfig); err != nil {
+		return nil, nil, fmt.Errorf("unable to read YAML configuration %s: %w", yamlPath, err)
+	}
+	return tomlConfig, yamlConfig, nil
+}
+
+// normalize converts the configuration to the generic types of JSON, so the
+// configurations translated in memory and the ones read from files are
+// walked the same way.
+func normalize(config any) (map[string]any, error) {
+	if config == nil {
+		return nil, nil
+	}
+	content, err := json.Marshal(config)
+	if err != nil {
+		return nil, err
+	}
+	var normalized map[string]any
+	if err = json.Unmarshal(content, &normalized); err != nil {
+		return nil, err
+	}
+	return normalized, nil
+}
+
+// componentType is the type of the OTel component or pipeline ID.
+func componentType(id string) string {
+	componentType, _, _ := strings.Cut(id, "/")
+	return componentType
+}
+
+func toMap(value any) map[string]any {
+	m, _ := value.(map[string]any)
+	return m
+}
+
+func toSlice(value any) []any {
+	s, _ := value.([]any)
+	return s
+}
+
+func toMaps(value any) []map[string]any {
+	var maps []map[string]any
+	for _, item := range toSlice(value) {
+		if m, ok := item.(map[string]any); ok {
+			maps = append(maps, m)
+		}
+	}
+	return maps
+}
+
+func toStrings(value any) []string {
+	var strs []string
+	for _, item := range toSlice(value) {
+		if str, ok := item.(string); ok {
+			strs = append(strs, str)
+		}
+	}
+	return strs
+}
+
+func sorted(set collections.Set[string]) []string {
+	items := make([]string, 0, len(set))
+	for item := range set {
+		items = append(items, item)
+	}
+	sort.Strings(items)
+	return items
+}
+
+func joinOrNone(items []string) string {
+	if len(items) == 0 {
+		return "none"
+	}
+	return strings.Join(items, ", ")
+}
@@ -0,0 +1,91 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package configdiff
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal/util/collections"
+)
+
+const sampleConfigDir = "../tocwconfig/sampleConfig"
+
+func loadSummary(t *testing.T, name string) *Summary {
+	t.Helper()
+	tomlConfig, yamlConfig, err := LoadFiles(
+		filepath.Join(sampleConfigDir, name+".conf"),
+		filepath.Join(sampleConfigDir, name+".yaml"),
+	)
+	require.NoError(t, err)
+	summary, err := Summarize(tomlConfig, yamlConfig)
+	require.NoError(t, err)
+	return summary
+}
+
+func TestSummarize(t *testing.T) {
+	basic := loadSummary(t, "basic_config_linux")
+	assert.Equal(t, collections.NewSet("metrics/host"), basic.Pipelines)
+	assert.Equal(t, collections.NewSet("telegraf_disk", "telegraf_mem"), basic.Receivers)
+	assert.Equal(t, collections.NewSet("disk_used_percent", "mem_used_percent"), basic.Metrics)
+	assert.Equal(t, collections.NewSet("AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType"), basic.Dimensions)
+	assert.Empty(t, basic.Rollups)
+	assert.Empty(t, basic.LogGroups)
+	assert.Empty(t, basic.Unestimated)
+	assert.Equal(t, 2, basic.MetricCount)
+
+	complete := loadSummary(t, "complete_linux_config")
+	assert.True(t, complete.Metrics.Contains("cpu_usage_idle"))
+	assert.True(t, complete.Metrics.Contains("statsd_*"))
+	assert.True(t, complete.Dimensions.ContainsAll(collections.NewSet("d1", "d2", "d3", "d4", "InstanceId")))
+	assert.False(t, complete.Dimensions.Contains("aws:StorageResolution"))
+	assert.Equal(t, collections.NewSet("ImageId", "InstanceId,InstanceType", "d1", "(none)"), complete.Rollups)
+	assert.True(t, complete.LogGroups.ContainsAll(collections.NewSet("amazon-cloudwatch-agent.log", "test.log", "emf/logs/default")))
+	assert.True(t, complete.Unestimated.ContainsAll(collections.NewSet("socket_listener", "statsd")))
+	// 31 metrics published with their original dimensions and 4 rollups,
+	// except for the original cpu_time_active which is dropped
+	assert.Equal(t, 31*5-1, complete.MetricCount)
+}
+
+func TestSummarizeNil(t *testing.T) {
+	summary, err := Summarize(nil, nil)
+	require.NoError(t, err)
+	assert.Empty(t, summary.Pipelines)
+	assert.Equal(t, 0, summary.MetricCount)
+}
+
+func TestCompare(t *testing.T) {
+	current := loadSummary(t, "basic_config_linux")
+	proposed := loadSummary(t, "advanced_config_linux")
+
+	diff := Compare(current, proposed)
+	assert.True(t, diff.HasChanges())
+	sections := map[string]Section{}
+	for _, section := range diff.Sections {
+		sections[section.Name] = section
+	}
+	assert.Equal(t, []string{"metrics/hostDeltaMetrics"}, sections["Pipelines"].Added)
+	assert.Empty(t, sections["Pipelines"].Removed)
+	assert.Contains(t, sections["Receivers"].Added, "telegraf_cpu")
+	assert.Contains(t, sections["Metrics"].Added, "cpu_usage_idle")
+	assert.Equal(t, 2, diff.CurrentMetricCount)
+	assert.Equal(t, proposed.MetricCount, diff.ProposedMetricCount)
+
+	var buf bytes.Buffer
+	require.NoError(t, diff.Write(&buf))
+	assert.Contains(t, buf.String(), "Receivers:\n")
+	assert.Contains(t, buf.String(), "  + telegraf_cpu\n")
+	assert.Contains(t, buf.String(), "Estimated metrics published per interval: 2 -> ")
+
+	diff = Compare(current, current)
+	assert.False(t, diff.HasChanges())
+	buf.Reset()
+	require.NoError(t, diff.Write(&buf))
+	assert.Contains(t, buf.String(), "No changes")
+	assert.Contains(t, buf.String(), "2 -> 2 (+0)")
+}
@@ -12,6 +12,10 @@ import (
 	_ "github.com/aws/amazon-cloudwatch-agent/translator/translate/logs"
 	_ "github.com/aws/amazon-cloudwatch-agent/translator/translate/logs/logs_collected/files"
 	_ "github.com/aws/amazon-cloudwatch-agent/translator/translate/logs/logs_collected/files/collect_list"
+	_ "github.com/aws/amazon-cloudwatch-agent/translator/translate/logs/logs_collected/journald"
+	_ "github.com/aws/amazon-cloudwatch-agent/translator/translate/logs/logs_collected/journald/collect_list"
+	_ "github.com/aws/amazon-cloudwatch-agent/translator/translate/logs/logs_collected/syslog"
+	_ "github.com/aws/amazon-cloudwatch-agent/translator/translate/logs/logs_collected/syslog/collect_list"
 	_ "github.com/aws/amazon-cloudwatch-agent/translator/translate/logs/logs_collected/windows_events"
 	_ "github.com/aws/amazon-cloudwatch-agent/translator/translate/logs/logs_collected/windows_events/collect_list"
 	_ "github.com/aws/amazon-cloudwatch-agent/translator/translate/logs/metrics_collected/ecs"
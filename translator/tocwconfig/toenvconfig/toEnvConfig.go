@@ -19,6 +19,7 @@ const (
 	debugKey          = "debug"
 	awsSdkLogLevelKey = "aws_sdk_log_level"
 	usageDataKey      = "usage_data"
+	selfTelemetryKey  = "self_telemetry_address"
 )
 
 func ToEnvConfig(jsonConfigValue map[string]interface{}) []byte {
@@ -41,6 +42,11 @@ func ToEnvConfig(jsonConfigValue map[string]interface{}) []byte {
 		if usageData, ok := agentMap[usageDataKey].(bool); ok && !usageData {
 			envVars[envconfig.CWAGENT_USAGE_DATA] = "FALSE"
 		}
+
+		// Set CWAGENT_SELF_TELEMETRY_ADDRESS to serve the agent's own metrics if specified in agent section
+		if address, ok := agentMap[selfTelemetryKey].(string); ok && address != "" {
+			envVars[envconfig.CWAgentSelfTelemetryAddress] = address
+		}
 	}
 
 	proxy := util.GetHttpProxy(context.CurrentContext().Proxy())
@@ -42,14 +42,16 @@ func TestToEnvConfig(t *testing.T) {
 					debugKey:          true,
 					awsSdkLogLevelKey: "DEBUG",
 					usageDataKey:      false,
+					selfTelemetryKey:  "127.0.0.1:9102",
 				},
 			},
 			envVars: map[string]string{},
 			expectedEnv: map[string]string{
-				envconfig.CWAGENT_USER_AGENT: "custom-agent",
-				envconfig.CWAGENT_LOG_LEVEL:  "DEBUG",
-				envconfig.AWS_SDK_LOG_LEVEL:  "DEBUG",
-				envconfig.CWAGENT_USAGE_DATA: "FALSE",
+				envconfig.CWAGENT_USER_AGENT:          "custom-agent",
+				envconfig.CWAGENT_LOG_LEVEL:           "DEBUG",
+				envconfig.AWS_SDK_LOG_LEVEL:           "DEBUG",
+				envconfig.CWAGENT_USAGE_DATA:          "FALSE",
+				envconfig.CWAgentSelfTelemetryAddress: "127.0.0.1:9102",
 			},
 			contextSetup: func() {
 				context.CurrentContext().SetProxy(map[string]string{})
@@ -0,0 +1,68 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package logs
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+	"github.com/aws/amazon-cloudwatch-agent/translator/config"
+)
+
+// DestinationOutputKeys are the keys of a destination of a file that publish
+// it with other settings than the cloudwatchlogs output of the logs section,
+// e.g. to the log groups of another account or region.
+var DestinationOutputKeys = []string{"region", Role_Arn_Key, "endpoint_override"}
+
+// destinationOutputs are the settings of the cloudwatchlogs outputs of the
+// destinations, in the order of their aliases.
+var destinationOutputs []map[string]interface{}
+
+// AddDestinationOutput returns the alias of the cloudwatchlogs output that
+// publishes with the settings of a destination. The destinations with the
+// same settings share the output.
+func AddDestinationOutput(settings map[string]interface{}) string {
+	for i, s := range destinationOutputs {
+		if reflect.DeepEqual(s, settings) {
+			return destinationOutputAlias(i)
+		}
+	}
+	destinationOutputs = append(destinationOutputs, settings)
+	return destinationOutputAlias(len(destinationOutputs) - 1)
+}
+
+func destinationOutputAlias(i int) string {
+	return fmt.Sprintf("%s_%d", Output_Cloudwatch_Logs, i+1)
+}
+
+// outputsOfDestinations returns the cloudwatchlogs outputs of the destinations,
+// which otherwise have the settings of the output of the logs section. The
+// endpoint of the logs section is not used for another region, and each output
+// has its own dead letter directory.
+func outputsOfDestinations(cloudwatchConfig map[string]interface{}) []interface{} {
+	var outputs []interface{}
+	for i, settings := range destinationOutputs {
+		alias := destinationOutputAlias(i)
+		output := map[string]interface{}{"alias": alias}
+		for key, val := range cloudwatchConfig {
+			output[key] = val
+		}
+		if _, ok := settings["region"]; ok {
+			delete(output, "endpoint_override")
+		}
+		for key, val := range settings {
+			output[key] = val
+		}
+		if dir, ok := output["dead_letter_dir"].(string); ok {
+			separator := "/"
+			if translator.GetTargetPlatform() == config.OS_TYPE_WINDOWS {
+				separator = "\\"
+			}
+			output["dead_letter_dir"] = dir + separator + alias
+		}
+		outputs = append(outputs, output)
+	}
+	return outputs
+}
@@ -54,6 +54,7 @@ func (l *Logs) ApplyRule(input interface{}) (returnKey string, returnVal interfa
 	processors := map[string]interface{}{}
 	cloudwatchConfig := map[string]interface{}{}
 	GlobalLogConfig.MetadataInfo = util.GetMetadataInfo(util.Ec2MetadataInfoProvider)
+	destinationOutputs = nil
 
 	//Apply Environment and ServiceName rules
 	serviceName.ApplyRule(im[SectionKey])
@@ -99,7 +100,7 @@ func (l *Logs) ApplyRule(input interface{}) (returnKey string, returnVal interfa
 		}
 
 		cloudwatchInfo := map[string]interface{}{}
-		cloudwatchInfo["cloudwatchlogs"] = []interface{}{cloudwatchConfig}
+		cloudwatchInfo["cloudwatchlogs"] = append([]interface{}{cloudwatchConfig}, outputsOfDestinations(cloudwatchConfig)...)
 		result["outputs"] = cloudwatchInfo
 
 		if len(inputs) > 0 {
@@ -493,6 +493,107 @@ func TestMultiLineStartPattern(t *testing.T) {
 	assert.Equal(t, expectVal, val)
 }
 
+func TestMultiLineModes(t *testing.T) {
+	f := new(FileConfig)
+	var input interface{}
+	e := json.Unmarshal([]byte(`{
+		"collect_list":[
+			{
+				"file_path":"path1",
+				"multi_line_continue_pattern":"^\\s|^Caused by:",
+				"multi_line_end_pattern":";$",
+				"multi_line_negate":true,
+				"multi_line_max_lines":500,
+				"multi_line_timeout":10
+			}
+		]
+	}`), &input)
+	if e != nil {
+		assert.Fail(t, e.Error())
+	}
+	_, val := f.ApplyRule(input)
+	expectVal := []interface{}{map[string]interface{}{
+		"file_path":                   "path1",
+		"from_beginning":              true,
+		"pipe":                        false,
+		"retention_in_days":           -1,
+		"log_group_class":             "",
+		"multi_line_continue_pattern": "^\\s|^Caused by:",
+		"multi_line_end_pattern":      ";$",
+		"multi_line_negate":           true,
+		"multi_line_max_lines":        500,
+		"multi_line_timeout":          "10s",
+		"service_name":                "",
+		"deployment_environment":      "",
+	}}
+	assert.Equal(t, expectVal, val)
+}
+
+func TestMultiLinePreset(t *testing.T) {
+	f := new(FileConfig)
+	var input interface{}
+	e := json.Unmarshal([]byte(`{
+		"collect_list":[
+			{
+				"file_path":"path1",
+				"multi_line_preset":"java"
+			}
+		]
+	}`), &input)
+	if e != nil {
+		assert.Fail(t, e.Error())
+	}
+	_, val := f.ApplyRule(input)
+	expectVal := []interface{}{map[string]interface{}{
+		"file_path":              "path1",
+		"from_beginning":         true,
+		"pipe":                   false,
+		"retention_in_days":      -1,
+		"log_group_class":        "",
+		"multi_line_preset":      "java",
+		"service_name":           "",
+		"deployment_environment": "",
+	}}
+	assert.Equal(t, expectVal, val)
+}
+
+func TestLogGroupSettings(t *testing.T) {
+	f := new(FileConfig)
+	var input interface{}
+	e := json.Unmarshal([]byte(`{
+		"collect_list":[
+			{
+				"file_path":"path1",
+				"kms_key_id":"arn:aws:kms:us-east-1:123456789012:key/abc",
+				"log_group_tags":{"team":"security"},
+				"subscription_filter":{"filter_name":"to-lambda","destination_arn":"arn:aws:lambda:us-east-1:123456789012:function:f"},
+				"data_protection_policy":{"Name":"policy"}
+			}
+		]
+	}`), &input)
+	if e != nil {
+		assert.Fail(t, e.Error())
+	}
+	_, val := f.ApplyRule(input)
+	expectVal := []interface{}{map[string]interface{}{
+		"file_path":         "path1",
+		"from_beginning":    true,
+		"pipe":              false,
+		"retention_in_days": -1,
+		"log_group_class":   "",
+		"kms_key_id":        "arn:aws:kms:us-east-1:123456789012:key/abc",
+		"log_group_tags":    map[string]interface{}{"team": "security"},
+		"subscription_filter": map[string]interface{}{
+			"filter_name":     "to-lambda",
+			"destination_arn": "arn:aws:lambda:us-east-1:123456789012:function:f",
+		},
+		"data_protection_policy": `{"Name":"policy"}`,
+		"service_name":           "",
+		"deployment_environment": "",
+	}}
+	assert.Equal(t, expectVal, val)
+}
+
 func TestEncoding(t *testing.T) {
 	f := new(FileConfig)
 	var input interface{}
@@ -631,6 +732,56 @@ func TestAutoRemoval(t *testing.T) {
 	assert.Equal(t, expectVal, val)
 }
 
+func TestReadCompressedFiles(t *testing.T) {
+	f := new(FileConfig)
+	var input interface{}
+	e := json.Unmarshal([]byte(`{
+		"collect_list":[
+			{
+				"file_path":"/var/log/app.log*",
+				"read_compressed_files": true
+			}
+		]
+	}`), &input)
+	if e != nil {
+		assert.Fail(t, e.Error())
+	}
+	_, val := f.ApplyRule(input)
+	expectVal := []interface{}{map[string]interface{}{
+		"file_path":              "/var/log/app.log*",
+		"from_beginning":         true,
+		"pipe":                   false,
+		"retention_in_days":      -1,
+		"log_group_class":        "",
+		"read_compressed_files":  true,
+		"service_name":           "",
+		"deployment_environment": "",
+	}}
+	assert.Equal(t, expectVal, val)
+
+	e = json.Unmarshal([]byte(`{
+		"collect_list":[
+			{
+				"file_path":"/var/log/app.log*"
+			}
+		]
+	}`), &input)
+	if e != nil {
+		assert.Fail(t, e.Error())
+	}
+	_, val = f.ApplyRule(input)
+	expectVal = []interface{}{map[string]interface{}{
+		"file_path":              "/var/log/app.log*",
+		"from_beginning":         true,
+		"pipe":                   false,
+		"retention_in_days":      -1,
+		"log_group_class":        "",
+		"service_name":           "",
+		"deployment_environment": "",
+	}}
+	assert.Equal(t, expectVal, val)
+}
+
 func TestBackpressureDrop(t *testing.T) {
 	// Save original env var value and restore it after test
 	originalEnvVal := os.Getenv(envconfig.CWAgentLogsBackpressureMode)
@@ -0,0 +1,26 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package collect_list
+
+import (
+	logUtil "github.com/aws/amazon-cloudwatch-agent/translator/translate/logs/util"
+)
+
+type DataProtectionPolicy struct {
+}
+
+func (d *DataProtectionPolicy) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	im := input.(map[string]interface{})
+	if val, ok := im[logUtil.DataProtectionPolicySectionKey]; ok {
+		returnKey = logUtil.DataProtectionPolicySectionKey
+		returnVal = logUtil.GetDataProtectionPolicy(val, GetCurPath())
+	}
+	return
+}
+
+func init() {
+	d := new(DataProtectionPolicy)
+	r := []Rule{d}
+	RegisterRule(logUtil.DataProtectionPolicySectionKey, r)
+}
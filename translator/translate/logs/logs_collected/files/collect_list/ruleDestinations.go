@@ -0,0 +1,73 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package collect_list
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/logs"
+)
+
+const DestinationsSectionKey = "destinations"
+
+// destinationRules are applied to each of the additional destinations of a
+// file, the same way they are applied to the file itself.
+var destinationRules = []Rule{
+	new(LogGroupName),
+	new(LogStreamName),
+	new(LogGroupClass),
+	new(RetentionInDays),
+	new(LogFilter),
+}
+
+type Destinations struct {
+}
+
+func (d *Destinations) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	im := input.(map[string]interface{})
+	val, ok := im[DestinationsSectionKey]
+	if !ok {
+		return
+	}
+	var res []interface{}
+	for _, destination := range val.([]interface{}) {
+		destinationMap, ok := destination.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		res = append(res, translateDestination(destinationMap))
+	}
+	return DestinationsSectionKey, res
+}
+
+func translateDestination(destination map[string]interface{}) map[string]interface{} {
+	res := map[string]interface{}{}
+	for _, rule := range destinationRules {
+		key, val := rule.ApplyRule(destination)
+		if key == "" {
+			continue
+		}
+		// skip the defaults so the logfile plugin falls back to the values of the file
+		if s, ok := val.(string); ok && s == "" {
+			continue
+		}
+		res[key] = val
+	}
+	// A destination with its own region, role or endpoint is published by a
+	// cloudwatchlogs output of its own.
+	settings := map[string]interface{}{}
+	for _, key := range logs.DestinationOutputKeys {
+		if val, ok := destination[key].(string); ok && val != "" {
+			settings[key] = val
+		}
+	}
+	if len(settings) > 0 {
+		res["destination"] = logs.AddDestinationOutput(settings)
+	}
+	return res
+}
+
+func init() {
+	d := new(Destinations)
+	r := []Rule{d}
+	RegisterRule(DestinationsSectionKey, r)
+}
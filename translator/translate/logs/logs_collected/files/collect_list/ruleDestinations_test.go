@@ -0,0 +1,79 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package collect_list
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+func TestApplyDestinationsRule(t *testing.T) {
+	translator.ResetMessages()
+	r := new(Destinations)
+	var input interface{}
+	e := json.Unmarshal([]byte(`{
+		"destinations": [
+			{"log_group_name": "errors", "filters": [{"type": "include", "expression": "ERROR"}]},
+			{"log_group_name": "archive", "log_stream_name": "archive-stream", "log_group_class": "infrequent_access", "retention_in_days": 365}
+		]
+	}`), &input)
+	assert.Nil(t, e)
+
+	retKey, retVal := r.ApplyRule(input)
+	assert.Equal(t, "destinations", retKey)
+	assert.Len(t, translator.ErrorMessages, 0)
+	expected := []interface{}{
+		map[string]interface{}{
+			"log_group_name":    "errors",
+			"retention_in_days": -1,
+			"filters": []interface{}{
+				map[string]interface{}{"type": "include", "expression": "ERROR"},
+			},
+		},
+		map[string]interface{}{
+			"log_group_name":    "archive",
+			"log_stream_name":   "archive-stream",
+			"log_group_class":   "INFREQUENT_ACCESS",
+			"retention_in_days": 365,
+		},
+	}
+	assert.Equal(t, expected, retVal)
+}
+
+func TestApplyDestinationsRuleMissing(t *testing.T) {
+	r := new(Destinations)
+	retKey, retVal := r.ApplyRule(map[string]interface{}{})
+	assert.Equal(t, "", retKey)
+	assert.Nil(t, retVal)
+}
+
+func TestApplyDestinationsRuleOutputs(t *testing.T) {
+	translator.ResetMessages()
+	r := new(Destinations)
+	var input interface{}
+	e := json.Unmarshal([]byte(`{
+		"destinations": [
+			{"log_group_name": "audit", "role_arn": "arn:aws:iam::123456789012:role/audit"},
+			{"log_group_name": "replica", "region": "us-west-2", "endpoint_override": "https://logs.us-west-2.example.com"},
+			{"log_group_name": "audit-errors", "role_arn": "arn:aws:iam::123456789012:role/audit"}
+		]
+	}`), &input)
+	assert.Nil(t, e)
+
+	_, retVal := r.ApplyRule(input)
+	assert.Len(t, translator.ErrorMessages, 0)
+	destinations := retVal.([]interface{})
+	require.Len(t, destinations, 3)
+	// The destinations with the same settings share an output.
+	first := destinations[0].(map[string]interface{})["destination"]
+	assert.NotEmpty(t, first)
+	assert.NotEqual(t, first, destinations[1].(map[string]interface{})["destination"])
+	assert.Equal(t, first, destinations[2].(map[string]interface{})["destination"])
+	assert.NotContains(t, destinations[0], "role_arn")
+}
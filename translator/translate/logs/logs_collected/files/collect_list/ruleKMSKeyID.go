@@ -0,0 +1,24 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package collect_list
+
+const KMSKeyIDSectionKey = "kms_key_id"
+
+type KMSKeyID struct {
+}
+
+func (m *KMSKeyID) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	im := input.(map[string]interface{})
+	if val, ok := im[KMSKeyIDSectionKey]; ok {
+		returnKey = KMSKeyIDSectionKey
+		returnVal = val
+	}
+	return
+}
+
+func init() {
+	m := new(KMSKeyID)
+	r := []Rule{m}
+	RegisterRule(KMSKeyIDSectionKey, r)
+}
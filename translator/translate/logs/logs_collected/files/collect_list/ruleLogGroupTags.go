@@ -0,0 +1,24 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package collect_list
+
+const LogGroupTagsSectionKey = "log_group_tags"
+
+type LogGroupTags struct {
+}
+
+func (m *LogGroupTags) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	im := input.(map[string]interface{})
+	if val, ok := im[LogGroupTagsSectionKey]; ok {
+		returnKey = LogGroupTagsSectionKey
+		returnVal = val
+	}
+	return
+}
+
+func init() {
+	m := new(LogGroupTags)
+	r := []Rule{m}
+	RegisterRule(LogGroupTagsSectionKey, r)
+}
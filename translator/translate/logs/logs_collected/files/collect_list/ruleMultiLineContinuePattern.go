@@ -0,0 +1,24 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package collect_list
+
+const MultiLineContinuePatternSectionKey = "multi_line_continue_pattern"
+
+type MultiLineContinuePattern struct {
+}
+
+func (m *MultiLineContinuePattern) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	im := input.(map[string]interface{})
+	if val, ok := im[MultiLineContinuePatternSectionKey]; ok {
+		returnKey = MultiLineContinuePatternSectionKey
+		returnVal = val
+	}
+	return
+}
+
+func init() {
+	m := new(MultiLineContinuePattern)
+	r := []Rule{m}
+	RegisterRule(MultiLineContinuePatternSectionKey, r)
+}
@@ -0,0 +1,24 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package collect_list
+
+const MultiLineEndPatternSectionKey = "multi_line_end_pattern"
+
+type MultiLineEndPattern struct {
+}
+
+func (m *MultiLineEndPattern) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	im := input.(map[string]interface{})
+	if val, ok := im[MultiLineEndPatternSectionKey]; ok {
+		returnKey = MultiLineEndPatternSectionKey
+		returnVal = val
+	}
+	return
+}
+
+func init() {
+	m := new(MultiLineEndPattern)
+	r := []Rule{m}
+	RegisterRule(MultiLineEndPatternSectionKey, r)
+}
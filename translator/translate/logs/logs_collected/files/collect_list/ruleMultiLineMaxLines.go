@@ -0,0 +1,27 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package collect_list
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+const MultiLineMaxLinesSectionKey = "multi_line_max_lines"
+
+type MultiLineMaxLines struct {
+}
+
+func (m *MultiLineMaxLines) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	im := input.(map[string]interface{})
+	if _, ok := im[MultiLineMaxLinesSectionKey]; !ok {
+		return
+	}
+	return translator.DefaultIntegralCase(MultiLineMaxLinesSectionKey, float64(0), input)
+}
+
+func init() {
+	m := new(MultiLineMaxLines)
+	r := []Rule{m}
+	RegisterRule(MultiLineMaxLinesSectionKey, r)
+}
@@ -0,0 +1,32 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package collect_list
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+const MultiLineNegateSectionKey = "multi_line_negate"
+
+type MultiLineNegate struct {
+}
+
+func (m *MultiLineNegate) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	_, returnVal = translator.DefaultCase(MultiLineNegateSectionKey, "", input)
+	if returnVal == "" {
+		return
+	}
+	returnKey = MultiLineNegateSectionKey
+	var ok bool
+	if returnVal, ok = returnVal.(bool); !ok {
+		returnVal = false
+	}
+	return
+}
+
+func init() {
+	m := new(MultiLineNegate)
+	r := []Rule{m}
+	RegisterRule(MultiLineNegateSectionKey, r)
+}
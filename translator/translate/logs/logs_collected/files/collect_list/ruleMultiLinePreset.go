@@ -0,0 +1,24 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package collect_list
+
+const MultiLinePresetSectionKey = "multi_line_preset"
+
+type MultiLinePreset struct {
+}
+
+func (m *MultiLinePreset) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	im := input.(map[string]interface{})
+	if val, ok := im[MultiLinePresetSectionKey]; ok {
+		returnKey = MultiLinePresetSectionKey
+		returnVal = val
+	}
+	return
+}
+
+func init() {
+	m := new(MultiLinePreset)
+	r := []Rule{m}
+	RegisterRule(MultiLinePresetSectionKey, r)
+}
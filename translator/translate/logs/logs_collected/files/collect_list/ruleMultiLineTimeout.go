@@ -0,0 +1,27 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package collect_list
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+const MultiLineTimeoutSectionKey = "multi_line_timeout"
+
+type MultiLineTimeout struct {
+}
+
+func (m *MultiLineTimeout) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	im := input.(map[string]interface{})
+	if _, ok := im[MultiLineTimeoutSectionKey]; !ok {
+		return
+	}
+	return translator.DefaultTimeIntervalCase(MultiLineTimeoutSectionKey, float64(0), input)
+}
+
+func init() {
+	m := new(MultiLineTimeout)
+	r := []Rule{m}
+	RegisterRule(MultiLineTimeoutSectionKey, r)
+}
@@ -0,0 +1,96 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package collect_list
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+const (
+	ProcessorsSectionKey            = "processors"
+	ProcessorsTypeSectionKey        = "type"
+	ProcessorsExpressionSectionKey  = "expression"
+	ProcessorsPatternsSectionKey    = "patterns"
+	ProcessorsFieldsSectionKey      = "fields"
+	ProcessorsKeysSectionKey        = "keys"
+	ProcessorsReplacementSectionKey = "replacement"
+
+	parseRegexProcessorType = "parse_regex"
+	parseGrokProcessorType  = "parse_grok"
+	addKeysProcessorType    = "add_keys"
+	removeKeysProcessorType = "remove_keys"
+	renameKeysProcessorType = "rename_keys"
+	maskProcessorType       = "mask"
+)
+
+type LogProcessors struct {
+}
+
+func (lp *LogProcessors) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	im := input.(map[string]interface{})
+	val, ok := im[ProcessorsSectionKey]
+	if !ok {
+		return
+	}
+	var res []interface{}
+	for _, processor := range val.([]interface{}) {
+		if processorMap, err := translateProcessor(processor.(map[string]interface{})); err != nil {
+			translator.AddErrorMessages(GetCurPath()+ProcessorsSectionKey, fmt.Sprintf("Processor %v is invalid: %v", processor, err))
+		} else {
+			res = append(res, processorMap)
+		}
+	}
+	return ProcessorsSectionKey, res
+}
+
+func translateProcessor(processor map[string]interface{}) (map[string]interface{}, error) {
+	processorType, _ := processor[ProcessorsTypeSectionKey].(string)
+	expression, _ := processor[ProcessorsExpressionSectionKey].(string)
+	res := map[string]interface{}{ProcessorsTypeSectionKey: processorType}
+	var requiredKey string
+	switch processorType {
+	case parseRegexProcessorType, maskProcessorType:
+		if _, err := regexp.Compile(expression); err != nil {
+			return nil, fmt.Errorf("expression %q is not a valid regular expression", expression)
+		}
+		if expression == "" {
+			return nil, fmt.Errorf("missing %s", ProcessorsExpressionSectionKey)
+		}
+		res[ProcessorsExpressionSectionKey] = expression
+		if val, ok := processor[ProcessorsReplacementSectionKey]; ok && processorType == maskProcessorType {
+			res[ProcessorsReplacementSectionKey] = val
+		}
+	case parseGrokProcessorType:
+		if expression == "" {
+			return nil, fmt.Errorf("missing %s", ProcessorsExpressionSectionKey)
+		}
+		res[ProcessorsExpressionSectionKey] = expression
+		if val, ok := processor[ProcessorsPatternsSectionKey]; ok {
+			res[ProcessorsPatternsSectionKey] = val
+		}
+	case addKeysProcessorType, renameKeysProcessorType:
+		requiredKey = ProcessorsFieldsSectionKey
+	case removeKeysProcessorType:
+		requiredKey = ProcessorsKeysSectionKey
+	default:
+		return nil, fmt.Errorf("unknown type %q", processorType)
+	}
+	if requiredKey != "" {
+		val, ok := processor[requiredKey]
+		if !ok {
+			return nil, fmt.Errorf("missing %s", requiredKey)
+		}
+		res[requiredKey] = val
+	}
+	return res, nil
+}
+
+func init() {
+	lp := new(LogProcessors)
+	r := []Rule{lp}
+	RegisterRule(ProcessorsSectionKey, r)
+}
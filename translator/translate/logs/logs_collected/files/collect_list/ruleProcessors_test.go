@@ -0,0 +1,75 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package collect_list
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+func TestApplyLogProcessorsRule(t *testing.T) {
+	translator.ResetMessages()
+	r := new(LogProcessors)
+	var input interface{}
+	e := json.Unmarshal([]byte(`{
+		"processors": [
+			{"type": "parse_grok", "expression": "%{IP:client} %{REQ:request}", "patterns": {"REQ": "req-\\d+"}},
+			{"type": "parse_regex", "expression": "^(?P<level>\\w+)"},
+			{"type": "add_keys", "fields": {"env": "prod"}},
+			{"type": "remove_keys", "keys": ["password"]},
+			{"type": "rename_keys", "fields": {"msg": "message"}},
+			{"type": "mask", "expression": "\\d{16}", "replacement": "****"}
+		]
+	}`), &input)
+	assert.Nil(t, e)
+
+	retKey, retVal := r.ApplyRule(input)
+	assert.Equal(t, "processors", retKey)
+	assert.Len(t, translator.ErrorMessages, 0)
+	expected := []interface{}{
+		map[string]interface{}{
+			"type":       "parse_grok",
+			"expression": "%{IP:client} %{REQ:request}",
+			"patterns":   map[string]interface{}{"REQ": "req-\\d+"},
+		},
+		map[string]interface{}{"type": "parse_regex", "expression": "^(?P<level>\\w+)"},
+		map[string]interface{}{"type": "add_keys", "fields": map[string]interface{}{"env": "prod"}},
+		map[string]interface{}{"type": "remove_keys", "keys": []interface{}{"password"}},
+		map[string]interface{}{"type": "rename_keys", "fields": map[string]interface{}{"msg": "message"}},
+		map[string]interface{}{"type": "mask", "expression": "\\d{16}", "replacement": "****"},
+	}
+	assert.Equal(t, expected, retVal)
+}
+
+func TestApplyLogProcessorsRuleInvalid(t *testing.T) {
+	translator.ResetMessages()
+	r := new(LogProcessors)
+	var input interface{}
+	e := json.Unmarshal([]byte(`{
+		"processors": [
+			{"type": "unknown"},
+			{"type": "parse_regex", "expression": "(?!re)"},
+			{"type": "parse_grok"},
+			{"type": "mask"},
+			{"type": "add_keys"},
+			{"type": "remove_keys"}
+		]
+	}`), &input)
+	assert.Nil(t, e)
+	retKey, retVal := r.ApplyRule(input)
+	assert.Equal(t, "processors", retKey)
+	assert.Nil(t, retVal)
+	assert.Len(t, translator.ErrorMessages, 6)
+}
+
+func TestApplyLogProcessorsRuleMissing(t *testing.T) {
+	r := new(LogProcessors)
+	retKey, retVal := r.ApplyRule(map[string]interface{}{})
+	assert.Equal(t, "", retKey)
+	assert.Nil(t, retVal)
+}
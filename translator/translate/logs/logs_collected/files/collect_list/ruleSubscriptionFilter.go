@@ -0,0 +1,24 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package collect_list
+
+const SubscriptionFilterSectionKey = "subscription_filter"
+
+type SubscriptionFilter struct {
+}
+
+func (m *SubscriptionFilter) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	im := input.(map[string]interface{})
+	if val, ok := im[SubscriptionFilterSectionKey]; ok {
+		returnKey = SubscriptionFilterSectionKey
+		returnVal = val
+	}
+	return
+}
+
+func init() {
+	m := new(SubscriptionFilter)
+	r := []Rule{m}
+	RegisterRule(SubscriptionFilterSectionKey, r)
+}
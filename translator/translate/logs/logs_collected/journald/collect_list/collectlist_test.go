@@ -0,0 +1,90 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package collectlist
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aws/amazon-cloudwatch-agent/tool/util"
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+func TestApplyRule(t *testing.T) {
+	c := new(CollectList)
+	var rawJsonString = `
+{
+    "collect_list": [
+      {
+        "units": ["sshd.service"],
+        "priority": "warning",
+        "matches": ["_TRANSPORT=syslog"],
+        "fields": ["MESSAGE", "_HOSTNAME"],
+        "log_group_name": "journal",
+        "log_group_class": "STANDARD"
+      },
+      {
+        "directory": "/var/log/journal",
+        "from_beginning": true,
+        "log_group_name": "all",
+        "log_stream_name": "stream",
+        "retention_in_days": 1
+      }
+    ]
+}
+`
+	var expected = []interface{}{
+		map[string]interface{}{
+			"units":             []interface{}{"sshd.service"},
+			"priority":          "warning",
+			"matches":           []interface{}{"_TRANSPORT=syslog"},
+			"fields":            []interface{}{"MESSAGE", "_HOSTNAME"},
+			"log_group_name":    "journal",
+			"retention_in_days": -1,
+			"log_group_class":   util.StandardLogGroupClass,
+		},
+		map[string]interface{}{
+			"directory":         "/var/log/journal",
+			"from_beginning":    true,
+			"log_group_name":    "all",
+			"log_stream_name":   "stream",
+			"retention_in_days": 1,
+			"log_group_class":   "",
+		},
+	}
+
+	var input interface{}
+	require.NoError(t, json.Unmarshal([]byte(rawJsonString), &input))
+	_, actual := c.ApplyRule(input)
+	assert.Equal(t, expected, actual)
+}
+
+func TestConflictingRetention(t *testing.T) {
+	translator.ResetMessages()
+	c := new(CollectList)
+	var rawJsonString = `
+{
+    "collect_list": [
+      {
+        "log_group_name": "journal",
+        "retention_in_days": 3
+      },
+      {
+        "units": ["sshd.service"],
+        "log_group_name": "journal",
+        "retention_in_days": 5
+      }
+    ]
+}
+`
+	var input interface{}
+	require.NoError(t, json.Unmarshal([]byte(rawJsonString), &input))
+	c.ApplyRule(input)
+	assert.Len(t, translator.ErrorMessages, 1)
+	assert.Equal(t, "Under path : /logs/logs_collected/journald/collect_list/ | Error : Different retention_in_days values can't be set for the same log group: journal", translator.ErrorMessages[0])
+	translator.ResetMessages()
+}
@@ -0,0 +1,52 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package journald
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aws/amazon-cloudwatch-agent/translator/config"
+	"github.com/aws/amazon-cloudwatch-agent/translator/context"
+)
+
+func TestApplyRule(t *testing.T) {
+	j := new(Journald)
+	var rawJsonString = `
+{
+	"journald": {
+		"collect_list": [
+			{
+				"units": ["sshd.service"],
+				"log_group_name": "journal"
+			}
+		]
+	}
+}
+`
+	var expected = map[string]interface{}{
+		"journald": []interface{}{
+			map[string]interface{}{
+				"destination":       "cloudwatchlogs",
+				"file_state_folder": "/opt/aws/amazon-cloudwatch-agent/logs/state",
+			},
+		},
+	}
+
+	var input interface{}
+	require.NoError(t, json.Unmarshal([]byte(rawJsonString), &input))
+	context.CurrentContext().SetOs(config.OS_TYPE_LINUX)
+	key, actual := j.ApplyRule(input)
+	assert.Equal(t, "inputs", key)
+	assert.Equal(t, expected, actual)
+}
+
+func TestApplyRule_Missing(t *testing.T) {
+	j := new(Journald)
+	key, _ := j.ApplyRule(map[string]interface{}{})
+	assert.Equal(t, "", key)
+}
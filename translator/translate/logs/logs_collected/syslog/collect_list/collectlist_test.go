@@ -0,0 +1,73 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package collectlist
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aws/amazon-cloudwatch-agent/tool/util"
+)
+
+func TestApplyRule(t *testing.T) {
+	c := new(CollectList)
+	var rawJsonString = `
+{
+    "collect_list": [
+      {
+        "protocol": "udp",
+        "service_address": ":514",
+        "format": "rfc3164",
+        "log_group_name": "syslog/{syslog_facility}",
+        "log_stream_name": "{syslog_hostname}",
+        "log_group_class": "STANDARD"
+      },
+      {
+        "protocol": "tls",
+        "service_address": ":6514",
+        "event_format": "json",
+        "max_message_size": 8192,
+        "tls": {
+          "cert_file": "/etc/ssl/syslog.pem",
+          "key_file": "/etc/ssl/syslog.key",
+          "ca_file": "/etc/ssl/ca.pem"
+        },
+        "log_group_name": "syslog",
+        "retention_in_days": 7
+      }
+    ]
+}
+`
+	var expected = []interface{}{
+		map[string]interface{}{
+			"protocol":          "udp",
+			"service_address":   ":514",
+			"format":            "rfc3164",
+			"log_group_name":    "syslog/{syslog_facility}",
+			"log_stream_name":   "{syslog_hostname}",
+			"retention_in_days": -1,
+			"log_group_class":   util.StandardLogGroupClass,
+		},
+		map[string]interface{}{
+			"protocol":            "tls",
+			"service_address":     ":6514",
+			"event_format":        "json",
+			"max_message_size":    8192,
+			"tls_cert":            "/etc/ssl/syslog.pem",
+			"tls_key":             "/etc/ssl/syslog.key",
+			"tls_allowed_cacerts": []interface{}{"/etc/ssl/ca.pem"},
+			"log_group_name":      "syslog",
+			"retention_in_days":   7,
+			"log_group_class":     "",
+		},
+	}
+
+	var input interface{}
+	require.NoError(t, json.Unmarshal([]byte(rawJsonString), &input))
+	_, actual := c.ApplyRule(input)
+	assert.Equal(t, expected, actual)
+}
@@ -0,0 +1,24 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package collectlist
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+const MaxMessageSizeSectionKey = "max_message_size"
+
+type MaxMessageSize struct {
+}
+
+func (m *MaxMessageSize) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	if _, ok := input.(map[string]interface{})[MaxMessageSizeSectionKey]; !ok {
+		return
+	}
+	return translator.DefaultIntegralCase(MaxMessageSizeSectionKey, float64(0), input)
+}
+
+func init() {
+	RegisterRule(MaxMessageSizeSectionKey, new(MaxMessageSize))
+}
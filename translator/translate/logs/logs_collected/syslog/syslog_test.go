@@ -0,0 +1,48 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package syslog
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyRule(t *testing.T) {
+	s := new(Syslog)
+	var rawJsonString = `
+{
+	"syslog": {
+		"collect_list": [
+			{
+				"protocol": "udp",
+				"service_address": ":514",
+				"log_group_name": "syslog"
+			}
+		]
+	}
+}
+`
+	var expected = map[string]interface{}{
+		"syslog": []interface{}{
+			map[string]interface{}{
+				"destination": "cloudwatchlogs",
+			},
+		},
+	}
+
+	var input interface{}
+	require.NoError(t, json.Unmarshal([]byte(rawJsonString), &input))
+	key, actual := s.ApplyRule(input)
+	assert.Equal(t, "inputs", key)
+	assert.Equal(t, expected, actual)
+}
+
+func TestApplyRule_Missing(t *testing.T) {
+	s := new(Syslog)
+	key, _ := s.ApplyRule(map[string]interface{}{})
+	assert.Equal(t, "", key)
+}
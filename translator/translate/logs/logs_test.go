@@ -10,6 +10,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/aws/amazon-cloudwatch-agent/translator"
 	"github.com/aws/amazon-cloudwatch-agent/translator/config"
 	"github.com/aws/amazon-cloudwatch-agent/translator/context"
 	"github.com/aws/amazon-cloudwatch-agent/translator/translate/agent"
@@ -239,3 +240,155 @@ func TestLogs_ServiceAndEnvironmentMissing(t *testing.T) {
 	assert.Equal(t, "my-service", GlobalLogConfig.ServiceName)
 	assert.Equal(t, "ec2:group", GlobalLogConfig.DeploymentEnvironment)
 }
+
+func TestLogs_DeadLetter(t *testing.T) {
+	l := new(Logs)
+	agent.Global_Config.Region = "us-east-1"
+	agent.Global_Config.RegionType = "any"
+
+	testCases := map[string]struct {
+		input   string
+		wantDir string
+		wantMax int64
+	}{
+		"WithDefaults": {
+			input:   `{"logs":{"log_stream_name":"LOG_STREAM_NAME","dead_letter":{}}}`,
+			wantDir: "/opt/aws/amazon-cloudwatch-agent/logs/dead-letter",
+			wantMax: 100 * 1024 * 1024,
+		},
+		"WithConfig": {
+			input:   `{"logs":{"log_stream_name":"LOG_STREAM_NAME","dead_letter":{"directory":"/tmp/dead-letter","max_size_mb":10}}}`,
+			wantDir: "/tmp/dead-letter",
+			wantMax: 10 * 1024 * 1024,
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			var input interface{}
+			assert.NoError(t, json.Unmarshal([]byte(testCase.input), &input))
+			_, actual := l.ApplyRule(input)
+			expected := map[string]interface{}{
+				"outputs": map[string]interface{}{
+					"cloudwatchlogs": []interface{}{
+						map[string]interface{}{
+							"region":                "us-east-1",
+							"region_type":           "any",
+							"mode":                  "",
+							"log_stream_name":       "LOG_STREAM_NAME",
+							"force_flush_interval":  "5s",
+							"dead_letter_dir":       testCase.wantDir,
+							"dead_letter_max_bytes": testCase.wantMax,
+						},
+					},
+				},
+			}
+			assert.Equal(t, expected, actual)
+		})
+	}
+}
+
+func TestLogs_LogGroupSettings(t *testing.T) {
+	l := new(Logs)
+	agent.Global_Config.Region = "us-east-1"
+	agent.Global_Config.RegionType = "any"
+
+	var input interface{}
+	assert.NoError(t, json.Unmarshal([]byte(`{"logs":{"log_stream_name":"LOG_STREAM_NAME",
+		"kms_key_id":"arn:aws:kms:us-east-1:123456789012:key/abc",
+		"log_group_tags":{"team":"security"},
+		"subscription_filter":{"destination_arn":"arn:aws:lambda:us-east-1:123456789012:function:f","filter_pattern":"ERROR"},
+		"data_protection_policy":{"Name":"policy","Version":"2021-06-01"}}}`), &input))
+	_, actual := l.ApplyRule(input)
+	expected := map[string]interface{}{
+		"outputs": map[string]interface{}{
+			"cloudwatchlogs": []interface{}{
+				map[string]interface{}{
+					"region":               "us-east-1",
+					"region_type":          "any",
+					"mode":                 "",
+					"log_stream_name":      "LOG_STREAM_NAME",
+					"force_flush_interval": "5s",
+					"kms_key_id":           "arn:aws:kms:us-east-1:123456789012:key/abc",
+					"log_group_tags":       map[string]interface{}{"team": "security"},
+					"subscription_filter": map[string]interface{}{
+						"destination_arn": "arn:aws:lambda:us-east-1:123456789012:function:f",
+						"filter_pattern":  "ERROR",
+					},
+					"data_protection_policy": `{"Name":"policy","Version":"2021-06-01"}`,
+				},
+			},
+		},
+	}
+	assert.Equal(t, expected, actual)
+}
+
+// addDestinations adds the outputs of destinations as the destinations rule
+// of collect_list does.
+type addDestinations struct {
+	aliases []string
+}
+
+func (r *addDestinations) ApplyRule(interface{}) (string, interface{}) {
+	r.aliases = []string{
+		AddDestinationOutput(map[string]interface{}{"role_arn": "arn:aws:iam::123456789012:role/audit"}),
+		AddDestinationOutput(map[string]interface{}{"region": "us-west-2"}),
+		AddDestinationOutput(map[string]interface{}{"role_arn": "arn:aws:iam::123456789012:role/audit"}),
+	}
+	return "", nil
+}
+
+func TestLogs_DestinationOutputs(t *testing.T) {
+	rule := &addDestinations{}
+	RegisterRule("test_destinations", rule)
+	t.Cleanup(func() { delete(ChildRule, "test_destinations") })
+	l := new(Logs)
+	agent.Global_Config.Region = "us-east-1"
+	agent.Global_Config.RegionType = "any"
+	translator.SetTargetPlatform("linux")
+
+	var input interface{}
+	assert.NoError(t, json.Unmarshal([]byte(`{"logs":{"log_stream_name":"LOG_STREAM_NAME","endpoint_override":"https://logs.example.com","dead_letter":{}}}`), &input))
+	_, actual := l.ApplyRule(input)
+	assert.Equal(t, []string{"cloudwatchlogs_1", "cloudwatchlogs_2", "cloudwatchlogs_1"}, rule.aliases)
+	main := map[string]interface{}{
+		"region":                "us-east-1",
+		"region_type":           "any",
+		"mode":                  "",
+		"endpoint_override":     "https://logs.example.com",
+		"force_flush_interval":  "5s",
+		"log_stream_name":       "LOG_STREAM_NAME",
+		"dead_letter_dir":       "/opt/aws/amazon-cloudwatch-agent/logs/dead-letter",
+		"dead_letter_max_bytes": int64(100 * 1024 * 1024),
+	}
+	expected := map[string]interface{}{
+		"outputs": map[string]interface{}{
+			"cloudwatchlogs": []interface{}{
+				main,
+				map[string]interface{}{
+					"alias":                 "cloudwatchlogs_1",
+					"region":                "us-east-1",
+					"region_type":           "any",
+					"mode":                  "",
+					"endpoint_override":     "https://logs.example.com",
+					"force_flush_interval":  "5s",
+					"log_stream_name":       "LOG_STREAM_NAME",
+					"role_arn":              "arn:aws:iam::123456789012:role/audit",
+					"dead_letter_dir":       "/opt/aws/amazon-cloudwatch-agent/logs/dead-letter/cloudwatchlogs_1",
+					"dead_letter_max_bytes": int64(100 * 1024 * 1024),
+				},
+				// The endpoint of the logs section is not used in another region.
+				map[string]interface{}{
+					"alias":                 "cloudwatchlogs_2",
+					"region":                "us-west-2",
+					"region_type":           "any",
+					"mode":                  "",
+					"force_flush_interval":  "5s",
+					"log_stream_name":       "LOG_STREAM_NAME",
+					"dead_letter_dir":       "/opt/aws/amazon-cloudwatch-agent/logs/dead-letter/cloudwatchlogs_2",
+					"dead_letter_max_bytes": int64(100 * 1024 * 1024),
+				},
+			},
+		},
+	}
+	assert.Equal(t, expected, actual)
+}
@@ -0,0 +1,42 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package logs
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+	logUtil "github.com/aws/amazon-cloudwatch-agent/translator/translate/logs/util"
+)
+
+const (
+	DeadLetterSectionKey = "dead_letter"
+	defaultDeadLetterMB  = 100
+)
+
+// DeadLetter enables writing the log events that could not be published to a
+// local directory.
+type DeadLetter struct {
+}
+
+func (d *DeadLetter) ApplyRule(input interface{}) (string, interface{}) {
+	im := input.(map[string]interface{})
+	section, ok := im[DeadLetterSectionKey].(map[string]interface{})
+	if !ok {
+		return "", nil
+	}
+	result := map[string]interface{}{}
+	_, dir := translator.DefaultCase("directory", logUtil.GetDeadLetterFolder(), section)
+	result["dead_letter_dir"] = dir
+	maxSizeMB := float64(defaultDeadLetterMB)
+	if _, val := translator.DefaultCase("max_size_mb", maxSizeMB, section); val != nil {
+		if v, ok := val.(float64); ok && v > 0 {
+			maxSizeMB = v
+		}
+	}
+	result["dead_letter_max_bytes"] = int64(maxSizeMB) * 1024 * 1024
+	return Output_Cloudwatch_Logs, result
+}
+
+func init() {
+	RegisterRule(DeadLetterSectionKey, new(DeadLetter))
+}
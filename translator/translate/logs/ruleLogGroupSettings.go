@@ -0,0 +1,35 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package logs
+
+import (
+	logUtil "github.com/aws/amazon-cloudwatch-agent/translator/translate/logs/util"
+)
+
+// LogGroupSettings are the defaults of the settings applied to the log groups
+// the agent creates. The collect_list entries can override them.
+type LogGroupSettings struct {
+}
+
+func (l *LogGroupSettings) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	im := input.(map[string]interface{})
+	res := map[string]interface{}{}
+	for _, key := range []string{"kms_key_id", "log_group_tags", "subscription_filter"} {
+		if val, ok := im[key]; ok {
+			res[key] = val
+		}
+	}
+	if val, ok := im[logUtil.DataProtectionPolicySectionKey]; ok {
+		res[logUtil.DataProtectionPolicySectionKey] = logUtil.GetDataProtectionPolicy(val, GetCurPath())
+	}
+	if len(res) > 0 {
+		returnKey = Output_Cloudwatch_Logs
+		returnVal = res
+	}
+	return
+}
+
+func init() {
+	RegisterRule("log_group_settings", new(LogGroupSettings))
+}
@@ -0,0 +1,24 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package util
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+const DataProtectionPolicySectionKey = "data_protection_policy"
+
+// GetDataProtectionPolicy returns the data protection policy document of the
+// JSON config as the string PutDataProtectionPolicy expects.
+func GetDataProtectionPolicy(policy interface{}, path string) string {
+	doc, err := json.Marshal(policy)
+	if err != nil {
+		translator.AddErrorMessages(path+DataProtectionPolicySectionKey, fmt.Sprintf("Invalid data protection policy: %v", err))
+		return ""
+	}
+	return string(doc)
+}
@@ -0,0 +1,21 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package util
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+	"github.com/aws/amazon-cloudwatch-agent/translator/config"
+	"github.com/aws/amazon-cloudwatch-agent/translator/util"
+)
+
+const Dead_Letter_Folder_Linux = "/opt/aws/amazon-cloudwatch-agent/logs/dead-letter"
+
+func GetDeadLetterFolder() (deadLetterFolder string) {
+	if translator.GetTargetPlatform() == config.OS_TYPE_WINDOWS {
+		deadLetterFolder = util.GetWindowsProgramDataPath() + "\\Amazon\\AmazonCloudWatchAgent\\Logs\\dead-letter"
+	} else {
+		deadLetterFolder = Dead_Letter_Folder_Linux
+	}
+	return
+}
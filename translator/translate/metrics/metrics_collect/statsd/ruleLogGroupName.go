@@ -0,0 +1,26 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package statsd
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+type LogGroupName struct {
+}
+
+const SectionKey_LogGroupName = "log_group_name"
+
+func (obj *LogGroupName) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	key, val := translator.DefaultCase(SectionKey_LogGroupName, "", input)
+	if val != "" {
+		return key, val
+	}
+	return
+}
+
+func init() {
+	obj := new(LogGroupName)
+	RegisterRule(SectionKey_LogGroupName, obj)
+}
@@ -0,0 +1,26 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package statsd
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+type LogStreamName struct {
+}
+
+const SectionKey_LogStreamName = "log_stream_name"
+
+func (obj *LogStreamName) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	key, val := translator.DefaultCase(SectionKey_LogStreamName, "", input)
+	if val != "" {
+		return key, val
+	}
+	return
+}
+
+func init() {
+	obj := new(LogStreamName)
+	RegisterRule(SectionKey_LogStreamName, obj)
+}
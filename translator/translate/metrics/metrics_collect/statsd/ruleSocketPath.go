@@ -0,0 +1,26 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package statsd
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+type SocketPath struct {
+}
+
+const SectionKey_SocketPath = "socket_path"
+
+func (obj *SocketPath) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	key, val := translator.DefaultCase(SectionKey_SocketPath, "", input)
+	if val != "" {
+		return key, val
+	}
+	return
+}
+
+func init() {
+	obj := new(SocketPath)
+	RegisterRule(SectionKey_SocketPath, obj)
+}
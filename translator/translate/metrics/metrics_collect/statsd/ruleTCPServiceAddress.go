@@ -0,0 +1,26 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package statsd
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+type TCPServiceAddress struct {
+}
+
+const SectionKey_TCPServiceAddress = "tcp_service_address"
+
+func (obj *TCPServiceAddress) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	key, val := translator.DefaultCase(SectionKey_TCPServiceAddress, "", input)
+	if val != "" {
+		return key, val
+	}
+	return
+}
+
+func init() {
+	obj := new(TCPServiceAddress)
+	RegisterRule(SectionKey_TCPServiceAddress, obj)
+}
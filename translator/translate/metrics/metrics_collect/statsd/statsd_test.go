@@ -100,3 +100,34 @@ func TestStatsD_MetricSeparator(t *testing.T) {
 
 	assert.Equal(t, expect, actual)
 }
+
+func TestStatsD_DogStatsD(t *testing.T) {
+	obj := new(StatsD)
+	var input interface{}
+	err := json.Unmarshal([]byte(`{"statsd": {
+					"tcp_service_address": ":8125",
+					"max_tcp_connections": 100,
+					"socket_path": "/var/run/datadog/dsd.socket",
+					"log_group_name": "statsd-events",
+					"log_stream_name": "my-host"
+					}}`), &input)
+	assert.NoError(t, err)
+
+	_, actual := obj.ApplyRule(input)
+
+	expect := []interface{}{
+		map[string]interface{}{
+			"service_address":     ":8125",
+			"tcp_service_address": ":8125",
+			"max_tcp_connections": 100,
+			"socket_path":         "/var/run/datadog/dsd.socket",
+			"log_group_name":      "statsd-events",
+			"log_stream_name":     "my-host",
+			"interval":            "10s",
+			"parse_data_dog_tags": true,
+			"tags":                map[string]interface{}{"aws:AggregationInterval": "60s"},
+		},
+	}
+
+	assert.Equal(t, expect, actual)
+}
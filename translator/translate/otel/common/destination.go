@@ -3,20 +3,41 @@
 
 package common
 
-import "go.opentelemetry.io/collector/confmap"
+import (
+	"log"
+	"strings"
+
+	"go.opentelemetry.io/collector/confmap"
+)
 
 const (
 	DefaultDestination = ""
+
+	// CloudWatchDestinationsKey is the list of named CloudWatch destinations
+	// in the cloudwatch metrics destination.
+	CloudWatchDestinationsKey = "destinations"
+	// CloudWatchDestinationNameKey is the name of a CloudWatch destination.
+	CloudWatchDestinationNameKey = "name"
 )
 
 var (
-	metricsDestinationsKey = ConfigKey(MetricsKey, MetricsDestinationsKey)
+	metricsDestinationsKey    = ConfigKey(MetricsKey, MetricsDestinationsKey)
+	cloudWatchDestinationsKey = ConfigKey(metricsDestinationsKey, CloudWatchKey, CloudWatchDestinationsKey)
 )
 
+// GetMetricsDestinations returns the destinations of the metrics section.
+// Each named CloudWatch destination is returned as "cloudwatch/<name>"
+// instead of "cloudwatch".
 func GetMetricsDestinations(conf *confmap.Conf) []string {
 	var destinations []string
 	if conf.IsSet(ConfigKey(metricsDestinationsKey, CloudWatchKey)) {
-		destinations = append(destinations, CloudWatchKey)
+		if names := getCloudWatchDestinationNames(conf); len(names) > 0 {
+			for _, name := range names {
+				destinations = append(destinations, CloudWatchKey+"/"+name)
+			}
+		} else {
+			destinations = append(destinations, CloudWatchKey)
+		}
 	}
 	if conf.IsSet(ConfigKey(metricsDestinationsKey, AMPKey)) {
 		destinations = append(destinations, AMPKey)
@@ -30,3 +51,46 @@ func GetMetricsDestinations(conf *confmap.Conf) []string {
 func GetLogsDestinations() []string {
 	return []string{CloudWatchLogsKey}
 }
+
+// IsCloudWatchDestination returns true for the default, the cloudwatch and
+// the named CloudWatch destinations.
+func IsCloudWatchDestination(destination string) bool {
+	return destination == DefaultDestination || destination == CloudWatchKey ||
+		strings.HasPrefix(destination, CloudWatchKey+"/")
+}
+
+// CloudWatchDestinationName returns the name of a named CloudWatch
+// destination, or an empty string for the others.
+func CloudWatchDestinationName(destination string) string {
+	name, ok := strings.CutPrefix(destination, CloudWatchKey+"/")
+	if !ok {
+		return ""
+	}
+	return name
+}
+
+// GetCloudWatchDestination returns the config of the named CloudWatch
+// destination.
+func GetCloudWatchDestination(conf *confmap.Conf, name string) (map[string]any, bool) {
+	for _, destination := range GetArray[map[string]any](conf, cloudWatchDestinationsKey) {
+		if destination[CloudWatchDestinationNameKey] == name {
+			return destination, true
+		}
+	}
+	return nil, false
+}
+
+func getCloudWatchDestinationNames(conf *confmap.Conf) []string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, destination := range GetArray[map[string]any](conf, cloudWatchDestinationsKey) {
+		name, _ := destination[CloudWatchDestinationNameKey].(string)
+		if name == "" || seen[name] {
+			log.Printf("W! Ignoring CloudWatch destination with missing or duplicate name %q", name)
+			continue
+		}
+		seen[name] = true
+		names = append(names, name)
+	}
+	return names
+}
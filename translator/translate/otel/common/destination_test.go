@@ -66,3 +66,48 @@ func TestGetMetricsDestinations(t *testing.T) {
 		})
 	}
 }
+
+func TestGetMetricsDestinationsWithNamedCloudWatchDestinations(t *testing.T) {
+	conf := confmap.NewFromStringMap(map[string]any{
+		"metrics": map[string]any{
+			"metrics_destinations": map[string]any{
+				"cloudwatch": map[string]any{
+					"destinations": []any{
+						map[string]any{"name": "central", "region": "us-east-1"},
+						map[string]any{"name": "team"},
+						map[string]any{"name": "central"},
+						map[string]any{"region": "us-west-2"},
+					},
+				},
+				"amp": map[string]any{},
+			},
+		},
+	})
+	assert.Equal(t, []string{"cloudwatch/central", "cloudwatch/team", AMPKey}, GetMetricsDestinations(conf))
+
+	got, ok := GetCloudWatchDestination(conf, "central")
+	assert.True(t, ok)
+	assert.Equal(t, map[string]any{"name": "central", "region": "us-east-1"}, got)
+	_, ok = GetCloudWatchDestination(conf, "missing")
+	assert.False(t, ok)
+}
+
+func TestCloudWatchDestinationName(t *testing.T) {
+	testCases := map[string]struct {
+		destination  string
+		isCloudWatch bool
+		name         string
+	}{
+		"Default":    {destination: DefaultDestination, isCloudWatch: true},
+		"CloudWatch": {destination: CloudWatchKey, isCloudWatch: true},
+		"Named":      {destination: "cloudwatch/central", isCloudWatch: true, name: "central"},
+		"AMP":        {destination: AMPKey, isCloudWatch: false},
+		"Logs":       {destination: CloudWatchLogsKey, isCloudWatch: false},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, testCase.isCloudWatch, IsCloudWatchDestination(testCase.destination))
+			assert.Equal(t, testCase.name, CloudWatchDestinationName(testCase.destination))
+		})
+	}
+}
@@ -4,6 +4,8 @@
 package awscloudwatch
 
 import (
+	"path/filepath"
+
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/confmap"
 	"go.opentelemetry.io/collector/exporter"
@@ -16,8 +18,24 @@ import (
 
 const (
 	namespaceKey          = "namespace"
+	regionKey             = "region"
 	forceFlushIntervalKey = "force_flush_interval"
 	dropOriginalWildcard  = "*"
+	diskBufferKey         = "disk_buffer"
+	directoryKey          = "directory"
+	maxBytesKey           = "max_bytes"
+	maxAgeKey             = "max_age"
+	preFlushKey           = "pre_flush_aggregation"
+	intervalKey           = "interval"
+	modeKey               = "mode"
+	rulesKey              = "rules"
+	metricNamesKey        = "metric_names"
+	cardinalityLimitKey   = "cardinality_limit"
+	perMetricKey          = "max_dimension_sets_per_metric"
+	perNamespaceKey       = "max_dimension_sets_per_namespace"
+	rotationIntervalKey   = "rotation_interval"
+	overflowValueKey      = "overflow_value"
+	maxDimensionSetsKey   = "max_dimension_sets"
 
 	internalMaxValuesPerDatum = 5000
 )
@@ -33,6 +51,8 @@ func NewTranslator() common.ComponentTranslator {
 	return NewTranslatorWithName("")
 }
 
+// NewTranslatorWithName creates a translator for the named CloudWatch
+// destination. The name is empty for the default destination.
 func NewTranslatorWithName(name string) common.ComponentTranslator {
 	return &translator{name, cloudwatch.NewFactory()}
 }
@@ -72,9 +92,129 @@ func (t *translator) Translate(conf *confmap.Conf) (component.Config, error) {
 		cfg.DropOriginalConfigs = dropOriginalMetrics
 	}
 	cfg.MiddlewareID = &agenthealth.MetricsID
+	cfg.DiskBuffer = getDiskBuffer(conf)
+	cfg.PreFlushAggregation = getPreFlushAggregation(conf)
+	cfg.CardinalityLimit = getCardinalityLimit(conf)
+	if t.name != "" {
+		destination, ok := common.GetCloudWatchDestination(conf, t.name)
+		if !ok {
+			return nil, &common.MissingKeyError{ID: t.ID(), JsonKey: common.ConfigKey(common.MetricsKey, common.MetricsDestinationsKey, common.CloudWatchKey, common.CloudWatchDestinationsKey)}
+		}
+		applyDestination(cfg, destination)
+		// each destination needs its own buffer so the exporters don't read
+		// each other's batches
+		if cfg.DiskBuffer != nil {
+			cfg.DiskBuffer.Directory = filepath.Join(cfg.DiskBuffer.Directory, t.name)
+		}
+	}
 	return cfg, nil
 }
 
+// applyDestination overrides the exporter config with the fields set in the
+// named CloudWatch destination.
+func applyDestination(cfg *cloudwatch.Config, destination map[string]any) {
+	if region, ok := destination[regionKey].(string); ok && region != "" {
+		cfg.Region = region
+	}
+	if roleARN, ok := destination[common.RoleARNKey].(string); ok && roleARN != "" {
+		cfg.RoleARN = roleARN
+	}
+	if namespace, ok := destination[namespaceKey].(string); ok && namespace != "" {
+		cfg.Namespace = namespace
+	}
+	if endpointOverride, ok := destination[common.EndpointOverrideKey].(string); ok && endpointOverride != "" {
+		cfg.EndpointOverride = endpointOverride
+	}
+}
+
+// getDiskBuffer returns the disk buffer config if a directory is set in the
+// metrics section.
+func getDiskBuffer(conf *confmap.Conf) *cloudwatch.DiskBufferConfig {
+	directory, ok := common.GetString(conf, common.ConfigKey(common.MetricsKey, diskBufferKey, directoryKey))
+	if !ok || directory == "" {
+		return nil
+	}
+	diskBuffer := &cloudwatch.DiskBufferConfig{Directory: directory}
+	if maxBytes, ok := common.GetNumber(conf, common.ConfigKey(common.MetricsKey, diskBufferKey, maxBytesKey)); ok {
+		diskBuffer.MaxBytes = int64(maxBytes)
+	}
+	if maxAge, ok := common.GetDuration(conf, common.ConfigKey(common.MetricsKey, diskBufferKey, maxAgeKey)); ok {
+		diskBuffer.MaxAge = maxAge
+	}
+	return diskBuffer
+}
+
+// getPreFlushAggregation returns the pre-flush aggregation config if it is set
+// in the metrics section.
+func getPreFlushAggregation(conf *confmap.Conf) *cloudwatch.PreFlushAggregationConfig {
+	key := common.ConfigKey(common.MetricsKey, preFlushKey)
+	if !conf.IsSet(key) {
+		return nil
+	}
+	preFlush := &cloudwatch.PreFlushAggregationConfig{}
+	if interval, ok := common.GetDuration(conf, common.ConfigKey(key, intervalKey)); ok {
+		preFlush.Interval = interval
+	}
+	if mode, ok := common.GetString(conf, common.ConfigKey(key, modeKey)); ok {
+		preFlush.Mode = mode
+	}
+	for _, rule := range common.GetArray[map[string]any](conf, common.ConfigKey(key, rulesKey)) {
+		r := cloudwatch.PreFlushAggregationRule{}
+		if metricNames, ok := rule[metricNamesKey].([]any); ok {
+			for _, name := range metricNames {
+				if s, ok := name.(string); ok {
+					r.MetricNames = append(r.MetricNames, s)
+				}
+			}
+		}
+		if interval, err := common.ParseDuration(rule[intervalKey]); err == nil {
+			r.Interval = interval
+		}
+		if mode, ok := rule[modeKey].(string); ok {
+			r.Mode = mode
+		}
+		preFlush.Rules = append(preFlush.Rules, r)
+	}
+	return preFlush
+}
+
+// getCardinalityLimit returns the cardinality limit config if it is set in
+// the metrics section.
+func getCardinalityLimit(conf *confmap.Conf) *cloudwatch.CardinalityLimitConfig {
+	key := common.ConfigKey(common.MetricsKey, cardinalityLimitKey)
+	if !conf.IsSet(key) {
+		return nil
+	}
+	limit := &cloudwatch.CardinalityLimitConfig{}
+	if perMetric, ok := common.GetNumber(conf, common.ConfigKey(key, perMetricKey)); ok {
+		limit.MaxDimensionSetsPerMetric = int(perMetric)
+	}
+	if perNamespace, ok := common.GetNumber(conf, common.ConfigKey(key, perNamespaceKey)); ok {
+		limit.MaxDimensionSetsPerNamespace = int(perNamespace)
+	}
+	if interval, ok := common.GetDuration(conf, common.ConfigKey(key, rotationIntervalKey)); ok {
+		limit.RotationInterval = interval
+	}
+	if overflowValue, ok := common.GetString(conf, common.ConfigKey(key, overflowValueKey)); ok {
+		limit.OverflowValue = overflowValue
+	}
+	for _, rule := range common.GetArray[map[string]any](conf, common.ConfigKey(key, rulesKey)) {
+		r := cloudwatch.CardinalityLimitRule{}
+		if metricNames, ok := rule[metricNamesKey].([]any); ok {
+			for _, name := range metricNames {
+				if s, ok := name.(string); ok {
+					r.MetricNames = append(r.MetricNames, s)
+				}
+			}
+		}
+		if maxDimensionSets, ok := rule[maxDimensionSetsKey].(float64); ok {
+			r.MaxDimensionSets = int(maxDimensionSets)
+		}
+		limit.Rules = append(limit.Rules, r)
+	}
+	return limit
+}
+
 func getRoleARN(conf *confmap.Conf) string {
 	key := common.ConfigKey(common.MetricsKey, common.CredentialsKey, common.RoleARNKey)
 	roleARN, ok := common.GetString(conf, key)
@@ -62,6 +62,88 @@ func TestTranslator(t *testing.T) {
 				RoleARN:            "global_arn",
 			},
 		},
+		"WithDiskBuffer": {
+			input: map[string]interface{}{"metrics": map[string]interface{}{
+				"disk_buffer": map[string]interface{}{
+					"directory": "/opt/aws/amazon-cloudwatch-agent/var/metrics",
+					"max_bytes": float64(1048576),
+					"max_age":   float64(3600),
+				},
+			}},
+			want: &cloudwatch.Config{
+				Namespace:          "CWAgent",
+				Region:             "us-east-1",
+				ForceFlushInterval: time.Minute,
+				MaxValuesPerDatum:  150,
+				RoleARN:            "global_arn",
+				DiskBuffer: &cloudwatch.DiskBufferConfig{
+					Directory: "/opt/aws/amazon-cloudwatch-agent/var/metrics",
+					MaxBytes:  1048576,
+					MaxAge:    time.Hour,
+				},
+			},
+		},
+		"WithPreFlushAggregation": {
+			input: map[string]interface{}{"metrics": map[string]interface{}{
+				"pre_flush_aggregation": map[string]interface{}{
+					"interval": float64(30),
+					"mode":     "statistic_set",
+					"rules": []interface{}{
+						map[string]interface{}{
+							"metric_names": []interface{}{"^latency_"},
+							"interval":     float64(10),
+							"mode":         "distribution",
+						},
+					},
+				},
+			}},
+			want: &cloudwatch.Config{
+				Namespace:          "CWAgent",
+				Region:             "us-east-1",
+				ForceFlushInterval: time.Minute,
+				MaxValuesPerDatum:  150,
+				RoleARN:            "global_arn",
+				PreFlushAggregation: &cloudwatch.PreFlushAggregationConfig{
+					Interval: 30 * time.Second,
+					Mode:     "statistic_set",
+					Rules: []cloudwatch.PreFlushAggregationRule{
+						{MetricNames: []string{"^latency_"}, Interval: 10 * time.Second, Mode: "distribution"},
+					},
+				},
+			},
+		},
+		"WithCardinalityLimit": {
+			input: map[string]interface{}{"metrics": map[string]interface{}{
+				"cardinality_limit": map[string]interface{}{
+					"max_dimension_sets_per_metric":    float64(100),
+					"max_dimension_sets_per_namespace": float64(5000),
+					"rotation_interval":                float64(1800),
+					"overflow_value":                   "Overflow",
+					"rules": []interface{}{
+						map[string]interface{}{
+							"metric_names":       []interface{}{"^statsd_"},
+							"max_dimension_sets": float64(10),
+						},
+					},
+				},
+			}},
+			want: &cloudwatch.Config{
+				Namespace:          "CWAgent",
+				Region:             "us-east-1",
+				ForceFlushInterval: time.Minute,
+				MaxValuesPerDatum:  150,
+				RoleARN:            "global_arn",
+				CardinalityLimit: &cloudwatch.CardinalityLimitConfig{
+					MaxDimensionSetsPerMetric:    100,
+					MaxDimensionSetsPerNamespace: 5000,
+					RotationInterval:             30 * time.Minute,
+					OverflowValue:                "Overflow",
+					Rules: []cloudwatch.CardinalityLimitRule{
+						{MetricNames: []string{"^statsd_"}, MaxDimensionSets: 10},
+					},
+				},
+			},
+		},
 		"WithInvalidCredentialFields": {
 			input: map[string]interface{}{"metrics": map[string]interface{}{}},
 			credentials: map[string]interface{}{
@@ -164,6 +246,9 @@ func TestTranslator(t *testing.T) {
 				assert.Equal(t, testCase.want.SharedCredentialFilename, gotCfg.SharedCredentialFilename)
 				assert.Equal(t, testCase.want.MaxValuesPerDatum, gotCfg.MaxValuesPerDatum)
 				assert.Equal(t, testCase.want.RollupDimensions, gotCfg.RollupDimensions)
+				assert.Equal(t, testCase.want.DiskBuffer, gotCfg.DiskBuffer)
+				assert.Equal(t, testCase.want.PreFlushAggregation, gotCfg.PreFlushAggregation)
+				assert.Equal(t, testCase.want.CardinalityLimit, gotCfg.CardinalityLimit)
 				assert.NotNil(t, gotCfg.MiddlewareID)
 				assert.Equal(t, "agenthealth/metrics", gotCfg.MiddlewareID.String())
 				if testCase.wantWindows != nil && runtime.GOOS == "windows" {
@@ -175,3 +260,54 @@ func TestTranslator(t *testing.T) {
 		})
 	}
 }
+
+func TestTranslatorWithName(t *testing.T) {
+	agent.Global_Config.Region = "us-east-1"
+	agent.Global_Config.Role_arn = "global_arn"
+	agent.Global_Config.Internal = false
+	agent.Global_Config.Credentials = nil
+	conf := confmap.NewFromStringMap(map[string]any{
+		"metrics": map[string]any{
+			"namespace": "CustomNamespace",
+			"disk_buffer": map[string]any{
+				"directory": "/tmp/buffer",
+			},
+			"metrics_destinations": map[string]any{
+				"cloudwatch": map[string]any{
+					"destinations": []any{
+						map[string]any{
+							"name":              "central",
+							"region":            "eu-west-1",
+							"role_arn":          "central_arn",
+							"namespace":         "Central",
+							"endpoint_override": "https://monitoring.eu-west-1.amazonaws.com",
+						},
+						map[string]any{"name": "team"},
+					},
+				},
+			},
+		},
+	})
+
+	cwt := NewTranslatorWithName("central")
+	require.EqualValues(t, "awscloudwatch/central", cwt.ID().String())
+	got, err := cwt.Translate(conf)
+	require.NoError(t, err)
+	gotCfg := got.(*cloudwatch.Config)
+	assert.Equal(t, "eu-west-1", gotCfg.Region)
+	assert.Equal(t, "central_arn", gotCfg.RoleARN)
+	assert.Equal(t, "Central", gotCfg.Namespace)
+	assert.Equal(t, "https://monitoring.eu-west-1.amazonaws.com", gotCfg.EndpointOverride)
+	require.NotNil(t, gotCfg.DiskBuffer)
+	assert.Equal(t, filepath.Join("/tmp/buffer", "central"), gotCfg.DiskBuffer.Directory)
+
+	got, err = NewTranslatorWithName("team").Translate(conf)
+	require.NoError(t, err)
+	gotCfg = got.(*cloudwatch.Config)
+	assert.Equal(t, "us-east-1", gotCfg.Region)
+	assert.Equal(t, "global_arn", gotCfg.RoleARN)
+	assert.Equal(t, "CustomNamespace", gotCfg.Namespace)
+
+	_, err = NewTranslatorWithName("missing").Translate(conf)
+	assert.Error(t, err)
+}
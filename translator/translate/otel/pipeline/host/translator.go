@@ -6,7 +6,6 @@ package host
 import (
 	"fmt"
 	"log"
-	"slices"
 	"strings"
 
 	"go.opentelemetry.io/collector/component"
@@ -27,6 +26,7 @@ import (
 	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/processor/cumulativetodeltaprocessor"
 	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/processor/deltatocumulativeprocessor"
 	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/processor/ec2taggerprocessor"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/processor/filterprocessor"
 	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/processor/metricsdecorator"
 	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/processor/rollupprocessor"
 	"github.com/aws/amazon-cloudwatch-agent/translator/translate/util"
@@ -41,12 +41,6 @@ type translator struct {
 
 var _ common.PipelineTranslator = (*translator)(nil)
 
-var supportedEntityProcessorDestinations = [...]string{
-	common.DefaultDestination,
-	common.CloudWatchKey,
-	common.CloudWatchLogsKey,
-}
-
 // NewTranslator creates a new host pipeline translator. The receiver types
 // passed in are converted to config.ComponentIDs, sorted, and used directly
 // in the translated pipeline.
@@ -97,6 +91,11 @@ func (t translator) Translate(conf *confmap.Conf) (*common.ComponentTranslators,
 			ec2TaggerEnabled = true
 		}
 
+		if rt := filterprocessor.NewRouteTranslator(t.Destination()); rt.IsSet(conf) {
+			log.Printf("D! filter processor required because routing is set for destination %s", t.Destination())
+			translators.Processors.Set(rt)
+		}
+
 		mdt := metricsdecorator.NewTranslator(metricsdecorator.WithIgnorePlugins(common.JmxKey))
 		if mdt.IsSet(conf) {
 			log.Printf("D! metric decorator required because measurement fields are set")
@@ -112,10 +111,10 @@ func (t translator) Translate(conf *confmap.Conf) (*common.ComponentTranslators,
 			entityProcessor = awsentity.NewTranslatorWithEntityType(awsentity.Service, common.OtlpKey, false)
 			translators.Extensions.Set(k8smetadata.NewTranslator())
 		} else if currentContext.Mode() == config.ModeEC2 {
-			switch t.Destination() {
-			case common.DefaultDestination, common.CloudWatchKey:
+			switch {
+			case common.IsCloudWatchDestination(t.Destination()):
 				entityProcessor = util.CreateEntityProcessorFromConfig(common.OtlpKey+"/"+common.CloudWatchKey, common.ConfigKey(common.MetricsKey, common.MetricsCollectedKey, common.OtlpKey), conf)
-			case common.CloudWatchLogsKey:
+			case t.Destination() == common.CloudWatchLogsKey:
 				entityProcessor = util.CreateEntityProcessorFromConfig(common.OtlpKey+"/"+common.CloudWatchLogsKey, common.ConfigKey(common.LogsKey, common.MetricsCollectedKey, common.OtlpKey), conf)
 			}
 		}
@@ -129,19 +128,19 @@ func (t translator) Translate(conf *confmap.Conf) (*common.ComponentTranslators,
 		}
 	}
 
-	validDestination := slices.Contains(supportedEntityProcessorDestinations[:], t.Destination())
+	validDestination := common.IsCloudWatchDestination(t.Destination()) || t.Destination() == common.CloudWatchLogsKey
 	// ECS is not in scope for entity association, so we only add the entity processor in non-ECS platforms
 	isECS := ecsutil.GetECSUtilSingleton().IsECS()
 	if entityProcessor != nil && currentContext.Mode() == config.ModeEC2 && !isECS && validDestination {
 		translators.Processors.Set(entityProcessor)
 	}
 
-	switch t.Destination() {
-	case common.DefaultDestination, common.CloudWatchKey:
-		translators.Exporters.Set(awscloudwatch.NewTranslator())
+	switch destination := t.Destination(); {
+	case common.IsCloudWatchDestination(destination):
+		translators.Exporters.Set(awscloudwatch.NewTranslatorWithName(common.CloudWatchDestinationName(destination)))
 		translators.Extensions.Set(agenthealth.NewTranslator(agenthealth.MetricsName, []string{agenthealth.OperationPutMetricData}))
 		translators.Extensions.Set(agenthealth.NewTranslatorWithStatusCode(agenthealth.StatusCodeName, nil, true))
-	case common.AMPKey:
+	case destination == common.AMPKey:
 		if conf.IsSet(common.MetricsAggregationDimensionsKey) {
 			translators.Processors.Set(rollupprocessor.NewTranslator())
 		}
@@ -150,13 +149,13 @@ func (t translator) Translate(conf *confmap.Conf) (*common.ComponentTranslators,
 		translators.Processors.Set(deltatocumulativeprocessor.NewTranslator(common.WithName(t.name)))
 		translators.Exporters.Set(prometheusremotewrite.NewTranslatorWithName(common.AMPKey))
 		translators.Extensions.Set(sigv4auth.NewTranslator())
-	case common.CloudWatchLogsKey:
+	case destination == common.CloudWatchLogsKey:
 		translators.Processors.Set(batchprocessor.NewTranslatorWithNameAndSection(t.name, common.LogsKey))
 		translators.Exporters.Set(awsemf.NewTranslator())
 		translators.Extensions.Set(agenthealth.NewTranslator(agenthealth.LogsName, []string{agenthealth.OperationPutLogEvents}))
 		translators.Extensions.Set(agenthealth.NewTranslatorWithStatusCode(agenthealth.StatusCodeName, nil, true))
 	default:
-		return nil, fmt.Errorf("pipeline (%s) does not support destination (%s) in configuration", t.name, t.Destination())
+		return nil, fmt.Errorf("pipeline (%s) does not support destination (%s) in configuration", t.name, destination)
 	}
 
 	return &translators, nil
@@ -312,6 +312,57 @@ func TestTranslator(t *testing.T) {
 				extensions: []string{"sigv4auth"},
 			},
 		},
+		"WithNamedCloudWatchDestination": {
+			input: map[string]interface{}{
+				"metrics": map[string]interface{}{
+					"metrics_destinations": map[string]interface{}{
+						"cloudwatch": map[string]interface{}{
+							"destinations": []interface{}{
+								map[string]interface{}{"name": "central", "region": "us-east-1"},
+							},
+						},
+					},
+				},
+			},
+			pipelineName: common.PipelineNameHost,
+			destination:  "cloudwatch/central",
+			mode:         config.ModeEC2,
+			want: &want{
+				pipelineID: "metrics/host/cloudwatch/central",
+				receivers:  []string{"nop", "other"},
+				processors: []string{"awsentity/resource"},
+				exporters:  []string{"awscloudwatch/central"},
+				extensions: []string{"agenthealth/metrics", "agenthealth/statuscode"},
+			},
+		},
+		"WithNamedCloudWatchDestination/Routing": {
+			input: map[string]interface{}{
+				"metrics": map[string]interface{}{
+					"metrics_destinations": map[string]interface{}{
+						"cloudwatch": map[string]interface{}{
+							"destinations": []interface{}{
+								map[string]interface{}{
+									"name": "central",
+									"routing": map[string]interface{}{
+										"measurements": []interface{}{"cpu"},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			pipelineName: common.PipelineNameHost,
+			destination:  "cloudwatch/central",
+			mode:         config.ModeEC2,
+			want: &want{
+				pipelineID: "metrics/host/cloudwatch/central",
+				receivers:  []string{"nop", "other"},
+				processors: []string{"filter/cloudwatch/central", "awsentity/resource"},
+				exporters:  []string{"awscloudwatch/central"},
+				extensions: []string{"agenthealth/metrics", "agenthealth/statuscode"},
+			},
+		},
 		"WithPRWExporter/NoAggregation": {
 			input: map[string]interface{}{
 				"metrics": map[string]interface{}{},
@@ -96,6 +96,34 @@ func TestTranslators(t *testing.T) {
 				},
 			},
 		},
+		"WithNamedCloudWatchDestinations": {
+			input: map[string]any{
+				"metrics": map[string]any{
+					"metrics_destinations": map[string]any{
+						"cloudwatch": map[string]any{
+							"destinations": []any{
+								map[string]any{"name": "central", "region": "us-east-1"},
+								map[string]any{"name": "team", "namespace": "Team"},
+							},
+						},
+					},
+					"metrics_collected": map[string]any{
+						"cpu": map[string]any{},
+					},
+				},
+			},
+			configSection: MetricsKey,
+			want: map[string]want{
+				"metrics/host/cloudwatch/central": {
+					receivers: []string{"telegraf_cpu"},
+					exporters: []string{"awscloudwatch/central"},
+				},
+				"metrics/host/cloudwatch/team": {
+					receivers: []string{"telegraf_cpu"},
+					exporters: []string{"awscloudwatch/team"},
+				},
+			},
+		},
 		"WithDeltaMetrics": {
 			input: map[string]any{
 				"metrics": map[string]any{
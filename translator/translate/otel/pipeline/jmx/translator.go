@@ -111,12 +111,15 @@ func (t *translator) Translate(conf *confmap.Conf) (*common.ComponentTranslators
 		translators.Processors.Set(ec2taggerprocessor.NewTranslator())
 	}
 
-	switch t.Destination() {
-	case common.DefaultDestination, common.CloudWatchKey:
+	switch destination := t.Destination(); {
+	case common.IsCloudWatchDestination(destination):
+		if rt := filterprocessor.NewRouteTranslator(destination); rt.IsSet(conf) {
+			translators.Processors.Set(rt)
+		}
 		translators.Processors.Set(cumulativetodeltaprocessor.NewTranslator(common.WithName(common.PipelineNameJmx), cumulativetodeltaprocessor.WithConfigKeys(common.JmxConfigKey)))
-		translators.Exporters.Set(awscloudwatch.NewTranslator())
+		translators.Exporters.Set(awscloudwatch.NewTranslatorWithName(common.CloudWatchDestinationName(destination)))
 		translators.Extensions.Set(agenthealth.NewTranslatorWithStatusCode(agenthealth.MetricsName, []string{agenthealth.OperationPutMetricData}, true))
-	case common.AMPKey:
+	case destination == common.AMPKey:
 		translators.Processors.Set(batchprocessor.NewTranslatorWithNameAndSection(t.name, common.MetricsKey))
 		if conf.IsSet(common.MetricsAggregationDimensionsKey) {
 			translators.Processors.Set(rollupprocessor.NewTranslator())
@@ -126,7 +129,7 @@ func (t *translator) Translate(conf *confmap.Conf) (*common.ComponentTranslators
 		translators.Exporters.Set(prometheusremotewrite.NewTranslatorWithName(common.AMPKey))
 		translators.Extensions.Set(sigv4auth.NewTranslator())
 	default:
-		return nil, fmt.Errorf("pipeline (%s) does not support destination (%s) in configuration", t.name, t.Destination())
+		return nil, fmt.Errorf("pipeline (%s) does not support destination (%s) in configuration", t.name, destination)
 	}
 
 	return &translators, nil
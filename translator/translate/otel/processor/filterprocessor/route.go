@@ -0,0 +1,159 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package filterprocessor
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/filterprocessor"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/confmap"
+	"go.opentelemetry.io/collector/processor"
+
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/common"
+)
+
+const (
+	routingKey      = "routing"
+	measurementsKey = "measurements"
+	metricNamesKey  = "metric_names"
+	dimensionsKey   = "dimensions"
+
+	// anyDimensionValue matches any value of a dimension, as long as it is set.
+	anyDimensionValue = "*"
+)
+
+type RouteTranslator interface {
+	common.ComponentTranslator
+	// IsSet determines whether the destination has routing rules.
+	IsSet(conf *confmap.Conf) bool
+}
+
+type routeTranslator struct {
+	destination string
+	factory     processor.Factory
+}
+
+var _ RouteTranslator = (*routeTranslator)(nil)
+
+// NewRouteTranslator creates a translator for the filter that only keeps the
+// metrics selected by the routing rules of a named CloudWatch destination.
+func NewRouteTranslator(destination string) RouteTranslator {
+	return &routeTranslator{destination: destination, factory: filterprocessor.NewFactory()}
+}
+
+func (t *routeTranslator) ID() component.ID {
+	return component.NewIDWithName(t.factory.Type(), t.destination)
+}
+
+// IsSet returns true if the destination has routing rules.
+func (t *routeTranslator) IsSet(conf *confmap.Conf) bool {
+	return len(t.rules(conf)) > 0
+}
+
+// Translate creates a filter that drops the data points not matching the
+// routing rules. All rules that are set must match. Within a rule, any of the
+// values can match.
+func (t *routeTranslator) Translate(conf *confmap.Conf) (component.Config, error) {
+	rules := t.rules(conf)
+	if len(rules) == 0 {
+		return nil, &common.MissingKeyError{ID: t.ID(), JsonKey: routingKey}
+	}
+	for i, rule := range rules {
+		rules[i] = "(" + rule + ")"
+	}
+
+	cfg := t.factory.CreateDefaultConfig().(*filterprocessor.Config)
+	c := confmap.NewFromStringMap(map[string]any{
+		"error_mode": "ignore",
+		"metrics": map[string]any{
+			"datapoint": []any{"not (" + strings.Join(rules, " and ") + ")"},
+		},
+	})
+	if err := c.Unmarshal(&cfg); err != nil {
+		return nil, fmt.Errorf("unable to unmarshal filter processor (%s): %w", t.ID(), err)
+	}
+	return cfg, nil
+}
+
+// rules returns an OTTL condition for each routing rule of the destination.
+func (t *routeTranslator) rules(conf *confmap.Conf) []string {
+	routing, ok := t.routing(conf)
+	if !ok {
+		return nil
+	}
+	var rules []string
+	if measurements := toStrings(routing[measurementsKey]); len(measurements) > 0 {
+		patterns := make([]string, len(measurements))
+		for i, measurement := range measurements {
+			patterns[i] = regexp.QuoteMeta(measurement)
+		}
+		rules = append(rules, fmt.Sprintf("IsMatch(metric.name, %s)", quote("^("+strings.Join(patterns, "|")+")_")))
+	}
+	if metricNames := toStrings(routing[metricNamesKey]); len(metricNames) > 0 {
+		conditions := make([]string, len(metricNames))
+		for i, metricName := range metricNames {
+			conditions[i] = "metric.name == " + quote(metricName)
+		}
+		rules = append(rules, strings.Join(conditions, " or "))
+	}
+	if dimensions, ok := routing[dimensionsKey].(map[string]any); ok {
+		keys := make([]string, 0, len(dimensions))
+		for key := range dimensions {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			attribute := "attributes[" + quote(key) + "]"
+			var conditions []string
+			for _, value := range toStrings(dimensions[key]) {
+				if value == anyDimensionValue {
+					conditions = append(conditions, attribute+" != nil")
+				} else {
+					conditions = append(conditions, attribute+" == "+quote(value))
+				}
+			}
+			if len(conditions) > 0 {
+				rules = append(rules, strings.Join(conditions, " or "))
+			}
+		}
+	}
+	return rules
+}
+
+func (t *routeTranslator) routing(conf *confmap.Conf) (map[string]any, bool) {
+	name := common.CloudWatchDestinationName(t.destination)
+	if conf == nil || name == "" {
+		return nil, false
+	}
+	destination, ok := common.GetCloudWatchDestination(conf, name)
+	if !ok {
+		return nil, false
+	}
+	routing, ok := destination[routingKey].(map[string]any)
+	return routing, ok && len(routing) > 0
+}
+
+func toStrings(value any) []string {
+	var result []string
+	switch v := value.(type) {
+	case string:
+		result = append(result, v)
+	case []any:
+		for _, entry := range v {
+			if s, ok := entry.(string); ok {
+				result = append(result, s)
+			}
+		}
+	}
+	return result
+}
+
+// quote returns the value as an OTTL string literal.
+func quote(value string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(value) + `"`
+}
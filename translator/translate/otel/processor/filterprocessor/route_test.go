@@ -0,0 +1,120 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package filterprocessor
+
+import (
+	"testing"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/filterprocessor"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/confmap"
+
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/common"
+)
+
+func TestRouteTranslator(t *testing.T) {
+	factory := filterprocessor.NewFactory()
+	withDestinations := func(destinations ...any) map[string]any {
+		return map[string]any{
+			"metrics": map[string]any{
+				"metrics_destinations": map[string]any{
+					"cloudwatch": map[string]any{
+						"destinations": destinations,
+					},
+				},
+			},
+		}
+	}
+	testCases := map[string]struct {
+		input       map[string]any
+		destination string
+		wantIsSet   bool
+		want        []string
+		wantErr     error
+	}{
+		"WithDefaultDestination": {
+			input:       withDestinations(),
+			destination: common.CloudWatchKey,
+			wantErr: &common.MissingKeyError{
+				ID:      component.NewIDWithName(factory.Type(), common.CloudWatchKey),
+				JsonKey: routingKey,
+			},
+		},
+		"WithoutRouting": {
+			input: withDestinations(map[string]any{
+				"name":   "central",
+				"region": "us-east-1",
+			}),
+			destination: "cloudwatch/central",
+			wantErr: &common.MissingKeyError{
+				ID:      component.NewIDWithName(factory.Type(), "cloudwatch/central"),
+				JsonKey: routingKey,
+			},
+		},
+		"WithEmptyRules": {
+			input: withDestinations(map[string]any{
+				"name": "central",
+				"routing": map[string]any{
+					"measurements": []any{},
+				},
+			}),
+			destination: "cloudwatch/central",
+			wantErr: &common.MissingKeyError{
+				ID:      component.NewIDWithName(factory.Type(), "cloudwatch/central"),
+				JsonKey: routingKey,
+			},
+		},
+		"WithMeasurements": {
+			input: withDestinations(map[string]any{
+				"name": "central",
+				"routing": map[string]any{
+					"measurements": []any{"cpu", "disk.io"},
+				},
+			}),
+			destination: "cloudwatch/central",
+			wantIsSet:   true,
+			want:        []string{`not ((IsMatch(metric.name, "^(cpu|disk\\.io)_")))`},
+		},
+		"WithAllRules": {
+			input: withDestinations(
+				map[string]any{"name": "other"},
+				map[string]any{
+					"name": "central",
+					"routing": map[string]any{
+						"measurements": []any{"mem"},
+						"metric_names": []any{"mem_used_percent", `quoted"name`},
+						"dimensions": map[string]any{
+							"host": []any{"*"},
+							"env":  []any{"prod", "staging"},
+						},
+					},
+				},
+			),
+			destination: "cloudwatch/central",
+			wantIsSet:   true,
+			want: []string{
+				`not ((IsMatch(metric.name, "^(mem)_")) and (metric.name == "mem_used_percent" or metric.name == "quoted\"name") and ` +
+					`(attributes["env"] == "prod" or attributes["env"] == "staging") and (attributes["host"] != nil))`,
+			},
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			tt := NewRouteTranslator(testCase.destination)
+			require.EqualValues(t, "filter/"+testCase.destination, tt.ID().String())
+			conf := confmap.NewFromStringMap(testCase.input)
+			require.Equal(t, testCase.wantIsSet, tt.IsSet(conf))
+			got, err := tt.Translate(conf)
+			require.Equal(t, testCase.wantErr, err)
+			if err == nil {
+				require.NotNil(t, got)
+				gotCfg, ok := got.(*filterprocessor.Config)
+				require.True(t, ok)
+				require.EqualValues(t, "ignore", gotCfg.ErrorMode)
+				require.Equal(t, testCase.want, gotCfg.Metrics.DataPointConditions)
+			}
+		})
+	}
+}
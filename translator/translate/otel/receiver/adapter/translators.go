@@ -15,6 +15,8 @@ import (
 	"github.com/aws/amazon-cloudwatch-agent/internal/util/collections"
 	translatorconfig "github.com/aws/amazon-cloudwatch-agent/translator/config"
 	"github.com/aws/amazon-cloudwatch-agent/translator/translate/logs/logs_collected/files"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/logs/logs_collected/journald"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/logs/logs_collected/syslog"
 	"github.com/aws/amazon-cloudwatch-agent/translator/translate/logs/logs_collected/windows_events"
 	"github.com/aws/amazon-cloudwatch-agent/translator/translate/metrics/metrics_collect"
 	collectd "github.com/aws/amazon-cloudwatch-agent/translator/translate/metrics/metrics_collect/collectd"
@@ -33,7 +35,7 @@ const (
 var (
 	logKey           = common.ConfigKey(common.LogsKey, common.LogsCollectedKey)
 	metricKey        = common.ConfigKey(common.MetricsKey, common.MetricsCollectedKey)
-	skipInputSet     = collections.NewSet[string](files.SectionKey, windows_events.SectionKey)
+	skipInputSet     = collections.NewSet[string](files.SectionKey, journald.SectionKey, syslog.SectionKey, windows_events.SectionKey)
 	multipleInputSet = collections.NewSet[string](procstat.SectionKey)
 	// Order by PidFile, ExeKey, Pattern Key according to the public documents
 	// if multiple configuration is specified